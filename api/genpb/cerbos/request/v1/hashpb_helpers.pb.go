@@ -567,6 +567,25 @@ func cerbos_request_v1_AuxData_hashpb_sum(m *AuxData, hasher hash.Hash, ignore m
 		}
 
 	}
+	if _, ok := ignore["cerbos.request.v1.AuxData.jwts"]; !ok {
+		if len(m.Jwts) > 0 {
+			keys := make([]string, len(m.Jwts))
+			i := 0
+			for k := range m.Jwts {
+				keys[i] = k
+				i++
+			}
+
+			sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+
+			for _, k := range keys {
+				if m.Jwts[k] != nil {
+					cerbos_request_v1_AuxData_JWT_hashpb_sum(m.Jwts[k], hasher, ignore)
+				}
+
+			}
+		}
+	}
 }
 
 func cerbos_request_v1_CheckResourceBatchRequest_BatchEntry_hashpb_sum(m *CheckResourceBatchRequest_BatchEntry, hasher hash.Hash, ignore map[string]struct{}) {
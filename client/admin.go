@@ -66,14 +66,24 @@ func NewAdminClientWithCredentials(address, username, password string, opts ...O
 		basicAuth = basicAuth.Insecure()
 	}
 
-	return &GrpcAdminClient{client: svcv1.NewCerbosAdminServiceClient(grpcConn), creds: basicAuth}, nil
+	return &GrpcAdminClient{client: svcv1.NewCerbosAdminServiceClient(grpcConn), conn: grpcConn, creds: basicAuth}, nil
 }
 
 type GrpcAdminClient struct {
 	client svcv1.CerbosAdminServiceClient
+	conn   *grpc.ClientConn
 	creds  credentials.PerRPCCredentials
 }
 
+// Close closes the connection to the server. The client cannot be used afterwards.
+func (c *GrpcAdminClient) Close() error {
+	if c.conn == nil {
+		return nil
+	}
+
+	return c.conn.Close()
+}
+
 func (c *GrpcAdminClient) AddOrUpdatePolicy(ctx context.Context, policies *PolicySet) error {
 	if err := policies.Validate(); err != nil {
 		return err
@@ -5,7 +5,6 @@ package server
 
 import (
 	"context"
-	"fmt"
 	"os"
 	"os/signal"
 	"strings"
@@ -15,7 +14,6 @@ import (
 	"github.com/google/gops/agent"
 	"go.uber.org/automaxprocs/maxprocs"
 	"go.uber.org/zap"
-	"helm.sh/helm/v3/pkg/strvals"
 
 	"github.com/cerbos/cerbos/internal/config"
 	"github.com/cerbos/cerbos/internal/observability/logging"
@@ -50,6 +48,7 @@ type Cmd struct {
 	DebugListenAddr string       `help:"Address to start the gops listener" placeholder:":6666"`
 	LogLevel        LogLevelFlag `help:"Log level (${enum})" default:"info" enum:"debug,info,warn,error"`
 	Config          string       `help:"Path to config file" type:"existingfile" required:"" placeholder:"./config.yaml" env:"CERBOS_CONFIG"`
+	SecretsEnv      string       `help:"Name of the environment variable holding the secrets referenced by the config" placeholder:"APP_SECRETS" env:"CERBOS_SECRETS_ENV"`
 	Set             []string     `help:"Config overrides" placeholder:"server.adminAPI.enabled=true"`
 	ZPagesEnabled   bool         `help:"Enable zpages" hidden:""`
 }
@@ -76,11 +75,13 @@ func (c *Cmd) Run() error {
 	}
 
 	// load any config overrides
-	confOverrides := map[string]any{}
-	for _, override := range c.Set {
-		if err := strvals.ParseInto(override, confOverrides); err != nil {
-			return fmt.Errorf("failed to parse config override [%s]: %w", override, err)
-		}
+	confOverrides, err := config.ParseOverrides(c.Set)
+	if err != nil {
+		return err
+	}
+
+	if c.SecretsEnv != "" {
+		config.SetSecretsEnv(c.SecretsEnv)
 	}
 
 	// load configuration
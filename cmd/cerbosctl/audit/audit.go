@@ -6,9 +6,14 @@ package audit
 import (
 	"bufio"
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"os"
+	"os/signal"
 	"strings"
+	"syscall"
+	"time"
 
 	"github.com/alecthomas/chroma"
 	"github.com/alecthomas/chroma/formatters"
@@ -16,17 +21,45 @@ import (
 	"github.com/alecthomas/chroma/styles"
 	"github.com/alecthomas/kong"
 	"github.com/jwalton/gchalk"
+	"go.uber.org/multierr"
 	"google.golang.org/protobuf/encoding/protojson"
 	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
 	auditv1 "github.com/cerbos/cerbos/api/genpb/cerbos/audit/v1"
 	"github.com/cerbos/cerbos/client"
 	cmdclient "github.com/cerbos/cerbos/cmd/cerbosctl/internal/client"
 	"github.com/cerbos/cerbos/cmd/cerbosctl/internal/flagset"
+	"github.com/cerbos/cerbos/cmd/cerbosctl/internal/settings"
 )
 
 var newline = []byte("\n")
 
+const (
+	formatCSV      = "csv"
+	formatJSON     = "json"
+	formatNDJSON   = "ndjson"
+	formatParquet  = "parquet"
+	formatProtobuf = "protobuf"
+	formatRich     = "rich"
+	formatYAML     = "yaml"
+)
+
+var outputFormats = map[string]struct{}{
+	formatCSV:      {},
+	formatJSON:     {},
+	formatNDJSON:   {},
+	formatParquet:  {},
+	formatProtobuf: {},
+	formatRich:     {},
+	formatYAML:     {},
+}
+
+// outputFormatEnvVar is read by outputFormat rather than bound to the flag so that Validate only sees explicitly requested formats.
+const outputFormatEnvVar = "CERBOSCTL_AUDIT_OUTPUT_FORMAT"
+
+var errParquetFollow = errors.New("the parquet output format cannot be combined with --follow because the file can only be finalized once all records are written")
+
 const (
 	dashLen = 54
 	help    = `View audit logs.
@@ -50,26 +83,215 @@ cerbosctl audit --kind=decision --between=2021-07-01T00:00:00Z
 cerbosctl audit --kind=access --since=3h --raw
 
 # View a specific access log entry by call ID
-cerbosctl audit --kind=access --lookup=01F9Y5MFYTX7Y87A30CTJ2FB0S`
+cerbosctl audit --kind=access --lookup=01F9Y5MFYTX7Y87A30CTJ2FB0S
+
+# View several decision log entries by call ID, in the order given
+cerbosctl audit --kind=decision --lookup=01F9Y5MFYTX7Y87A30CTJ2FB0S,01F9Y5N5RD3BJQZC6Z3XQYB4CH
+
+# View the last 10 decision logs and keep streaming new entries as they arrive
+cerbosctl audit --kind=decision --tail=10 --follow
+
+# Keep streaming decision logs and reconnect whenever server.address changes in the cerbosctl configuration file
+cerbosctl audit --kind=decision --follow --watch-config
+
+# Export the decision logs from midnight 2021-07-01 to midnight 2021-07-02 to a Parquet file
+cerbosctl audit --kind=decision --between=2021-07-01T00:00:00Z,2021-07-02T00:00:00Z --output-format=parquet > decisions.parquet
+
+# Export the decision logs from the last day as length-prefixed protobuf messages for re-ingesting elsewhere
+cerbosctl audit --kind=decision --since=24h --output-format=protobuf > decisions.pb
+
+# Export the access logs from 3 hours ago to now as CSV
+cerbosctl audit --kind=access --since=3h --output=csv > access.csv
+
+# Write the decision logs from midnight 2021-07-01 to now to a file as newline-delimited JSON
+cerbosctl audit --kind=decision --between=2021-07-01T00:00:00Z --out=decisions.ndjson
+
+# View the decision logs from 3 hours ago to now for principal harry on leave_request resources
+cerbosctl audit --kind=decision --since=3h --principal=harry --resource=leave_request
+
+# View the last 10 decision logs using a theme suitable for terminals with a light background
+cerbosctl audit --kind=decision --tail=10 --theme=solarized-light
+
+# Summarise the decision logs from 3 hours ago to now
+cerbosctl audit --kind=decision --since=3h --summary
+
+# View the last 10 decision logs with timestamps in the Europe/London time zone
+cerbosctl audit --kind=decision --tail=10 --timezone=Europe/London
+
+# Summarise the first 1000 decision logs from 3 hours ago to now for principal harry
+cerbosctl audit --kind=decision --since=3h --principal=harry --summary --max-results=1000
+
+# Export the decision logs from the last day as JSON, preceded by a record describing the server, the filters and the export time
+cerbosctl audit --kind=decision --since=24h --output-format=json --meta > decisions.json
+
+# Export the decision logs from the last day as newline-delimited JSON, replacing the principal IDs with their SHA-256 hashes
+cerbosctl audit --kind=decision --since=24h --raw --redact=checkResources.inputs.principal.id,planResources.input.principal.id --redact-hash
+
+# Fail if principal harry has not made any requests in the last hour
+cerbosctl audit --kind=decision --since=1h --principal=harry --fail-if-empty
+
+# View the decision logs previously exported with --raw, without connecting to the server
+cerbosctl audit --kind=decision --from-file=decisions.ndjson --principal=harry
+
+# View the decision logs from 3 hours ago to now that denied at least one action
+cerbosctl audit --kind=decision --since=3h --effect=deny
+
+# View the decision logs from 3 hours ago to now grouped by principal
+cerbosctl audit --kind=decision --since=3h --sort-by=principal
+
+The output format is determined by the first of the following that is defined:
+the --output-format flag (or its alias --output, or --raw, which is equivalent to --output-format=ndjson),
+the CERBOSCTL_AUDIT_OUTPUT_FORMAT environment variable, the audit.outputFormat setting
+in the cerbosctl configuration file, and finally the built-in default of rich.
+Unless it is explicitly requested with the --output-format flag, the rich format is replaced by ndjson
+when the output is not a terminal or colours are disabled using --no-color or the NO_COLOR environment variable.`
 )
 
 type Cmd struct {
 	Kind string `default:"access" enum:"access,decision" help:"Kind of log entry (${enum})"`
 	flagset.AuditFilters
-	Raw bool `help:"Output results without formatting or colours"`
+	OutputFormat    string        `help:"Output format (rich, json, ndjson, yaml, csv, parquet, protobuf)" aliases:"output"`
+	Out             string        `help:"Write the output to the given file instead of stdout. The file is created or truncated" type:"path"`
+	Raw             bool          `help:"Output results without formatting or colours"`
+	NoColor         bool          `help:"Disable colours. Unless the rich format is explicitly requested, newline-delimited JSON is used instead. Implied by the NO_COLOR environment variable"`
+	Theme           string        `help:"Chroma style used to colour the rich output format" default:"solarized-dark256"`
+	Follow          bool          `help:"Keep streaming new records as they arrive"`
+	WatchConfig     bool          `help:"Reconnect to the server address defined in the cerbosctl configuration file whenever the file changes. Requires --follow"`
+	Progress        bool          `help:"Periodically report progress to stderr. Disabled when stderr is not a terminal unless --force-progress is set"`
+	ForceProgress   bool          `help:"Report progress to stderr even if it is not a terminal"`
+	Timezone        string        `help:"Display timestamps in the given IANA time zone (e.g. Europe/London) or the local time zone of the machine if set to local. Only applies to the rich output format unless --localtime is set"`
+	LocalTime       bool          `help:"Convert timestamps to the time zone set by --timezone (or the local time zone if it is not set) in all output formats except parquet and protobuf" name:"localtime"`
+	SortBy          string        `help:"Sort the output by the given field (callId, method, peer, principal, resource, timestamp)"`
+	SortDesc        bool          `help:"Sort in descending order when used with --sort-by"`
+	Summary         bool          `help:"Print a summary of the records instead of the records themselves. The summary is a table in the rich format and a JSON object otherwise"`
+	SummaryTop      int           `help:"Number of most frequent principals, resources and methods to include in the summary" default:"10"`
+	MaxResults      int           `help:"Stop after writing the given number of records. Records excluded by the --principal, --resource, --action or --effect filters are not counted"`
+	FailIfEmpty     bool          `help:"Exit with status code 3 if no records were written"`
+	Meta            bool          `help:"Write a metadata record with the server address, the filters and the export time before the records. Only supported by the rich, json, ndjson and yaml output formats"`
+	Redact          []string      `help:"Remove the fields at the given dot-separated paths (e.g. checkResources.inputs.principal.attr.email) from each record before writing it. Use * to match any field name. Can be repeated or given a comma-separated list"`
+	RedactHash      bool          `help:"Replace the fields selected by --redact with the hex-encoded SHA-256 hash of their values instead of removing them. Only string fields can be hashed"`
+	FromFile        string        `help:"Read the records from a file written with the ndjson (or --raw) or protobuf output formats instead of the server. The --tail, --between, --since and --lookup filters are applied to the records in the file" type:"existingfile"`
+	ShutdownTimeout time.Duration `help:"Maximum time to spend flushing pending records after receiving an interrupt or termination signal" default:"10s"`
 }
 
-func (c *Cmd) Run(k *kong.Kong, ctx *cmdclient.Context) error {
-	var writer auditLogWriter
-	if c.Raw {
-		writer = newRawAuditLogWriter(k.Stdout)
+// auditLogEntry is the common interface of access and decision log entries.
+type auditLogEntry interface {
+	proto.Message
+	GetCallId() string
+	GetTimestamp() *timestamppb.Timestamp
+}
+
+func (c *Cmd) Run(k *kong.Kong, globals *flagset.Globals, ctx *cmdclient.Context) (outErr error) {
+	out := k.Stdout
+	if c.Out != "" {
+		f, err := os.Create(c.Out)
+		if err != nil {
+			return fmt.Errorf("failed to create output file: %w", err)
+		}
+
+		defer func() {
+			if err := f.Close(); err != nil {
+				outErr = multierr.Append(outErr, fmt.Errorf("failed to close output file: %w", err))
+			}
+		}()
+
+		out = f
+	}
+
+	format, err := c.outputFormat(globals, out)
+	if err != nil {
+		return err
+	}
+
+	if c.Follow && format == formatParquet {
+		return errParquetFollow
+	}
+
+	if c.Meta && !supportsMeta(format) {
+		return fmt.Errorf("--meta cannot be combined with --output-format=%s", format)
+	}
+
+	loc, err := c.location()
+	if err != nil {
+		return err
+	}
+
+	runCtx, stopSignals := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stopSignals()
+
+	// the output is wrapped after choosing the format because the format depends on whether the output is a terminal
+	output := newAbortableOutput(out)
+
+	var base auditLogWriter
+	if c.Summary {
+		base = newSummaryWriter(output, c.SummaryTop, format != formatRich)
 	} else {
-		writer = newRichAuditLogWriter(k.Stdout)
+		base = newAuditLogWriter(format, c.Kind, c.Theme, c.writerLocation(format, loc), output)
 	}
-	defer writer.flush()
+
+	writer := base
+	if len(c.Redact) > 0 {
+		// records are redacted just before they are written, so sorting still uses the original values
+		if writer, err = newRedactingWriter(base, c.Kind, c.Redact, c.RedactHash); err != nil {
+			return err
+		}
+	}
+
+	defer func() {
+		if runCtx.Err() == nil {
+			if err := base.flush(); err != nil {
+				outErr = multierr.Append(outErr, fmt.Errorf("failed to flush output: %w", err))
+			}
+			return
+		}
+
+		// stop intercepting signals so that a second signal terminates the process if flushing takes too long
+		stopSignals()
+		fmt.Fprintf(k.Stderr, "Interrupted, flushing pending records (timeout %s)\n", c.ShutdownTimeout)
+		if err := flushWithDeadline(base, output, c.ShutdownTimeout); err != nil {
+			fmt.Fprintf(k.Stderr, "Failed to flush pending records: %v\n", err)
+		}
+	}()
 
 	logOptions := c.AuditFilters.GenOptions()
 
+	var sw *sortingWriter
+	if c.SortBy != "" {
+		sw = newSortingWriter(writer, c.SortBy, c.SortDesc)
+		writer = sw
+	}
+
+	// progress is reported for the records that pass the filters and the limit, so it wraps the writers that come after them
+	if c.progressEnabled(k.Stderr) {
+		pw := newProgressWriter(writer, k.Stderr, logOptions.StartTime, logOptions.EndTime)
+		defer pw.stop()
+		writer = pw
+	}
+
+	// streamCtx is cancelled when the maximum number of results is reached so that the server stops sending records
+	streamCtx, cancelStream := context.WithCancel(runCtx)
+	defer cancelStream()
+
+	var lw *limitingWriter
+	if c.MaxResults > 0 {
+		lw = newLimitingWriter(writer, c.MaxResults, cancelStream)
+		writer = lw
+	}
+
+	match, err := c.AuditFilters.DecisionFilter()
+	if err != nil {
+		return err
+	}
+
+	counter := &countingWriter{auditLogWriter: writer}
+	writer = counter
+
+	var fw *filteringWriter
+	if match != nil {
+		fw = newFilteringWriter(writer, match)
+		writer = fw
+	}
+
 	switch kind := c.Kind; kind {
 	case "access":
 		logOptions.Type = client.AccessLogs
@@ -77,55 +299,461 @@ func (c *Cmd) Run(k *kong.Kong, ctx *cmdclient.Context) error {
 		logOptions.Type = client.DecisionLogs
 	}
 
-	logs, err := ctx.AdminClient.AuditLogs(context.Background(), logOptions)
-	if err != nil {
-		return fmt.Errorf("could not get decision logs: %w", err)
+	fetch := fetchFromServer(ctx.AdminClient)
+	if c.FromFile != "" {
+		entries, err := readLogFile(c.FromFile, c.Kind)
+		if err != nil {
+			return err
+		}
+
+		fetch = fetchFromFile(entries)
+	}
+
+	if c.Meta {
+		server, err := c.initialServerAddress(globals)
+		if err != nil {
+			return err
+		}
+
+		if err := writeMeta(base, c.exportMeta(server, logOptions, c.writerLocation(format, loc))); err != nil {
+			return err
+		}
 	}
 
-	if err = streamLogsToWriter(writer, logs); err != nil {
+	if c.Follow {
+		followCtx, cancelFn := context.WithCancel(streamCtx)
+		defer cancelFn()
+
+		adminClient, err := c.initialAdminClient(globals, ctx)
+		if err != nil {
+			return err
+		}
+
+		f := newFollower(fetchFromServer(adminClient), logOptions.Type, k.Stderr)
+		if c.WatchConfig {
+			c.watchConfig(followCtx, globals, f, adminClient, k.Stderr)
+		}
+
+		if err := f.follow(followCtx, logOptions, writer); err != nil {
+			return fmt.Errorf("could not write audit logs: %w", err)
+		}
+
+		c.reportTruncation(k.Stderr, lw)
+		return nil
+	}
+
+	var missing []string
+	switch {
+	case len(c.Lookup) > 0:
+		missing, err = lookupLogs(streamCtx, fetch, logOptions.Type, c.Lookup, writer)
+	case c.FromFile != "":
+		_, err = writeFetchedLogs(streamCtx, fetch, logOptions, writer)
+	default:
+		var logs <-chan *client.AuditLogEntry
+		if logs, err = ctx.AdminClient.AuditLogs(streamCtx, logOptions); err != nil {
+			return fmt.Errorf("could not get decision logs: %w", err)
+		}
+
+		err = streamLogsToWriter(writer, logs)
+	}
+
+	// errors caused by interrupting the stream are expected: the records received so far are still written out
+	if err != nil && runCtx.Err() == nil && (lw == nil || !lw.reached()) {
 		return fmt.Errorf("could not write decision logs: %w", err)
 	}
+
+	if sw != nil {
+		if err := sw.emit(); err != nil {
+			return fmt.Errorf("could not write decision logs: %w", err)
+		}
+	}
+
+	if runCtx.Err() != nil {
+		return errInterrupted
+	}
+
+	if fw != nil && fw.matched == 0 {
+		fmt.Fprintln(k.Stderr, "No records matched the --principal, --resource, --action or --effect filters")
+	}
+
+	if len(missing) > 0 {
+		fmt.Fprintf(k.Stderr, "No records found for call IDs: %s\n", strings.Join(missing, ", "))
+	}
+
+	c.reportTruncation(k.Stderr, lw)
+
+	if c.FailIfEmpty && counter.count == 0 {
+		return NoRecordsError{}
+	}
+
 	return nil
 }
 
+// reportTruncation prints a notice if the output was cut short by --max-results.
+func (c *Cmd) reportTruncation(stderr io.Writer, lw *limitingWriter) {
+	if lw != nil && lw.reached() {
+		fmt.Fprintf(stderr, "Stopped after %d records because of --max-results. More records might be available\n", c.MaxResults)
+	}
+}
+
 func (c *Cmd) Help() string {
 	return help
 }
 
 func (c *Cmd) Validate() error {
+	if c.OutputFormat != "" {
+		if err := validateOutputFormat(c.OutputFormat); err != nil {
+			return err
+		}
+
+		if c.Raw && c.OutputFormat != formatNDJSON {
+			return fmt.Errorf("--raw cannot be combined with --output-format=%s", c.OutputFormat)
+		}
+	}
+
+	if c.WatchConfig && !c.Follow {
+		return errors.New("--watch-config requires --follow")
+	}
+
+	if c.FromFile != "" && c.Follow {
+		return errors.New("--from-file cannot be combined with --follow")
+	}
+
+	if c.HasDecisionFilter() && c.Kind != "decision" {
+		return errors.New("--principal, --resource, --action and --effect can only be used with --kind=decision")
+	}
+
+	if c.Follow && c.FailIfEmpty {
+		return errors.New("--fail-if-empty cannot be combined with --follow")
+	}
+
+	if c.Follow && (c.Between.IsSet() || len(c.Lookup) > 0) {
+		return errors.New("--follow cannot be combined with --between or --lookup")
+	}
+
+	if c.Follow && c.OutputFormat == formatParquet {
+		return errParquetFollow
+	}
+
+	if c.SortBy != "" {
+		if c.Follow {
+			return errors.New("--sort-by cannot be combined with --follow because sorting requires a bounded set of records")
+		}
+
+		if err := validateSortField(c.SortBy); err != nil {
+			return err
+		}
+	} else if c.SortDesc {
+		return errors.New("--sort-desc requires --sort-by")
+	}
+
+	if c.Summary {
+		if err := c.validateSummary(); err != nil {
+			return err
+		}
+	}
+
+	if c.Meta {
+		if c.Summary {
+			return errors.New("--meta cannot be combined with --summary")
+		}
+
+		if c.OutputFormat != "" && !supportsMeta(c.OutputFormat) {
+			return fmt.Errorf("--meta cannot be combined with --output-format=%s", c.OutputFormat)
+		}
+	}
+
+	if c.RedactHash && len(c.Redact) == 0 {
+		return errors.New("--redact-hash requires --redact")
+	}
+
+	if _, err := parseRedactPaths(c.Kind, c.Redact, c.RedactHash); err != nil {
+		return err
+	}
+
+	if c.NoColor && c.OutputFormat == formatRich {
+		return errors.New("--no-color cannot be combined with --output-format=rich")
+	}
+
+	if err := validateTheme(c.Theme); err != nil {
+		return err
+	}
+
+	if _, err := c.location(); err != nil {
+		return err
+	}
+
+	if c.MaxResults < 0 {
+		return errors.New("--max-results must not be negative")
+	}
+
+	if c.ShutdownTimeout <= 0 {
+		return errors.New("--shutdown-timeout must be greater than zero")
+	}
+
 	return c.AuditFilters.Validate()
 }
 
-func streamLogsToWriter(writer auditLogWriter, entries <-chan *client.AuditLogEntry) error {
-	for e := range entries {
-		aLog, err := e.AccessLog()
+func (c *Cmd) validateSummary() error {
+	if c.Follow {
+		return errors.New("--summary cannot be combined with --follow because the summary requires a bounded set of records")
+	}
+
+	if c.SortBy != "" {
+		return errors.New("--summary cannot be combined with --sort-by")
+	}
+
+	if c.OutputFormat == formatCSV || c.OutputFormat == formatParquet || c.OutputFormat == formatProtobuf {
+		return fmt.Errorf("--summary cannot be combined with --output-format=%s", c.OutputFormat)
+	}
+
+	if c.SummaryTop <= 0 {
+		return errors.New("--summary-top must be greater than zero")
+	}
+
+	return nil
+}
+
+// outputFormat determines the output format in order of precedence: flag, environment variable, cerbosctl configuration file and the built-in default.
+// The rich format is only used by default if colours are enabled and the output is a terminal. Otherwise, ndjson is used instead.
+func (c *Cmd) outputFormat(globals *flagset.Globals, out io.Writer) (string, error) {
+	if c.OutputFormat != "" {
+		return c.OutputFormat, nil
+	}
+
+	if c.Raw {
+		return formatNDJSON, nil
+	}
+
+	if f := os.Getenv(outputFormatEnvVar); f != "" {
+		if err := validateOutputFormat(f); err != nil {
+			return "", fmt.Errorf("invalid %s: %w", outputFormatEnvVar, err)
+		}
+
+		if f == formatRich && !c.colorsEnabled(out) {
+			return formatNDJSON, nil
+		}
+
+		return f, nil
+	}
+
+	s, err := settings.Load(globals.Config)
+	if err != nil {
+		return "", err
+	}
+
+	if f := s.Audit.OutputFormat; f != "" {
+		if err := validateOutputFormat(f); err != nil {
+			return "", fmt.Errorf("invalid audit.outputFormat in cerbosctl configuration: %w", err)
+		}
+
+		if f != formatRich || c.colorsEnabled(out) {
+			return f, nil
+		}
+	}
+
+	if !c.colorsEnabled(out) {
+		return formatNDJSON, nil
+	}
+
+	return formatRich, nil
+}
+
+// location returns the time zone to display timestamps in, or nil if they should be displayed in UTC.
+func (c *Cmd) location() (*time.Location, error) {
+	if c.Timezone == "" && c.LocalTime {
+		return parseTimezone(localTimezone)
+	}
+
+	return parseTimezone(c.Timezone)
+}
+
+// writerLocation returns the time zone the writer for the given format should use. Formats other than rich are meant to be
+// machine-readable, so their timestamps are left in UTC unless --localtime is set.
+func (c *Cmd) writerLocation(format string, loc *time.Location) *time.Location {
+	if format != formatRich && !c.LocalTime {
+		return nil
+	}
+
+	return loc
+}
+
+// colorsEnabled returns true if colours can be written to the given output.
+func (c *Cmd) colorsEnabled(out io.Writer) bool {
+	if c.NoColor || os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+
+	return isTerminal(out)
+}
+
+// initialAdminClient returns the admin client to start following the audit logs with.
+// When the configuration file is watched, the server address defined in it takes precedence.
+func (c *Cmd) initialAdminClient(globals *flagset.Globals, ctx *cmdclient.Context) (client.AdminClient, error) {
+	address, err := c.initialServerAddress(globals)
+	if err != nil {
+		return nil, err
+	}
+
+	if address == globals.Server {
+		return ctx.AdminClient, nil
+	}
+
+	return adminClientFor(globals, address)
+}
+
+// Offline returns true if the audit logs are read from a file, in which case no connection to the server is needed.
+func (c *Cmd) Offline() bool {
+	return c.FromFile != ""
+}
+
+// initialServerAddress returns the address of the server to get the audit logs from.
+// When the configuration file is watched, the server address defined in it takes precedence.
+// There is no server when the audit logs are read from a file.
+func (c *Cmd) initialServerAddress(globals *flagset.Globals) (string, error) {
+	if c.Offline() {
+		return "", nil
+	}
+
+	if !c.WatchConfig {
+		return globals.Server, nil
+	}
+
+	s, err := settings.Load(globals.Config)
+	if err != nil {
+		return "", err
+	}
+
+	if s.Server.Address == "" {
+		return globals.Server, nil
+	}
+
+	return s.Server.Address, nil
+}
+
+// watchConfig reconnects the follower to the server defined in the cerbosctl configuration file whenever it changes.
+// The client the follower was using until then is closed once the follower has switched to the new one.
+func (c *Cmd) watchConfig(ctx context.Context, globals *flagset.Globals, f *follower, current client.AdminClient, stderr io.Writer) {
+	err := settings.Watch(ctx, globals.Config, func(s *settings.Settings, err error) {
 		if err != nil {
-			return fmt.Errorf("error while receiving access logs: %w", err)
+			fmt.Fprintf(stderr, "Ignoring invalid cerbosctl configuration: %v\n", err)
+			return
 		}
-		if aLog != nil {
-			if err := writer.write(aLog); err != nil {
-				return err
-			}
-			continue
+
+		if s.Server.Address == "" {
+			return
 		}
 
-		dLog, err := e.DecisionLog()
+		ac, err := adminClientFor(globals, s.Server.Address)
 		if err != nil {
-			return fmt.Errorf("error while receiving decision logs: %w", err)
+			fmt.Fprintf(stderr, "Failed to connect to %s: %v\n", s.Server.Address, err)
+			return
 		}
-		if dLog != nil {
-			if err := writer.write(dLog); err != nil {
-				return err
+
+		fmt.Fprintf(stderr, "Configuration changed, reconnecting to %s\n", s.Server.Address)
+		f.setFetcher(fetchFromServer(ac))
+
+		// the follower no longer uses the previous client because setFetcher interrupts the stream it was reading from
+		if closer, ok := current.(io.Closer); ok {
+			if err := closer.Close(); err != nil {
+				fmt.Fprintf(stderr, "Failed to close the previous connection: %v\n", err)
 			}
 		}
+		current = ac
+	})
+	if err != nil {
+		fmt.Fprintf(stderr, "Not watching the cerbosctl configuration file for changes: %v\n", err)
+	}
+}
+
+func adminClientFor(globals *flagset.Globals, address string) (client.AdminClient, error) {
+	g := *globals
+	g.Server = address
+	return cmdclient.GetAdminClient(&g)
+}
+
+func validateOutputFormat(format string) error {
+	if _, ok := outputFormats[format]; !ok {
+		return fmt.Errorf("unknown output format %q", format)
+	}
+
+	return nil
+}
+
+func streamLogsToWriter(writer auditLogWriter, entries <-chan *client.AuditLogEntry) error {
+	for e := range entries {
+		entry, err := logEntry(e)
+		if err != nil {
+			return err
+		}
+
+		if entry == nil {
+			continue
+		}
+
+		if err := writer.write(entry); err != nil {
+			return err
+		}
 	}
 
 	return nil
 }
 
+// logEntry returns the access or decision log contained in the given entry.
+func logEntry(e *client.AuditLogEntry) (auditLogEntry, error) {
+	aLog, err := e.AccessLog()
+	if err != nil {
+		return nil, fmt.Errorf("error while receiving access logs: %w", err)
+	}
+	if aLog != nil {
+		return aLog, nil
+	}
+
+	dLog, err := e.DecisionLog()
+	if err != nil {
+		return nil, fmt.Errorf("error while receiving decision logs: %w", err)
+	}
+	if dLog != nil {
+		return dLog, nil
+	}
+
+	return nil, nil
+}
+
 type auditLogWriter interface {
 	write(proto.Message) error
-	flush()
+	flush() error
+}
+
+// newAuditLogWriter returns a writer for the given format. If loc is not nil, timestamps are written in that location
+// (except in the parquet and protobuf formats, which store timestamps without a time zone).
+func newAuditLogWriter(format, kind, theme string, loc *time.Location, out io.Writer) auditLogWriter {
+	switch format {
+	case formatCSV:
+		w := newCSVAuditLogWriter(out, kind)
+		w.loc = loc
+		return w
+	case formatJSON:
+		w := newJSONAuditLogWriter(out)
+		w.loc = loc
+		return w
+	case formatNDJSON:
+		w := newRawAuditLogWriter(out)
+		w.loc = loc
+		return w
+	case formatParquet:
+		return newParquetAuditLogWriter(out)
+	case formatProtobuf:
+		return newProtobufAuditLogWriter(out)
+	case formatYAML:
+		w := newYAMLAuditLogWriter(out)
+		w.loc = loc
+		return w
+	default:
+		w := newRichAuditLogWriter(out, theme)
+		w.loc = loc
+		return w
+	}
 }
 
 func newRawAuditLogWriter(out io.Writer) *rawAuditLogWriter {
@@ -134,6 +762,7 @@ func newRawAuditLogWriter(out io.Writer) *rawAuditLogWriter {
 
 type rawAuditLogWriter struct {
 	out io.Writer
+	loc *time.Location
 }
 
 func (r *rawAuditLogWriter) write(entry proto.Message) error {
@@ -142,7 +771,7 @@ func (r *rawAuditLogWriter) write(entry proto.Message) error {
 		return err
 	}
 
-	if _, err := r.out.Write(outBytes); err != nil {
+	if _, err := r.out.Write(localizeTimestamps(outBytes, r.loc)); err != nil {
 		return err
 	}
 
@@ -150,16 +779,18 @@ func (r *rawAuditLogWriter) write(entry proto.Message) error {
 	return err
 }
 
-func (r *rawAuditLogWriter) flush() {}
+func (r *rawAuditLogWriter) flush() error { return nil }
 
 type richAuditLogWriter struct {
 	out       *bufio.Writer
 	lexer     chroma.Lexer
 	formatter chroma.Formatter
+	style     *chroma.Style
+	loc       *time.Location
 	rowStyle  func(...string) string
 }
 
-func newRichAuditLogWriter(out io.Writer) *richAuditLogWriter {
+func newRichAuditLogWriter(out io.Writer, theme string) *richAuditLogWriter {
 	lexer := lexers.Get("json")
 	if lexer == nil {
 		lexer = lexers.Fallback
@@ -175,14 +806,41 @@ func newRichAuditLogWriter(out io.Writer) *richAuditLogWriter {
 		formatter = formatters.TTY
 	}
 
+	style := styles.Get(theme)
 	return &richAuditLogWriter{
 		out:       bufio.NewWriter(out),
 		lexer:     chroma.Coalesce(lexer),
 		formatter: formatter,
-		rowStyle:  gchalk.WithHex("#eeeeee").WithBgHex("#005fff").Bold,
+		style:     style,
+		rowStyle:  headerStyle(style),
 	}
 }
 
+// headerStyle derives the style of the header rows from the theme: the text is drawn in the background colour of the theme
+// over the colour used for JSON keys.
+func headerStyle(style *chroma.Style) func(...string) string {
+	fg, bg := "#eeeeee", "#005fff"
+	if c := style.Get(chroma.Background).Background; c.IsSet() {
+		fg = c.String()
+	}
+
+	if c := style.Get(chroma.NameTag).Colour; c.IsSet() {
+		bg = c.String()
+	}
+
+	return gchalk.WithHex(fg).WithBgHex(bg).Bold
+}
+
+func validateTheme(theme string) error {
+	for _, name := range styles.Names() {
+		if name == theme {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("unknown theme %q: must be one of %s", theme, strings.Join(styles.Names(), ", "))
+}
+
 func (r *richAuditLogWriter) write(entry proto.Message) error {
 	switch e := entry.(type) {
 	case *auditv1.AccessLogEntry:
@@ -203,12 +861,16 @@ func (r *richAuditLogWriter) header(h string) {
 }
 
 func (r *richAuditLogWriter) formattedJSON(msg proto.Message) error {
-	iterator, err := r.lexer.Tokenise(nil, protojson.Format(msg))
+	return r.highlightJSON(string(localizeTimestamps([]byte(protojson.Format(msg)), r.loc)))
+}
+
+func (r *richAuditLogWriter) highlightJSON(jsonStr string) error {
+	iterator, err := r.lexer.Tokenise(nil, jsonStr)
 	if err != nil {
 		return err
 	}
 
-	if err := r.formatter.Format(r.out, styles.SolarizedDark256, iterator); err != nil {
+	if err := r.formatter.Format(r.out, r.style, iterator); err != nil {
 		return err
 	}
 
@@ -216,6 +878,6 @@ func (r *richAuditLogWriter) formattedJSON(msg proto.Message) error {
 	return err
 }
 
-func (r *richAuditLogWriter) flush() {
-	_ = r.out.Flush()
+func (r *richAuditLogWriter) flush() error {
+	return r.out.Flush()
 }
@@ -0,0 +1,123 @@
+// Copyright 2021-2022 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/alecthomas/chroma/styles"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cerbos/cerbos/cmd/cerbosctl/internal/flagset"
+)
+
+func TestOutputFormat(t *testing.T) {
+	dir := t.TempDir()
+	emptyConf := filepath.Join(dir, "empty.yaml")
+	require.NoError(t, os.WriteFile(emptyConf, []byte("{}\n"), 0o600))
+	csvConf := filepath.Join(dir, "csv.yaml")
+	require.NoError(t, os.WriteFile(csvConf, []byte("audit:\n  outputFormat: csv\n"), 0o600))
+	richConf := filepath.Join(dir, "rich.yaml")
+	require.NoError(t, os.WriteFile(richConf, []byte("audit:\n  outputFormat: rich\n"), 0o600))
+
+	testCases := []struct {
+		name    string
+		cmd     Cmd
+		config  string
+		env     string
+		want    string
+		wantErr bool
+	}{
+		{name: "not_terminal", config: emptyConf, want: formatNDJSON},
+		{name: "rich_config_not_terminal", config: richConf, want: formatNDJSON},
+		{name: "rich_flag_not_terminal", cmd: Cmd{OutputFormat: formatRich}, config: emptyConf, want: formatRich},
+		{name: "out", cmd: Cmd{Out: "audit.log"}, config: emptyConf, want: formatNDJSON},
+		{name: "out_with_flag", cmd: Cmd{Out: "audit.log", OutputFormat: formatYAML}, config: emptyConf, want: formatYAML},
+		{name: "out_with_raw", cmd: Cmd{Out: "audit.log", Raw: true}, config: emptyConf, want: formatNDJSON},
+		{name: "out_with_config", cmd: Cmd{Out: "audit.log"}, config: csvConf, want: formatCSV},
+		{name: "env", config: emptyConf, env: formatYAML, want: formatYAML},
+		{name: "env_over_config", config: csvConf, env: formatYAML, want: formatYAML},
+		{name: "flag_over_env", cmd: Cmd{OutputFormat: formatJSON}, config: emptyConf, env: formatCSV, want: formatJSON},
+		{name: "raw_over_env", cmd: Cmd{Raw: true}, config: emptyConf, env: formatCSV, want: formatNDJSON},
+		{name: "rich_env_not_terminal", config: csvConf, env: formatRich, want: formatNDJSON},
+		{name: "rich_env_no_color", cmd: Cmd{NoColor: true}, config: emptyConf, env: formatRich, want: formatNDJSON},
+		{name: "invalid_env", config: emptyConf, env: "xml", wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv(outputFormatEnvVar, tc.env)
+
+			have, err := tc.cmd.outputFormat(&flagset.Globals{Config: tc.config}, &bytes.Buffer{})
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.want, have)
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	testCases := []struct {
+		name    string
+		cmd     Cmd
+		wantErr bool
+	}{
+		{name: "follow", cmd: Cmd{Follow: true}},
+		{name: "follow_with_lookup", cmd: Cmd{Follow: true, AuditFilters: flagset.AuditFilters{Lookup: []string{"01GH0000000000000000000001"}}}, wantErr: true},
+		{name: "watch_config_without_follow", cmd: Cmd{WatchConfig: true}, wantErr: true},
+		{name: "theme", cmd: Cmd{Theme: "solarized-light"}},
+		{name: "unknown_theme", cmd: Cmd{Theme: "wibble"}, wantErr: true},
+		{name: "no_color_with_rich", cmd: Cmd{NoColor: true, OutputFormat: formatRich}, wantErr: true},
+		{name: "summary", cmd: Cmd{Summary: true, SummaryTop: 5}},
+		{name: "summary_with_follow", cmd: Cmd{Summary: true, SummaryTop: 5, Follow: true}, wantErr: true},
+		{name: "summary_with_sort_by", cmd: Cmd{Summary: true, SummaryTop: 5, SortBy: "principal"}, wantErr: true},
+		{name: "summary_with_csv", cmd: Cmd{Summary: true, SummaryTop: 5, OutputFormat: formatCSV}, wantErr: true},
+		{name: "summary_with_protobuf", cmd: Cmd{Summary: true, SummaryTop: 5, OutputFormat: formatProtobuf}, wantErr: true},
+		{name: "summary_without_top", cmd: Cmd{Summary: true}, wantErr: true},
+		{name: "negative_max_results", cmd: Cmd{MaxResults: -1}, wantErr: true},
+		{name: "fail_if_empty_with_follow", cmd: Cmd{FailIfEmpty: true, Follow: true}, wantErr: true},
+		{name: "meta", cmd: Cmd{Meta: true, OutputFormat: formatJSON}},
+		{name: "meta_with_csv", cmd: Cmd{Meta: true, OutputFormat: formatCSV}, wantErr: true},
+		{name: "meta_with_summary", cmd: Cmd{Meta: true, Summary: true, SummaryTop: 5}, wantErr: true},
+		{name: "redact", cmd: Cmd{Redact: []string{"checkResources.inputs.principal.id"}, RedactHash: true}},
+		{name: "redact_invalid_path", cmd: Cmd{Redact: []string{"checkResources..principal"}}, wantErr: true},
+		{name: "redact_hash_without_redact", cmd: Cmd{RedactHash: true}, wantErr: true},
+		{name: "redact_non_string", cmd: Cmd{Kind: "decision", Redact: []string{"timestamp"}}},
+		{name: "redact_hash_non_string", cmd: Cmd{Kind: "decision", Redact: []string{"timestamp"}, RedactHash: true}, wantErr: true},
+		{name: "from_file", cmd: Cmd{FromFile: "decisions.ndjson", Kind: "decision", AuditFilters: flagset.AuditFilters{Principal: "harry"}}},
+		{name: "from_file_with_follow", cmd: Cmd{FromFile: "decisions.ndjson", Follow: true}, wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			tc.cmd.ShutdownTimeout = time.Second
+			if tc.cmd.Theme == "" {
+				tc.cmd.Theme = "solarized-dark256"
+			}
+			err := tc.cmd.Validate()
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestHeaderStyle(t *testing.T) {
+	for _, theme := range []string{"solarized-dark256", "solarized-light", "bw"} {
+		require.NotEmpty(t, headerStyle(styles.Get(theme))("header"), "Header is empty for theme %s", theme)
+	}
+}
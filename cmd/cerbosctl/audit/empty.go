@@ -0,0 +1,38 @@
+// Copyright 2021-2022 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"google.golang.org/protobuf/proto"
+)
+
+// ExitCodeNoRecords is the exit code used when --fail-if-empty is set and no records were written.
+const ExitCodeNoRecords = 3
+
+// NoRecordsError is returned when --fail-if-empty is set and no records were written.
+type NoRecordsError struct{}
+
+func (NoRecordsError) Error() string {
+	return "no records found"
+}
+
+// ExitCode returns the exit code that distinguishes an empty result from other failures.
+func (NoRecordsError) ExitCode() int {
+	return ExitCodeNoRecords
+}
+
+// countingWriter counts the entries written to the underlying writer.
+type countingWriter struct {
+	auditLogWriter
+	count int
+}
+
+func (cw *countingWriter) write(entry proto.Message) error {
+	if err := cw.auditLogWriter.write(entry); err != nil {
+		return err
+	}
+
+	cw.count++
+	return nil
+}
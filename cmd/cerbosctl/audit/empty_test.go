@@ -0,0 +1,35 @@
+// Copyright 2021-2022 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	auditv1 "github.com/cerbos/cerbos/api/genpb/cerbos/audit/v1"
+)
+
+func TestCountingWriter(t *testing.T) {
+	cw := &collectingWriter{}
+	counter := &countingWriter{auditLogWriter: cw}
+	fw := newFilteringWriter(counter, func(e *auditv1.DecisionLogEntry) bool { return e.CallId != "skip" })
+
+	for _, id := range []string{"a", "skip", "b"} {
+		require.NoError(t, fw.write(&auditv1.DecisionLogEntry{CallId: id}))
+	}
+
+	require.Equal(t, 2, counter.count)
+	require.Equal(t, []string{"a", "b"}, cw.callIDs())
+}
+
+func TestNoRecordsError(t *testing.T) {
+	err := fmt.Errorf("audit: %w", NoRecordsError{})
+
+	var ec interface{ ExitCode() int }
+	require.True(t, errors.As(err, &ec))
+	require.Equal(t, ExitCodeNoRecords, ec.ExitCode())
+}
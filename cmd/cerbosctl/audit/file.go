@@ -0,0 +1,149 @@
+// Copyright 2021-2022 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"google.golang.org/protobuf/encoding/protojson"
+
+	auditv1 "github.com/cerbos/cerbos/api/genpb/cerbos/audit/v1"
+	"github.com/cerbos/cerbos/client"
+)
+
+// metaPrefix is the start of the metadata record written by --meta in the ndjson output format.
+var metaPrefix = []byte(`{"` + metaKey + `":`)
+
+// ndjsonPrefix is the start of every record in the ndjson output format.
+var ndjsonPrefix = []byte(`{"`)
+
+// readLogFile reads the entries of the given kind from a file written with the ndjson or protobuf output formats.
+// The format is detected from the contents: the file is read as ndjson if it starts with a JSON object.
+//
+// Checking whether the first line is valid JSON is not enough, because a protobuf frame starts with its length followed
+// by the tag of the first field, which is a newline. The first line of a frame of 48 to 57 bytes is therefore a digit.
+// A frame can even start with an opening brace (a length of 123 bytes), but it is followed by the newline rather than
+// by the quote that starts the first key of a JSON record.
+func readLogFile(path, kind string) ([]auditLogEntry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	var entries []auditLogEntry
+	if bytes.HasPrefix(bytes.TrimLeft(data, " \t\r\n"), ndjsonPrefix) {
+		entries, err = readNDJSON(data, kind)
+	} else {
+		entries, err = readProtobufFrames(data, kind)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	return entries, nil
+}
+
+func readNDJSON(data []byte, kind string) ([]auditLogEntry, error) {
+	var entries []auditLogEntry
+	for lineNum, line := range bytes.Split(data, newline) {
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 || bytes.HasPrefix(line, metaPrefix) {
+			continue
+		}
+
+		entry := newLogEntry(kind)
+		if err := protojson.Unmarshal(line, entry); err != nil {
+			return nil, fmt.Errorf("invalid %s log entry on line %d: %w", kind, lineNum+1, err)
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+func readProtobufFrames(data []byte, kind string) ([]auditLogEntry, error) {
+	r := bufio.NewReader(bytes.NewReader(data))
+	var entries []auditLogEntry
+	for {
+		entry := newLogEntry(kind)
+		if err := ReadProtobufFrame(r, entry); err != nil {
+			if errors.Is(err, io.EOF) {
+				return entries, nil
+			}
+			return nil, fmt.Errorf("invalid %s log entry in frame %d: %w", kind, len(entries)+1, err)
+		}
+
+		entries = append(entries, entry)
+	}
+}
+
+func newLogEntry(kind string) auditLogEntry {
+	if kind == "decision" {
+		return &auditv1.DecisionLogEntry{}
+	}
+
+	return &auditv1.AccessLogEntry{}
+}
+
+// fetchFromFile returns a fetcher that selects records from the given entries the same way the server would,
+// so that the --tail, --between, --since and --lookup filters can be applied to a file.
+func fetchFromFile(entries []auditLogEntry) auditLogsFetcher {
+	return func(ctx context.Context, opts client.AuditLogOptions) (<-chan logResult, error) {
+		selected := selectEntries(entries, opts)
+
+		out := make(chan logResult)
+		go func() {
+			defer close(out)
+
+			for _, e := range selected {
+				select {
+				case out <- logResult{entry: e}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+
+		return out, nil
+	}
+}
+
+func selectEntries(entries []auditLogEntry, opts client.AuditLogOptions) []auditLogEntry {
+	switch {
+	case opts.Lookup != "":
+		for _, e := range entries {
+			if e.GetCallId() == opts.Lookup {
+				return []auditLogEntry{e}
+			}
+		}
+		return nil
+
+	case opts.Tail > 0:
+		if n := len(entries) - int(opts.Tail); n > 0 {
+			return entries[n:]
+		}
+		return entries
+
+	case !opts.StartTime.IsZero() || !opts.EndTime.IsZero():
+		var selected []auditLogEntry
+		for _, e := range entries {
+			ts := e.GetTimestamp().AsTime()
+			if ts.Before(opts.StartTime) || (!opts.EndTime.IsZero() && ts.After(opts.EndTime)) {
+				continue
+			}
+			selected = append(selected, e)
+		}
+		return selected
+
+	default:
+		return entries
+	}
+}
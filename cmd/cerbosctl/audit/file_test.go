@@ -0,0 +1,166 @@
+// Copyright 2021-2022 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/testing/protocmp"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	auditv1 "github.com/cerbos/cerbos/api/genpb/cerbos/audit/v1"
+	"github.com/cerbos/cerbos/client"
+)
+
+func TestReadLogFile(t *testing.T) {
+	ts := time.Date(2021, 7, 1, 0, 0, 0, 0, time.UTC)
+	entries := []*auditv1.AccessLogEntry{
+		{CallId: "01GH0000000000000000000001", Timestamp: timestamppb.New(ts), Method: "/cerbos.svc.v1.CerbosService/CheckResources"},
+		{CallId: "01GH0000000000000000000002", Timestamp: timestamppb.New(ts.Add(time.Hour)), Peer: &auditv1.Peer{Address: "1.1.1.1"}},
+		{CallId: "01GH0000000000000000000003", Timestamp: timestamppb.New(ts.Add(2 * time.Hour))},
+	}
+
+	writeFile := func(t *testing.T, name string, w func(*bytes.Buffer) auditLogWriter, meta bool) string {
+		t.Helper()
+
+		var buf bytes.Buffer
+		writer := w(&buf)
+		if meta {
+			require.NoError(t, writeMeta(writer, exportMeta{Kind: "access"}))
+		}
+		for _, e := range entries {
+			require.NoError(t, writer.write(e))
+		}
+		require.NoError(t, writer.flush())
+
+		path := filepath.Join(t.TempDir(), name)
+		require.NoError(t, os.WriteFile(path, buf.Bytes(), 0o600))
+		return path
+	}
+
+	testCases := []struct {
+		name string
+		path string
+	}{
+		{
+			name: "ndjson",
+			path: writeFile(t, "access.ndjson", func(b *bytes.Buffer) auditLogWriter { return newRawAuditLogWriter(b) }, false),
+		},
+		{
+			name: "ndjson_with_meta",
+			path: writeFile(t, "access.ndjson", func(b *bytes.Buffer) auditLogWriter { return newRawAuditLogWriter(b) }, true),
+		},
+		{
+			name: "protobuf",
+			path: writeFile(t, "access.pb", func(b *bytes.Buffer) auditLogWriter { return newProtobufAuditLogWriter(b) }, false),
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			have, err := readLogFile(tc.path, "access")
+			require.NoError(t, err)
+			require.Len(t, have, len(entries))
+			for i, want := range entries {
+				require.Empty(t, cmp.Diff(want, have[i], protocmp.Transform()))
+			}
+		})
+	}
+
+	t.Run("protobuf_frames_resembling_json", func(t *testing.T) {
+		// a frame starts with its length followed by a newline, which could be mistaken for a line of JSON
+		for _, size := range []int{48, 57, 123} {
+			entry := &auditv1.AccessLogEntry{CallId: strings.Repeat("x", size-2)}
+			require.Equal(t, size, proto.Size(entry))
+
+			var buf bytes.Buffer
+			writer := newProtobufAuditLogWriter(&buf)
+			require.NoError(t, writer.write(entry))
+			require.NoError(t, writer.flush())
+
+			path := filepath.Join(t.TempDir(), "access.pb")
+			require.NoError(t, os.WriteFile(path, buf.Bytes(), 0o600))
+
+			have, err := readLogFile(path, "access")
+			require.NoError(t, err, "frame of %d bytes", size)
+			require.Len(t, have, 1)
+			require.Empty(t, cmp.Diff(entry, have[0], protocmp.Transform()))
+		}
+	})
+
+	t.Run("wrong_kind", func(t *testing.T) {
+		_, err := readLogFile(testCases[0].path, "decision")
+		require.Error(t, err)
+	})
+
+	t.Run("missing_file", func(t *testing.T) {
+		_, err := readLogFile(filepath.Join(t.TempDir(), "missing.ndjson"), "access")
+		require.Error(t, err)
+	})
+}
+
+func TestFetchFromFile(t *testing.T) {
+	ts := time.Date(2021, 7, 1, 0, 0, 0, 0, time.UTC)
+	var entries []auditLogEntry
+	for i, id := range []string{"01GH0000000000000000000001", "01GH0000000000000000000002", "01GH0000000000000000000003"} {
+		entries = append(entries, &auditv1.DecisionLogEntry{CallId: id, Timestamp: timestamppb.New(ts.Add(time.Duration(i) * time.Hour))})
+	}
+
+	testCases := []struct {
+		name string
+		opts client.AuditLogOptions
+		want []string
+	}{
+		{
+			name: "all",
+			want: []string{"01GH0000000000000000000001", "01GH0000000000000000000002", "01GH0000000000000000000003"},
+		},
+		{
+			name: "tail",
+			opts: client.AuditLogOptions{Tail: 2},
+			want: []string{"01GH0000000000000000000002", "01GH0000000000000000000003"},
+		},
+		{
+			name: "tail_larger_than_file",
+			opts: client.AuditLogOptions{Tail: 30},
+			want: []string{"01GH0000000000000000000001", "01GH0000000000000000000002", "01GH0000000000000000000003"},
+		},
+		{
+			name: "between",
+			opts: client.AuditLogOptions{StartTime: ts.Add(30 * time.Minute), EndTime: ts.Add(2 * time.Hour)},
+			want: []string{"01GH0000000000000000000002", "01GH0000000000000000000003"},
+		},
+		{
+			name: "lookup",
+			opts: client.AuditLogOptions{Lookup: "01GH0000000000000000000002"},
+			want: []string{"01GH0000000000000000000002"},
+		},
+		{
+			name: "lookup_missing",
+			opts: client.AuditLogOptions{Lookup: "01GH0000000000000000000009"},
+			want: []string{},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			cw := &collectingWriter{}
+			found, err := writeFetchedLogs(context.Background(), fetchFromFile(entries), tc.opts, cw)
+			require.NoError(t, err)
+			require.Equal(t, len(tc.want) > 0, found)
+			require.Equal(t, tc.want, cw.callIDs())
+		})
+	}
+}
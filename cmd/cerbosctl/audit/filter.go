@@ -0,0 +1,30 @@
+// Copyright 2021-2022 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"google.golang.org/protobuf/proto"
+
+	auditv1 "github.com/cerbos/cerbos/api/genpb/cerbos/audit/v1"
+)
+
+// filteringWriter only writes the decision log entries that match the filter to the underlying writer.
+type filteringWriter struct {
+	auditLogWriter
+	match   func(*auditv1.DecisionLogEntry) bool
+	matched int
+}
+
+func newFilteringWriter(writer auditLogWriter, match func(*auditv1.DecisionLogEntry) bool) *filteringWriter {
+	return &filteringWriter{auditLogWriter: writer, match: match}
+}
+
+func (fw *filteringWriter) write(entry proto.Message) error {
+	if e, ok := entry.(*auditv1.DecisionLogEntry); !ok || !fw.match(e) {
+		return nil
+	}
+
+	fw.matched++
+	return fw.auditLogWriter.write(entry)
+}
@@ -0,0 +1,119 @@
+// Copyright 2021-2022 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	auditv1 "github.com/cerbos/cerbos/api/genpb/cerbos/audit/v1"
+	effectv1 "github.com/cerbos/cerbos/api/genpb/cerbos/effect/v1"
+	enginev1 "github.com/cerbos/cerbos/api/genpb/cerbos/engine/v1"
+	"github.com/cerbos/cerbos/cmd/cerbosctl/internal/flagset"
+)
+
+func TestFilteringWriter(t *testing.T) {
+	checkEntry := func(callID, principal, kind, id string, effects map[string]effectv1.Effect) *auditv1.DecisionLogEntry {
+		actions := make([]string, 0, len(effects))
+		actionEffects := make(map[string]*enginev1.CheckOutput_ActionEffect, len(effects))
+		for action, effect := range effects {
+			actions = append(actions, action)
+			actionEffects[action] = &enginev1.CheckOutput_ActionEffect{Effect: effect}
+		}
+
+		return &auditv1.DecisionLogEntry{
+			CallId: callID,
+			Method: &auditv1.DecisionLogEntry_CheckResources_{
+				CheckResources: &auditv1.DecisionLogEntry_CheckResources{
+					Inputs: []*enginev1.CheckInput{
+						{
+							Principal: &enginev1.Principal{Id: principal},
+							Resource:  &enginev1.Resource{Kind: kind, Id: id},
+							Actions:   actions,
+						},
+					},
+					Outputs: []*enginev1.CheckOutput{
+						{ResourceId: id, Actions: actionEffects},
+					},
+				},
+			},
+		}
+	}
+
+	planEntry := &auditv1.DecisionLogEntry{
+		CallId: "plan",
+		Method: &auditv1.DecisionLogEntry_PlanResources_{
+			PlanResources: &auditv1.DecisionLogEntry_PlanResources{
+				Input: &enginev1.PlanResourcesInput{
+					Principal: &enginev1.Principal{Id: "harry"},
+					Resource:  &enginev1.PlanResourcesInput_Resource{Kind: "leave_request"},
+					Action:    "approve",
+				},
+				Output: &enginev1.PlanResourcesOutput{
+					Filter: &enginev1.PlanResourcesFilter{Kind: enginev1.PlanResourcesFilter_KIND_ALWAYS_DENIED},
+				},
+			},
+		},
+	}
+
+	entries := []*auditv1.DecisionLogEntry{
+		checkEntry("harry_leave", "harry", "leave_request", "XX125", map[string]effectv1.Effect{"view": effectv1.Effect_EFFECT_ALLOW, "approve": effectv1.Effect_EFFECT_DENY}),
+		checkEntry("harry_album", "harry", "album:object", "YY001", map[string]effectv1.Effect{"view": effectv1.Effect_EFFECT_ALLOW}),
+		checkEntry("maggie_leave", "maggie", "leave_request", "XX150", map[string]effectv1.Effect{"view": effectv1.Effect_EFFECT_DENY}),
+		planEntry,
+	}
+
+	testCases := []struct {
+		name    string
+		filters flagset.AuditFilters
+		want    []string
+	}{
+		{name: "principal", filters: flagset.AuditFilters{Principal: "harry"}, want: []string{"harry_leave", "harry_album", "plan"}},
+		{name: "principal_glob", filters: flagset.AuditFilters{Principal: "ma*"}, want: []string{"maggie_leave"}},
+		{name: "resource_kind", filters: flagset.AuditFilters{Resource: "album:*"}, want: []string{"harry_album"}},
+		{name: "resource_id", filters: flagset.AuditFilters{Resource: "XX1*"}, want: []string{"harry_leave", "maggie_leave"}},
+		{name: "action", filters: flagset.AuditFilters{Action: "approve"}, want: []string{"harry_leave", "plan"}},
+		{name: "combined", filters: flagset.AuditFilters{Principal: "harry", Resource: "leave_request", Action: "view"}, want: []string{"harry_leave"}},
+		{name: "effect_allow", filters: flagset.AuditFilters{Effect: "allow"}, want: []string{"harry_leave", "harry_album"}},
+		{name: "effect_deny", filters: flagset.AuditFilters{Effect: "deny"}, want: []string{"harry_leave", "maggie_leave", "plan"}},
+		{name: "effect_combined", filters: flagset.AuditFilters{Principal: "harry", Effect: "deny"}, want: []string{"harry_leave", "plan"}},
+		{name: "no_match", filters: flagset.AuditFilters{Principal: "donald"}},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			match, err := tc.filters.DecisionFilter()
+			require.NoError(t, err)
+
+			cw := &collectingWriter{}
+			fw := newFilteringWriter(cw, match)
+			require.NoError(t, fw.write(&auditv1.AccessLogEntry{CallId: "access"}))
+			for _, e := range entries {
+				require.NoError(t, fw.write(e))
+			}
+
+			require.Equal(t, len(tc.want), fw.matched)
+			if len(tc.want) == 0 {
+				require.Empty(t, cw.callIDs())
+				return
+			}
+
+			require.Equal(t, tc.want, cw.callIDs())
+		})
+	}
+
+	t.Run("invalid_pattern", func(t *testing.T) {
+		af := flagset.AuditFilters{Principal: "[harry"}
+		_, err := af.DecisionFilter()
+		require.Error(t, err)
+	})
+
+	t.Run("invalid_effect", func(t *testing.T) {
+		af := flagset.AuditFilters{Effect: "maybe"}
+		_, err := af.DecisionFilter()
+		require.Error(t, err)
+	})
+}
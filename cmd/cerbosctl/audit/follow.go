@@ -0,0 +1,255 @@
+// Copyright 2021-2022 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/cerbos/cerbos/client"
+)
+
+const (
+	followPollInterval = 2 * time.Second
+	followMinBackoff   = 1 * time.Second
+	followMaxBackoff   = 1 * time.Minute
+	// followOverlap is how far before the newest entry seen so far each poll starts, to pick up entries that were
+	// logged out of order.
+	followOverlap = 10 * time.Second
+	// followMaxWindow is the longest time range requested by a poll.
+	followMaxWindow = 5 * time.Minute
+)
+
+// logResult holds an audit log entry or the error encountered while receiving it.
+type logResult struct {
+	entry auditLogEntry
+	err   error
+}
+
+type auditLogsFetcher func(context.Context, client.AuditLogOptions) (<-chan logResult, error)
+
+// fetchFromServer returns a fetcher that streams audit logs from the server using the given client.
+func fetchFromServer(ac client.AdminClient) auditLogsFetcher {
+	return func(ctx context.Context, opts client.AuditLogOptions) (<-chan logResult, error) {
+		entries, err := ac.AuditLogs(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		out := make(chan logResult)
+		go func() {
+			defer close(out)
+
+			for e := range entries {
+				entry, err := logEntry(e)
+				if entry == nil && err == nil {
+					continue
+				}
+
+				// keep draining the entries after cancellation so that the producer is not blocked forever
+				if ctx.Err() != nil {
+					continue
+				}
+
+				select {
+				case out <- logResult{entry: entry, err: err}:
+				case <-ctx.Done():
+				}
+			}
+		}()
+
+		return out, nil
+	}
+}
+
+// follower keeps streaming new audit log entries to a writer.
+// Whenever the server stream ends, it reconnects and resumes just after the last record it has seen.
+type follower struct {
+	fetch        auditLogsFetcher
+	stderr       io.Writer
+	cancelStream context.CancelFunc
+	seen         map[string]time.Time
+	lastSeenAt   time.Time
+	pollInterval time.Duration
+	minBackoff   time.Duration
+	maxBackoff   time.Duration
+	mu           sync.Mutex
+	logType      client.AuditLogType
+	reconnect    bool
+}
+
+func newFollower(fetch auditLogsFetcher, logType client.AuditLogType, stderr io.Writer) *follower {
+	return &follower{
+		fetch:        fetch,
+		stderr:       stderr,
+		logType:      logType,
+		seen:         make(map[string]time.Time),
+		pollInterval: followPollInterval,
+		minBackoff:   followMinBackoff,
+		maxBackoff:   followMaxBackoff,
+	}
+}
+
+// follow writes the entries matching the initial options and then keeps polling for new entries until the context is cancelled.
+func (f *follower) follow(ctx context.Context, initial client.AuditLogOptions, writer auditLogWriter) error {
+	opts := initial
+	backoff := time.Duration(0)
+	for {
+		retryable, err := f.stream(ctx, opts, writer)
+		if flushErr := writer.flush(); flushErr != nil {
+			return flushErr
+		}
+
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		if err != nil && !retryable {
+			return err
+		}
+
+		wait := f.pollInterval
+		switch {
+		case f.takeReconnect():
+			// the fetcher was replaced while streaming: errors caused by interrupting the old stream are expected
+			backoff = 0
+			wait = 0
+		case err != nil:
+			backoff = f.nextBackoff(backoff)
+			wait = backoff
+			fmt.Fprintf(f.stderr, "Audit log stream failed, reconnecting in %s: %v\n", wait, err)
+		default:
+			backoff = 0
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(wait):
+		}
+
+		now := time.Now()
+		opts = client.AuditLogOptions{
+			Type:      f.logType,
+			StartTime: f.resumeTime(now),
+			EndTime:   now,
+		}
+	}
+}
+
+// resumeTime returns the start of the next poll.
+// Polls resume from the timestamp of the newest entry seen so far, which is set by the server and therefore doesn't
+// depend on the client clock. They start followOverlap earlier to pick up entries logged out of order, and advance drops
+// the entries that have already been written. The window is limited to followMaxWindow so that polls don't keep
+// growing while no entries arrive (or if none have been seen yet).
+func (f *follower) resumeTime(now time.Time) time.Time {
+	start := f.lastSeenAt.Add(-followOverlap)
+	if earliest := now.Add(-followMaxWindow); start.Before(earliest) {
+		start = earliest
+	}
+
+	if start.After(now) {
+		return now
+	}
+
+	return start
+}
+
+// stream writes the unseen entries from a single server stream.
+// The returned flag indicates whether the error (if any) was caused by the stream and is worth retrying.
+func (f *follower) stream(ctx context.Context, opts client.AuditLogOptions, writer auditLogWriter) (bool, error) {
+	ctx, cancelFn := context.WithCancel(ctx)
+	defer cancelFn()
+
+	f.mu.Lock()
+	fetch := f.fetch
+	f.cancelStream = cancelFn
+	f.mu.Unlock()
+
+	results, err := fetch(ctx, opts)
+	if err != nil {
+		return true, err
+	}
+
+	for r := range results {
+		if r.err != nil {
+			return true, r.err
+		}
+
+		if !f.advance(r.entry) {
+			continue
+		}
+
+		if err := writer.write(r.entry); err != nil {
+			return false, err
+		}
+	}
+
+	return false, nil
+}
+
+// setFetcher replaces the fetcher used to retrieve audit logs (for example, to connect to a different server).
+// The current stream is interrupted and the follower reconnects immediately using the new fetcher, resuming after the last entry it has seen.
+func (f *follower) setFetcher(fetch auditLogsFetcher) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.fetch = fetch
+	f.reconnect = true
+	if f.cancelStream != nil {
+		f.cancelStream()
+	}
+}
+
+func (f *follower) takeReconnect() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	reconnect := f.reconnect
+	f.reconnect = false
+	return reconnect
+}
+
+// advance records the given entry as seen and returns false if the entry has already been seen.
+// Each poll starts shortly before the timestamp of the newest entry seen so far, so the entries captured in that overlap
+// are re-sent by the server. The call IDs of the entries are remembered until they fall out of the overlap.
+func (f *follower) advance(entry auditLogEntry) bool {
+	callID := entry.GetCallId()
+	if _, ok := f.seen[callID]; ok {
+		return false
+	}
+
+	var ts time.Time
+	if entryTS := entry.GetTimestamp(); entryTS != nil {
+		ts = entryTS.AsTime()
+	}
+
+	f.seen[callID] = ts
+	if ts.After(f.lastSeenAt) {
+		f.lastSeenAt = ts
+		resumeAt := ts.Add(-followOverlap)
+		for id, seenAt := range f.seen {
+			if seenAt.Before(resumeAt) {
+				delete(f.seen, id)
+			}
+		}
+	}
+
+	return true
+}
+
+func (f *follower) nextBackoff(current time.Duration) time.Duration {
+	if current < f.minBackoff {
+		return f.minBackoff
+	}
+
+	if next := current * 2; next < f.maxBackoff { //nolint:gomnd
+		return next
+	}
+
+	return f.maxBackoff
+}
@@ -0,0 +1,230 @@
+// Copyright 2021-2022 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	auditv1 "github.com/cerbos/cerbos/api/genpb/cerbos/audit/v1"
+	"github.com/cerbos/cerbos/client"
+)
+
+func TestFollower(t *testing.T) {
+	now := time.Now()
+	mkEntry := func(callID string, offset time.Duration) *auditv1.AccessLogEntry {
+		return &auditv1.AccessLogEntry{CallId: callID, Timestamp: timestamppb.New(now.Add(offset))}
+	}
+
+	errConn := errors.New("connection refused")
+	// each element is the result of a single call to the server
+	responses := []struct {
+		err     error
+		entries []*auditv1.AccessLogEntry
+	}{
+		{entries: []*auditv1.AccessLogEntry{mkEntry("01GH0000000000000000000001", -2*time.Second), mkEntry("01GH0000000000000000000002", -time.Second)}},
+		{err: errConn},
+		{entries: []*auditv1.AccessLogEntry{mkEntry("01GH0000000000000000000002", -time.Second), mkEntry("01GH0000000000000000000003", time.Second)}},
+		{},
+		{entries: []*auditv1.AccessLogEntry{mkEntry("01GH0000000000000000000003", time.Second), mkEntry("01GH0000000000000000000004", 2*time.Second)}},
+	}
+
+	ctx, cancelFn := context.WithCancel(context.Background())
+	t.Cleanup(cancelFn)
+
+	var calls []client.AuditLogOptions
+	fetch := func(_ context.Context, opts client.AuditLogOptions) (<-chan logResult, error) {
+		idx := len(calls)
+		calls = append(calls, opts)
+		if idx == len(responses)-1 {
+			defer cancelFn()
+		}
+
+		if idx >= len(responses) {
+			return nil, errConn
+		}
+
+		resp := responses[idx]
+		if resp.err != nil {
+			return nil, resp.err
+		}
+
+		ch := make(chan logResult, len(resp.entries))
+		for _, e := range resp.entries {
+			ch <- logResult{entry: e}
+		}
+		close(ch)
+
+		return ch, nil
+	}
+
+	f := newFollower(fetch, client.AccessLogs, io.Discard)
+	f.pollInterval = time.Millisecond
+	f.minBackoff = time.Millisecond
+	f.maxBackoff = 2 * time.Millisecond
+
+	w := &collectingWriter{}
+	require.NoError(t, f.follow(ctx, client.AuditLogOptions{Type: client.AccessLogs, Tail: 2}, w))
+
+	require.Equal(t, []string{
+		"01GH0000000000000000000001",
+		"01GH0000000000000000000002",
+		"01GH0000000000000000000003",
+		"01GH0000000000000000000004",
+	}, w.callIDs())
+
+	require.Len(t, calls, len(responses))
+	require.Equal(t, uint32(2), calls[0].Tail)
+	// subsequent calls should resume shortly before the timestamp of the newest entry seen so far
+	wantStart := []time.Time{
+		now.Add(-time.Second - followOverlap),
+		now.Add(-time.Second - followOverlap),
+		now.Add(time.Second - followOverlap),
+		now.Add(time.Second - followOverlap),
+	}
+	for i, c := range calls[1:] {
+		require.Equal(t, client.AccessLogs, c.Type)
+		require.True(t, wantStart[i].Equal(c.StartTime), "Call %d starts at %s, want %s", i+1, c.StartTime, wantStart[i])
+		require.False(t, c.StartTime.After(c.EndTime))
+	}
+}
+
+func TestFollowerResumeTime(t *testing.T) {
+	now := time.Now()
+	f := newFollower(nil, client.AccessLogs, io.Discard)
+
+	t.Run("nothing_seen", func(t *testing.T) {
+		require.Equal(t, now.Add(-followMaxWindow), f.resumeTime(now))
+	})
+
+	t.Run("seen", func(t *testing.T) {
+		f.lastSeenAt = now.Add(-time.Minute)
+		require.Equal(t, now.Add(-time.Minute-followOverlap), f.resumeTime(now))
+	})
+
+	t.Run("window_capped", func(t *testing.T) {
+		f.lastSeenAt = now.Add(-time.Hour)
+		require.Equal(t, now.Add(-followMaxWindow), f.resumeTime(now))
+	})
+
+	t.Run("server_clock_ahead", func(t *testing.T) {
+		f.lastSeenAt = now.Add(time.Minute)
+		require.Equal(t, now, f.resumeTime(now))
+	})
+}
+
+func TestFollowerSetFetcher(t *testing.T) {
+	ctx, cancelFn := context.WithCancel(context.Background())
+	t.Cleanup(cancelFn)
+
+	f := newFollower(nil, client.AccessLogs, io.Discard)
+	f.pollInterval = time.Minute
+	f.minBackoff = time.Minute
+	f.maxBackoff = time.Minute
+
+	newFetch := func(_ context.Context, opts client.AuditLogOptions) (<-chan logResult, error) {
+		defer cancelFn()
+
+		require.False(t, opts.StartTime.IsZero())
+
+		ch := make(chan logResult, 2)
+		ch <- logResult{entry: &auditv1.AccessLogEntry{CallId: "01GH0000000000000000000001"}}
+		ch <- logResult{entry: &auditv1.AccessLogEntry{CallId: "01GH0000000000000000000002"}}
+		close(ch)
+
+		return ch, nil
+	}
+
+	// the original stream never ends on its own, so the follower only makes progress if setFetcher interrupts it
+	f.fetch = func(streamCtx context.Context, _ client.AuditLogOptions) (<-chan logResult, error) {
+		ch := make(chan logResult, 1)
+		ch <- logResult{entry: &auditv1.AccessLogEntry{CallId: "01GH0000000000000000000001"}}
+		go func() {
+			<-streamCtx.Done()
+			ch <- logResult{err: streamCtx.Err()}
+			close(ch)
+		}()
+
+		go f.setFetcher(newFetch)
+		return ch, nil
+	}
+
+	w := &collectingWriter{}
+	require.NoError(t, f.follow(ctx, client.AuditLogOptions{Type: client.AccessLogs, Tail: 1}, w))
+	require.Equal(t, []string{"01GH0000000000000000000001", "01GH0000000000000000000002"}, w.callIDs())
+}
+
+func TestFollowerBackoff(t *testing.T) {
+	f := newFollower(nil, client.AccessLogs, io.Discard)
+
+	have := make([]time.Duration, 8)
+	var backoff time.Duration
+	for i := range have {
+		backoff = f.nextBackoff(backoff)
+		have[i] = backoff
+	}
+
+	want := []time.Duration{1 * time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 16 * time.Second, 32 * time.Second, time.Minute, time.Minute}
+	require.Equal(t, want, have)
+}
+
+type collectingWriter struct {
+	entries []proto.Message
+	mu      sync.Mutex
+}
+
+func (cw *collectingWriter) write(entry proto.Message) error {
+	cw.mu.Lock()
+	defer cw.mu.Unlock()
+
+	cw.entries = append(cw.entries, entry)
+	return nil
+}
+
+func (cw *collectingWriter) flush() error { return nil }
+
+func (cw *collectingWriter) callIDs() []string {
+	cw.mu.Lock()
+	defer cw.mu.Unlock()
+
+	ids := make([]string, len(cw.entries))
+	for i, e := range cw.entries {
+		ids[i] = e.(auditLogEntry).GetCallId()
+	}
+
+	return ids
+}
+
+func TestFollowerAdvance(t *testing.T) {
+	now := time.Now()
+	mkEntry := func(callID string, offset time.Duration) *auditv1.AccessLogEntry {
+		return &auditv1.AccessLogEntry{CallId: callID, Timestamp: timestamppb.New(now.Add(offset))}
+	}
+
+	f := newFollower(nil, client.AccessLogs, io.Discard)
+	f.lastSeenAt = now
+
+	require.True(t, f.advance(mkEntry("01GH0000000000000000000002", time.Second)))
+	// call IDs generated by different instances are not necessarily ordered
+	require.True(t, f.advance(mkEntry("01GH0000000000000000000001", time.Second)))
+	require.False(t, f.advance(mkEntry("01GH0000000000000000000002", time.Second)))
+	require.True(t, now.Add(time.Second).Equal(f.lastSeenAt))
+
+	require.True(t, f.advance(mkEntry("01GH0000000000000000000003", 2*time.Second)))
+	// entries within the overlap of the next poll are remembered
+	require.Len(t, f.seen, 3)
+
+	require.True(t, f.advance(mkEntry("01GH0000000000000000000004", followOverlap+2*time.Second)))
+	// entries that fell out of the overlap are forgotten
+	require.Len(t, f.seen, 2)
+	require.False(t, f.advance(mkEntry("01GH0000000000000000000003", 2*time.Second)))
+}
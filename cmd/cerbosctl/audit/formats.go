@@ -0,0 +1,189 @@
+// Copyright 2021-2022 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/ghodss/yaml"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	auditv1 "github.com/cerbos/cerbos/api/genpb/cerbos/audit/v1"
+)
+
+var (
+	accessLogCSVColumns   = []string{"call_id", "timestamp", "peer", "method", "status_code"}
+	decisionLogCSVColumns = []string{"call_id", "timestamp", "peer", "method", "principal", "resource", "action", "effect"}
+)
+
+// jsonAuditLogWriter writes the entries as a single JSON array.
+type jsonAuditLogWriter struct {
+	out     io.Writer
+	loc     *time.Location
+	started bool
+}
+
+func newJSONAuditLogWriter(out io.Writer) *jsonAuditLogWriter {
+	return &jsonAuditLogWriter{out: out}
+}
+
+func (j *jsonAuditLogWriter) write(entry proto.Message) error {
+	outBytes, err := protojson.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	outBytes = localizeTimestamps(outBytes, j.loc)
+	prefix := ",\n"
+	if !j.started {
+		prefix = "[\n"
+		j.started = true
+	}
+
+	if _, err := io.WriteString(j.out, prefix); err != nil {
+		return err
+	}
+
+	_, err = j.out.Write(outBytes)
+	return err
+}
+
+func (j *jsonAuditLogWriter) flush() error {
+	if !j.started {
+		_, err := io.WriteString(j.out, "[]\n")
+		return err
+	}
+
+	_, err := io.WriteString(j.out, "\n]\n")
+	return err
+}
+
+// yamlAuditLogWriter writes each entry as a separate YAML document.
+type yamlAuditLogWriter struct {
+	out io.Writer
+	loc *time.Location
+}
+
+func newYAMLAuditLogWriter(out io.Writer) *yamlAuditLogWriter {
+	return &yamlAuditLogWriter{out: out}
+}
+
+func (y *yamlAuditLogWriter) write(entry proto.Message) error {
+	jsonBytes, err := protojson.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	outBytes, err := yaml.JSONToYAML(localizeTimestamps(jsonBytes, y.loc))
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.WriteString(y.out, "---\n"); err != nil {
+		return err
+	}
+
+	_, err = y.out.Write(outBytes)
+	return err
+}
+
+func (y *yamlAuditLogWriter) flush() error { return nil }
+
+// csvAuditLogWriter writes the key fields of the entries as CSV. Access and decision logs have different columns.
+// Decision log entries produce a row for each resource and action pair.
+type csvAuditLogWriter struct {
+	out           *csv.Writer
+	loc           *time.Location
+	columns       []string
+	headerWritten bool
+}
+
+func newCSVAuditLogWriter(out io.Writer, kind string) *csvAuditLogWriter {
+	columns := accessLogCSVColumns
+	if kind == "decision" {
+		columns = decisionLogCSVColumns
+	}
+
+	return &csvAuditLogWriter{out: csv.NewWriter(out), columns: columns}
+}
+
+func (c *csvAuditLogWriter) write(entry proto.Message) error {
+	switch e := entry.(type) {
+	case *auditv1.AccessLogEntry:
+		return c.writeRow(e.GetCallId(), formatTimestamp(e, c.loc), e.GetPeer().GetAddress(), e.GetMethod(), strconv.FormatUint(uint64(e.GetStatusCode()), 10))
+	case *auditv1.DecisionLogEntry:
+		return c.writeDecision(e)
+	default:
+		return fmt.Errorf("unexpected audit log entry type %T", entry)
+	}
+}
+
+func (c *csvAuditLogWriter) writeDecision(e *auditv1.DecisionLogEntry) error {
+	callID, timestamp, peer, method := e.GetCallId(), formatTimestamp(e, c.loc), e.GetPeer().GetAddress(), entryMethod(e)
+
+	if pr := e.GetPlanResources(); pr != nil {
+		kind, _ := entryResource(e)
+		return c.writeRow(callID, timestamp, peer, method, entryPrincipal(e), kind, pr.GetInput().GetAction(), "")
+	}
+
+	inputs := checkInputs(e)
+	outputs := e.GetOutputs()
+	if cr := e.GetCheckResources(); cr != nil {
+		outputs = cr.GetOutputs()
+	}
+
+	if len(inputs) == 0 {
+		return c.writeRow(callID, timestamp, peer, method, "", "", "", "")
+	}
+
+	for i, input := range inputs {
+		var actionEffects map[string]string
+		if i < len(outputs) {
+			actionEffects = make(map[string]string, len(outputs[i].GetActions()))
+			for action, ae := range outputs[i].GetActions() {
+				actionEffects[action] = ae.GetEffect().String()
+			}
+		}
+
+		resource := input.GetResource().GetKind() + "#" + input.GetResource().GetId()
+		for _, action := range input.GetActions() {
+			if err := c.writeRow(callID, timestamp, peer, method, input.GetPrincipal().GetId(), resource, action, actionEffects[action]); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (c *csvAuditLogWriter) writeRow(values ...string) error {
+	if err := c.writeHeader(); err != nil {
+		return err
+	}
+
+	return c.out.Write(values)
+}
+
+func (c *csvAuditLogWriter) writeHeader() error {
+	if c.headerWritten {
+		return nil
+	}
+
+	c.headerWritten = true
+	return c.out.Write(c.columns)
+}
+
+func (c *csvAuditLogWriter) flush() error {
+	if err := c.writeHeader(); err != nil {
+		return err
+	}
+
+	c.out.Flush()
+	return c.out.Error()
+}
@@ -0,0 +1,127 @@
+// Copyright 2021-2022 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	auditv1 "github.com/cerbos/cerbos/api/genpb/cerbos/audit/v1"
+	effectv1 "github.com/cerbos/cerbos/api/genpb/cerbos/effect/v1"
+	enginev1 "github.com/cerbos/cerbos/api/genpb/cerbos/engine/v1"
+)
+
+func TestCSVAuditLogWriter(t *testing.T) {
+	ts := time.Date(2021, 7, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("access", func(t *testing.T) {
+		var buf bytes.Buffer
+		w := newCSVAuditLogWriter(&buf, "access")
+		require.NoError(t, w.write(&auditv1.AccessLogEntry{
+			CallId:     "01GH0000000000000000000001",
+			Timestamp:  timestamppb.New(ts),
+			Peer:       &auditv1.Peer{Address: "1.1.1.1"},
+			Method:     "/cerbos.svc.v1.CerbosService/CheckResources",
+			StatusCode: 5,
+		}))
+		require.NoError(t, w.flush())
+
+		require.Equal(t, `call_id,timestamp,peer,method,status_code
+01GH0000000000000000000001,2021-07-01T00:00:00Z,1.1.1.1,/cerbos.svc.v1.CerbosService/CheckResources,5
+`, buf.String())
+	})
+
+	t.Run("decision", func(t *testing.T) {
+		var buf bytes.Buffer
+		w := newCSVAuditLogWriter(&buf, "decision")
+		require.NoError(t, w.write(&auditv1.DecisionLogEntry{
+			CallId:    "01GH0000000000000000000002",
+			Timestamp: timestamppb.New(ts),
+			Method: &auditv1.DecisionLogEntry_CheckResources_{
+				CheckResources: &auditv1.DecisionLogEntry_CheckResources{
+					Inputs: []*enginev1.CheckInput{
+						{
+							Principal: &enginev1.Principal{Id: "harry"},
+							Resource:  &enginev1.Resource{Kind: "leave_request", Id: "XX125"},
+							Actions:   []string{"view", "approve"},
+						},
+					},
+					Outputs: []*enginev1.CheckOutput{
+						{
+							ResourceId: "XX125",
+							Actions: map[string]*enginev1.CheckOutput_ActionEffect{
+								"view": {Effect: effectv1.Effect_EFFECT_ALLOW},
+							},
+						},
+					},
+				},
+			},
+		}))
+		require.NoError(t, w.flush())
+
+		require.Equal(t, `call_id,timestamp,peer,method,principal,resource,action,effect
+01GH0000000000000000000002,2021-07-01T00:00:00Z,,CheckResources,harry,leave_request#XX125,view,EFFECT_ALLOW
+01GH0000000000000000000002,2021-07-01T00:00:00Z,,CheckResources,harry,leave_request#XX125,approve,
+`, buf.String())
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		var buf bytes.Buffer
+		w := newCSVAuditLogWriter(&buf, "decision")
+		require.NoError(t, w.flush())
+
+		require.Equal(t, strings.Join(decisionLogCSVColumns, ",")+"\n", buf.String())
+	})
+}
+
+func TestJSONAuditLogWriter(t *testing.T) {
+	testCases := []struct {
+		name    string
+		callIDs []string
+	}{
+		{name: "empty"},
+		{name: "single", callIDs: []string{"01GH0000000000000000000001"}},
+		{name: "multiple", callIDs: []string{"01GH0000000000000000000001", "01GH0000000000000000000002"}},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			w := newJSONAuditLogWriter(&buf)
+			for _, callID := range tc.callIDs {
+				require.NoError(t, w.write(&auditv1.AccessLogEntry{CallId: callID}))
+			}
+			require.NoError(t, w.flush())
+
+			var have []map[string]any
+			require.NoError(t, json.Unmarshal(buf.Bytes(), &have))
+			require.Len(t, have, len(tc.callIDs))
+			for i, callID := range tc.callIDs {
+				require.Equal(t, callID, have[i]["callId"])
+			}
+		})
+	}
+}
+
+func TestYAMLAuditLogWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := newYAMLAuditLogWriter(&buf)
+	require.NoError(t, w.write(&auditv1.AccessLogEntry{CallId: "01GH0000000000000000000001", Method: "CheckResources"}))
+	require.NoError(t, w.write(&auditv1.AccessLogEntry{CallId: "01GH0000000000000000000002"}))
+	require.NoError(t, w.flush())
+
+	require.Equal(t, `---
+callId: 01GH0000000000000000000001
+method: CheckResources
+---
+callId: 01GH0000000000000000000002
+`, buf.String())
+}
@@ -0,0 +1,46 @@
+// Copyright 2021-2022 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/protobuf/proto"
+)
+
+var errMaxResultsReached = errors.New("maximum number of results reached")
+
+// limitingWriter writes at most limit entries to the underlying writer and cancels the stream once the limit is reached.
+type limitingWriter struct {
+	auditLogWriter
+	cancel  context.CancelFunc
+	limit   int
+	written int
+}
+
+func newLimitingWriter(writer auditLogWriter, limit int, cancel context.CancelFunc) *limitingWriter {
+	return &limitingWriter{auditLogWriter: writer, limit: limit, cancel: cancel}
+}
+
+func (lw *limitingWriter) write(entry proto.Message) error {
+	if lw.reached() {
+		return errMaxResultsReached
+	}
+
+	if err := lw.auditLogWriter.write(entry); err != nil {
+		return err
+	}
+
+	lw.written++
+	if lw.reached() {
+		lw.cancel()
+	}
+
+	return nil
+}
+
+func (lw *limitingWriter) reached() bool {
+	return lw.written >= lw.limit
+}
@@ -0,0 +1,67 @@
+// Copyright 2021-2022 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	auditv1 "github.com/cerbos/cerbos/api/genpb/cerbos/audit/v1"
+)
+
+func TestLimitingWriter(t *testing.T) {
+	writeEntries := func(w auditLogWriter, n int) error {
+		for i := 0; i < n; i++ {
+			if err := w.write(&auditv1.AccessLogEntry{CallId: fmt.Sprintf("%d", i)}); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	t.Run("truncated", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		cw := &collectingWriter{}
+		lw := newLimitingWriter(cw, 3, cancel)
+
+		err := writeEntries(lw, 5)
+		require.ErrorIs(t, err, errMaxResultsReached)
+		require.True(t, lw.reached())
+		require.Equal(t, []string{"0", "1", "2"}, cw.callIDs())
+		require.Error(t, ctx.Err(), "stream should be cancelled")
+	})
+
+	t.Run("under_limit", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		cw := &collectingWriter{}
+		lw := newLimitingWriter(cw, 10, cancel)
+
+		require.NoError(t, writeEntries(lw, 5))
+		require.False(t, lw.reached())
+		require.Len(t, cw.callIDs(), 5)
+		require.NoError(t, ctx.Err())
+	})
+
+	t.Run("with_filter", func(t *testing.T) {
+		_, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		cw := &collectingWriter{}
+		lw := newLimitingWriter(cw, 1, cancel)
+		fw := newFilteringWriter(lw, func(e *auditv1.DecisionLogEntry) bool { return e.CallId == "match" })
+
+		require.NoError(t, fw.write(&auditv1.DecisionLogEntry{CallId: "skip"}))
+		require.NoError(t, fw.write(&auditv1.DecisionLogEntry{CallId: "match"}))
+		require.ErrorIs(t, fw.write(&auditv1.DecisionLogEntry{CallId: "match"}), errMaxResultsReached)
+		require.Equal(t, []string{"match"}, cw.callIDs())
+	})
+}
@@ -0,0 +1,55 @@
+// Copyright 2021-2022 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cerbos/cerbos/client"
+)
+
+// lookupLogs retrieves the records with the given call IDs one at a time so that they are written in the order they were requested.
+// It returns the IDs for which no record was found.
+func lookupLogs(ctx context.Context, fetch auditLogsFetcher, logType client.AuditLogType, ids []string, writer auditLogWriter) ([]string, error) {
+	var missing []string
+	for _, id := range ids {
+		found, err := writeFetchedLogs(ctx, fetch, client.AuditLogOptions{Type: logType, Lookup: id}, writer)
+		if err != nil {
+			return missing, fmt.Errorf("failed to look up %s: %w", id, err)
+		}
+
+		if !found {
+			missing = append(missing, id)
+		}
+	}
+
+	return missing, nil
+}
+
+// writeFetchedLogs writes all the records returned by the fetcher for the given options and reports whether there were any.
+func writeFetchedLogs(ctx context.Context, fetch auditLogsFetcher, opts client.AuditLogOptions, writer auditLogWriter) (bool, error) {
+	// cancelling the context on return stops the fetcher if the stream is abandoned early
+	ctx, cancelFn := context.WithCancel(ctx)
+	defer cancelFn()
+
+	results, err := fetch(ctx, opts)
+	if err != nil {
+		return false, err
+	}
+
+	found := false
+	for r := range results {
+		if r.err != nil {
+			return found, r.err
+		}
+
+		found = true
+		if err := writer.write(r.entry); err != nil {
+			return found, err
+		}
+	}
+
+	return found, nil
+}
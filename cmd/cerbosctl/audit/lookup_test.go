@@ -0,0 +1,62 @@
+// Copyright 2021-2022 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	auditv1 "github.com/cerbos/cerbos/api/genpb/cerbos/audit/v1"
+	"github.com/cerbos/cerbos/client"
+)
+
+func TestLookupLogs(t *testing.T) {
+	errLookup := errors.New("invalid call ID")
+	stored := map[string]*auditv1.DecisionLogEntry{
+		"01GH0000000000000000000001": {CallId: "01GH0000000000000000000001"},
+		"01GH0000000000000000000002": {CallId: "01GH0000000000000000000002"},
+		"01GH0000000000000000000003": {CallId: "01GH0000000000000000000003"},
+	}
+
+	var requested []client.AuditLogOptions
+	fetch := func(_ context.Context, opts client.AuditLogOptions) (<-chan logResult, error) {
+		requested = append(requested, opts)
+		if opts.Lookup == "invalid" {
+			return nil, errLookup
+		}
+
+		out := make(chan logResult, 1)
+		if e, ok := stored[opts.Lookup]; ok {
+			out <- logResult{entry: e}
+		}
+		close(out)
+		return out, nil
+	}
+
+	t.Run("in_order", func(t *testing.T) {
+		requested = nil
+		ids := []string{"01GH0000000000000000000003", "01GH0000000000000000000009", "01GH0000000000000000000001", "01GH0000000000000000000008"}
+
+		cw := &collectingWriter{}
+		missing, err := lookupLogs(context.Background(), fetch, client.DecisionLogs, ids, cw)
+		require.NoError(t, err)
+		require.Equal(t, []string{"01GH0000000000000000000003", "01GH0000000000000000000001"}, cw.callIDs())
+		require.Equal(t, []string{"01GH0000000000000000000009", "01GH0000000000000000000008"}, missing)
+
+		require.Len(t, requested, len(ids))
+		for i, opts := range requested {
+			require.Equal(t, client.AuditLogOptions{Type: client.DecisionLogs, Lookup: ids[i]}, opts)
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		cw := &collectingWriter{}
+		_, err := lookupLogs(context.Background(), fetch, client.DecisionLogs, []string{"01GH0000000000000000000002", "invalid", "01GH0000000000000000000001"}, cw)
+		require.ErrorIs(t, err, errLookup)
+		require.Equal(t, []string{"01GH0000000000000000000002"}, cw.callIDs())
+	})
+}
@@ -0,0 +1,171 @@
+// Copyright 2021-2022 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/ghodss/yaml"
+
+	"github.com/cerbos/cerbos/client"
+	"github.com/cerbos/cerbos/internal/util"
+)
+
+const metaKey = "_meta"
+
+// exportMeta describes the provenance of an export. It is written before the records when --meta is set.
+type exportMeta struct {
+	ExportedAt       time.Time   `json:"exportedAt"`
+	Filters          metaFilters `json:"filters"`
+	Server           string      `json:"server"`
+	Kind             string      `json:"kind"`
+	CerbosctlVersion string      `json:"cerbosctlVersion"`
+}
+
+// metaFilters are the filters that were used to select the exported records.
+type metaFilters struct {
+	StartTime  *time.Time `json:"startTime,omitempty"`
+	EndTime    *time.Time `json:"endTime,omitempty"`
+	Principal  string     `json:"principal,omitempty"`
+	Resource   string     `json:"resource,omitempty"`
+	Action     string     `json:"action,omitempty"`
+	Lookup     []string   `json:"lookup,omitempty"`
+	MaxResults int        `json:"maxResults,omitempty"`
+	Tail       uint32     `json:"tail,omitempty"`
+	Follow     bool       `json:"follow,omitempty"`
+}
+
+func (c *Cmd) exportMeta(server string, opts client.AuditLogOptions, loc *time.Location) exportMeta {
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	m := exportMeta{
+		ExportedAt: time.Now().In(loc),
+		Server:     server,
+		Kind:       c.Kind,
+		Filters: metaFilters{
+			Principal:  c.Principal,
+			Resource:   c.Resource,
+			Action:     c.Action,
+			Lookup:     c.Lookup,
+			MaxResults: c.MaxResults,
+			Tail:       opts.Tail,
+			Follow:     c.Follow,
+		},
+		CerbosctlVersion: util.Version,
+	}
+
+	if !opts.StartTime.IsZero() {
+		start := opts.StartTime.In(loc)
+		m.Filters.StartTime = &start
+	}
+
+	if !opts.EndTime.IsZero() {
+		end := opts.EndTime.In(loc)
+		m.Filters.EndTime = &end
+	}
+
+	return m
+}
+
+// metaWriter is implemented by the writers of the output formats that can include the export metadata.
+type metaWriter interface {
+	writeMeta(exportMeta) error
+}
+
+// supportsMeta returns true if the given output format can include the export metadata.
+func supportsMeta(format string) bool {
+	switch format {
+	case formatJSON, formatNDJSON, formatRich, formatYAML:
+		return true
+	default:
+		return false
+	}
+}
+
+// writeMeta writes the export metadata using the given writer.
+func writeMeta(w auditLogWriter, m exportMeta) error {
+	mw, ok := w.(metaWriter)
+	if !ok {
+		return errors.New("the output format does not support --meta")
+	}
+
+	if err := mw.writeMeta(m); err != nil {
+		return fmt.Errorf("failed to write metadata: %w", err)
+	}
+
+	return nil
+}
+
+func metaJSON(m exportMeta) ([]byte, error) {
+	return json.Marshal(map[string]exportMeta{metaKey: m})
+}
+
+// writeMeta writes the metadata as the first element of the array.
+func (j *jsonAuditLogWriter) writeMeta(m exportMeta) error {
+	outBytes, err := metaJSON(m)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.WriteString(j.out, "[\n"); err != nil {
+		return err
+	}
+	j.started = true
+
+	_, err = j.out.Write(outBytes)
+	return err
+}
+
+// writeMeta writes the metadata as the first line.
+func (r *rawAuditLogWriter) writeMeta(m exportMeta) error {
+	outBytes, err := metaJSON(m)
+	if err != nil {
+		return err
+	}
+
+	if _, err := r.out.Write(outBytes); err != nil {
+		return err
+	}
+
+	_, err = r.out.Write(newline)
+	return err
+}
+
+// writeMeta writes the metadata as the first document.
+func (y *yamlAuditLogWriter) writeMeta(m exportMeta) error {
+	jsonBytes, err := metaJSON(m)
+	if err != nil {
+		return err
+	}
+
+	outBytes, err := yaml.JSONToYAML(jsonBytes)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.WriteString(y.out, "---\n"); err != nil {
+		return err
+	}
+
+	_, err = y.out.Write(outBytes)
+	return err
+}
+
+// writeMeta writes the metadata as a header block.
+func (r *richAuditLogWriter) writeMeta(m exportMeta) error {
+	outBytes, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	r.header(fmt.Sprintf("Export metadata %s", strings.Repeat("┈", dashLen)))
+	return r.highlightJSON(string(outBytes))
+}
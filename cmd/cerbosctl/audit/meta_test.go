@@ -0,0 +1,143 @@
+// Copyright 2021-2022 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ghodss/yaml"
+	"github.com/stretchr/testify/require"
+
+	auditv1 "github.com/cerbos/cerbos/api/genpb/cerbos/audit/v1"
+	"github.com/cerbos/cerbos/client"
+	"github.com/cerbos/cerbos/cmd/cerbosctl/internal/flagset"
+	"github.com/cerbos/cerbos/internal/util"
+)
+
+func TestExportMeta(t *testing.T) {
+	start := time.Date(2021, 7, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(24 * time.Hour)
+
+	cmd := &Cmd{
+		Kind:         "decision",
+		AuditFilters: flagset.AuditFilters{Principal: "harry"},
+		MaxResults:   100,
+	}
+
+	t.Run("time_range", func(t *testing.T) {
+		loc, err := time.LoadLocation("Europe/London")
+		require.NoError(t, err)
+
+		m := cmd.exportMeta("localhost:3593", client.AuditLogOptions{StartTime: start, EndTime: end}, loc)
+		require.Equal(t, "localhost:3593", m.Server)
+		require.Equal(t, "decision", m.Kind)
+		require.Equal(t, util.Version, m.CerbosctlVersion)
+		require.Equal(t, loc, m.ExportedAt.Location())
+		require.Equal(t, "harry", m.Filters.Principal)
+		require.Equal(t, 100, m.Filters.MaxResults)
+		require.True(t, start.Equal(*m.Filters.StartTime))
+		require.Equal(t, loc, m.Filters.StartTime.Location())
+		require.True(t, end.Equal(*m.Filters.EndTime))
+		require.Zero(t, m.Filters.Tail)
+	})
+
+	t.Run("tail", func(t *testing.T) {
+		m := cmd.exportMeta("localhost:3593", client.AuditLogOptions{Tail: 10}, nil)
+		require.Equal(t, time.UTC, m.ExportedAt.Location())
+		require.Equal(t, uint32(10), m.Filters.Tail)
+		require.Nil(t, m.Filters.StartTime)
+		require.Nil(t, m.Filters.EndTime)
+	})
+}
+
+func TestWriteMeta(t *testing.T) {
+	m := exportMeta{
+		ExportedAt:       time.Date(2021, 7, 2, 0, 0, 0, 0, time.UTC),
+		Server:           "localhost:3593",
+		Kind:             "access",
+		Filters:          metaFilters{Tail: 10},
+		CerbosctlVersion: "0.0.0",
+	}
+
+	wantMeta := map[string]any{
+		"exportedAt":       "2021-07-02T00:00:00Z",
+		"server":           "localhost:3593",
+		"kind":             "access",
+		"filters":          map[string]any{"tail": float64(10)},
+		"cerbosctlVersion": "0.0.0",
+	}
+
+	t.Run("json", func(t *testing.T) {
+		var buf bytes.Buffer
+		w := newJSONAuditLogWriter(&buf)
+		require.NoError(t, writeMeta(w, m))
+		require.NoError(t, w.write(&auditv1.AccessLogEntry{CallId: "01GH0000000000000000000001"}))
+		require.NoError(t, w.flush())
+
+		var have []map[string]any
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &have))
+		require.Len(t, have, 2)
+		require.Equal(t, wantMeta, have[0][metaKey])
+		require.Equal(t, "01GH0000000000000000000001", have[1]["callId"])
+	})
+
+	t.Run("json_without_records", func(t *testing.T) {
+		var buf bytes.Buffer
+		w := newJSONAuditLogWriter(&buf)
+		require.NoError(t, writeMeta(w, m))
+		require.NoError(t, w.flush())
+
+		var have []map[string]any
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &have))
+		require.Len(t, have, 1)
+		require.Equal(t, wantMeta, have[0][metaKey])
+	})
+
+	t.Run("ndjson", func(t *testing.T) {
+		var buf bytes.Buffer
+		w := newRawAuditLogWriter(&buf)
+		require.NoError(t, writeMeta(w, m))
+		require.NoError(t, w.write(&auditv1.AccessLogEntry{CallId: "01GH0000000000000000000001"}))
+		require.NoError(t, w.flush())
+
+		scanner := bufio.NewScanner(&buf)
+		var have []map[string]any
+		for scanner.Scan() {
+			var line map[string]any
+			require.NoError(t, json.Unmarshal(scanner.Bytes(), &line))
+			have = append(have, line)
+		}
+
+		require.Len(t, have, 2)
+		require.Equal(t, wantMeta, have[0][metaKey])
+		require.Equal(t, "01GH0000000000000000000001", have[1]["callId"])
+	})
+
+	t.Run("yaml", func(t *testing.T) {
+		var buf bytes.Buffer
+		w := newYAMLAuditLogWriter(&buf)
+		require.NoError(t, writeMeta(w, m))
+		require.NoError(t, w.write(&auditv1.AccessLogEntry{CallId: "01GH0000000000000000000001"}))
+		require.NoError(t, w.flush())
+
+		docs := strings.Split(buf.String(), "---\n")
+		require.Len(t, docs, 3)
+		require.Empty(t, docs[0])
+		require.Equal(t, "callId: 01GH0000000000000000000001\n", docs[2])
+
+		var have map[string]any
+		require.NoError(t, yaml.Unmarshal([]byte(docs[1]), &have))
+		require.Equal(t, wantMeta, have[metaKey])
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.Error(t, writeMeta(newCSVAuditLogWriter(&buf, "access"), m))
+	})
+}
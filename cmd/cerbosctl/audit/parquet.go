@@ -0,0 +1,160 @@
+// Copyright 2021-2022 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"fmt"
+	"io"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	auditv1 "github.com/cerbos/cerbos/api/genpb/cerbos/audit/v1"
+	enginev1 "github.com/cerbos/cerbos/api/genpb/cerbos/engine/v1"
+	"github.com/cerbos/cerbos/cmd/cerbosctl/internal/parquet"
+)
+
+// parquetColumns is the schema of the Parquet output.
+// Decision log entries produce a row for each resource and action pair. The data column contains the entry as JSON,
+// limited to the resource described by the row. Columns that are not applicable to the entry are null.
+var parquetColumns = []parquet.Column{
+	{Name: "call_id", Type: parquet.String},
+	{Name: "timestamp", Type: parquet.Timestamp, Optional: true},
+	{Name: "kind", Type: parquet.String},
+	{Name: "principal", Type: parquet.String, Optional: true},
+	{Name: "resource", Type: parquet.String, Optional: true},
+	{Name: "action", Type: parquet.String, Optional: true},
+	{Name: "effect", Type: parquet.String, Optional: true},
+	{Name: "data", Type: parquet.JSON},
+}
+
+var _ aborter = (*parquetAuditLogWriter)(nil)
+
+type parquetAuditLogWriter struct {
+	out rowWriter
+	dst io.Writer
+}
+
+type rowWriter interface {
+	WriteRow(...any) error
+	Close() error
+}
+
+func newParquetAuditLogWriter(out io.Writer) *parquetAuditLogWriter {
+	return &parquetAuditLogWriter{out: parquet.NewWriter(out, parquetColumns, parquet.DefaultRowGroupSize), dst: out}
+}
+
+func (p *parquetAuditLogWriter) write(entry proto.Message) error {
+	switch e := entry.(type) {
+	case *auditv1.AccessLogEntry:
+		return p.writeRow(e, "access", nil, nil, nil, nil, e)
+	case *auditv1.DecisionLogEntry:
+		return p.writeDecision(e)
+	default:
+		return fmt.Errorf("unexpected audit log entry type %T", entry)
+	}
+}
+
+func (p *parquetAuditLogWriter) writeDecision(e *auditv1.DecisionLogEntry) error {
+	principal := nullable(entryPrincipal(e))
+
+	if pr := e.GetPlanResources(); pr != nil {
+		kind, _ := entryResource(e)
+		return p.writeRow(e, "decision", principal, nullable(kind), nullable(pr.GetInput().GetAction()), nil, e)
+	}
+
+	inputs := checkInputs(e)
+	outputs := e.GetOutputs()
+	if cr := e.GetCheckResources(); cr != nil {
+		outputs = cr.GetOutputs()
+	}
+
+	if len(inputs) == 0 {
+		return p.writeRow(e, "decision", principal, nil, nil, nil, e)
+	}
+
+	for i, input := range inputs {
+		var output *enginev1.CheckOutput
+		if i < len(outputs) {
+			output = outputs[i]
+		}
+
+		data := singleResourceDecision(e, input, output)
+		resource := input.GetResource().GetKind() + "#" + input.GetResource().GetId()
+		for _, action := range input.GetActions() {
+			var effect any
+			if ae, ok := output.GetActions()[action]; ok {
+				effect = ae.GetEffect().String()
+			}
+
+			if err := p.writeRow(e, "decision", nullable(input.GetPrincipal().GetId()), resource, action, effect, data); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (p *parquetAuditLogWriter) writeRow(e auditLogEntry, kind string, principal, resource, action, effect any, data proto.Message) error {
+	dataJSON, err := protojson.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	var timestamp any
+	if ts := e.GetTimestamp(); ts != nil {
+		timestamp = ts.AsTime()
+	}
+
+	return p.out.WriteRow(e.GetCallId(), timestamp, kind, principal, resource, action, effect, string(dataJSON))
+}
+
+// flush finalizes the Parquet file by writing the footer. No more entries can be written afterwards.
+// The file is corrupt if writing the footer fails, so the error must be reported.
+func (p *parquetAuditLogWriter) flush() error {
+	return p.out.Close()
+}
+
+// abort discards the output if flush could not complete, because a Parquet file without a footer cannot be read.
+func (p *parquetAuditLogWriter) abort() error {
+	d, ok := p.dst.(discarder)
+	if !ok {
+		return errCannotDiscard
+	}
+
+	return d.discard()
+}
+
+// singleResourceDecision returns a copy of the decision log entry that only contains the given input and output.
+func singleResourceDecision(e *auditv1.DecisionLogEntry, input *enginev1.CheckInput, output *enginev1.CheckOutput) *auditv1.DecisionLogEntry {
+	cr := &auditv1.DecisionLogEntry_CheckResources{
+		Inputs: []*enginev1.CheckInput{input},
+		Error:  e.GetCheckResources().GetError(),
+	}
+
+	if cr.Error == "" {
+		cr.Error = e.GetError()
+	}
+
+	if output != nil {
+		cr.Outputs = []*enginev1.CheckOutput{output}
+	}
+
+	return &auditv1.DecisionLogEntry{
+		CallId:    e.GetCallId(),
+		Timestamp: e.GetTimestamp(),
+		Peer:      e.GetPeer(),
+		Method:    &auditv1.DecisionLogEntry_CheckResources_{CheckResources: cr},
+	}
+}
+
+// nullable returns nil for empty strings so that they are written as null values.
+func nullable(s string) any {
+	if s == "" {
+		return nil
+	}
+
+	return s
+}
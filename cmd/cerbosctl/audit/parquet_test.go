@@ -0,0 +1,116 @@
+// Copyright 2021-2022 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	auditv1 "github.com/cerbos/cerbos/api/genpb/cerbos/audit/v1"
+	effectv1 "github.com/cerbos/cerbos/api/genpb/cerbos/effect/v1"
+	enginev1 "github.com/cerbos/cerbos/api/genpb/cerbos/engine/v1"
+)
+
+func TestParquetAuditLogWriter(t *testing.T) {
+	ts := time.Date(2021, 7, 1, 0, 0, 0, 0, time.UTC)
+
+	rw := &collectingRowWriter{}
+	w := &parquetAuditLogWriter{out: rw}
+
+	require.NoError(t, w.write(&auditv1.AccessLogEntry{CallId: "01GH0000000000000000000001", Timestamp: timestamppb.New(ts), Method: "/cerbos.svc.v1.CerbosService/CheckResources"}))
+	require.NoError(t, w.write(&auditv1.DecisionLogEntry{
+		CallId:    "01GH0000000000000000000002",
+		Timestamp: timestamppb.New(ts),
+		Method: &auditv1.DecisionLogEntry_CheckResources_{
+			CheckResources: &auditv1.DecisionLogEntry_CheckResources{
+				Inputs: []*enginev1.CheckInput{
+					{
+						Principal: &enginev1.Principal{Id: "harry"},
+						Resource:  &enginev1.Resource{Kind: "leave_request", Id: "XX125"},
+						Actions:   []string{"view", "approve"},
+					},
+					{
+						Principal: &enginev1.Principal{Id: "harry"},
+						Resource:  &enginev1.Resource{Kind: "album:object", Id: "YY001"},
+						Actions:   []string{"view"},
+					},
+				},
+				Outputs: []*enginev1.CheckOutput{
+					{
+						ResourceId: "XX125",
+						Actions: map[string]*enginev1.CheckOutput_ActionEffect{
+							"view":    {Effect: effectv1.Effect_EFFECT_ALLOW},
+							"approve": {Effect: effectv1.Effect_EFFECT_DENY},
+						},
+					},
+				},
+			},
+		},
+	}))
+	require.NoError(t, w.flush())
+
+	require.True(t, rw.closed)
+	require.Len(t, rw.rows, 4)
+
+	// strip the JSON data column for comparison
+	have := make([][]any, len(rw.rows))
+	for i, row := range rw.rows {
+		require.Len(t, row, len(parquetColumns))
+		require.NotEmpty(t, row[7])
+		have[i] = row[:7]
+	}
+
+	require.Equal(t, [][]any{
+		{"01GH0000000000000000000001", ts, "access", nil, nil, nil, nil},
+		{"01GH0000000000000000000002", ts, "decision", "harry", "leave_request#XX125", "view", "EFFECT_ALLOW"},
+		{"01GH0000000000000000000002", ts, "decision", "harry", "leave_request#XX125", "approve", "EFFECT_DENY"},
+		{"01GH0000000000000000000002", ts, "decision", "harry", "album:object#YY001", "view", nil},
+	}, have)
+}
+
+func TestParquetAuditLogWriterFlushError(t *testing.T) {
+	errFooter := errors.New("no space left on device")
+	w := &parquetAuditLogWriter{out: &collectingRowWriter{closeErr: errFooter}}
+	require.NoError(t, w.write(&auditv1.AccessLogEntry{CallId: "01GH0000000000000000000001"}))
+	require.ErrorIs(t, w.flush(), errFooter)
+}
+
+func TestParquetAuditLogWriterAbort(t *testing.T) {
+	f, err := os.Create(filepath.Join(t.TempDir(), "decisions.parquet"))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = f.Close() })
+
+	w := newParquetAuditLogWriter(newAbortableOutput(f))
+	require.NoError(t, w.write(&auditv1.AccessLogEntry{CallId: "01GH0000000000000000000001"}))
+	require.NoError(t, w.abort())
+
+	fi, err := f.Stat()
+	require.NoError(t, err)
+	require.Zero(t, fi.Size(), "Incomplete Parquet file should be discarded")
+
+	require.ErrorIs(t, newParquetAuditLogWriter(io.Discard).abort(), errCannotDiscard)
+}
+
+type collectingRowWriter struct {
+	closeErr error
+	rows     [][]any
+	closed   bool
+}
+
+func (c *collectingRowWriter) WriteRow(row ...any) error {
+	c.rows = append(c.rows, row)
+	return nil
+}
+
+func (c *collectingRowWriter) Close() error {
+	c.closed = true
+	return c.closeErr
+}
@@ -0,0 +1,142 @@
+// Copyright 2021-2022 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/mattn/go-isatty"
+	"google.golang.org/protobuf/proto"
+)
+
+const progressInterval = 1 * time.Second
+
+// progressEnabled returns true if progress should be reported to the given writer.
+func (c *Cmd) progressEnabled(out io.Writer) bool {
+	return c.ForceProgress || (c.Progress && isTerminal(out))
+}
+
+// progressWriter counts the entries written to the underlying writer and periodically reports progress.
+type progressWriter struct {
+	auditLogWriter
+	out        io.Writer
+	done       chan struct{}
+	start      time.Time
+	rangeStart time.Time
+	rangeEnd   time.Time
+	lastSeenAt time.Time
+	wg         sync.WaitGroup
+	count      int64
+	mu         sync.Mutex
+	tty        bool
+}
+
+// newProgressWriter wraps the given writer to report progress to out.
+// If the range start and end times are non-zero, the report includes the position within that range.
+func newProgressWriter(writer auditLogWriter, out io.Writer, rangeStart, rangeEnd time.Time) *progressWriter {
+	pw := &progressWriter{
+		auditLogWriter: writer,
+		out:            out,
+		done:           make(chan struct{}),
+		start:          time.Now(),
+		rangeStart:     rangeStart,
+		rangeEnd:       rangeEnd,
+		tty:            isTerminal(out),
+	}
+
+	pw.wg.Add(1)
+	go pw.report()
+
+	return pw
+}
+
+func (pw *progressWriter) write(entry proto.Message) error {
+	if err := pw.auditLogWriter.write(entry); err != nil {
+		return err
+	}
+
+	pw.mu.Lock()
+	defer pw.mu.Unlock()
+
+	pw.count++
+	if e, ok := entry.(auditLogEntry); ok && e.GetTimestamp() != nil {
+		pw.lastSeenAt = e.GetTimestamp().AsTime()
+	}
+
+	return nil
+}
+
+func (pw *progressWriter) report() {
+	defer pw.wg.Done()
+
+	ticker := time.NewTicker(progressInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-pw.done:
+			pw.render("\n")
+			return
+		case <-ticker.C:
+			if pw.tty {
+				pw.render("")
+			} else {
+				pw.render("\n")
+			}
+		}
+	}
+}
+
+func (pw *progressWriter) render(suffix string) {
+	pw.mu.Lock()
+	defer pw.mu.Unlock()
+
+	line := fmt.Sprintf("%d records written in %s", pw.count, time.Since(pw.start).Round(time.Second))
+	if pos, ok := pw.rangePosition(); ok {
+		line = fmt.Sprintf("%s (%.0f%% of the requested time range)", line, pos*100) //nolint:gomnd
+	}
+
+	if pw.tty {
+		_, _ = fmt.Fprintf(pw.out, "\r%s%s", line, suffix)
+		return
+	}
+
+	_, _ = fmt.Fprintf(pw.out, "%s%s", line, suffix)
+}
+
+// rangePosition estimates how far through the requested time range the export is, based on the timestamp of the last entry written.
+func (pw *progressWriter) rangePosition() (float64, bool) {
+	if pw.rangeStart.IsZero() || pw.rangeEnd.IsZero() || pw.lastSeenAt.IsZero() || !pw.rangeEnd.After(pw.rangeStart) {
+		return 0, false
+	}
+
+	pos := float64(pw.lastSeenAt.Sub(pw.rangeStart)) / float64(pw.rangeEnd.Sub(pw.rangeStart))
+	switch {
+	case pos < 0:
+		return 0, true
+	case pos > 1:
+		return 1, true
+	default:
+		return pos, true
+	}
+}
+
+// stop stops reporting and renders the final progress line.
+func (pw *progressWriter) stop() {
+	close(pw.done)
+	pw.wg.Wait()
+}
+
+func isTerminal(out io.Writer) bool {
+	f, ok := out.(*os.File)
+	if !ok {
+		return false
+	}
+
+	return isatty.IsTerminal(f.Fd()) || isatty.IsCygwinTerminal(f.Fd())
+}
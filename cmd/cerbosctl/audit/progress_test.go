@@ -0,0 +1,94 @@
+// Copyright 2021-2022 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	auditv1 "github.com/cerbos/cerbos/api/genpb/cerbos/audit/v1"
+)
+
+func TestRangePosition(t *testing.T) {
+	rangeStart := time.Date(2022, 10, 1, 0, 0, 0, 0, time.UTC)
+	rangeEnd := rangeStart.Add(10 * time.Hour)
+
+	testCases := []struct {
+		name       string
+		rangeStart time.Time
+		rangeEnd   time.Time
+		lastSeenAt time.Time
+		want       float64
+		wantOK     bool
+	}{
+		{name: "start", rangeStart: rangeStart, rangeEnd: rangeEnd, lastSeenAt: rangeStart, want: 0, wantOK: true},
+		{name: "middle", rangeStart: rangeStart, rangeEnd: rangeEnd, lastSeenAt: rangeStart.Add(5 * time.Hour), want: 0.5, wantOK: true},
+		{name: "end", rangeStart: rangeStart, rangeEnd: rangeEnd, lastSeenAt: rangeEnd, want: 1, wantOK: true},
+		{name: "before_start", rangeStart: rangeStart, rangeEnd: rangeEnd, lastSeenAt: rangeStart.Add(-time.Hour), want: 0, wantOK: true},
+		{name: "after_end", rangeStart: rangeStart, rangeEnd: rangeEnd, lastSeenAt: rangeEnd.Add(time.Hour), want: 1, wantOK: true},
+		{name: "nothing_seen", rangeStart: rangeStart, rangeEnd: rangeEnd},
+		{name: "no_range", lastSeenAt: rangeStart},
+		{name: "open_range", rangeStart: rangeStart, lastSeenAt: rangeStart},
+		{name: "empty_range", rangeStart: rangeStart, rangeEnd: rangeStart, lastSeenAt: rangeStart},
+		{name: "inverted_range", rangeStart: rangeEnd, rangeEnd: rangeStart, lastSeenAt: rangeStart},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			pw := &progressWriter{rangeStart: tc.rangeStart, rangeEnd: tc.rangeEnd, lastSeenAt: tc.lastSeenAt}
+			have, ok := pw.rangePosition()
+			require.Equal(t, tc.wantOK, ok)
+			require.InDelta(t, tc.want, have, 1e-9)
+		})
+	}
+}
+
+func TestProgressWriter(t *testing.T) {
+	rangeStart := time.Now().Add(-4 * time.Hour)
+	rangeEnd := rangeStart.Add(4 * time.Hour)
+
+	var out bytes.Buffer
+	cw := &collectingWriter{}
+	pw := newProgressWriter(cw, &out, rangeStart, rangeEnd)
+
+	require.NoError(t, pw.write(&auditv1.AccessLogEntry{CallId: "1", Timestamp: timestamppb.New(rangeStart.Add(time.Hour))}))
+	require.NoError(t, pw.write(&auditv1.AccessLogEntry{CallId: "2", Timestamp: timestamppb.New(rangeStart.Add(2 * time.Hour))}))
+	pw.stop()
+
+	require.Equal(t, []string{"1", "2"}, cw.callIDs())
+
+	// the output is not a terminal, so each report is written on its own line
+	output := out.String()
+	require.True(t, strings.HasSuffix(output, "\n"), "Final report should end with a newline")
+	require.NotContains(t, output, "\r")
+
+	lines := strings.Split(strings.TrimSuffix(output, "\n"), "\n")
+	require.Regexp(t, `^2 records written in \d+s \(50% of the requested time range\)$`, lines[len(lines)-1])
+}
+
+func TestProgressEnabled(t *testing.T) {
+	testCases := []struct {
+		name string
+		cmd  Cmd
+		want bool
+	}{
+		{name: "default"},
+		{name: "progress_not_terminal", cmd: Cmd{Progress: true}},
+		{name: "force_progress", cmd: Cmd{ForceProgress: true}, want: true},
+		{name: "progress_and_force_progress", cmd: Cmd{Progress: true, ForceProgress: true}, want: true},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.want, tc.cmd.progressEnabled(&bytes.Buffer{}))
+		})
+	}
+}
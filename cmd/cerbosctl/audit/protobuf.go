@@ -0,0 +1,81 @@
+// Copyright 2021-2022 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// MaxProtobufFrameSize is the largest frame accepted by ReadProtobufFrame.
+//
+// The protobuf output format is a sequence of frames, one per entry. Each frame consists of the length of the entry
+// in bytes encoded as an unsigned varint (as written by binary.PutUvarint), followed by the entry encoded in the protobuf
+// binary wire format. The entries are AccessLogEntry or DecisionLogEntry messages from the cerbos.audit.v1 package,
+// depending on the kind of the audit log. This is the same framing as Java's writeDelimitedTo and parseDelimitedFrom.
+const MaxProtobufFrameSize = 64 << 20
+
+var errFrameTooLarge = fmt.Errorf("protobuf frame is larger than %d bytes", MaxProtobufFrameSize)
+
+// protobufAuditLogWriter writes the entries as length-prefixed protobuf frames, which preserve all the information
+// in the entries exactly.
+type protobufAuditLogWriter struct {
+	out io.Writer
+	buf []byte
+}
+
+func newProtobufAuditLogWriter(out io.Writer) *protobufAuditLogWriter {
+	return &protobufAuditLogWriter{out: out}
+}
+
+func (p *protobufAuditLogWriter) write(entry proto.Message) error {
+	var outBytes []byte
+	var err error
+	if vt, ok := entry.(interface{ MarshalVT() ([]byte, error) }); ok {
+		outBytes, err = vt.MarshalVT()
+	} else {
+		outBytes, err = proto.Marshal(entry)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit log entry: %w", err)
+	}
+
+	p.buf = binary.AppendUvarint(p.buf[:0], uint64(len(outBytes)))
+	if _, err := p.out.Write(p.buf); err != nil {
+		return err
+	}
+
+	_, err = p.out.Write(outBytes)
+	return err
+}
+
+func (p *protobufAuditLogWriter) flush() error { return nil }
+
+// ReadProtobufFrame reads the next frame written by the protobuf output format into dest.
+// It returns io.EOF if there are no more frames and io.ErrUnexpectedEOF if the last frame is truncated.
+func ReadProtobufFrame(r *bufio.Reader, dest proto.Message) error {
+	size, err := binary.ReadUvarint(r)
+	if err != nil {
+		return err
+	}
+
+	if size > MaxProtobufFrameSize {
+		return errFrameTooLarge
+	}
+
+	data := make([]byte, size)
+	if _, err := io.ReadFull(r, data); err != nil {
+		if errors.Is(err, io.EOF) {
+			return io.ErrUnexpectedEOF
+		}
+		return err
+	}
+
+	return proto.Unmarshal(data, dest)
+}
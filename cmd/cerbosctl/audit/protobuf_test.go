@@ -0,0 +1,107 @@
+// Copyright 2021-2022 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/testing/protocmp"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	auditv1 "github.com/cerbos/cerbos/api/genpb/cerbos/audit/v1"
+	effectv1 "github.com/cerbos/cerbos/api/genpb/cerbos/effect/v1"
+	enginev1 "github.com/cerbos/cerbos/api/genpb/cerbos/engine/v1"
+)
+
+func TestProtobufAuditLogWriter(t *testing.T) {
+	ts := time.Date(2021, 7, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("access", func(t *testing.T) {
+		entries := []*auditv1.AccessLogEntry{
+			{
+				CallId:     "01GH0000000000000000000001",
+				Timestamp:  timestamppb.New(ts),
+				Peer:       &auditv1.Peer{Address: "1.1.1.1"},
+				Method:     "/cerbos.svc.v1.CerbosService/CheckResources",
+				StatusCode: 5,
+			},
+			{CallId: "01GH0000000000000000000002"},
+			{},
+		}
+
+		var buf bytes.Buffer
+		w := newProtobufAuditLogWriter(&buf)
+		for _, e := range entries {
+			require.NoError(t, w.write(e))
+		}
+		require.NoError(t, w.flush())
+
+		r := bufio.NewReader(&buf)
+		for _, want := range entries {
+			have := &auditv1.AccessLogEntry{}
+			require.NoError(t, ReadProtobufFrame(r, have))
+			require.Empty(t, cmp.Diff(want, have, protocmp.Transform()))
+		}
+		require.ErrorIs(t, ReadProtobufFrame(r, &auditv1.AccessLogEntry{}), io.EOF)
+	})
+
+	t.Run("decision", func(t *testing.T) {
+		want := &auditv1.DecisionLogEntry{
+			CallId:    "01GH0000000000000000000003",
+			Timestamp: timestamppb.New(ts),
+			Method: &auditv1.DecisionLogEntry_CheckResources_{
+				CheckResources: &auditv1.DecisionLogEntry_CheckResources{
+					Inputs: []*enginev1.CheckInput{
+						{
+							Principal: &enginev1.Principal{Id: "harry", Roles: []string{"employee"}},
+							Resource:  &enginev1.Resource{Kind: "leave_request", Id: "XX125"},
+							Actions:   []string{"view", "approve"},
+						},
+					},
+					Outputs: []*enginev1.CheckOutput{
+						{
+							ResourceId: "XX125",
+							Actions: map[string]*enginev1.CheckOutput_ActionEffect{
+								"view":    {Effect: effectv1.Effect_EFFECT_ALLOW},
+								"approve": {Effect: effectv1.Effect_EFFECT_DENY},
+							},
+						},
+					},
+				},
+			},
+		}
+
+		var buf bytes.Buffer
+		w := newAuditLogWriter(formatProtobuf, "decision", "", time.Local, &buf)
+		require.NoError(t, w.write(want))
+		require.NoError(t, w.flush())
+
+		r := bufio.NewReader(&buf)
+		have := &auditv1.DecisionLogEntry{}
+		require.NoError(t, ReadProtobufFrame(r, have))
+		require.Empty(t, cmp.Diff(want, have, protocmp.Transform()))
+		require.ErrorIs(t, ReadProtobufFrame(r, have), io.EOF)
+	})
+
+	t.Run("truncated", func(t *testing.T) {
+		var buf bytes.Buffer
+		w := newProtobufAuditLogWriter(&buf)
+		require.NoError(t, w.write(&auditv1.AccessLogEntry{CallId: "01GH0000000000000000000004"}))
+
+		r := bufio.NewReader(bytes.NewReader(buf.Bytes()[:buf.Len()-1]))
+		require.ErrorIs(t, ReadProtobufFrame(r, &auditv1.AccessLogEntry{}), io.ErrUnexpectedEOF)
+	})
+
+	t.Run("too_large", func(t *testing.T) {
+		r := bufio.NewReader(bytes.NewReader(binary.AppendUvarint(nil, MaxProtobufFrameSize+1)))
+		require.ErrorIs(t, ReadProtobufFrame(r, &auditv1.AccessLogEntry{}), errFrameTooLarge)
+	})
+}
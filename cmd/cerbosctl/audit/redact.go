@@ -0,0 +1,252 @@
+// Copyright 2021-2022 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+const (
+	// redactWildcard matches any key of an object in a redaction path.
+	redactWildcard = "*"
+
+	wellKnownTypesPackage protoreflect.FullName = "google.protobuf"
+	stringValueMessage    protoreflect.FullName = "google.protobuf.StringValue"
+	valueMessage          protoreflect.FullName = "google.protobuf.Value"
+)
+
+// redactingWriter removes (or replaces with their SHA-256 hash) the fields at the given paths before passing the entries
+// to the underlying writer. The paths refer to the field names of the protojson representation of the entries.
+type redactingWriter struct {
+	auditLogWriter
+	paths [][]string
+	hash  bool
+}
+
+func newRedactingWriter(writer auditLogWriter, kind string, paths []string, hash bool) (*redactingWriter, error) {
+	segments, err := parseRedactPaths(kind, paths, hash)
+	if err != nil {
+		return nil, err
+	}
+
+	return &redactingWriter{auditLogWriter: writer, paths: segments, hash: hash}, nil
+}
+
+// parseRedactPaths parses the paths to redact from the entries of the given kind.
+// If the values are going to be hashed, the paths must only select fields that accept a string. Otherwise the redacted
+// entries couldn't be decoded and the export would fail after some records have already been written.
+func parseRedactPaths(kind string, paths []string, hash bool) ([][]string, error) {
+	desc := newLogEntry(kind).ProtoReflect().Descriptor()
+	parsed := make([][]string, len(paths))
+	for i, p := range paths {
+		segments, err := parseRedactPath(p)
+		if err != nil {
+			return nil, err
+		}
+
+		if hash {
+			if err := checkHashable(desc, segments); err != nil {
+				return nil, fmt.Errorf("invalid --redact path %q: %w", p, err)
+			}
+		}
+
+		parsed[i] = segments
+	}
+
+	return parsed, nil
+}
+
+// parseRedactPath splits a dot-separated path such as checkResources.inputs.principal.id into its segments.
+// A leading $. is accepted for compatibility with the JSONPath syntax.
+func parseRedactPath(p string) ([]string, error) {
+	trimmed := strings.TrimPrefix(strings.TrimSpace(p), "$.")
+	if trimmed == "" {
+		return nil, fmt.Errorf("invalid --redact path %q: path is empty", p)
+	}
+
+	segments := strings.Split(trimmed, ".")
+	for _, s := range segments {
+		if s == "" {
+			return nil, fmt.Errorf("invalid --redact path %q: empty field name", p)
+		}
+	}
+
+	return segments, nil
+}
+
+// checkHashable returns an error if the path selects fields of the message that cannot be replaced with a string.
+// The path follows the protojson representation of the message: map keys are path segments and repeated fields are
+// traversed transparently. Fields that don't exist are ignored because the path never matches them.
+func checkHashable(md protoreflect.MessageDescriptor, path []string) error {
+	if md.FullName().Parent() == wellKnownTypesPackage {
+		// the JSON representation of the well-known types doesn't follow their fields, and all the values nested in a
+		// Struct, ListValue or Value are Values that accept a string
+		return nil
+	}
+
+	var fields []protoreflect.FieldDescriptor
+	if path[0] == redactWildcard {
+		for i := 0; i < md.Fields().Len(); i++ {
+			fields = append(fields, md.Fields().Get(i))
+		}
+	} else if fd := md.Fields().ByJSONName(path[0]); fd != nil {
+		fields = append(fields, fd)
+	}
+
+	for _, fd := range fields {
+		if err := checkHashableField(fd, path[1:]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func checkHashableField(fd protoreflect.FieldDescriptor, path []string) error {
+	if fd.IsMap() && len(path) > 0 {
+		// the next segment is a key of the map
+		fd = fd.MapValue()
+		path = path[1:]
+	}
+
+	if len(path) > 0 {
+		if fd.Message() == nil {
+			return nil
+		}
+
+		return checkHashable(fd.Message(), path)
+	}
+
+	if fd.Cardinality() != protoreflect.Repeated {
+		if fd.Kind() == protoreflect.StringKind {
+			return nil
+		}
+
+		if md := fd.Message(); md != nil && (md.FullName() == valueMessage || md.FullName() == stringValueMessage) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("--redact-hash can only replace string fields and %s is not a string field", fd.FullName())
+}
+
+func (rw *redactingWriter) write(entry proto.Message) error {
+	redacted, err := rw.redact(entry)
+	if err != nil {
+		return fmt.Errorf("failed to redact entry: %w", err)
+	}
+
+	return rw.auditLogWriter.write(redacted)
+}
+
+// redact returns a copy of the entry with the fields at the configured paths redacted.
+// The entry is returned as is if none of the paths match.
+func (rw *redactingWriter) redact(entry proto.Message) (proto.Message, error) {
+	jsonBytes, err := protojson.Marshal(entry)
+	if err != nil {
+		return nil, err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(jsonBytes))
+	dec.UseNumber()
+
+	var doc any
+	if err := dec.Decode(&doc); err != nil {
+		return nil, err
+	}
+
+	changed := false
+	for _, path := range rw.paths {
+		if rw.redactPath(doc, path) {
+			changed = true
+		}
+	}
+
+	if !changed {
+		return entry, nil
+	}
+
+	outBytes, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	redacted := entry.ProtoReflect().New().Interface()
+	if err := protojson.Unmarshal(outBytes, redacted); err != nil {
+		return nil, err
+	}
+
+	return redacted, nil
+}
+
+// redactPath redacts the values matching the path under v and reports whether any values were redacted.
+// Arrays are traversed transparently so that the path applies to each of their elements.
+func (rw *redactingWriter) redactPath(v any, path []string) bool {
+	switch t := v.(type) {
+	case []any:
+		changed := false
+		for _, elem := range t {
+			if rw.redactPath(elem, path) {
+				changed = true
+			}
+		}
+		return changed
+
+	case map[string]any:
+		keys := []string{path[0]}
+		if path[0] == redactWildcard {
+			keys = make([]string, 0, len(t))
+			for k := range t {
+				keys = append(keys, k)
+			}
+		}
+
+		changed := false
+		for _, k := range keys {
+			child, ok := t[k]
+			if !ok {
+				continue
+			}
+
+			if len(path) > 1 {
+				if rw.redactPath(child, path[1:]) {
+					changed = true
+				}
+				continue
+			}
+
+			if rw.hash {
+				t[k] = hashValue(child)
+			} else {
+				delete(t, k)
+			}
+			changed = true
+		}
+		return changed
+
+	default:
+		return false
+	}
+}
+
+// hashValue returns the hex-encoded SHA-256 hash of a string or of the JSON encoding of any other value.
+func hashValue(v any) string {
+	b, ok := v.(string)
+	if !ok {
+		jsonBytes, _ := json.Marshal(v)
+		b = string(jsonBytes)
+	}
+
+	sum := sha256.Sum256([]byte(b))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,147 @@
+// Copyright 2021-2022 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	auditv1 "github.com/cerbos/cerbos/api/genpb/cerbos/audit/v1"
+	enginev1 "github.com/cerbos/cerbos/api/genpb/cerbos/engine/v1"
+)
+
+func TestRedactingWriter(t *testing.T) {
+	mkEntry := func() *auditv1.DecisionLogEntry {
+		return &auditv1.DecisionLogEntry{
+			CallId: "01GH0000000000000000000001",
+			Method: &auditv1.DecisionLogEntry_CheckResources_{
+				CheckResources: &auditv1.DecisionLogEntry_CheckResources{
+					Inputs: []*enginev1.CheckInput{
+						{
+							Principal: &enginev1.Principal{
+								Id:    "harry",
+								Roles: []string{"employee"},
+								Attr: map[string]*structpb.Value{
+									"email":      structpb.NewStringValue("harry@example.com"),
+									"department": structpb.NewStringValue("marketing"),
+								},
+							},
+							Resource: &enginev1.Resource{
+								Kind: "leave_request",
+								Id:   "XX125",
+								Attr: map[string]*structpb.Value{"owner": structpb.NewStringValue("harry")},
+							},
+							Actions: []string{"view"},
+						},
+						{
+							Principal: &enginev1.Principal{Id: "maggie", Roles: []string{"manager"}},
+							Resource:  &enginev1.Resource{Kind: "leave_request", Id: "XX150"},
+							Actions:   []string{"approve"},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	sha256Hex := func(s string) string {
+		sum := sha256.Sum256([]byte(s))
+		return hex.EncodeToString(sum[:])
+	}
+
+	inputs := func(t *testing.T, entry proto.Message) []*enginev1.CheckInput {
+		t.Helper()
+
+		e, ok := entry.(*auditv1.DecisionLogEntry)
+		require.True(t, ok)
+		return e.GetCheckResources().GetInputs()
+	}
+
+	t.Run("remove", func(t *testing.T) {
+		cw := &collectingWriter{}
+		rw, err := newRedactingWriter(cw, "decision", []string{"checkResources.inputs.principal.id", "$.checkResources.inputs.principal.attr.email"}, false)
+		require.NoError(t, err)
+
+		in := mkEntry()
+		require.NoError(t, rw.write(in))
+		require.Len(t, cw.entries, 1)
+
+		have := inputs(t, cw.entries[0])
+		require.Empty(t, have[0].Principal.Id)
+		require.Empty(t, have[1].Principal.Id)
+		require.NotContains(t, have[0].Principal.Attr, "email")
+		require.Equal(t, "marketing", have[0].Principal.Attr["department"].GetStringValue())
+		require.Equal(t, []string{"employee"}, have[0].Principal.Roles)
+
+		require.Equal(t, "harry", in.GetCheckResources().GetInputs()[0].Principal.Id, "Original entry should not be modified")
+	})
+
+	t.Run("hash", func(t *testing.T) {
+		cw := &collectingWriter{}
+		rw, err := newRedactingWriter(cw, "decision", []string{"checkResources.inputs.principal.id", "checkResources.inputs.*.attr.*"}, true)
+		require.NoError(t, err)
+
+		require.NoError(t, rw.write(mkEntry()))
+		require.Len(t, cw.entries, 1)
+
+		have := inputs(t, cw.entries[0])
+		require.Equal(t, sha256Hex("harry"), have[0].Principal.Id)
+		require.Equal(t, sha256Hex("maggie"), have[1].Principal.Id)
+		require.Equal(t, sha256Hex("harry@example.com"), have[0].Principal.Attr["email"].GetStringValue())
+		require.Equal(t, sha256Hex("harry"), have[0].Resource.Attr["owner"].GetStringValue())
+		require.Equal(t, "XX125", have[0].Resource.Id)
+	})
+
+	t.Run("hash_non_string_field", func(t *testing.T) {
+		testCases := []struct {
+			kind string
+			path string
+		}{
+			{kind: "decision", path: "checkResources.inputs.principal"},
+			{kind: "decision", path: "checkResources.inputs.principal.roles"},
+			{kind: "decision", path: "checkResources.inputs.principal.attr"},
+			{kind: "decision", path: "timestamp"},
+			{kind: "decision", path: "checkResources.outputs.actions.*.effect"},
+			{kind: "decision", path: "checkResources.inputs.*"},
+			{kind: "access", path: "statusCode"},
+		}
+
+		for _, tc := range testCases {
+			_, err := newRedactingWriter(&collectingWriter{}, tc.kind, []string{tc.path}, true)
+			require.Error(t, err, "Expected error for path %q", tc.path)
+
+			_, err = newRedactingWriter(&collectingWriter{}, tc.kind, []string{tc.path}, false)
+			require.NoError(t, err, "Fields of any type can be removed")
+		}
+	})
+
+	t.Run("hash_string_field", func(t *testing.T) {
+		for _, p := range []string{"callId", "checkResources.outputs.actions.*.policy", "checkResources.inputs.*.attr.*", "timestamp.seconds", "unknown"} {
+			_, err := newRedactingWriter(&collectingWriter{}, "decision", []string{p}, true)
+			require.NoError(t, err, "Unexpected error for path %q", p)
+		}
+	})
+
+	t.Run("no_match", func(t *testing.T) {
+		cw := &collectingWriter{}
+		rw, err := newRedactingWriter(cw, "decision", []string{"planResources.input.principal.id"}, false)
+		require.NoError(t, err)
+
+		in := mkEntry()
+		require.NoError(t, rw.write(in))
+		require.Same(t, in, cw.entries[0])
+	})
+
+	t.Run("invalid_paths", func(t *testing.T) {
+		for _, p := range []string{"", "$.", "principal..id", ".principal", "principal."} {
+			_, err := newRedactingWriter(&collectingWriter{}, "decision", []string{p}, false)
+			require.Error(t, err, "Expected error for path %q", p)
+		}
+	})
+}
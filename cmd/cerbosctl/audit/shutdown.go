@@ -0,0 +1,118 @@
+// Copyright 2021-2022 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"go.uber.org/multierr"
+)
+
+var (
+	errInterrupted   = errors.New("interrupted before all records were retrieved")
+	errFlushDeadline = errors.New("deadline exceeded while flushing pending records")
+	errOutputAborted = errors.New("output aborted")
+	errCannotDiscard = errors.New("output is not a regular file and cannot be discarded")
+)
+
+// aborter is implemented by writers that can discard an output that cannot be finalized
+// (for example, by removing a Parquet file without a footer) instead of leaving it in an incomplete state.
+type aborter interface {
+	abort() error
+}
+
+// discarder is implemented by outputs whose contents can be discarded.
+type discarder interface {
+	discard() error
+}
+
+// abortableOutput wraps the output of the writers so that nothing is written to it once it is aborted.
+type abortableOutput struct {
+	out     io.Writer
+	mu      sync.Mutex
+	aborted bool
+}
+
+func newAbortableOutput(out io.Writer) *abortableOutput {
+	return &abortableOutput{out: out}
+}
+
+func (ao *abortableOutput) Write(p []byte) (int, error) {
+	ao.mu.Lock()
+	defer ao.mu.Unlock()
+
+	if ao.aborted {
+		return 0, errOutputAborted
+	}
+
+	return ao.out.Write(p)
+}
+
+// abort makes all subsequent writes fail. It waits for a write in progress to complete.
+func (ao *abortableOutput) abort() {
+	ao.mu.Lock()
+	defer ao.mu.Unlock()
+
+	ao.aborted = true
+}
+
+// discard truncates the output if it is a regular file. Other outputs, such as pipes, cannot be discarded.
+func (ao *abortableOutput) discard() error {
+	ao.mu.Lock()
+	defer ao.mu.Unlock()
+
+	f, ok := ao.out.(*os.File)
+	if !ok {
+		return errCannotDiscard
+	}
+
+	fi, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	if !fi.Mode().IsRegular() {
+		return errCannotDiscard
+	}
+
+	return f.Truncate(0)
+}
+
+// flushWithDeadline flushes the writer, giving up after the given timeout.
+// If the deadline is exceeded, writes to the output are stopped and the function waits for the flush to return, so that
+// the output can be closed safely afterwards. The output is then aborted if the writer supports it.
+func flushWithDeadline(writer auditLogWriter, out *abortableOutput, timeout time.Duration) error {
+	done := make(chan error, 1)
+	go func() {
+		done <- writer.flush()
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case err := <-done:
+		return err
+	case <-timer.C:
+	}
+
+	out.abort()
+	<-done
+
+	a, ok := writer.(aborter)
+	if !ok {
+		return errFlushDeadline
+	}
+
+	if err := a.abort(); err != nil {
+		return multierr.Combine(errFlushDeadline, fmt.Errorf("failed to abort: %w", err))
+	}
+
+	return fmt.Errorf("%w: %v", errFlushDeadline, errOutputAborted)
+}
@@ -0,0 +1,130 @@
+// Copyright 2021-2022 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestFlushWithDeadline(t *testing.T) {
+	t.Run("flushed", func(t *testing.T) {
+		var buf bytes.Buffer
+		w := newSlowWriter(&buf, 0)
+		require.NoError(t, flushWithDeadline(w, newAbortableOutput(&buf), time.Second))
+		require.True(t, w.flushed())
+		require.Equal(t, "flushed", buf.String())
+	})
+
+	t.Run("deadline_exceeded", func(t *testing.T) {
+		var buf bytes.Buffer
+		out := newAbortableOutput(&buf)
+		w := newSlowWriter(out, 100*time.Millisecond)
+		require.ErrorIs(t, flushWithDeadline(w, out, 10*time.Millisecond), errFlushDeadline)
+		require.True(t, w.flushed(), "Flush should have returned")
+		require.Empty(t, buf.String(), "Nothing should be written after the deadline")
+	})
+
+	t.Run("aborted", func(t *testing.T) {
+		var buf bytes.Buffer
+		out := newAbortableOutput(&buf)
+		w := &abortingWriter{slowWriter: newSlowWriter(out, 100*time.Millisecond)}
+		require.ErrorIs(t, flushWithDeadline(w, out, 10*time.Millisecond), errFlushDeadline)
+		require.True(t, w.aborted)
+	})
+
+	t.Run("abort_failed", func(t *testing.T) {
+		errAbort := errors.New("abort failed")
+		var buf bytes.Buffer
+		out := newAbortableOutput(&buf)
+		w := &abortingWriter{slowWriter: newSlowWriter(out, 100*time.Millisecond), err: errAbort}
+		err := flushWithDeadline(w, out, 10*time.Millisecond)
+		require.ErrorIs(t, err, errFlushDeadline)
+		require.ErrorIs(t, err, errAbort)
+	})
+}
+
+func TestAbortableOutput(t *testing.T) {
+	t.Run("abort", func(t *testing.T) {
+		var buf bytes.Buffer
+		out := newAbortableOutput(&buf)
+
+		_, err := out.Write([]byte("a"))
+		require.NoError(t, err)
+
+		out.abort()
+		_, err = out.Write([]byte("b"))
+		require.ErrorIs(t, err, errOutputAborted)
+		require.Equal(t, "a", buf.String())
+	})
+
+	t.Run("discard_file", func(t *testing.T) {
+		f, err := os.Create(filepath.Join(t.TempDir(), "out"))
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = f.Close() })
+
+		out := newAbortableOutput(f)
+		_, err = out.Write([]byte("partial"))
+		require.NoError(t, err)
+		require.NoError(t, out.discard())
+
+		fi, err := f.Stat()
+		require.NoError(t, err)
+		require.Zero(t, fi.Size())
+	})
+
+	t.Run("discard_not_file", func(t *testing.T) {
+		require.ErrorIs(t, newAbortableOutput(io.Discard).discard(), errCannotDiscard)
+	})
+}
+
+type slowWriter struct {
+	out   io.Writer
+	done  chan struct{}
+	delay time.Duration
+}
+
+func newSlowWriter(out io.Writer, delay time.Duration) *slowWriter {
+	return &slowWriter{out: out, delay: delay, done: make(chan struct{})}
+}
+
+func (sw *slowWriter) write(proto.Message) error {
+	return nil
+}
+
+func (sw *slowWriter) flush() error {
+	defer close(sw.done)
+
+	time.Sleep(sw.delay)
+	_, err := sw.out.Write([]byte("flushed"))
+	return err
+}
+
+func (sw *slowWriter) flushed() bool {
+	select {
+	case <-sw.done:
+		return true
+	default:
+		return false
+	}
+}
+
+type abortingWriter struct {
+	*slowWriter
+	err     error
+	aborted bool
+}
+
+func (aw *abortingWriter) abort() error {
+	aw.aborted = true
+	return aw.err
+}
@@ -0,0 +1,180 @@
+// Copyright 2021-2022 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"go.uber.org/multierr"
+	"google.golang.org/protobuf/proto"
+
+	auditv1 "github.com/cerbos/cerbos/api/genpb/cerbos/audit/v1"
+	enginev1 "github.com/cerbos/cerbos/api/genpb/cerbos/engine/v1"
+)
+
+// sortableTimeFormat is a fixed-width time format that sorts lexicographically in chronological order.
+const sortableTimeFormat = "2006-01-02T15:04:05.000000000Z"
+
+// sortFields maps the field names accepted by --sort-by to functions that extract the sort key from an entry.
+var sortFields = map[string]func(auditLogEntry) string{
+	"callId": func(e auditLogEntry) string {
+		return e.GetCallId()
+	},
+	"timestamp": func(e auditLogEntry) string {
+		if ts := e.GetTimestamp(); ts != nil {
+			return ts.AsTime().UTC().Format(sortableTimeFormat)
+		}
+		return ""
+	},
+	"peer": func(e auditLogEntry) string {
+		switch le := e.(type) {
+		case *auditv1.AccessLogEntry:
+			return le.GetPeer().GetAddress()
+		case *auditv1.DecisionLogEntry:
+			return le.GetPeer().GetAddress()
+		default:
+			return ""
+		}
+	},
+	"method":    entryMethod,
+	"principal": entryPrincipal,
+	"resource": func(e auditLogEntry) string {
+		// the separator sorts before any other character so that entries are ordered by kind first
+		kind, id := entryResource(e)
+		return kind + "\x00" + id
+	},
+}
+
+func validateSortField(field string) error {
+	if _, ok := sortFields[field]; !ok {
+		return fmt.Errorf("unknown sort field %q: must be one of %s", field, strings.Join(sortFieldNames(), ", "))
+	}
+
+	return nil
+}
+
+func sortFieldNames() []string {
+	names := make([]string, 0, len(sortFields))
+	for name := range sortFields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// sortingWriter buffers all entries and writes them to the underlying writer in sorted order when flushed.
+type sortingWriter struct {
+	auditLogWriter
+	key     func(auditLogEntry) string
+	entries []auditLogEntry
+	desc    bool
+}
+
+func newSortingWriter(writer auditLogWriter, field string, desc bool) *sortingWriter {
+	return &sortingWriter{auditLogWriter: writer, key: sortFields[field], desc: desc}
+}
+
+func (sw *sortingWriter) write(entry proto.Message) error {
+	e, ok := entry.(auditLogEntry)
+	if !ok {
+		return fmt.Errorf("unexpected audit log entry type %T", entry)
+	}
+
+	sw.entries = append(sw.entries, e)
+	return nil
+}
+
+// emit sorts the buffered entries and writes them to the underlying writer.
+func (sw *sortingWriter) emit() error {
+	entries := sw.entries
+	sw.entries = nil
+
+	keys := make(map[auditLogEntry]string, len(entries))
+	for _, e := range entries {
+		keys[e] = sw.key(e)
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		if sw.desc {
+			return keys[entries[i]] > keys[entries[j]]
+		}
+		return keys[entries[i]] < keys[entries[j]]
+	})
+
+	for _, e := range entries {
+		if err := sw.auditLogWriter.write(e); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (sw *sortingWriter) flush() error {
+	return multierr.Combine(sw.emit(), sw.auditLogWriter.flush())
+}
+
+func entryMethod(e auditLogEntry) string {
+	switch le := e.(type) {
+	case *auditv1.AccessLogEntry:
+		return le.GetMethod()
+	case *auditv1.DecisionLogEntry:
+		if le.GetPlanResources() != nil {
+			return "PlanResources"
+		}
+		return "CheckResources"
+	default:
+		return ""
+	}
+}
+
+// entryPrincipal returns the ID of the principal of a decision log entry.
+func entryPrincipal(e auditLogEntry) string {
+	le, ok := e.(*auditv1.DecisionLogEntry)
+	if !ok {
+		return ""
+	}
+
+	if pr := le.GetPlanResources(); pr != nil {
+		return pr.GetInput().GetPrincipal().GetId()
+	}
+
+	if inputs := checkInputs(le); len(inputs) > 0 {
+		return inputs[0].GetPrincipal().GetId()
+	}
+
+	return ""
+}
+
+// entryResource returns the kind and ID of the first resource of a decision log entry.
+// Plan resources requests do not have a resource ID.
+func entryResource(e auditLogEntry) (kind, id string) {
+	le, ok := e.(*auditv1.DecisionLogEntry)
+	if !ok {
+		return "", ""
+	}
+
+	if pr := le.GetPlanResources(); pr != nil {
+		return pr.GetInput().GetResource().GetKind(), ""
+	}
+
+	if inputs := checkInputs(le); len(inputs) > 0 {
+		r := inputs[0].GetResource()
+		return r.GetKind(), r.GetId()
+	}
+
+	return "", ""
+}
+
+// checkInputs returns the check inputs of a decision log entry, falling back to the deprecated field used by older servers.
+func checkInputs(e *auditv1.DecisionLogEntry) []*enginev1.CheckInput {
+	if cr := e.GetCheckResources(); cr != nil {
+		return cr.GetInputs()
+	}
+
+	return e.GetInputs()
+}
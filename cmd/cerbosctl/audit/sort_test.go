@@ -0,0 +1,91 @@
+// Copyright 2021-2022 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	auditv1 "github.com/cerbos/cerbos/api/genpb/cerbos/audit/v1"
+	enginev1 "github.com/cerbos/cerbos/api/genpb/cerbos/engine/v1"
+)
+
+func TestSortingWriter(t *testing.T) {
+	mkEntry := func(callID, principal, kind, id string) *auditv1.DecisionLogEntry {
+		return &auditv1.DecisionLogEntry{
+			CallId: callID,
+			Method: &auditv1.DecisionLogEntry_CheckResources_{
+				CheckResources: &auditv1.DecisionLogEntry_CheckResources{
+					Inputs: []*enginev1.CheckInput{
+						{
+							Principal: &enginev1.Principal{Id: principal},
+							Resource:  &enginev1.Resource{Kind: kind, Id: id},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	entries := []*auditv1.DecisionLogEntry{
+		mkEntry("01GH0000000000000000000001", "harry", "leave_request", "XX125"),
+		mkEntry("01GH0000000000000000000002", "alicia", "purchase_order", "YY001"),
+		mkEntry("01GH0000000000000000000003", "harry", "leave_request", "XX100"),
+		mkEntry("01GH0000000000000000000004", "bob", "leave_request:v2", "XX001"),
+	}
+
+	testCases := []struct {
+		field string
+		want  []string
+		desc  bool
+	}{
+		{
+			field: "principal",
+			want:  []string{"01GH0000000000000000000002", "01GH0000000000000000000004", "01GH0000000000000000000001", "01GH0000000000000000000003"},
+		},
+		{
+			field: "principal",
+			desc:  true,
+			want:  []string{"01GH0000000000000000000001", "01GH0000000000000000000003", "01GH0000000000000000000004", "01GH0000000000000000000002"},
+		},
+		{
+			field: "resource",
+			want:  []string{"01GH0000000000000000000003", "01GH0000000000000000000001", "01GH0000000000000000000004", "01GH0000000000000000000002"},
+		},
+		{
+			field: "callId",
+			desc:  true,
+			want:  []string{"01GH0000000000000000000004", "01GH0000000000000000000003", "01GH0000000000000000000002", "01GH0000000000000000000001"},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		name := tc.field
+		if tc.desc {
+			name += "_desc"
+		}
+
+		t.Run(name, func(t *testing.T) {
+			cw := &collectingWriter{}
+			sw := newSortingWriter(cw, tc.field, tc.desc)
+			for _, e := range entries {
+				require.NoError(t, sw.write(e))
+			}
+
+			require.Empty(t, cw.callIDs(), "Entries should be buffered until emitted")
+			require.NoError(t, sw.emit())
+			require.Equal(t, tc.want, cw.callIDs())
+		})
+	}
+}
+
+func TestValidateSortField(t *testing.T) {
+	for _, field := range sortFieldNames() {
+		require.NoError(t, validateSortField(field))
+	}
+
+	require.Error(t, validateSortField("wibble"))
+}
@@ -0,0 +1,205 @@
+// Copyright 2021-2022 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	auditv1 "github.com/cerbos/cerbos/api/genpb/cerbos/audit/v1"
+)
+
+const summaryHourFormat = "2006-01-02T15:00Z07:00"
+
+// countEntry is the number of entries with a particular value.
+type countEntry struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+// summary is the aggregated view of the audit log entries.
+type summary struct {
+	Effects       map[string]int `json:"effects,omitempty"`
+	Methods       []countEntry   `json:"methods"`
+	Principals    []countEntry   `json:"principals,omitempty"`
+	ResourceKinds []countEntry   `json:"resourceKinds,omitempty"`
+	PerHour       []countEntry   `json:"perHour"`
+	Total         int            `json:"total"`
+}
+
+// summaryWriter aggregates the entries instead of writing them and writes the summary when flushed.
+type summaryWriter struct {
+	out           io.Writer
+	effects       map[string]int
+	methods       map[string]int
+	principals    map[string]int
+	resourceKinds map[string]int
+	perHour       map[string]int
+	top           int
+	total         int
+	asJSON        bool
+}
+
+func newSummaryWriter(out io.Writer, top int, asJSON bool) *summaryWriter {
+	return &summaryWriter{
+		out:           out,
+		effects:       make(map[string]int),
+		methods:       make(map[string]int),
+		principals:    make(map[string]int),
+		resourceKinds: make(map[string]int),
+		perHour:       make(map[string]int),
+		top:           top,
+		asJSON:        asJSON,
+	}
+}
+
+func (s *summaryWriter) write(entry proto.Message) error {
+	e, ok := entry.(auditLogEntry)
+	if !ok {
+		return fmt.Errorf("unexpected audit log entry type %T", entry)
+	}
+
+	s.total++
+	s.methods[entryMethod(e)]++
+	if ts := e.GetTimestamp(); ts != nil {
+		s.perHour[ts.AsTime().UTC().Truncate(time.Hour).Format(summaryHourFormat)]++
+	}
+
+	if de, ok := e.(*auditv1.DecisionLogEntry); ok {
+		s.addDecision(de)
+	}
+
+	return nil
+}
+
+func (s *summaryWriter) addDecision(e *auditv1.DecisionLogEntry) {
+	if p := entryPrincipal(e); p != "" {
+		s.principals[p]++
+	}
+
+	if pr := e.GetPlanResources(); pr != nil {
+		if kind := pr.GetInput().GetResource().GetKind(); kind != "" {
+			s.resourceKinds[kind]++
+		}
+		return
+	}
+
+	// count each resource kind once per entry
+	kinds := make(map[string]struct{})
+	for _, input := range checkInputs(e) {
+		if kind := input.GetResource().GetKind(); kind != "" {
+			kinds[kind] = struct{}{}
+		}
+	}
+
+	for kind := range kinds {
+		s.resourceKinds[kind]++
+	}
+
+	outputs := e.GetOutputs()
+	if cr := e.GetCheckResources(); cr != nil {
+		outputs = cr.GetOutputs()
+	}
+
+	for _, output := range outputs {
+		for _, ae := range output.GetActions() {
+			s.effects[ae.GetEffect().String()]++
+		}
+	}
+}
+
+func (s *summaryWriter) summary() summary {
+	sum := summary{
+		Total:         s.total,
+		Methods:       topCounts(s.methods, s.top),
+		Principals:    topCounts(s.principals, s.top),
+		ResourceKinds: topCounts(s.resourceKinds, s.top),
+		PerHour:       make([]countEntry, 0, len(s.perHour)),
+	}
+
+	if len(s.effects) > 0 {
+		sum.Effects = s.effects
+	}
+
+	for hour, count := range s.perHour {
+		sum.PerHour = append(sum.PerHour, countEntry{Value: hour, Count: count})
+	}
+
+	sort.Slice(sum.PerHour, func(i, j int) bool {
+		return sum.PerHour[i].Value < sum.PerHour[j].Value
+	})
+
+	return sum
+}
+
+func (s *summaryWriter) flush() error {
+	sum := s.summary()
+	if s.asJSON {
+		enc := json.NewEncoder(s.out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(sum)
+	}
+
+	tw := tabwriter.NewWriter(s.out, 0, 0, 2, ' ', 0) //nolint:gomnd
+	fmt.Fprintf(tw, "Total entries\t%d\n", sum.Total)
+
+	if len(sum.Effects) > 0 {
+		effects := make([]string, 0, len(sum.Effects))
+		for effect := range sum.Effects {
+			effects = append(effects, effect)
+		}
+		sort.Strings(effects)
+
+		fmt.Fprintln(tw, "\nEffect\tCount")
+		for _, effect := range effects {
+			fmt.Fprintf(tw, "%s\t%d\n", effect, sum.Effects[effect])
+		}
+	}
+
+	writeCountsTable(tw, fmt.Sprintf("Top %d methods", s.top), sum.Methods)
+	writeCountsTable(tw, fmt.Sprintf("Top %d principals", s.top), sum.Principals)
+	writeCountsTable(tw, fmt.Sprintf("Top %d resource kinds", s.top), sum.ResourceKinds)
+	writeCountsTable(tw, "Hour", sum.PerHour)
+
+	return tw.Flush()
+}
+
+func writeCountsTable(out io.Writer, title string, counts []countEntry) {
+	if len(counts) == 0 {
+		return
+	}
+
+	fmt.Fprintf(out, "\n%s\tCount\n", title)
+	for _, c := range counts {
+		fmt.Fprintf(out, "%s\t%d\n", c.Value, c.Count)
+	}
+}
+
+// topCounts returns the n values with the highest counts, in descending order of count.
+// Ties are broken by value to make the output stable.
+func topCounts(counts map[string]int, n int) []countEntry {
+	entries := make([]countEntry, 0, len(counts))
+	for value, count := range counts {
+		entries = append(entries, countEntry{Value: value, Count: count})
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Count != entries[j].Count {
+			return entries[i].Count > entries[j].Count
+		}
+		return entries[i].Value < entries[j].Value
+	})
+
+	if len(entries) > n {
+		entries = entries[:n]
+	}
+
+	return entries
+}
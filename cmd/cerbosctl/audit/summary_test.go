@@ -0,0 +1,109 @@
+// Copyright 2021-2022 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	auditv1 "github.com/cerbos/cerbos/api/genpb/cerbos/audit/v1"
+	effectv1 "github.com/cerbos/cerbos/api/genpb/cerbos/effect/v1"
+	enginev1 "github.com/cerbos/cerbos/api/genpb/cerbos/engine/v1"
+)
+
+func TestSummaryWriter(t *testing.T) {
+	ts := time.Date(2021, 7, 1, 10, 15, 0, 0, time.UTC)
+
+	decision := func(principal, kind string, effects map[string]effectv1.Effect, at time.Time) *auditv1.DecisionLogEntry {
+		actions := make(map[string]*enginev1.CheckOutput_ActionEffect, len(effects))
+		for action, effect := range effects {
+			actions[action] = &enginev1.CheckOutput_ActionEffect{Effect: effect}
+		}
+
+		return &auditv1.DecisionLogEntry{
+			Timestamp: timestamppb.New(at),
+			Method: &auditv1.DecisionLogEntry_CheckResources_{
+				CheckResources: &auditv1.DecisionLogEntry_CheckResources{
+					Inputs: []*enginev1.CheckInput{
+						{Principal: &enginev1.Principal{Id: principal}, Resource: &enginev1.Resource{Kind: kind, Id: "XX125"}},
+					},
+					Outputs: []*enginev1.CheckOutput{{ResourceId: "XX125", Actions: actions}},
+				},
+			},
+		}
+	}
+
+	t.Run("decision", func(t *testing.T) {
+		var buf bytes.Buffer
+		w := newSummaryWriter(&buf, 1, true)
+		require.NoError(t, w.write(decision("harry", "leave_request", map[string]effectv1.Effect{"view": effectv1.Effect_EFFECT_ALLOW, "approve": effectv1.Effect_EFFECT_DENY}, ts)))
+		require.NoError(t, w.write(decision("harry", "leave_request", map[string]effectv1.Effect{"view": effectv1.Effect_EFFECT_ALLOW}, ts.Add(time.Minute))))
+		require.NoError(t, w.write(decision("maggie", "purchase_order", map[string]effectv1.Effect{"view": effectv1.Effect_EFFECT_DENY}, ts.Add(time.Hour))))
+		require.NoError(t, w.flush())
+
+		var have summary
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &have))
+		require.Equal(t, summary{
+			Total:         3,
+			Effects:       map[string]int{"EFFECT_ALLOW": 2, "EFFECT_DENY": 2},
+			Methods:       []countEntry{{Value: "CheckResources", Count: 3}},
+			Principals:    []countEntry{{Value: "harry", Count: 2}},
+			ResourceKinds: []countEntry{{Value: "leave_request", Count: 2}},
+			PerHour: []countEntry{
+				{Value: "2021-07-01T10:00Z", Count: 2},
+				{Value: "2021-07-01T11:00Z", Count: 1},
+			},
+		}, have)
+	})
+
+	t.Run("access", func(t *testing.T) {
+		var buf bytes.Buffer
+		w := newSummaryWriter(&buf, 10, true)
+		require.NoError(t, w.write(&auditv1.AccessLogEntry{Timestamp: timestamppb.New(ts), Method: "/cerbos.svc.v1.CerbosService/CheckResources"}))
+		require.NoError(t, w.write(&auditv1.AccessLogEntry{Timestamp: timestamppb.New(ts), Method: "/cerbos.svc.v1.CerbosService/PlanResources"}))
+		require.NoError(t, w.write(&auditv1.AccessLogEntry{Timestamp: timestamppb.New(ts), Method: "/cerbos.svc.v1.CerbosService/CheckResources"}))
+		require.NoError(t, w.flush())
+
+		var have summary
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &have))
+		require.Equal(t, summary{
+			Total: 3,
+			Methods: []countEntry{
+				{Value: "/cerbos.svc.v1.CerbosService/CheckResources", Count: 2},
+				{Value: "/cerbos.svc.v1.CerbosService/PlanResources", Count: 1},
+			},
+			PerHour: []countEntry{{Value: "2021-07-01T10:00Z", Count: 3}},
+		}, have)
+	})
+
+	t.Run("table", func(t *testing.T) {
+		var buf bytes.Buffer
+		w := newSummaryWriter(&buf, 10, false)
+		require.NoError(t, w.write(decision("harry", "leave_request", map[string]effectv1.Effect{"view": effectv1.Effect_EFFECT_ALLOW}, ts)))
+		require.NoError(t, w.flush())
+
+		require.Equal(t, `Total entries  1
+
+Effect        Count
+EFFECT_ALLOW  1
+
+Top 10 methods  Count
+CheckResources  1
+
+Top 10 principals  Count
+harry              1
+
+Top 10 resource kinds  Count
+leave_request          1
+
+Hour               Count
+2021-07-01T10:00Z  1
+`, buf.String())
+	})
+}
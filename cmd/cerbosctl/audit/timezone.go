@@ -0,0 +1,65 @@
+// Copyright 2021-2022 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+)
+
+const localTimezone = "local"
+
+var timestampFieldRegexp = regexp.MustCompile(`("timestamp":\s*")([^"]*)(")`)
+
+// parseTimezone returns the location identified by the given IANA time zone name or `local`.
+// It returns nil if the name is empty, which means that timestamps are left in UTC.
+func parseTimezone(name string) (*time.Location, error) {
+	switch name {
+	case "":
+		return nil, nil
+	case localTimezone:
+		return time.Local, nil
+	default:
+		loc, err := time.LoadLocation(name)
+		if err != nil {
+			return nil, fmt.Errorf("invalid time zone %q: %w", name, err)
+		}
+		return loc, nil
+	}
+}
+
+// localizeTimestamps rewrites the values of the timestamp fields of the given protojson output to the given location.
+func localizeTimestamps(jsonBytes []byte, loc *time.Location) []byte {
+	if loc == nil {
+		return jsonBytes
+	}
+
+	return timestampFieldRegexp.ReplaceAllFunc(jsonBytes, func(match []byte) []byte {
+		parts := timestampFieldRegexp.FindSubmatch(match)
+		ts, err := time.Parse(time.RFC3339Nano, string(parts[2]))
+		if err != nil {
+			return match
+		}
+
+		out := make([]byte, 0, len(match))
+		out = append(out, parts[1]...)
+		out = ts.In(loc).AppendFormat(out, time.RFC3339Nano)
+		return append(out, parts[3]...)
+	})
+}
+
+// formatTimestamp formats the timestamp of the given entry as RFC3339 in the given location, or in UTC if the location is nil.
+func formatTimestamp(e auditLogEntry, loc *time.Location) string {
+	ts := e.GetTimestamp()
+	if ts == nil {
+		return ""
+	}
+
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	return ts.AsTime().In(loc).Format(time.RFC3339Nano)
+}
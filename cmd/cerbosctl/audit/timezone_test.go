@@ -0,0 +1,93 @@
+// Copyright 2021-2022 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	auditv1 "github.com/cerbos/cerbos/api/genpb/cerbos/audit/v1"
+)
+
+func TestParseTimezone(t *testing.T) {
+	loc, err := parseTimezone("")
+	require.NoError(t, err)
+	require.Nil(t, loc)
+
+	loc, err = parseTimezone("local")
+	require.NoError(t, err)
+	require.Equal(t, time.Local, loc)
+
+	loc, err = parseTimezone("Asia/Tokyo")
+	require.NoError(t, err)
+	require.Equal(t, "Asia/Tokyo", loc.String())
+
+	_, err = parseTimezone("Middle/Earth")
+	require.Error(t, err)
+}
+
+func TestLocalizeTimestamps(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name  string
+		input string
+		loc   *time.Location
+		want  string
+	}{
+		{
+			name:  "utc",
+			input: `{"callId":"01GH0000000000000000000001","timestamp":"2021-07-01T10:15:00Z"}`,
+			want:  `{"callId":"01GH0000000000000000000001","timestamp":"2021-07-01T10:15:00Z"}`,
+		},
+		{
+			name:  "compact",
+			input: `{"callId":"01GH0000000000000000000001","timestamp":"2021-07-01T10:15:00.5Z"}`,
+			loc:   tokyo,
+			want:  `{"callId":"01GH0000000000000000000001","timestamp":"2021-07-01T19:15:00.5+09:00"}`,
+		},
+		{
+			name:  "indented",
+			input: "{\n  \"timestamp\": \"2021-07-01T10:15:00Z\"\n}",
+			loc:   tokyo,
+			want:  "{\n  \"timestamp\": \"2021-07-01T19:15:00+09:00\"\n}",
+		},
+		{
+			name:  "not_a_timestamp",
+			input: `{"timestamp":"wibble"}`,
+			loc:   tokyo,
+			want:  `{"timestamp":"wibble"}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.want, string(localizeTimestamps([]byte(tc.input), tc.loc)))
+		})
+	}
+}
+
+func TestCSVAuditLogWriterTimezone(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	w := newAuditLogWriter(formatCSV, "access", "", tokyo, &buf)
+	require.NoError(t, w.write(&auditv1.AccessLogEntry{
+		CallId:    "01GH0000000000000000000001",
+		Timestamp: timestamppb.New(time.Date(2021, 7, 1, 0, 0, 0, 0, time.UTC)),
+		Method:    "/cerbos.svc.v1.CerbosService/CheckResources",
+	}))
+	require.NoError(t, w.flush())
+
+	require.Equal(t, `call_id,timestamp,peer,method,status_code
+01GH0000000000000000000001,2021-07-01T09:00:00+09:00,,/cerbos.svc.v1.CerbosService/CheckResources,0
+`, buf.String())
+}
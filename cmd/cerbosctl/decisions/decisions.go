@@ -59,19 +59,39 @@ func (c *Cmd) Run(k *kong.Kong, ctx *cmdclient.Context) error {
 	logOptions := c.AuditFilters.GenOptions()
 	logOptions.Type = client.DecisionLogs
 
-	entries, err := ctx.AdminClient.AuditLogs(context.Background(), logOptions)
+	// each call ID is looked up separately so that the entries are listed in the order requested
+	allOptions := []client.AuditLogOptions{logOptions}
+	if len(c.Lookup) > 1 {
+		allOptions = make([]client.AuditLogOptions, len(c.Lookup))
+		for i, id := range c.Lookup {
+			allOptions[i] = client.AuditLogOptions{Type: client.DecisionLogs, Lookup: id}
+		}
+	}
+
+	match, err := c.AuditFilters.DecisionFilter()
 	if err != nil {
 		return err
 	}
 
 	decisions := make([]*auditv1.DecisionLogEntry, 0)
-	for entry := range entries {
-		decisionEntry, err := entry.DecisionLog()
+	for _, opts := range allOptions {
+		entries, err := ctx.AdminClient.AuditLogs(context.Background(), opts)
 		if err != nil {
 			return err
 		}
 
-		decisions = append(decisions, decisionEntry)
+		for entry := range entries {
+			decisionEntry, err := entry.DecisionLog()
+			if err != nil {
+				return err
+			}
+
+			if match != nil && !match(decisionEntry) {
+				continue
+			}
+
+			decisions = append(decisions, decisionEntry)
+		}
 	}
 
 	ui := mkUI(decisions)
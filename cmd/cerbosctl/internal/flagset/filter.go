@@ -5,18 +5,28 @@ package flagset
 
 import (
 	"errors"
+	"fmt"
 	"time"
 
+	"github.com/gobwas/glob"
+
+	auditv1 "github.com/cerbos/cerbos/api/genpb/cerbos/audit/v1"
+	effectv1 "github.com/cerbos/cerbos/api/genpb/cerbos/effect/v1"
+	enginev1 "github.com/cerbos/cerbos/api/genpb/cerbos/engine/v1"
 	"github.com/cerbos/cerbos/client"
 )
 
 var errMoreThanOneFilter = errors.New("more than one filter specified: choose from either `tail`, `between`, `since` or `lookup`")
 
 type AuditFilters struct {
-	Lookup  string        `help:"View a specific record using the Cerbos Call ID"`
-	Between timerange     `help:"View records captured between two timestamps. The timestamps must be formatted as ISO-8601"`
-	Since   time.Duration `help:"View records from X hours/minutes/seconds ago to now. Unit suffixes are: h=hours, m=minutes s=seconds"`
-	Tail    uint16        `help:"View the last N records"`
+	Lookup    []string      `help:"View specific records using their Cerbos Call IDs. Can be repeated or given a comma-separated list"`
+	Principal string        `help:"Only view decision records for principals whose ID matches the given glob pattern"`
+	Resource  string        `help:"Only view decision records for resources whose kind or ID matches the given glob pattern"`
+	Action    string        `help:"Only view decision records for actions matching the given glob pattern"`
+	Effect    string        `help:"Only view decision records with at least one result with the given effect (allow or deny)"`
+	Between   timerange     `help:"View records captured between two timestamps. The timestamps must be formatted as ISO-8601. The end can also be a negative offset from now (e.g. -2h)"`
+	Since     time.Duration `help:"View records from X hours/minutes/seconds ago to now. Unit suffixes are: h=hours, m=minutes s=seconds"`
+	Tail      uint16        `help:"View the last N records"`
 }
 
 func (af *AuditFilters) Validate() error {
@@ -33,7 +43,7 @@ func (af *AuditFilters) Validate() error {
 		filterCount++
 	}
 
-	if af.Lookup != "" {
+	if len(af.Lookup) > 0 {
 		filterCount++
 	}
 
@@ -45,7 +55,147 @@ func (af *AuditFilters) Validate() error {
 		af.Tail = 30
 	}
 
-	return nil
+	_, err := af.DecisionFilter()
+	return err
+}
+
+// HasDecisionFilter returns true if any of the principal, resource, action or effect filters is set.
+func (af *AuditFilters) HasDecisionFilter() bool {
+	return af.Principal != "" || af.Resource != "" || af.Action != "" || af.Effect != ""
+}
+
+// DecisionFilter returns a function that reports whether a decision log entry matches the principal, resource, action and effect filters.
+// An entry matches if any of its inputs matches all the principal, resource and action filters that are set, and any of its
+// results has the effect set by the effect filter. The results don't have to belong to the matching inputs.
+// It returns nil if none of the filters are set.
+func (af *AuditFilters) DecisionFilter() (func(*auditv1.DecisionLogEntry) bool, error) {
+	if !af.HasDecisionFilter() {
+		return nil, nil
+	}
+
+	effect, err := parseEffect(af.Effect)
+	if err != nil {
+		return nil, err
+	}
+
+	principal, err := compileGlob("principal", af.Principal)
+	if err != nil {
+		return nil, err
+	}
+
+	resource, err := compileGlob("resource", af.Resource)
+	if err != nil {
+		return nil, err
+	}
+
+	action, err := compileGlob("action", af.Action)
+	if err != nil {
+		return nil, err
+	}
+
+	matches := func(principalID, kind, id string, actions []string) bool {
+		if principal != nil && !principal.Match(principalID) {
+			return false
+		}
+
+		if resource != nil && !resource.Match(kind) && (id == "" || !resource.Match(id)) {
+			return false
+		}
+
+		if action == nil {
+			return true
+		}
+
+		for _, a := range actions {
+			if action.Match(a) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	return func(e *auditv1.DecisionLogEntry) bool {
+		if effect != effectv1.Effect_EFFECT_UNSPECIFIED && !hasEffect(e, effect) {
+			return false
+		}
+
+		if pr := e.GetPlanResources(); pr != nil {
+			input := pr.GetInput()
+			return matches(input.GetPrincipal().GetId(), input.GetResource().GetKind(), "", []string{input.GetAction()})
+		}
+
+		inputs := e.GetInputs()
+		if cr := e.GetCheckResources(); cr != nil {
+			inputs = cr.GetInputs()
+		}
+
+		for _, input := range inputs {
+			if matches(input.GetPrincipal().GetId(), input.GetResource().GetKind(), input.GetResource().GetId(), input.GetActions()) {
+				return true
+			}
+		}
+
+		return false
+	}, nil
+}
+
+func parseEffect(effect string) (effectv1.Effect, error) {
+	switch effect {
+	case "":
+		return effectv1.Effect_EFFECT_UNSPECIFIED, nil
+	case "allow":
+		return effectv1.Effect_EFFECT_ALLOW, nil
+	case "deny":
+		return effectv1.Effect_EFFECT_DENY, nil
+	default:
+		return effectv1.Effect_EFFECT_UNSPECIFIED, fmt.Errorf("invalid --effect %q: must be allow or deny", effect)
+	}
+}
+
+// hasEffect returns true if any of the results of the decision log entry has the given effect.
+// Plans are matched by the kind of their filter, and conditional plans match both effects because they can go either way.
+func hasEffect(e *auditv1.DecisionLogEntry, effect effectv1.Effect) bool {
+	if pr := e.GetPlanResources(); pr != nil {
+		switch pr.GetOutput().GetFilter().GetKind() {
+		case enginev1.PlanResourcesFilter_KIND_ALWAYS_ALLOWED:
+			return effect == effectv1.Effect_EFFECT_ALLOW
+		case enginev1.PlanResourcesFilter_KIND_ALWAYS_DENIED:
+			return effect == effectv1.Effect_EFFECT_DENY
+		case enginev1.PlanResourcesFilter_KIND_CONDITIONAL:
+			return true
+		default:
+			return false
+		}
+	}
+
+	outputs := e.GetOutputs()
+	if cr := e.GetCheckResources(); cr != nil {
+		outputs = cr.GetOutputs()
+	}
+
+	for _, output := range outputs {
+		for _, ae := range output.GetActions() {
+			if ae.GetEffect() == effect {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func compileGlob(flag, pattern string) (glob.Glob, error) {
+	if pattern == "" {
+		return nil, nil
+	}
+
+	g, err := glob.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --%s pattern %q: %w", flag, pattern, err)
+	}
+
+	return g, nil
 }
 
 func (af *AuditFilters) GenOptions() client.AuditLogOptions {
@@ -64,9 +214,9 @@ func (af *AuditFilters) GenOptions() client.AuditLogOptions {
 			StartTime: time.Now().Add(time.Duration(-1) * af.Since),
 			EndTime:   time.Now(),
 		}
-	case af.Lookup != "":
+	case len(af.Lookup) > 0:
 		return client.AuditLogOptions{
-			Lookup: af.Lookup,
+			Lookup: af.Lookup[0],
 		}
 	default:
 		return client.AuditLogOptions{}
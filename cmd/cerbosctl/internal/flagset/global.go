@@ -9,6 +9,7 @@ type Globals struct {
 	Server        string `help:"Address of the Cerbos server" env:"CERBOS_SERVER" default:"localhost:3593"`
 	Username      string `help:"Admin username" env:"CERBOS_USERNAME"`
 	Password      string `help:"Admin password" env:"CERBOS_PASSWORD"`
+	Config        string `help:"Path to the cerbosctl configuration file. Defaults to $XDG_CONFIG_HOME/cerbosctl/config.yaml" env:"CERBOSCTL_CONFIG" type:"path"`
 	CaCert        string `help:"Path to the CA certificate for verifying server identity"`
 	TLSClientCert string `name:"client-cert" help:"Path to the TLS client certificate"`
 	TLSClientKey  string `name:"client-key" help:"Path to the TLS client key"`
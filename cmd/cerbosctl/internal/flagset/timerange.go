@@ -24,6 +24,12 @@ func (t *timerange) Decode(ctx *kong.DecodeContext) error {
 		return err
 	}
 
+	return t.parse(tr, time.Now())
+}
+
+// parse parses a time range made of an ISO-8601 start timestamp and an optional end.
+// The end can be an ISO-8601 timestamp or a negative offset (such as -2h) from now. If it is left out, it defaults to now.
+func (t *timerange) parse(tr string, now time.Time) error {
 	r := csv.NewReader(strings.NewReader(tr))
 	parts, err := r.Read()
 	if err != nil {
@@ -34,24 +40,45 @@ func (t *timerange) Decode(ctx *kong.DecodeContext) error {
 		return fmt.Errorf("invalid time range [%s]", tr)
 	}
 
-	t.Values = make([]*timestamppb.Timestamp, 2) //nolint:gomnd
+	start, err := time.Parse(time.RFC3339, parts[0])
+	if err != nil {
+		return fmt.Errorf("invalid timestamp [%s]: %w", parts[0], err)
+	}
 
-	for i := 0; i < len(parts); i++ {
-		parsedTime, err := time.Parse(time.RFC3339, parts[i])
-		if err != nil {
-			return fmt.Errorf("invalid timestamp [%s]: %w", parts[i], err)
+	// default to current time if only one timestamp value is provided
+	end := now
+	if len(parts) == 2 { //nolint:gomnd
+		if end, err = parseEnd(parts[1], now); err != nil {
+			return err
 		}
-		t.Values[i] = timestamppb.New(parsedTime)
 	}
 
-	// default to current time if only one timestamp value is provided
-	if len(parts) == 1 {
-		t.Values[1] = timestamppb.Now()
+	if end.Before(start) {
+		return fmt.Errorf("invalid time range [%s]: end is before start", tr)
 	}
 
+	t.Values = []*timestamppb.Timestamp{timestamppb.New(start), timestamppb.New(end)}
 	return nil
 }
 
+func parseEnd(value string, now time.Time) (time.Time, error) {
+	if !strings.HasPrefix(value, "-") {
+		end, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid timestamp [%s]: %w", value, err)
+		}
+
+		return end, nil
+	}
+
+	offset, err := time.ParseDuration(value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid relative end [%s]: %w", value, err)
+	}
+
+	return now.Add(offset), nil
+}
+
 func (t timerange) IsSet() bool {
 	return len(t.Values) > 0
 }
@@ -0,0 +1,50 @@
+// Copyright 2021-2022 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package flagset
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimerangeParse(t *testing.T) {
+	now := time.Date(2021, 7, 3, 12, 0, 0, 0, time.UTC)
+	start := time.Date(2021, 7, 1, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name    string
+		input   string
+		wantEnd time.Time
+		wantErr bool
+	}{
+		{name: "absolute", input: "2021-07-01T00:00:00Z,2021-07-02T00:00:00Z", wantEnd: time.Date(2021, 7, 2, 0, 0, 0, 0, time.UTC)},
+		{name: "start_only", input: "2021-07-01T00:00:00Z", wantEnd: now},
+		{name: "relative_end", input: "2021-07-01T00:00:00Z,-2h", wantEnd: now.Add(-2 * time.Hour)},
+		{name: "relative_end_compound", input: "2021-07-01T00:00:00Z,-1h30m", wantEnd: now.Add(-90 * time.Minute)},
+		{name: "relative_end_before_start", input: "2021-07-01T00:00:00Z,-72h", wantErr: true},
+		{name: "end_before_start", input: "2021-07-02T00:00:00Z,2021-07-01T00:00:00Z", wantErr: true},
+		{name: "invalid_relative_end", input: "2021-07-01T00:00:00Z,-2x", wantErr: true},
+		{name: "relative_start", input: "-2h,2021-07-02T00:00:00Z", wantErr: true},
+		{name: "too_many_parts", input: "2021-07-01T00:00:00Z,-2h,-1h", wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			var tr timerange
+			err := tr.parse(tc.input, now)
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.True(t, tr.IsSet())
+			require.True(t, start.Equal(tr.Values[0].AsTime()))
+			require.True(t, tc.wantEnd.Equal(tr.Values[1].AsTime()))
+		})
+	}
+}
@@ -0,0 +1,42 @@
+#!/usr/bin/env python3
+# Copyright 2021-2022 Zenauth Ltd.
+# SPDX-License-Identifier: Apache-2.0
+
+# Checks that golden.parquet can be read by pyarrow and contains the rows written by TestWriterGolden.
+# Usage: pip install pyarrow && python3 verify_golden.py
+
+import datetime
+import os
+
+import pyarrow as pa
+import pyarrow.parquet as pq
+
+path = os.path.join(os.path.dirname(os.path.abspath(__file__)), "golden.parquet")
+
+metadata = pq.read_metadata(path)
+assert metadata.num_rows == 5, metadata.num_rows
+assert metadata.num_row_groups == 3, metadata.num_row_groups
+assert metadata.created_by == "cerbosctl", metadata.created_by
+
+table = pq.read_table(path)
+assert table.column_names == ["id", "ts", "note", "data"], table.column_names
+assert table.schema.field("ts").type == pa.timestamp("ms", tz="UTC"), table.schema.field("ts").type
+assert not table.schema.field("id").nullable
+assert table.schema.field("note").nullable
+
+ts = datetime.datetime(2022, 10, 1, 12, 0, 0, tzinfo=datetime.timezone.utc)
+want = [
+    {"id": "a", "ts": ts, "note": "first", "data": '{"x":1}'},
+    {"id": "b", "ts": ts + datetime.timedelta(seconds=1), "note": None, "data": '{"x":2}'},
+    {"id": "c", "ts": ts + datetime.timedelta(seconds=2), "note": None, "data": '{"x":3}'},
+    {"id": "d", "ts": ts + datetime.timedelta(seconds=3), "note": "last", "data": '{"x":4}'},
+    {"id": "e", "ts": ts + datetime.timedelta(seconds=4), "note": "extra", "data": '{"x":5}'},
+]
+have = table.to_pylist()
+# depending on the pyarrow version, the JSON column is read as a string or as raw bytes
+for row in have:
+    if isinstance(row["data"], bytes):
+        row["data"] = row["data"].decode()
+assert have == want, have
+
+print("OK")
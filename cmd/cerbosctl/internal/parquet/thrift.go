@@ -0,0 +1,122 @@
+// Copyright 2021-2022 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package parquet
+
+import (
+	"bytes"
+	"encoding/binary"
+)
+
+// Thrift compact protocol type identifiers.
+const (
+	thriftI32    = 5
+	thriftI64    = 6
+	thriftBinary = 8
+	thriftList   = 9
+	thriftStruct = 12
+)
+
+const (
+	maxShortListSize = 15
+	maxFieldDelta    = 15
+	// headerShift is the position of the field delta or the list size in the header byte, which holds the type in the lower 4 bits.
+	headerShift    = 4
+	longListHeader = 0xf0
+)
+
+// thriftWriter is a minimal encoder for the Thrift compact protocol, which is used by the Parquet metadata structures.
+type thriftWriter struct {
+	lastField []int16
+	buf       bytes.Buffer
+}
+
+func newThriftWriter() *thriftWriter {
+	return &thriftWriter{lastField: []int16{0}}
+}
+
+func (tw *thriftWriter) fieldHeader(id int16, typ byte) {
+	last := tw.lastField[len(tw.lastField)-1]
+	if delta := id - last; delta > 0 && delta <= maxFieldDelta {
+		tw.buf.WriteByte(byte(delta)<<headerShift | typ)
+	} else {
+		tw.buf.WriteByte(typ)
+		tw.varint(int64(id))
+	}
+	tw.lastField[len(tw.lastField)-1] = id
+}
+
+func (tw *thriftWriter) varint(v int64) {
+	var b [binary.MaxVarintLen64]byte
+	n := binary.PutVarint(b[:], v) // zigzag encoding
+	tw.buf.Write(b[:n])
+}
+
+func (tw *thriftWriter) uvarint(v uint64) {
+	var b [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(b[:], v)
+	tw.buf.Write(b[:n])
+}
+
+func (tw *thriftWriter) i32Field(id int16, v int32) {
+	tw.fieldHeader(id, thriftI32)
+	tw.varint(int64(v))
+}
+
+func (tw *thriftWriter) i64Field(id int16, v int64) {
+	tw.fieldHeader(id, thriftI64)
+	tw.varint(v)
+}
+
+func (tw *thriftWriter) stringField(id int16, v string) {
+	tw.fieldHeader(id, thriftBinary)
+	tw.str(v)
+}
+
+func (tw *thriftWriter) str(v string) {
+	tw.uvarint(uint64(len(v)))
+	tw.buf.WriteString(v)
+}
+
+func (tw *thriftWriter) listHeader(id int16, elemType byte, size int) {
+	tw.fieldHeader(id, thriftList)
+	if size < maxShortListSize {
+		tw.buf.WriteByte(byte(size)<<headerShift | elemType)
+		return
+	}
+
+	tw.buf.WriteByte(longListHeader | elemType)
+	tw.uvarint(uint64(size))
+}
+
+func (tw *thriftWriter) i32ListField(id int16, values []int32) {
+	tw.listHeader(id, thriftI32, len(values))
+	for _, v := range values {
+		tw.varint(int64(v))
+	}
+}
+
+func (tw *thriftWriter) stringListField(id int16, values []string) {
+	tw.listHeader(id, thriftBinary, len(values))
+	for _, v := range values {
+		tw.str(v)
+	}
+}
+
+// structField writes a nested struct as the given field.
+func (tw *thriftWriter) structField(id int16, fn func()) {
+	tw.fieldHeader(id, thriftStruct)
+	tw.structBody(fn)
+}
+
+// structBody writes a struct without a field header, as required for top-level structs and list elements.
+func (tw *thriftWriter) structBody(fn func()) {
+	tw.lastField = append(tw.lastField, 0)
+	fn()
+	tw.buf.WriteByte(0) // stop
+	tw.lastField = tw.lastField[:len(tw.lastField)-1]
+}
+
+func (tw *thriftWriter) bytes() []byte {
+	return tw.buf.Bytes()
+}
@@ -0,0 +1,368 @@
+// Copyright 2021-2022 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package parquet implements a minimal writer for Parquet files with a flat schema.
+// Data is written uncompressed using the PLAIN encoding, which is readable by all Parquet implementations.
+package parquet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+const (
+	magic               = "PAR1"
+	createdBy           = "cerbosctl"
+	DefaultRowGroupSize = 10_000
+
+	int32Size = 4
+	int64Size = 8
+)
+
+// Parquet physical types, converted types, repetition types and encodings used by this writer.
+const (
+	typeInt64     int32 = 2
+	typeByteArray int32 = 6
+
+	convertedUTF8            int32 = 0
+	convertedTimestampMillis int32 = 9
+	convertedJSON            int32 = 19
+
+	repetitionRequired int32 = 0
+	repetitionOptional int32 = 1
+
+	encodingPlain int32 = 0
+	encodingRLE   int32 = 3
+
+	codecUncompressed int32 = 0
+	pageTypeData      int32 = 0
+)
+
+var ErrClosed = errors.New("parquet writer is closed")
+
+type ColumnType int
+
+const (
+	// String is a UTF-8 string column.
+	String ColumnType = iota
+	// JSON is a string column containing JSON documents.
+	JSON
+	// Timestamp is a column of timestamps with millisecond precision.
+	Timestamp
+)
+
+// Column describes a column of the file schema.
+type Column struct {
+	Name     string
+	Type     ColumnType
+	Optional bool
+}
+
+// Writer writes rows to a Parquet file.
+// Rows are buffered in memory and written out as a row group whenever the configured row group size is reached.
+// Close must be called to write the file footer.
+type Writer struct {
+	out          io.Writer
+	columns      []Column
+	values       [][]any
+	rowGroups    []rowGroup
+	offset       int64
+	numRows      int64
+	rowGroupSize int
+	closed       bool
+}
+
+type rowGroup struct {
+	chunks        []columnChunk
+	totalByteSize int64
+	numRows       int64
+}
+
+type columnChunk struct {
+	dataPageOffset int64
+	size           int64
+	numValues      int64
+}
+
+// NewWriter creates a writer that writes a file with the given schema to out.
+func NewWriter(out io.Writer, columns []Column, rowGroupSize int) *Writer {
+	if rowGroupSize <= 0 {
+		rowGroupSize = DefaultRowGroupSize
+	}
+
+	return &Writer{
+		out:          out,
+		columns:      columns,
+		values:       make([][]any, len(columns)),
+		rowGroupSize: rowGroupSize,
+	}
+}
+
+// WriteRow adds a row to the file. Values must be in the same order as the columns.
+// String and JSON columns accept string values, Timestamp columns accept time.Time values and optional columns accept nil.
+func (w *Writer) WriteRow(row ...any) error {
+	if w.closed {
+		return ErrClosed
+	}
+
+	if len(row) != len(w.columns) {
+		return fmt.Errorf("expected %d values but got %d", len(w.columns), len(row))
+	}
+
+	for i, v := range row {
+		if err := w.columns[i].check(v); err != nil {
+			return err
+		}
+	}
+
+	if w.offset == 0 {
+		if err := w.write([]byte(magic)); err != nil {
+			return err
+		}
+	}
+
+	for i, v := range row {
+		w.values[i] = append(w.values[i], v)
+	}
+
+	if len(w.values[0]) >= w.rowGroupSize {
+		return w.writeRowGroup()
+	}
+
+	return nil
+}
+
+// Close writes any buffered rows and the file footer. The underlying writer is not closed.
+func (w *Writer) Close() error {
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+
+	if w.offset == 0 {
+		if err := w.write([]byte(magic)); err != nil {
+			return err
+		}
+	}
+
+	if len(w.columns) > 0 && len(w.values[0]) > 0 {
+		if err := w.writeRowGroup(); err != nil {
+			return err
+		}
+	}
+
+	footer := w.fileMetadata()
+	if err := w.write(footer); err != nil {
+		return err
+	}
+
+	var footerLen [int32Size]byte
+	binary.LittleEndian.PutUint32(footerLen[:], uint32(len(footer)))
+	if err := w.write(footerLen[:]); err != nil {
+		return err
+	}
+
+	return w.write([]byte(magic))
+}
+
+func (w *Writer) write(b []byte) error {
+	n, err := w.out.Write(b)
+	w.offset += int64(n)
+	return err
+}
+
+func (w *Writer) writeRowGroup() error {
+	rg := rowGroup{numRows: int64(len(w.values[0])), chunks: make([]columnChunk, len(w.columns))}
+	for i, col := range w.columns {
+		page := col.encode(w.values[i])
+		header := pageHeader(len(page), len(w.values[i]))
+
+		rg.chunks[i] = columnChunk{dataPageOffset: w.offset, size: int64(len(header) + len(page)), numValues: int64(len(w.values[i]))}
+		rg.totalByteSize += rg.chunks[i].size
+
+		if err := w.write(header); err != nil {
+			return err
+		}
+
+		if err := w.write(page); err != nil {
+			return err
+		}
+
+		w.values[i] = w.values[i][:0]
+	}
+
+	w.rowGroups = append(w.rowGroups, rg)
+	w.numRows += rg.numRows
+	return nil
+}
+
+func (w *Writer) fileMetadata() []byte {
+	tw := newThriftWriter()
+	tw.structBody(func() {
+		tw.i32Field(1, 1) // version
+
+		tw.listHeader(2, thriftStruct, len(w.columns)+1) // schema
+		tw.structBody(func() {
+			tw.stringField(4, "schema")
+			tw.i32Field(5, int32(len(w.columns)))
+		})
+		for _, col := range w.columns {
+			col := col
+			tw.structBody(func() {
+				physicalType, convertedType := col.types()
+				tw.i32Field(1, physicalType)
+				repetition := repetitionRequired
+				if col.Optional {
+					repetition = repetitionOptional
+				}
+				tw.i32Field(3, repetition)
+				tw.stringField(4, col.Name)
+				tw.i32Field(6, convertedType)
+			})
+		}
+
+		tw.i64Field(3, w.numRows)
+
+		tw.listHeader(4, thriftStruct, len(w.rowGroups)) // row groups
+		for _, rg := range w.rowGroups {
+			rg := rg
+			tw.structBody(func() {
+				tw.listHeader(1, thriftStruct, len(rg.chunks))
+				for i, chunk := range rg.chunks {
+					col, chunk := w.columns[i], chunk
+					tw.structBody(func() {
+						tw.i64Field(2, chunk.dataPageOffset) // file_offset
+						tw.structField(3, func() {
+							physicalType, _ := col.types()
+							tw.i32Field(1, physicalType)
+							tw.i32ListField(2, []int32{encodingPlain, encodingRLE})
+							tw.stringListField(3, []string{col.Name})
+							tw.i32Field(4, codecUncompressed)
+							tw.i64Field(5, chunk.numValues)
+							tw.i64Field(6, chunk.size) // total_uncompressed_size
+							tw.i64Field(7, chunk.size) // total_compressed_size
+							tw.i64Field(9, chunk.dataPageOffset)
+						})
+					})
+				}
+				tw.i64Field(2, rg.totalByteSize)
+				tw.i64Field(3, rg.numRows)
+			})
+		}
+
+		tw.stringField(6, createdBy)
+	})
+
+	return tw.bytes()
+}
+
+func pageHeader(pageSize, numValues int) []byte {
+	tw := newThriftWriter()
+	tw.structBody(func() {
+		tw.i32Field(1, pageTypeData)
+		tw.i32Field(2, int32(pageSize)) // uncompressed_page_size
+		tw.i32Field(3, int32(pageSize)) // compressed_page_size
+		tw.structField(5, func() {
+			tw.i32Field(1, int32(numValues))
+			tw.i32Field(2, encodingPlain)
+			tw.i32Field(3, encodingRLE) // definition levels
+			tw.i32Field(4, encodingRLE) // repetition levels
+		})
+	})
+
+	return tw.bytes()
+}
+
+func (c Column) types() (physicalType, convertedType int32) {
+	switch c.Type {
+	case Timestamp:
+		return typeInt64, convertedTimestampMillis
+	case JSON:
+		return typeByteArray, convertedJSON
+	default:
+		return typeByteArray, convertedUTF8
+	}
+}
+
+func (c Column) check(v any) error {
+	if v == nil {
+		if !c.Optional {
+			return fmt.Errorf("column %q is required", c.Name)
+		}
+		return nil
+	}
+
+	switch c.Type {
+	case Timestamp:
+		if _, ok := v.(time.Time); !ok {
+			return fmt.Errorf("column %q expects a time.Time value but got %T", c.Name, v)
+		}
+	default:
+		if _, ok := v.(string); !ok {
+			return fmt.Errorf("column %q expects a string value but got %T", c.Name, v)
+		}
+	}
+
+	return nil
+}
+
+// encode encodes the values of a single column as a data page (without the header).
+// Optional columns are prefixed with the definition levels. There are no repetition levels because the schema is flat.
+func (c Column) encode(values []any) []byte {
+	var page bytes.Buffer
+	if c.Optional {
+		levels := definitionLevels(values)
+		var levelsLen [int32Size]byte
+		binary.LittleEndian.PutUint32(levelsLen[:], uint32(len(levels)))
+		page.Write(levelsLen[:])
+		page.Write(levels)
+	}
+
+	var buf [int64Size]byte
+	for _, v := range values {
+		switch val := v.(type) {
+		case nil:
+			continue
+		case time.Time:
+			binary.LittleEndian.PutUint64(buf[:], uint64(val.UnixMilli()))
+			page.Write(buf[:int64Size])
+		case string:
+			binary.LittleEndian.PutUint32(buf[:], uint32(len(val)))
+			page.Write(buf[:int32Size])
+			page.WriteString(val)
+		}
+	}
+
+	return page.Bytes()
+}
+
+// definitionLevels encodes the definition levels of an optional column using RLE runs of the RLE/bit-packing hybrid encoding.
+// With a bit width of 1, each run is the varint-encoded run length shifted left by one, followed by a single byte holding the value.
+func definitionLevels(values []any) []byte {
+	var buf bytes.Buffer
+	var varint [binary.MaxVarintLen64]byte
+
+	for i := 0; i < len(values); {
+		defined := values[i] != nil
+		j := i + 1
+		for j < len(values) && (values[j] != nil) == defined {
+			j++
+		}
+
+		n := binary.PutUvarint(varint[:], uint64(j-i)<<1)
+		buf.Write(varint[:n])
+		if defined {
+			buf.WriteByte(1)
+		} else {
+			buf.WriteByte(0)
+		}
+
+		i = j
+	}
+
+	return buf.Bytes()
+}
@@ -0,0 +1,200 @@
+// Copyright 2021-2022 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package parquet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriter(t *testing.T) {
+	columns := []Column{
+		{Name: "id", Type: String},
+		{Name: "ts", Type: Timestamp},
+		{Name: "note", Type: String, Optional: true},
+		{Name: "data", Type: JSON},
+	}
+
+	now := time.Now()
+	rows := [][]any{
+		{"a", now, "first", `{"x":1}`},
+		{"b", now.Add(time.Second), nil, `{"x":2}`},
+		{"c", now.Add(2 * time.Second), nil, `{"x":3}`},
+		{"d", now.Add(3 * time.Second), "last", `{"x":4}`},
+		{"e", now.Add(4 * time.Second), "extra", `{"x":5}`},
+	}
+
+	var out bytes.Buffer
+	w := NewWriter(&out, columns, 2)
+	for _, row := range rows {
+		require.NoError(t, w.WriteRow(row...))
+	}
+	require.NoError(t, w.Close())
+	require.NoError(t, w.Close(), "Close should be idempotent")
+	require.ErrorIs(t, w.WriteRow(rows[0]...), ErrClosed)
+
+	file := out.Bytes()
+	require.Equal(t, magic, string(file[:4]))
+	require.Equal(t, magic, string(file[len(file)-4:]))
+
+	footerLen := int(binary.LittleEndian.Uint32(file[len(file)-8 : len(file)-4]))
+	footer := file[len(file)-8-footerLen : len(file)-8]
+	meta := readStruct(t, bytes.NewReader(footer))
+
+	require.Equal(t, int64(1), meta[1])
+	require.Equal(t, int64(len(rows)), meta[3])
+	require.Len(t, meta[2], len(columns)+1)
+	require.Equal(t, "cerbosctl", meta[6])
+
+	rowGroups, ok := meta[4].([]any)
+	require.True(t, ok)
+	require.Len(t, rowGroups, 3)
+
+	wantRows := []int64{2, 2, 1}
+	for i, rg := range rowGroups {
+		rgFields, ok := rg.(map[int16]any)
+		require.True(t, ok)
+		require.Equal(t, wantRows[i], rgFields[3])
+
+		chunks, ok := rgFields[1].([]any)
+		require.True(t, ok)
+		require.Len(t, chunks, len(columns))
+
+		for _, chunk := range chunks {
+			chunkMeta, ok := chunk.(map[int16]any)[3].(map[int16]any)
+			require.True(t, ok)
+
+			// the page header at the data page offset must describe the same number of values
+			offset, ok := chunkMeta[9].(int64)
+			require.True(t, ok)
+			pageHeader := readStruct(t, bytes.NewReader(file[offset:]))
+			dataPageHeader, ok := pageHeader[5].(map[int16]any)
+			require.True(t, ok)
+			require.Equal(t, chunkMeta[5], dataPageHeader[1])
+		}
+	}
+}
+
+var updateGolden = flag.Bool("updateGolden", false, "Update the golden values for the tests")
+
+// TestWriterGolden compares the writer output with testdata/golden.parquet.
+// testdata/verify_golden.py checks that pyarrow reads the golden file back correctly, so re-run it whenever the file is regenerated.
+func TestWriterGolden(t *testing.T) {
+	columns := []Column{
+		{Name: "id", Type: String},
+		{Name: "ts", Type: Timestamp},
+		{Name: "note", Type: String, Optional: true},
+		{Name: "data", Type: JSON},
+	}
+
+	ts := time.Date(2022, 10, 1, 12, 0, 0, 0, time.UTC)
+	rows := [][]any{
+		{"a", ts, "first", `{"x":1}`},
+		{"b", ts.Add(time.Second), nil, `{"x":2}`},
+		{"c", ts.Add(2 * time.Second), nil, `{"x":3}`},
+		{"d", ts.Add(3 * time.Second), "last", `{"x":4}`},
+		{"e", ts.Add(4 * time.Second), "extra", `{"x":5}`},
+	}
+
+	var out bytes.Buffer
+	w := NewWriter(&out, columns, 2)
+	for _, row := range rows {
+		require.NoError(t, w.WriteRow(row...))
+	}
+	require.NoError(t, w.Close())
+
+	goldenFile := filepath.Join("testdata", "golden.parquet")
+	// go test -v ./cmd/cerbosctl/internal/parquet/... --args -updateGolden
+	if *updateGolden {
+		require.NoError(t, os.WriteFile(goldenFile, out.Bytes(), 0o600))
+	}
+
+	want, err := os.ReadFile(goldenFile)
+	require.NoError(t, err)
+	require.Equal(t, want, out.Bytes())
+}
+
+func TestWriterValidation(t *testing.T) {
+	w := NewWriter(&bytes.Buffer{}, []Column{{Name: "id", Type: String}, {Name: "ts", Type: Timestamp}}, 0)
+
+	require.Error(t, w.WriteRow("a"))
+	require.Error(t, w.WriteRow(nil, time.Now()))
+	require.Error(t, w.WriteRow("a", "not a time"))
+	require.NoError(t, w.WriteRow("a", time.Now()))
+}
+
+func TestDefinitionLevels(t *testing.T) {
+	have := definitionLevels([]any{"a", "b", nil, "c"})
+	require.Equal(t, []byte{2 << 1, 1, 1 << 1, 0, 1 << 1, 1}, have)
+}
+
+// readStruct decodes a Thrift compact protocol struct into a map of field IDs to values.
+// Integers are returned as int64, binaries as strings, lists as []any and structs as map[int16]any.
+func readStruct(t *testing.T, r *bytes.Reader) map[int16]any {
+	t.Helper()
+
+	fields := make(map[int16]any)
+	var lastID int16
+	for {
+		header, err := r.ReadByte()
+		require.NoError(t, err)
+		if header == 0 {
+			return fields
+		}
+
+		typ := header & 0x0f
+		if delta := int16(header >> 4); delta != 0 {
+			lastID += delta
+		} else {
+			id, err := binary.ReadVarint(r)
+			require.NoError(t, err)
+			lastID = int16(id)
+		}
+
+		fields[lastID] = readValue(t, r, typ)
+	}
+}
+
+func readValue(t *testing.T, r *bytes.Reader, typ byte) any {
+	t.Helper()
+
+	switch typ {
+	case thriftI32, thriftI64:
+		v, err := binary.ReadVarint(r)
+		require.NoError(t, err)
+		return v
+	case thriftBinary:
+		n, err := binary.ReadUvarint(r)
+		require.NoError(t, err)
+		b := make([]byte, n)
+		_, err = r.Read(b)
+		require.NoError(t, err)
+		return string(b)
+	case thriftList:
+		header, err := r.ReadByte()
+		require.NoError(t, err)
+		size := uint64(header >> 4)
+		if size == 0x0f {
+			size, err = binary.ReadUvarint(r)
+			require.NoError(t, err)
+		}
+		list := make([]any, size)
+		for i := range list {
+			list[i] = readValue(t, r, header&0x0f)
+		}
+		return list
+	case thriftStruct:
+		return readStruct(t, r)
+	default:
+		t.Fatalf("Unexpected type %d", typ)
+		return nil
+	}
+}
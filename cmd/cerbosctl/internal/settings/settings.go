@@ -0,0 +1,96 @@
+// Copyright 2021-2022 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package settings
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/adrg/xdg"
+
+	"github.com/cerbos/cerbos/internal/config"
+)
+
+const (
+	auditKey  = "audit"
+	serverKey = "server"
+	confDir   = "cerbosctl"
+	confFile  = "config.yaml"
+)
+
+// Settings holds the cerbosctl settings read from its configuration file.
+type Settings struct {
+	// Audit holds the settings for the audit command.
+	Audit Audit `yaml:"audit"`
+	// Server holds the settings for connecting to the Cerbos server.
+	Server Server `yaml:"server"`
+}
+
+type Audit struct {
+	// OutputFormat is the output format to use when one is not provided on the command line.
+	OutputFormat string `yaml:"outputFormat"`
+}
+
+type Server struct {
+	// Address is the address of the Cerbos server. It is only used when the configuration file is watched for changes.
+	Address string `yaml:"address"`
+}
+
+// DefaultPath returns the default location of the cerbosctl configuration file.
+func DefaultPath() string {
+	return filepath.Join(xdg.ConfigHome, confDir, confFile)
+}
+
+// Load reads the settings from the given configuration file.
+// If the path is empty, the file is read from the default location if it exists.
+func Load(path string) (*Settings, error) {
+	s := &Settings{}
+
+	if path == "" {
+		path = DefaultPath()
+		if _, err := os.Stat(path); errors.Is(err, fs.ErrNotExist) {
+			return s, nil
+		}
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open cerbosctl configuration file %s: %w", path, err)
+	}
+	defer f.Close()
+
+	w, err := config.WrapperFromReader(f, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cerbosctl configuration file %s: %w", path, err)
+	}
+
+	if err := w.Get(auditKey, &s.Audit); err != nil {
+		return nil, fmt.Errorf("failed to read audit settings from %s: %w", path, err)
+	}
+
+	if err := w.Get(serverKey, &s.Server); err != nil {
+		return nil, fmt.Errorf("failed to read server settings from %s: %w", path, err)
+	}
+
+	return s, nil
+}
+
+// Watch calls fn with the new settings whenever the contents of the configuration file at the given path (or the default
+// location if the path is empty) change, until the context is cancelled. It uses the same file watcher as the Cerbos
+// server configuration and returns an error if the file cannot be watched.
+func Watch(ctx context.Context, path string, fn func(*Settings, error)) error {
+	if path == "" {
+		path = DefaultPath()
+	}
+
+	return config.WatchFileChanges(ctx, []string{path}, func() error {
+		s, err := Load(path)
+		fn(s, err)
+		return err
+	})
+}
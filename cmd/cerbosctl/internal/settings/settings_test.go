@@ -0,0 +1,105 @@
+// Copyright 2021-2022 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package settings_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/adrg/xdg"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cerbos/cerbos/cmd/cerbosctl/internal/settings"
+)
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	confFile := filepath.Join(dir, "cerbosctl.yaml")
+	require.NoError(t, os.WriteFile(confFile, []byte("audit:\n  outputFormat: csv\nserver:\n  address: localhost:3593\n"), 0o600))
+
+	want := &settings.Settings{
+		Audit:  settings.Audit{OutputFormat: "csv"},
+		Server: settings.Server{Address: "localhost:3593"},
+	}
+
+	t.Run("path", func(t *testing.T) {
+		have, err := settings.Load(confFile)
+		require.NoError(t, err)
+		require.Equal(t, want, have)
+	})
+
+	t.Run("missing_path", func(t *testing.T) {
+		_, err := settings.Load(filepath.Join(dir, "missing.yaml"))
+		require.Error(t, err)
+	})
+
+	t.Run("invalid_file", func(t *testing.T) {
+		invalidFile := filepath.Join(dir, "invalid.yaml")
+		require.NoError(t, os.WriteFile(invalidFile, []byte("audit: [\n"), 0o600))
+
+		_, err := settings.Load(invalidFile)
+		require.Error(t, err)
+	})
+
+	t.Run("default_path", func(t *testing.T) {
+		configHome := xdg.ConfigHome
+		t.Cleanup(func() { xdg.ConfigHome = configHome })
+		xdg.ConfigHome = t.TempDir()
+
+		have, err := settings.Load("")
+		require.NoError(t, err)
+		require.Equal(t, &settings.Settings{}, have)
+
+		contents, err := os.ReadFile(confFile)
+		require.NoError(t, err)
+		require.NoError(t, os.MkdirAll(filepath.Dir(settings.DefaultPath()), 0o700))
+		require.NoError(t, os.WriteFile(settings.DefaultPath(), contents, 0o600))
+
+		have, err = settings.Load("")
+		require.NoError(t, err)
+		require.Equal(t, want, have)
+	})
+}
+
+func TestWatch(t *testing.T) {
+	confFile := filepath.Join(t.TempDir(), "cerbosctl.yaml")
+	require.NoError(t, os.WriteFile(confFile, []byte("server:\n  address: localhost:3593\n"), 0o600))
+
+	ctx, cancelFn := context.WithCancel(context.Background())
+	t.Cleanup(cancelFn)
+
+	changes := make(chan *settings.Settings, 1)
+	require.NoError(t, settings.Watch(ctx, confFile, func(s *settings.Settings, err error) {
+		if err != nil {
+			return
+		}
+
+		select {
+		case changes <- s:
+		default:
+		}
+	}))
+
+	// replace the file atomically so that the watcher never reads a partially written file
+	tmpFile := confFile + ".tmp"
+	require.NoError(t, os.WriteFile(tmpFile, []byte("server:\n  address: cerbos.example.com:3593\n"), 0o600))
+	require.NoError(t, os.Rename(tmpFile, confFile))
+
+	select {
+	case s := <-changes:
+		require.Equal(t, "cerbos.example.com:3593", s.Server.Address)
+	case <-time.After(10 * time.Second):
+		require.Fail(t, "timed out waiting for the change to be detected")
+	}
+}
+
+func TestWatchMissingFile(t *testing.T) {
+	ctx, cancelFn := context.WithCancel(context.Background())
+	t.Cleanup(cancelFn)
+
+	require.Error(t, settings.Watch(ctx, filepath.Join(t.TempDir(), "missing.yaml"), func(*settings.Settings, error) {}))
+}
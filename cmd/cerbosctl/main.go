@@ -4,6 +4,8 @@
 package main
 
 import (
+	"errors"
+
 	"github.com/alecthomas/kong"
 
 	"github.com/cerbos/cerbos/cmd/cerbosctl/internal/client"
@@ -18,18 +20,36 @@ func main() {
 		kong.UsageOnError(),
 	)
 
-	c, err := client.GetClient(&cli.Globals)
-	if err != nil {
-		ctx.Fatalf("failed to get the client: %v", err)
+	clientCtx := &client.Context{}
+	// audit logs read from a file don't need a connection to the server (or credentials)
+	if !cli.Audit.Offline() {
+		c, err := client.GetClient(&cli.Globals)
+		if err != nil {
+			ctx.Fatalf("failed to get the client: %v", err)
+		}
+
+		ac, err := client.GetAdminClient(&cli.Globals)
+		if err != nil {
+			ctx.Fatalf("failed to get the admin client: %v", err)
+		}
+
+		clientCtx.Client = c
+		clientCtx.AdminClient = ac
 	}
 
-	ac, err := client.GetAdminClient(&cli.Globals)
-	if err != nil {
-		ctx.Fatalf("failed to get the admin client: %v", err)
+	err := ctx.Run(&cli.Globals, clientCtx)
+
+	// commands can request a specific exit code to distinguish some outcomes from other failures
+	var ec exitCoder
+	if errors.As(err, &ec) {
+		ctx.Errorf("%v", err)
+		ctx.Exit(ec.ExitCode())
+		return
 	}
 
-	ctx.FatalIfErrorf(ctx.Run(&cli.Globals, &client.Context{
-		Client:      c,
-		AdminClient: ac,
-	}))
+	ctx.FatalIfErrorf(err)
+}
+
+type exitCoder interface {
+	ExitCode() int
 }
@@ -34,6 +34,10 @@ type confHolder struct {
 	DecisionLogsEnabled bool `yaml:"decisionLogsEnabled" conf:",example=false"`
 }
 
+func init() {
+	config.RegisterSection(&Conf{})
+}
+
 func (c *Conf) UnmarshalYAML(unmarshal func(any) error) error {
 	// This is a workaround to circumvent strict config parsing.
 	// Consider the following:
@@ -5,6 +5,7 @@ package auxdata
 
 import (
 	"context"
+	"errors"
 
 	enginev1 "github.com/cerbos/cerbos/api/genpb/cerbos/engine/v1"
 	requestv1 "github.com/cerbos/cerbos/api/genpb/cerbos/request/v1"
@@ -16,36 +17,121 @@ type AuxData struct {
 	jwt *jwtHelper
 }
 
-func New(ctx context.Context) (*AuxData, error) {
+// Opt is an option for customizing the behaviour of AuxData.
+type Opt func(*options)
+
+type options struct {
+	validators map[string][]TokenValidator
+}
+
+// WithKeySetValidator registers a custom validator to be invoked on every token verified using the given keyset.
+// Validators are invoked after the standard validations, in the order they were registered.
+func WithKeySetValidator(keySetID string, validator TokenValidator) Opt {
+	return func(o *options) {
+		if o.validators == nil {
+			o.validators = make(map[string][]TokenValidator)
+		}
+
+		o.validators[keySetID] = append(o.validators[keySetID], validator)
+	}
+}
+
+// ExtractOpt is an option for customizing a single extraction.
+type ExtractOpt func(*extractOptions)
+
+type extractOptions struct {
+	audience string
+}
+
+// WithAudience requires the JWT to have been issued for the given audience.
+// This is useful when the acceptable audience depends on the request (for example, the tenant being accessed).
+// The audience must be one of the audiences listed in the requestAudiences configuration; otherwise, the extraction fails.
+func WithAudience(audience string) ExtractOpt {
+	return func(o *extractOptions) {
+		o.audience = audience
+	}
+}
+
+func New(ctx context.Context, opts ...Opt) (*AuxData, error) {
 	conf := &Conf{}
 	if err := config.GetSection(conf); err != nil {
 		return nil, err
 	}
 
-	return NewFromConf(ctx, conf), nil
+	return NewFromConf(ctx, conf, opts...), nil
+}
+
+func NewFromConf(ctx context.Context, conf *Conf, opts ...Opt) *AuxData {
+	return &AuxData{jwt: newJWTHelper(ctx, conf.JWT, mkOptions(opts))}
 }
 
-func NewFromConf(ctx context.Context, conf *Conf) *AuxData {
-	return &AuxData{jwt: newJWTHelper(ctx, conf.JWT)}
+func NewWithoutVerification(ctx context.Context, opts ...Opt) *AuxData {
+	return &AuxData{jwt: newJWTHelper(ctx, &JWTConf{DisableVerification: true}, mkOptions(opts))}
 }
 
-func NewWithoutVerification(ctx context.Context) *AuxData {
-	return &AuxData{jwt: newJWTHelper(ctx, &JWTConf{DisableVerification: true})}
+func mkOptions(opts []Opt) *options {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return o
+}
+
+func mkExtractOptions(opts []ExtractOpt) *extractOptions {
+	o := &extractOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return o
 }
 
 // Extract auxiliary data and convert to format expected by the engine.
-func (ad *AuxData) Extract(ctx context.Context, adProto *requestv1.AuxData) (*enginev1.AuxData, error) {
-	if adProto == nil {
+// The claims of the tokens in the jwts map (for example, a user token and a service token) are placed under the namespace
+// each token is keyed by, next to the claims of the jwt token. Claims are never overwritten: the request is rejected if a
+// namespace is already used by a claim of the jwt token, and claims with the same name in different tokens don't collide
+// because each token has its own namespace.
+func (ad *AuxData) Extract(ctx context.Context, adProto *requestv1.AuxData, opts ...ExtractOpt) (*enginev1.AuxData, error) {
+	if adProto == nil && !ad.jwt.requireToken {
 		return nil, nil
 	}
 
 	ctx, span := tracing.StartSpan(ctx, "aux_data.Extract")
 	defer span.End()
 
-	jwtPB, err := ad.jwt.extract(ctx, adProto.Jwt)
+	jwtPB, err := ad.jwt.extract(ctx, adProto.GetJwt(), opts...)
 	if err != nil {
 		return nil, err
 	}
 
+	if len(adProto.GetJwts()) > 0 {
+		if jwtPB, err = ad.jwt.extractNamespaced(ctx, jwtPB, adProto.GetJwts(), opts...); err != nil {
+			return nil, err
+		}
+	}
+
 	return &enginev1.AuxData{Jwt: jwtPB}, nil
 }
+
+// Healthy returns an error if any of the remote JWT keysets has not been fetched successfully for too long.
+// It is intended to be used by readiness checks because tokens verified by such keysets are rejected.
+func (ad *AuxData) Healthy() error {
+	return ad.jwt.Healthy()
+}
+
+// ErrKeyIDResolutionRequiresFetch is returned by ResolveJWTKeySet when the keyset can only be determined from the key ID
+// of the token and some of the candidate keysets are remote.
+var ErrKeyIDResolutionRequiresFetch = errors.New("resolving the keyset from the JWT key ID requires fetching the remote keysets")
+
+// ResolveJWTKeySet returns the ID of the keyset that would be used to verify the given token, without verifying it.
+// This is intended for diagnosing keyset routing issues and does not perform any network I/O. Bearer tokens and JWE-wrapped
+// tokens are accepted. If the keyset would be resolved from the key ID of the token and any of the keysets is remote,
+// ErrKeyIDResolutionRequiresFetch is returned instead.
+func (ad *AuxData) ResolveJWTKeySet(auxJWT *requestv1.AuxData_JWT) (string, error) {
+	if auxJWT == nil {
+		return "", nil
+	}
+
+	return ad.jwt.resolveKeySetLocally(context.Background(), auxJWT.Token, auxJWT.KeySetId)
+}
@@ -4,10 +4,15 @@
 package auxdata
 
 import (
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
+	"github.com/lestrrat-go/jwx/v2/jwa"
 	"go.uber.org/multierr"
+
+	"github.com/cerbos/cerbos/internal/config"
 )
 
 const (
@@ -23,35 +28,125 @@ type Conf struct {
 type JWTConf struct {
 	// KeySets is the list of keysets to be used to verify tokens.
 	KeySets []JWTKeySet `yaml:"keySets"`
+	// AcceptableAudiences is the list of audiences accepted by Cerbos. If defined, tokens must have at least one of these audiences.
+	AcceptableAudiences []string `yaml:"acceptableAudiences" conf:",example=['service-a', 'service-b']"`
+	// AcceptableIssuers is the list of issuers accepted by Cerbos. If defined, tokens must be issued by one of these issuers.
+	AcceptableIssuers []string `yaml:"acceptableIssuers" conf:",example=['https://issuer-a.tld', 'https://issuer-b.tld']"`
 	// DisableVerification disables JWT verification.
 	DisableVerification bool `yaml:"disableVerification" conf:",example=false"`
+	// IndexArrayClaims adds an entry for each element of the array claims, keyed by the claim name and the index of the element (e.g. roles.0).
+	IndexArrayClaims bool `yaml:"indexArrayClaims" conf:",example=false"`
+	// ResolveKeySetByKeyID uses the keyset containing the key referenced by the kid header of the token when the request does not specify a keyset and multiple keysets are defined.
+	ResolveKeySetByKeyID bool `yaml:"resolveKeySetByKeyID" conf:",example=false"`
+	// RequireToken rejects the requests that don't include a token.
+	RequireToken bool `yaml:"requireToken" conf:",example=false"`
+	// RequireVerified rejects the tokens resolved to a keyset with verification disabled. Cannot be used with DisableVerification.
+	RequireVerified bool `yaml:"requireVerified" conf:",example=false"`
+	// CachePolicy is the eviction policy of the token caches: arc, lru or lfu. Defaults to arc.
+	CachePolicy string `yaml:"cachePolicy" conf:",example=arc"`
 	// CacheSize sets the number of verified tokens cached in memory. Set to negative value to disable caching.
 	CacheSize int `yaml:"cacheSize" conf:",example=256"`
+	// ClockSkew is the tolerance for differences between the clocks of the token issuer and Cerbos when validating the time based claims (exp, nbf and iat).
+	ClockSkew time.Duration `yaml:"clockSkew" conf:",example=30s"`
+	// MaxClaims sets the maximum number of claims accepted in a token. Set to negative value to disable the limit.
+	MaxClaims int `yaml:"maxClaims" conf:",example=1024"`
+	// NamespaceClaims is the allowlist of claims whose value can be used as the namespace of the claims of a token. The first claim in the list that the token has determines the namespace: for example, with ['token_use'], the sub claim of a token with the token_use claim set to service is available as request.aux_data.jwt.service.sub. Tokens without any of the claims are rejected.
+	NamespaceClaims []string `yaml:"namespaceClaims" conf:",example=['token_use']"`
+	// Prefetch fetches the remote keysets in the background at startup so that the first requests don't wait for them. Defaults to true.
+	Prefetch *bool `yaml:"prefetch" conf:",example=true"`
+	// RequestAudiences is the allowlist of audiences that can be required on a per-request basis.
+	RequestAudiences []string `yaml:"requestAudiences" conf:",example=['tenant-a', 'tenant-b']"`
+	// ReservedNamespaces is the list of namespaces that cannot be derived from the NamespaceClaims of a token, so that a token from a less-trusted source cannot shadow the claims that policies expect to come from a trusted one. Matched case-insensitively.
+	ReservedNamespaces []string `yaml:"reservedNamespaces" conf:",example=['admin', 'internal']"`
+	// TruncateClaims ignores the claims exceeding MaxClaims instead of rejecting the token.
+	TruncateClaims bool `yaml:"truncateClaims" conf:",example=false"`
+}
+
+// PrefetchEnabled returns true unless prefetching the remote keysets has been explicitly disabled.
+func (c *JWTConf) PrefetchEnabled() bool {
+	return c.Prefetch == nil || *c.Prefetch
 }
 
 type JWTKeySet struct {
-	// Remote defines a remote keyset. Mutually exclusive with Local.
+	// Claims is the list of claims to extract from the tokens verified by this keyset. Nested claims can be referenced using dotted paths. If not set, all claims are extracted.
+	Claims []string `yaml:"claims" conf:",example=['sub', 'resource_access.myapp.roles']"`
+	// ExcludeClaims is the list of claims to discard from the tokens verified by this keyset. Nested claims can be referenced using dotted paths.
+	ExcludeClaims []string `yaml:"excludeClaims" conf:",example=['email']"`
+	// RequiredClaims is the list of claims that must be present and not empty in the tokens verified by this keyset. Nested claims can be referenced using dotted paths.
+	RequiredClaims []string `yaml:"requiredClaims" conf:",example=['sub', 'tenant_id']"`
+	// AllowedAlgorithms is the list of signature algorithms accepted for the tokens verified by this keyset. Defaults to all supported algorithms except none.
+	AllowedAlgorithms []string `yaml:"allowedAlgorithms" conf:",example=['RS256', 'ES384']"`
+	// Remote defines a remote keyset. Mutually exclusive with Local and Symmetric.
 	Remote *RemoteSource `yaml:"remote"`
-	// Local defines a local keyset. Mutually exclusive with Remote.
+	// Local defines a local keyset. Mutually exclusive with Remote and Symmetric.
 	Local *LocalSource `yaml:"local"`
+	// Symmetric defines a keyset containing a shared secret for verifying HMAC signed tokens. Mutually exclusive with Local and Remote.
+	Symmetric *SymmetricSource `yaml:"symmetric"`
+	// Decrypt defines the private keys for decrypting JWE-wrapped tokens before they are verified by this keyset.
+	Decrypt *LocalSource `yaml:"decrypt"`
 	// ID is the unique reference to this keyset.
 	ID string `yaml:"id" conf:"required,example=ks1"`
+	// Issuer is the issuer of the tokens verified by this keyset. Tokens without a keyset ID are verified using the keyset matching their iss claim.
+	Issuer string `yaml:"issuer" conf:",example=https://domain.tld"`
+	// CacheSize sets the number of tokens verified by this keyset that are cached in a dedicated cache. If not set, the global cache is used. Set to negative value to disable caching.
+	CacheSize int `yaml:"cacheSize" conf:",example=1024"`
+	// DisableVerification accepts the tokens resolved to this keyset without verifying their signatures. The claims are still validated.
+	// Anyone can forge a token that is accepted by this keyset, so only use it for tokens from a fully trusted source, and only with
+	// requests that select the keyset explicitly by ID. Such keysets don't need a source, cannot be mapped to an issuer and are never
+	// resolved by key ID. Verification is disabled for all keysets if the global disableVerification is true.
+	DisableVerification bool `yaml:"disableVerification" conf:",example=false"`
 }
 
 type RemoteSource struct {
+	// TLS defines the TLS configuration for fetching the keyset.
+	TLS *RemoteTLSConf `yaml:"tls"`
 	// URL is the JWKS URL to fetch the keyset from.
 	URL string `yaml:"url" conf:"required,example=https://domain.tld/.well-known/keys.jwks"`
 	// RefreshInterval is the refresh interval for the keyset.
 	RefreshInterval time.Duration `yaml:"refreshInterval" conf:",example=1h"`
+	// MinRefreshInterval is the lower bound of the refresh interval derived from the Cache-Control and Expires headers returned by the remote source.
+	MinRefreshInterval time.Duration `yaml:"minRefreshInterval" conf:",example=15m"`
+	// RefreshJitter is the upper bound of the random delay applied to the first refresh of the keyset, to avoid multiple instances refreshing in lockstep.
+	RefreshJitter time.Duration `yaml:"refreshJitter" conf:",example=5m"`
+	// Timeout is the maximum amount of time to wait for the keyset to be fetched. No timeout is applied if not set.
+	Timeout time.Duration `yaml:"timeout" conf:",example=10s"`
+}
+
+// RemoteTLSConf holds the TLS configuration for fetching a remote keyset.
+type RemoteTLSConf struct {
+	// CACert is the path to the CA certificate for verifying the server certificate. The system CA certificates are used if not set.
+	CACert string `yaml:"caCert" conf:",example=/path/to/CA_certificate"`
+	// ClientCert is the path to the client certificate for mutual TLS. Requires ClientKey.
+	ClientCert string `yaml:"clientCert" conf:",example=/path/to/client_certificate"`
+	// ClientKey is the path to the client private key for mutual TLS. Requires ClientCert.
+	ClientKey string `yaml:"clientKey" conf:",example=/path/to/client_key"`
 }
 
 type LocalSource struct {
 	// Data is the encoded JWK data for this keyset. Mutually exclusive with File.
 	Data string `yaml:"data" conf:",example=base64encodedJWK"`
-	// File is the path to file containing JWK data. Mutually exclusive with Data.
+	// File is the path to file containing JWK data. The file is read again when it changes. Mutually exclusive with Data.
 	File string `yaml:"file" conf:",example=/path/to/keys.jwk"`
 	// PEM indicates that the data is PEM encoded.
 	PEM bool `yaml:"pem" conf:",example=true"`
+	// PublicKeysOnly discards the private components of the keys (for example, when the PEM file contains both a private key and its certificate).
+	// Only the public keys are required for verifying tokens.
+	PublicKeysOnly bool `yaml:"publicKeysOnly" conf:",example=true"`
+}
+
+type SymmetricSource struct {
+	// Secret is the base64 encoded shared secret. Mutually exclusive with File.
+	Secret string `yaml:"secret" conf:",example=base64encodedSecret"`
+	// File is the path to file containing the shared secret. Mutually exclusive with Secret.
+	File string `yaml:"file" conf:",example=/path/to/secret"`
+	// Algorithm is the HMAC algorithm used to sign the tokens (HS256, HS384 or HS512). Defaults to HS256.
+	Algorithm string `yaml:"algorithm" conf:",example=HS256"`
+	// KeyID is the key ID of the secret. Tokens without a key ID are verified using the secret regardless.
+	KeyID string `yaml:"keyID" conf:",example=secret1"`
+}
+
+func init() {
+	config.RegisterSection(&Conf{})
 }
 
 func (c *Conf) Key() string {
@@ -67,7 +162,40 @@ func (c *Conf) Validate() (errs error) {
 		c.JWT.CacheSize = defaultCacheSize
 	}
 
+	if c.JWT.CachePolicy == "" {
+		c.JWT.CachePolicy = defaultCachePolicy
+	}
+
+	if _, ok := cachePolicies[c.JWT.CachePolicy]; !ok {
+		errs = multierr.Append(errs, fmt.Errorf("unsupported cachePolicy '%s': must be one of arc, lru or lfu", c.JWT.CachePolicy))
+	}
+
+	if c.JWT.RequireVerified && c.JWT.DisableVerification {
+		errs = multierr.Append(errs, errors.New("requireVerified cannot be used with disableVerification"))
+	}
+
+	if c.JWT.ClockSkew < 0 {
+		errs = multierr.Append(errs, errors.New("clockSkew must not be negative"))
+	}
+
+	if c.JWT.MaxClaims == 0 {
+		c.JWT.MaxClaims = defaultMaxClaims
+	}
+
+	for _, claim := range c.JWT.NamespaceClaims {
+		if claim == "" {
+			errs = multierr.Append(errs, errors.New("namespaceClaims must not contain empty claim names"))
+		}
+	}
+
+	for _, ns := range c.JWT.ReservedNamespaces {
+		if err := checkNamespace(ns, nil); err != nil {
+			errs = multierr.Append(errs, fmt.Errorf("invalid reservedNamespaces entry: %w", err))
+		}
+	}
+
 	idSet := make(map[string]struct{}, len(c.JWT.KeySets))
+	issuers := make(map[string]string)
 	for _, ks := range c.JWT.KeySets {
 		if _, ok := idSet[ks.ID]; ok {
 			errs = multierr.Append(errs, fmt.Errorf("duplicate keyset id '%s'", ks.ID))
@@ -76,21 +204,59 @@ func (c *Conf) Validate() (errs error) {
 
 		idSet[ks.ID] = struct{}{}
 
-		if ks.Remote == nil && ks.Local == nil {
-			errs = multierr.Append(errs, fmt.Errorf("keyset '%s': should have one of `local` or `remote` defined", ks.ID))
-			continue
+		for _, c := range append(append(append([]string{}, ks.Claims...), ks.ExcludeClaims...), ks.RequiredClaims...) {
+			if c == "" || strings.HasPrefix(c, ".") || strings.HasSuffix(c, ".") {
+				errs = multierr.Append(errs, fmt.Errorf("keyset '%s': invalid claim path '%s'", ks.ID, c))
+			}
 		}
 
-		if ks.Remote != nil && ks.Local != nil {
-			errs = multierr.Append(errs, fmt.Errorf("keyset '%s': only one of `local` or `remote` should be defined", ks.ID))
-			continue
+		for _, alg := range ks.AllowedAlgorithms {
+			if _, ok := defaultAlgorithms[jwa.SignatureAlgorithm(alg)]; !ok {
+				errs = multierr.Append(errs, fmt.Errorf("keyset '%s': unsupported algorithm '%s' in allowedAlgorithms", ks.ID, alg))
+			}
 		}
 
-		if ks.Remote != nil && ks.Remote.URL == "" {
-			errs = multierr.Append(errs, fmt.Errorf("keyset '%s': remote URL is empty", ks.ID))
+		if ks.Issuer != "" {
+			if other, ok := issuers[ks.Issuer]; ok {
+				errs = multierr.Append(errs, fmt.Errorf("keyset '%s': issuer '%s' is already used by keyset '%s'", ks.ID, ks.Issuer, other))
+			}
+			issuers[ks.Issuer] = ks.ID
+		}
+
+		if ks.DisableVerification && ks.Issuer != "" {
+			errs = multierr.Append(errs, fmt.Errorf("keyset '%s': issuer cannot be used with disableVerification because the issuer of an unverified token cannot be trusted", ks.ID))
+		}
+
+		switch numSources := countSources(ks); {
+		case numSources == 0 && ks.DisableVerification:
+			// keys are not needed to accept unverified tokens
+		case numSources == 0:
+			errs = multierr.Append(errs, fmt.Errorf("keyset '%s': should have one of `local`, `remote` or `symmetric` defined", ks.ID))
+			continue
+		case numSources > 1:
+			errs = multierr.Append(errs, fmt.Errorf("keyset '%s': only one of `local`, `remote` or `symmetric` should be defined", ks.ID))
 			continue
 		}
 
+		if r := ks.Remote; r != nil {
+			if r.URL == "" {
+				errs = multierr.Append(errs, fmt.Errorf("keyset '%s': remote URL is empty", ks.ID))
+				continue
+			}
+
+			if r.Timeout < 0 {
+				errs = multierr.Append(errs, fmt.Errorf("keyset '%s': remote timeout must not be negative", ks.ID))
+			}
+
+			if r.MinRefreshInterval < 0 || r.RefreshJitter < 0 {
+				errs = multierr.Append(errs, fmt.Errorf("keyset '%s': remote minRefreshInterval and refreshJitter must not be negative", ks.ID))
+			}
+
+			if r.TLS != nil && (r.TLS.ClientCert == "") != (r.TLS.ClientKey == "") {
+				errs = multierr.Append(errs, fmt.Errorf("keyset '%s': both 'remote.tls.clientCert' and 'remote.tls.clientKey' must be defined", ks.ID))
+			}
+		}
+
 		if l := ks.Local; l != nil {
 			if l.Data == "" && l.File == "" {
 				errs = multierr.Append(errs, fmt.Errorf("keyset '%s': at least one of 'local.data' or 'local.file' must be defined", ks.ID))
@@ -101,7 +267,49 @@ func (c *Conf) Validate() (errs error) {
 				errs = multierr.Append(errs, fmt.Errorf("keyset '%s': only one of 'loca.data' or 'local.file' must be defined", ks.ID))
 			}
 		}
+
+		if d := ks.Decrypt; d != nil {
+			if (d.Data == "") == (d.File == "") {
+				errs = multierr.Append(errs, fmt.Errorf("keyset '%s': exactly one of 'decrypt.data' or 'decrypt.file' must be defined", ks.ID))
+			}
+
+			if d.PublicKeysOnly {
+				errs = multierr.Append(errs, fmt.Errorf("keyset '%s': 'decrypt.publicKeysOnly' cannot be used because decryption requires private keys", ks.ID))
+			}
+		}
+
+		if s := ks.Symmetric; s != nil {
+			if (s.Secret == "") == (s.File == "") {
+				errs = multierr.Append(errs, fmt.Errorf("keyset '%s': exactly one of 'symmetric.secret' or 'symmetric.file' must be defined", ks.ID))
+				continue
+			}
+
+			if s.Algorithm == "" {
+				s.Algorithm = defaultHMACAlgorithm
+			}
+
+			if _, ok := hmacAlgorithms[s.Algorithm]; !ok {
+				errs = multierr.Append(errs, fmt.Errorf("keyset '%s': unsupported symmetric algorithm '%s'", ks.ID, s.Algorithm))
+			}
+		}
 	}
 
 	return errs
 }
+
+func countSources(ks JWTKeySet) int {
+	n := 0
+	if ks.Remote != nil {
+		n++
+	}
+
+	if ks.Local != nil {
+		n++
+	}
+
+	if ks.Symmetric != nil {
+		n++
+	}
+
+	return n
+}
@@ -4,7 +4,9 @@
 package auxdata_test
 
 import (
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -79,6 +81,44 @@ func TestConfigValidate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "remote keyset with TLS and timeout",
+			conf: map[string]any{
+				"auxData": map[string]any{
+					"jwt": map[string]any{
+						"keySets": []map[string]any{
+							{"id": "foo", "remote": map[string]any{"url": "https://domain.tld/.well-known/foo.jwks", "timeout": "10s", "tls": map[string]any{"caCert": "/path/to/ca.crt", "clientCert": "/path/to/tls.crt", "clientKey": "/path/to/tls.key"}}},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "remote keyset with client cert but no key",
+			conf: map[string]any{
+				"auxData": map[string]any{
+					"jwt": map[string]any{
+						"keySets": []map[string]any{
+							{"id": "foo", "remote": map[string]any{"url": "https://domain.tld/.well-known/foo.jwks", "tls": map[string]any{"clientCert": "/path/to/tls.crt"}}},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "remote keyset with negative timeout",
+			conf: map[string]any{
+				"auxData": map[string]any{
+					"jwt": map[string]any{
+						"keySets": []map[string]any{
+							{"id": "foo", "remote": map[string]any{"url": "https://domain.tld/.well-known/foo.jwks", "timeout": "-1s"}},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
 		{
 			name: "empty remote URL in jwt keyset",
 			conf: map[string]any{
@@ -121,6 +161,264 @@ func TestConfigValidate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "valid symmetric jwt keyset",
+			conf: map[string]any{
+				"auxData": map[string]any{
+					"jwt": map[string]any{
+						"keySets": []map[string]any{
+							{"id": "foo", "symmetric": map[string]any{"secret": "c2VjcmV0", "algorithm": "HS384"}},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "both local and symmetric defined in jwt keyset",
+			conf: map[string]any{
+				"auxData": map[string]any{
+					"jwt": map[string]any{
+						"keySets": []map[string]any{
+							{"id": "foo", "local": map[string]any{"data": "data"}, "symmetric": map[string]any{"secret": "c2VjcmV0"}},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "both symmetric secret and file defined in jwt keyset",
+			conf: map[string]any{
+				"auxData": map[string]any{
+					"jwt": map[string]any{
+						"keySets": []map[string]any{
+							{"id": "foo", "symmetric": map[string]any{"secret": "c2VjcmV0", "file": "/path"}},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "unsupported symmetric algorithm in jwt keyset",
+			conf: map[string]any{
+				"auxData": map[string]any{
+					"jwt": map[string]any{
+						"keySets": []map[string]any{
+							{"id": "foo", "symmetric": map[string]any{"secret": "c2VjcmV0", "algorithm": "RS256"}},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "duplicate issuer in jwt keysets",
+			conf: map[string]any{
+				"auxData": map[string]any{
+					"jwt": map[string]any{
+						"keySets": []map[string]any{
+							{"id": "foo", "issuer": "https://domain.tld", "local": map[string]any{"data": "data"}},
+							{"id": "bar", "issuer": "https://domain.tld", "local": map[string]any{"data": "data"}},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "valid claim filters in jwt keyset",
+			conf: map[string]any{
+				"auxData": map[string]any{
+					"jwt": map[string]any{
+						"keySets": []map[string]any{
+							{"id": "foo", "local": map[string]any{"data": "data"}, "claims": []string{"sub", "resource_access.myapp.roles"}, "excludeClaims": []string{"email"}},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "invalid required claim path in jwt keyset",
+			conf: map[string]any{
+				"auxData": map[string]any{
+					"jwt": map[string]any{
+						"keySets": []map[string]any{
+							{"id": "foo", "local": map[string]any{"data": "data"}, "requiredClaims": []string{"sub", ".tenant_id"}},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid claim path in jwt keyset",
+			conf: map[string]any{
+				"auxData": map[string]any{
+					"jwt": map[string]any{
+						"keySets": []map[string]any{
+							{"id": "foo", "local": map[string]any{"data": "data"}, "claims": []string{"resource_access."}},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "valid decrypt source in jwt keyset",
+			conf: map[string]any{
+				"auxData": map[string]any{
+					"jwt": map[string]any{
+						"keySets": []map[string]any{
+							{"id": "foo", "local": map[string]any{"data": "data"}, "decrypt": map[string]any{"file": "/path/to/key.pem", "pem": true}},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "both decrypt data and file not defined in jwt keyset",
+			conf: map[string]any{
+				"auxData": map[string]any{
+					"jwt": map[string]any{
+						"keySets": []map[string]any{
+							{"id": "foo", "local": map[string]any{"data": "data"}, "decrypt": map[string]any{"pem": true}},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "decrypt source with public keys only in jwt keyset",
+			conf: map[string]any{
+				"auxData": map[string]any{
+					"jwt": map[string]any{
+						"keySets": []map[string]any{
+							{"id": "foo", "local": map[string]any{"data": "data"}, "decrypt": map[string]any{"file": "/path/to/key.pem", "publicKeysOnly": true}},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "valid allowed algorithms in jwt keyset",
+			conf: map[string]any{
+				"auxData": map[string]any{
+					"jwt": map[string]any{
+						"keySets": []map[string]any{
+							{"id": "foo", "local": map[string]any{"data": "data"}, "allowedAlgorithms": []string{"RS256", "ES384"}},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "none in allowed algorithms of jwt keyset",
+			conf: map[string]any{
+				"auxData": map[string]any{
+					"jwt": map[string]any{
+						"keySets": []map[string]any{
+							{"id": "foo", "local": map[string]any{"data": "data"}, "allowedAlgorithms": []string{"RS256", "none"}},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "unverified jwt keyset without source",
+			conf: map[string]any{
+				"auxData": map[string]any{
+					"jwt": map[string]any{
+						"keySets": []map[string]any{
+							{"id": "foo", "disableVerification": true},
+							{"id": "bar", "local": map[string]any{"data": "data"}},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "unverified jwt keyset with issuer",
+			conf: map[string]any{
+				"auxData": map[string]any{
+					"jwt": map[string]any{
+						"keySets": []map[string]any{
+							{"id": "foo", "disableVerification": true, "issuer": "https://domain.tld"},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "jwt require verified with verification disabled",
+			conf: map[string]any{
+				"auxData": map[string]any{
+					"jwt": map[string]any{
+						"disableVerification": true,
+						"requireVerified":     true,
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "jwt cache policy",
+			conf: map[string]any{
+				"auxData": map[string]any{
+					"jwt": map[string]any{
+						"cachePolicy": "lfu",
+						"keySets": []map[string]any{
+							{"id": "foo", "local": map[string]any{"data": "data"}},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "unsupported jwt cache policy",
+			conf: map[string]any{
+				"auxData": map[string]any{
+					"jwt": map[string]any{
+						"cachePolicy": "fifo",
+						"keySets": []map[string]any{
+							{"id": "foo", "local": map[string]any{"data": "data"}},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "reserved namespaces",
+			conf: map[string]any{
+				"auxData": map[string]any{
+					"jwt": map[string]any{
+						"reservedNamespaces": []string{"admin", "Internal"},
+						"keySets": []map[string]any{
+							{"id": "foo", "local": map[string]any{"data": "data"}},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "invalid reserved namespace",
+			conf: map[string]any{
+				"auxData": map[string]any{
+					"jwt": map[string]any{
+						"reservedNamespaces": []string{"admin.users"},
+						"keySets": []map[string]any{
+							{"id": "foo", "local": map[string]any{"data": "data"}},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -138,3 +436,28 @@ func TestConfigValidate(t *testing.T) {
 		})
 	}
 }
+
+func TestRemoteSourceDurations(t *testing.T) {
+	conf := `
+auxData:
+  jwt:
+    keySets:
+      - id: remote
+        remote:
+          url: https://domain.tld/.well-known/keys.jwks
+          refreshInterval: 15m
+          minRefreshInterval: 1h30m
+          timeout: 10s
+`
+
+	require.NoError(t, config.LoadReader(strings.NewReader(conf), nil))
+
+	var ac auxdata.Conf
+	require.NoError(t, config.GetSection(&ac))
+	require.Len(t, ac.JWT.KeySets, 1)
+
+	remote := ac.JWT.KeySets[0].Remote
+	require.Equal(t, 15*time.Minute, remote.RefreshInterval)
+	require.Equal(t, 90*time.Minute, remote.MinRefreshInterval)
+	require.Equal(t, 10*time.Second, remote.Timeout)
+}
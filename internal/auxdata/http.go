@@ -0,0 +1,82 @@
+// Copyright 2021-2022 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package auxdata
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+const bearerScheme = "bearer"
+
+var (
+	// ErrUnauthenticated is the failure reason for HTTP requests that do not carry a valid bearer token.
+	// Callers should respond with 401 Unauthorized when errors.Is(err, ErrUnauthenticated) is true.
+	ErrUnauthenticated = errors.New("unauthenticated")
+	// ErrMissingBearerToken is returned when the Authorization header is missing or does not use the bearer scheme.
+	ErrMissingBearerToken = errors.New("missing bearer token")
+	// ErrUnsupportedAuthScheme is returned when an Authorization header value uses a scheme other than bearer.
+	ErrUnsupportedAuthScheme = errors.New("unsupported authorization scheme")
+)
+
+// VerifyHTTPRequest verifies the bearer token from the Authorization header of the given HTTP request and returns its claims.
+// The token is verified using the keyset with the given ID, or the default keyset if the ID is empty, exactly as it would be
+// for a request to the policy decision point. The same cache and metrics are used as well.
+// Errors caused by the token (including a missing token) match ErrUnauthenticated. Other errors (such as a keyset
+// that cannot be retrieved) indicate a problem on the server side.
+func (ad *AuxData) VerifyHTTPRequest(r *http.Request, keySetID string) (map[string]*structpb.Value, error) {
+	token, ok := bearerToken(r.Header.Get("Authorization"))
+	if !ok {
+		return nil, jwtError{reason: ErrUnauthenticated, cause: ErrMissingBearerToken}
+	}
+
+	claims, err := ad.Verifier().Verify(r.Context(), token, keySetID)
+	if err != nil {
+		if errors.Is(err, ErrJWTKeySetUnavailable) {
+			return nil, err
+		}
+
+		return nil, jwtError{reason: ErrUnauthenticated, cause: err}
+	}
+
+	return claims, nil
+}
+
+// bearerToken returns the token from an Authorization header value that uses the bearer scheme.
+func bearerToken(header string) (string, bool) {
+	scheme, token, ok := strings.Cut(strings.TrimSpace(header), " ")
+	if !ok || !strings.EqualFold(scheme, bearerScheme) {
+		return "", false
+	}
+
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
+
+// ParseBearerToken returns the token from an Authorization header value. The bearer scheme is optional and
+// matched case-insensitively, so the value can be either a bare token or a token preceded by the scheme.
+// Values using other schemes are rejected with ErrUnsupportedAuthScheme.
+func ParseBearerToken(header string) (string, error) {
+	fields := strings.Fields(header)
+	switch len(fields) {
+	case 0:
+		return "", ErrMissingBearerToken
+	case 1:
+		if strings.EqualFold(fields[0], bearerScheme) {
+			return "", ErrMissingBearerToken
+		}
+		return fields[0], nil
+	case 2: //nolint:gomnd
+		if !strings.EqualFold(fields[0], bearerScheme) {
+			return "", fmt.Errorf("%w: %s", ErrUnsupportedAuthScheme, fields[0])
+		}
+		return fields[1], nil
+	default:
+		return "", errors.New("invalid authorization header value")
+	}
+}
@@ -0,0 +1,117 @@
+// Copyright 2021-2022 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package auxdata
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cerbos/cerbos/internal/test"
+)
+
+func TestVerifyHTTPRequest(t *testing.T) {
+	keysDir := test.PathToDir(t, "auxdata")
+
+	ctx, cancelFn := context.WithCancel(context.Background())
+	t.Cleanup(cancelFn)
+
+	ad := NewFromConf(ctx, &Conf{
+		JWT: &JWTConf{
+			KeySets: []JWTKeySet{
+				{
+					ID:    "local_file",
+					Local: &LocalSource{File: filepath.Join(keysDir, "verify_key.jwk")},
+				},
+			},
+			CacheSize: defaultCacheSize,
+		},
+	})
+
+	mkRequest := func(authz string) *http.Request {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		if authz != "" {
+			req.Header.Set("Authorization", authz)
+		}
+		return req
+	}
+
+	t.Run("valid_token", func(t *testing.T) {
+		expiry := time.Now().Add(1 * time.Hour)
+		have, err := ad.VerifyHTTPRequest(mkRequest("Bearer "+mkSignedToken(t, expiry)), "")
+		require.NoError(t, err)
+		require.Equal(t, "foobar", have["customString"].GetStringValue())
+	})
+
+	t.Run("lowercase_scheme", func(t *testing.T) {
+		_, err := ad.VerifyHTTPRequest(mkRequest("bearer "+mkSignedToken(t, time.Now().Add(1*time.Hour))), "local_file")
+		require.NoError(t, err)
+	})
+
+	t.Run("expired_token", func(t *testing.T) {
+		_, err := ad.VerifyHTTPRequest(mkRequest("Bearer "+mkSignedToken(t, time.Now().Add(-1*time.Hour))), "")
+		require.ErrorIs(t, err, ErrUnauthenticated)
+	})
+
+	t.Run("unknown_keyset", func(t *testing.T) {
+		_, err := ad.VerifyHTTPRequest(mkRequest("Bearer "+mkSignedToken(t, time.Now().Add(1*time.Hour))), "wibble")
+		require.ErrorIs(t, err, ErrUnauthenticated)
+	})
+
+	testCases := map[string]string{
+		"missing_header": "",
+		"basic_scheme":   "Basic dXNlcjpwYXNzd29yZA==",
+		"empty_token":    "Bearer ",
+	}
+
+	for name, authz := range testCases {
+		authz := authz
+		t.Run(name, func(t *testing.T) {
+			_, err := ad.VerifyHTTPRequest(mkRequest(authz), "")
+			require.ErrorIs(t, err, ErrUnauthenticated)
+			require.ErrorIs(t, err, ErrMissingBearerToken)
+		})
+	}
+}
+
+func TestParseBearerToken(t *testing.T) {
+	testCases := []struct {
+		name    string
+		header  string
+		want    string
+		wantErr error
+	}{
+		{name: "bare_token", header: "token", want: "token"},
+		{name: "bearer_scheme", header: "Bearer token", want: "token"},
+		{name: "lowercase_scheme", header: "bearer token", want: "token"},
+		{name: "extra_whitespace", header: "  Bearer \t token \n", want: "token"},
+		{name: "empty", header: " ", wantErr: ErrMissingBearerToken},
+		{name: "scheme_only", header: "Bearer", wantErr: ErrMissingBearerToken},
+		{name: "other_scheme", header: "Basic dXNlcjpwYXNzd29yZA==", wantErr: ErrUnsupportedAuthScheme},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			have, err := ParseBearerToken(tc.header)
+			if tc.wantErr != nil {
+				require.ErrorIs(t, err, tc.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.want, have)
+		})
+	}
+
+	t.Run("too_many_fields", func(t *testing.T) {
+		_, err := ParseBearerToken("Bearer token token")
+		require.Error(t, err)
+	})
+}
@@ -4,19 +4,38 @@
 package auxdata
 
 import (
+	"bytes"
 	"context"
+	"crypto"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
 	"encoding/base64"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"math/rand"
+	"net/http"
+	"os"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/bluele/gcache"
 	"github.com/lestrrat-go/httprc"
+	"github.com/lestrrat-go/jwx/v2/jwa"
+	"github.com/lestrrat-go/jwx/v2/jwe"
 	"github.com/lestrrat-go/jwx/v2/jwk"
+	"github.com/lestrrat-go/jwx/v2/jws"
 	"github.com/lestrrat-go/jwx/v2/jwt"
 	"go.opencensus.io/stats"
 	"go.opencensus.io/tag"
+	"go.uber.org/multierr"
 	"go.uber.org/zap"
 	"google.golang.org/protobuf/types/known/structpb"
 
@@ -28,255 +47,1779 @@ import (
 )
 
 const (
-	cacheKind          = "jwt"
-	defaultCacheExpiry = 10 * time.Minute
-	defaultCacheSize   = 256
+	cacheKind            = "jwt"
+	defaultCacheExpiry   = 10 * time.Minute
+	defaultCacheSize     = 256
+	defaultMaxClaims     = 1024
+	defaultHMACAlgorithm = "HS256"
+	defaultCachePolicy   = "arc"
+	maxNamespaceLen      = 64
+	// jweSegments is the number of dot-separated segments in a compact JWE.
+	jweSegments = 5
+	// cacheReportInterval is how often the size of the caches is reported.
+	cacheReportInterval = 1 * time.Minute
+	// sharedCacheKeySet is the keyset tag value used for the metrics of the cache shared by the keysets without a dedicated cache.
+	sharedCacheKeySet = "_shared"
+	// prefetchTimeout is the maximum amount of time spent fetching the remote keysets at startup.
+	prefetchTimeout = 30 * time.Second
+	// defaultRemoteRefreshInterval is the refresh interval used by the keyset cache when neither the configuration nor the remote source specifies one.
+	defaultRemoteRefreshInterval = time.Hour
+	// staleRefreshIntervals is the number of refresh intervals a remote keyset may keep failing to refresh before it is reported as unhealthy.
+	staleRefreshIntervals = 3
+)
+
+// hmacAlgorithms is the set of algorithms supported by symmetric keysets.
+var hmacAlgorithms = map[string]jwa.SignatureAlgorithm{
+	"HS256": jwa.HS256,
+	"HS384": jwa.HS384,
+	"HS512": jwa.HS512,
+}
+
+// cachePolicies maps the supported cache eviction policies to the builder methods that select them.
+var cachePolicies = map[string]func(*gcache.CacheBuilder) *gcache.CacheBuilder{
+	"arc": (*gcache.CacheBuilder).ARC,
+	"lfu": (*gcache.CacheBuilder).LFU,
+	"lru": (*gcache.CacheBuilder).LRU,
+}
+
+// defaultAlgorithms is the set of signature algorithms accepted by keysets that don't define their own allowlist.
+// It deliberately excludes "none".
+var defaultAlgorithms = map[jwa.SignatureAlgorithm]struct{}{
+	jwa.ES256:  {},
+	jwa.ES256K: {},
+	jwa.ES384:  {},
+	jwa.ES512:  {},
+	jwa.EdDSA:  {},
+	jwa.HS256:  {},
+	jwa.HS384:  {},
+	jwa.HS512:  {},
+	jwa.PS256:  {},
+	jwa.PS384:  {},
+	jwa.PS512:  {},
+	jwa.RS256:  {},
+	jwa.RS384:  {},
+	jwa.RS512:  {},
+}
+
+var (
+	cacheEntry            = struct{}{}
+	errNilLocalKeySet     = errors.New("nil local keyset")
+	errNilSymmetricKeySet = errors.New("nil symmetric keyset")
+	errEmptySecret        = errors.New("shared secret is empty")
+	errNoKeySetToVerify   = errors.New("cannot determine keyset to use for validating the JWT")
+	errUnverifiedKeySet   = errors.New("verification is disabled for this keyset")
 )
 
 var (
-	cacheEntry          = struct{}{}
-	errNilLocalKeySet   = errors.New("nil local keyset")
-	errNoKeySetToVerify = errors.New("cannot determine keyset to use for validating the JWT")
+	// ErrJWTRejectedByValidator is the failure reason for tokens rejected by a custom validator.
+	ErrJWTRejectedByValidator = errors.New("JWT rejected by custom validator")
+	// ErrJWTInvalidNamespace is the failure reason for tokens that would place their claims under an invalid or reserved namespace.
+	ErrJWTInvalidNamespace = errors.New("invalid JWT claims namespace")
+	// ErrJWTTooManyClaims is the failure reason for tokens with more claims than the configured maximum.
+	ErrJWTTooManyClaims = errors.New("JWT has too many claims")
+	// ErrJWTAudienceNotAllowed is the failure reason for requests that expect an audience that is not in the configured allowlist.
+	ErrJWTAudienceNotAllowed = errors.New("requested JWT audience is not allowed")
+	// ErrJWTKeySetUnavailable is the failure reason when the keyset required to verify a token cannot be retrieved.
+	ErrJWTKeySetUnavailable = errors.New("JWT keyset unavailable")
+	// ErrJWTNoKeySetForIssuer is the failure reason for tokens whose issuer does not match any of the configured keysets.
+	ErrJWTNoKeySetForIssuer = errors.New("no keyset configured for JWT issuer")
+	// ErrJWTNoKeySetForKeyID is the failure reason for tokens whose key ID does not match a key from exactly one of the configured keysets.
+	ErrJWTNoKeySetForKeyID = errors.New("no keyset configured for JWT key ID")
+	// ErrJWTExpired is the failure reason for expired tokens.
+	ErrJWTExpired = errors.New("JWT expired")
+	// ErrJWTBadSignature is the failure reason for tokens that could not be verified with the keyset.
+	ErrJWTBadSignature = errors.New("JWT signature verification failed")
+	// ErrJWTUnknownKeySet is the failure reason for requests that reference a keyset that is not configured,
+	// or that don't reference a keyset when it cannot be determined from the configuration.
+	ErrJWTUnknownKeySet = errors.New("unknown JWT keyset")
+	// ErrJWTMalformed is the failure reason for tokens that cannot be parsed.
+	ErrJWTMalformed = errors.New("malformed JWT")
+	// ErrJWTAlgorithmNotAllowed is the failure reason for tokens signed with an algorithm that is not allowed by the keyset.
+	ErrJWTAlgorithmNotAllowed = errors.New("JWT signature algorithm not allowed")
+	// ErrJWTDecryptionFailed is the failure reason for JWE-wrapped tokens that could not be decrypted with the configured keys.
+	ErrJWTDecryptionFailed = errors.New("JWT decryption failed")
+	// ErrJWTMissing is the failure reason for requests without a token when a token is required.
+	ErrJWTMissing = errors.New("JWT required but not provided")
+	// ErrJWTUnverified is the failure reason for tokens that cannot be verified when verification is required.
+	ErrJWTUnverified = errors.New("JWT cannot be verified")
+	// ErrJWTMissingClaim is the failure reason for tokens that don't have one of the claims required by the keyset.
+	ErrJWTMissingClaim = errors.New("JWT is missing a required claim")
 )
 
+// failureReasons maps the failure reasons to the values used to tag the failure metric.
+var failureReasons = map[error]string{
+	ErrJWTRejectedByValidator: "rejected_by_validator",
+	ErrJWTInvalidNamespace:    "invalid_namespace",
+	ErrJWTTooManyClaims:       "too_many_claims",
+	ErrJWTAudienceNotAllowed:  "audience_not_allowed",
+	ErrJWTKeySetUnavailable:   "keyset_unavailable",
+	ErrJWTNoKeySetForIssuer:   "no_keyset_for_issuer",
+	ErrJWTNoKeySetForKeyID:    "no_keyset_for_key_id",
+	ErrJWTExpired:             "expired",
+	ErrJWTBadSignature:        "bad_signature",
+	ErrJWTUnknownKeySet:       "unknown_keyset",
+	ErrJWTMalformed:           "malformed",
+	ErrJWTDecryptionFailed:    "decryption_failed",
+	ErrJWTAlgorithmNotAllowed: "algorithm_not_allowed",
+	ErrJWTMissing:             "missing",
+	ErrJWTUnverified:          "unverified",
+	ErrJWTMissingClaim:        "missing_claim",
+}
+
+var namespaceRegexp = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_-]*$`)
+
+// TokenValidator is a custom check applied to a token after the standard validations have passed.
+type TokenValidator func(jwt.Token) error
+
+// jwtError associates a failure reason with the underlying cause so that both can be matched with errors.Is.
+type jwtError struct {
+	reason error
+	cause  error
+}
+
+func (je jwtError) Error() string {
+	return fmt.Sprintf("%v: %v", je.reason, je.cause)
+}
+
+func (je jwtError) Is(target error) bool {
+	return target == je.reason //nolint:errorlint
+}
+
+func (je jwtError) Unwrap() error {
+	return je.cause
+}
+
+// checkNamespace makes sure that a namespace derived from token claims is safe to use.
+// Namespaces must start with a letter, only contain alphanumeric characters, underscores or dashes, and must not
+// shadow any of the reserved namespaces. The latter prevents a token from a less-trusted issuer from masquerading
+// as a token from a trusted one by choosing a colliding claim value. Reserved namespaces are matched case-insensitively
+// and must be provided in lower case.
+func checkNamespace(ns string, reserved map[string]struct{}) error {
+	if len(ns) > maxNamespaceLen {
+		return jwtError{reason: ErrJWTInvalidNamespace, cause: fmt.Errorf("namespace is longer than %d characters", maxNamespaceLen)}
+	}
+
+	if !namespaceRegexp.MatchString(ns) {
+		return jwtError{reason: ErrJWTInvalidNamespace, cause: fmt.Errorf("namespace %q contains invalid characters", ns)}
+	}
+
+	if _, ok := reserved[strings.ToLower(ns)]; ok {
+		return jwtError{reason: ErrJWTInvalidNamespace, cause: fmt.Errorf("namespace %q is reserved", ns)}
+	}
+
+	return nil
+}
+
 type jwtHelper struct {
-	keySets map[string]keySet
-	cache   gcache.Cache
-	verify  bool
+	keySets          map[string]keySet
+	decryptKeys      map[string]keySet
+	allowedAlgs      map[string]map[jwa.SignatureAlgorithm]struct{}
+	validators       map[string][]TokenValidator
+	claimValidators  []jwt.Validator
+	keySetIssuers    map[string]string
+	issuerKeySets    map[string]string
+	clockSkew        time.Duration
+	requestAudiences map[string]struct{}
+	namespaceClaims  []string
+	reservedNS       map[string]struct{}
+	cache            gcache.Cache
+	keySetCaches     map[string]gcache.Cache
+	cacheUsage       []*cacheUsage
+	claimFilters     map[string]claimFilter
+	requiredClaims   map[string][]string
+	cachePolicy      string
+	maxClaims        int
+	truncateClaims   bool
+	resolveByKeyID   bool
+	indexArrays      bool
+	verify           bool
+	requireToken     bool
+	requireVerified  bool
 }
 
-func newJWTHelper(ctx context.Context, conf *JWTConf) *jwtHelper {
+func newJWTHelper(ctx context.Context, conf *JWTConf, opts *options) *jwtHelper {
 	jh := &jwtHelper{verify: true}
+	if opts != nil {
+		jh.validators = opts.validators
+	}
 
 	if conf == nil {
 		return jh
 	}
 
 	jh.verify = !conf.DisableVerification
+	jh.cachePolicy = conf.CachePolicy
+
+	for _, ks := range conf.KeySets {
+		if ks.Decrypt != nil {
+			if jh.decryptKeys == nil {
+				jh.decryptKeys = make(map[string]keySet)
+			}
+			jh.decryptKeys[ks.ID] = newLocalKeySet(ks.Decrypt)
+		}
+
+		if len(ks.RequiredClaims) > 0 {
+			if jh.requiredClaims == nil {
+				jh.requiredClaims = make(map[string][]string)
+			}
+			jh.requiredClaims[ks.ID] = ks.RequiredClaims
+		}
+
+		if len(ks.Claims) == 0 && len(ks.ExcludeClaims) == 0 {
+			continue
+		}
+
+		if jh.claimFilters == nil {
+			jh.claimFilters = make(map[string]claimFilter)
+		}
+		jh.claimFilters[ks.ID] = claimFilter{include: ks.Claims, exclude: ks.ExcludeClaims}
+	}
+	jh.maxClaims = conf.MaxClaims
+	jh.truncateClaims = conf.TruncateClaims
+	jh.indexArrays = conf.IndexArrayClaims
+	jh.resolveByKeyID = conf.ResolveKeySetByKeyID
+	jh.requireToken = conf.RequireToken
+	jh.requireVerified = conf.RequireVerified
+	jh.clockSkew = conf.ClockSkew
+
+	if len(conf.AcceptableIssuers) > 0 {
+		jh.claimValidators = append(jh.claimValidators, oneOfValidator(jwt.IssuerKey, conf.AcceptableIssuers, func(t jwt.Token) []string {
+			if iss := t.Issuer(); iss != "" {
+				return []string{iss}
+			}
+			return nil
+		}))
+	}
+
+	if len(conf.AcceptableAudiences) > 0 {
+		jh.claimValidators = append(jh.claimValidators, oneOfValidator(jwt.AudienceKey, conf.AcceptableAudiences, jwt.Token.Audience))
+	}
+
+	jh.namespaceClaims = conf.NamespaceClaims
+	if len(conf.ReservedNamespaces) > 0 {
+		jh.reservedNS = make(map[string]struct{}, len(conf.ReservedNamespaces))
+		for _, ns := range conf.ReservedNamespaces {
+			jh.reservedNS[strings.ToLower(ns)] = struct{}{}
+		}
+	}
+
+	if len(conf.RequestAudiences) > 0 {
+		jh.requestAudiences = make(map[string]struct{}, len(conf.RequestAudiences))
+		for _, aud := range conf.RequestAudiences {
+			jh.requestAudiences[aud] = struct{}{}
+		}
+	}
 
 	if jh.verify {
 		jh.keySets = make(map[string]keySet, len(conf.KeySets))
+		jh.keySetIssuers = make(map[string]string)
+		jh.issuerKeySets = make(map[string]string)
+		jh.keySetCaches = make(map[string]gcache.Cache)
+		jh.allowedAlgs = make(map[string]map[jwa.SignatureAlgorithm]struct{}, len(conf.KeySets))
 
-		var jwkCache *jwk.Cache
+		hasRemoteKeySets := false
 		for _, ks := range conf.KeySets {
 			ks := ks
+			if ks.Issuer != "" {
+				jh.keySetIssuers[ks.ID] = ks.Issuer
+				jh.issuerKeySets[ks.Issuer] = ks.ID
+			}
+
+			jh.allowedAlgs[ks.ID] = defaultAlgorithms
+			if len(ks.AllowedAlgorithms) > 0 {
+				allowed := make(map[jwa.SignatureAlgorithm]struct{}, len(ks.AllowedAlgorithms))
+				for _, alg := range ks.AllowedAlgorithms {
+					allowed[jwa.SignatureAlgorithm(alg)] = struct{}{}
+				}
+				jh.allowedAlgs[ks.ID] = allowed
+			}
+
 			switch {
-			case ks.Remote != nil:
-				if jwkCache == nil {
-					log := logging.FromContext(ctx).Named("auxdata")
-					errSink := func(err error) {
-						log.Warn("Error refreshing keyset", zap.Error(err))
-					}
+			case ks.CacheSize > 0:
+				jh.keySetCaches[ks.ID] = jh.mkCache(ks.ID, ks.CacheSize)
+			case ks.CacheSize < 0:
+				// caching is disabled for this keyset
+				jh.keySetCaches[ks.ID] = nil
+			}
 
-					jwkCache = jwk.NewCache(ctx, jwk.WithErrSink(httprc.ErrSinkFunc(errSink)))
+			switch {
+			case ks.DisableVerification:
+				jh.keySets[ks.ID] = unverifiedKeySet{}
+			case ks.Remote != nil:
+				// each remote keyset has its own cache so that refresh errors can be attributed to the keyset
+				health := &keySetHealth{}
+				log := logging.FromContext(ctx).Named("auxdata").With(zap.String("keyset", ks.ID), zap.String("url", ks.Remote.URL))
+				errSink := func(err error) {
+					log.Warn("Error refreshing keyset", zap.Error(err))
+					health.failure(err)
 				}
-				jh.keySets[ks.ID] = newRemoteKeySet(jwkCache, ks.Remote)
+
+				jwkCache := jwk.NewCache(ctx, jwk.WithErrSink(httprc.ErrSinkFunc(errSink)))
+				jh.keySets[ks.ID] = newRemoteKeySetWithHealth(ctx, jwkCache, ks.Remote, health)
+				hasRemoteKeySets = true
 			case ks.Local != nil:
 				jh.keySets[ks.ID] = newLocalKeySet(ks.Local)
+			case ks.Symmetric != nil:
+				jh.keySets[ks.ID] = newSymmetricKeySet(ks.Symmetric)
 			}
 		}
 
 		if conf.CacheSize > 0 {
-			jh.cache = mkCache(conf.CacheSize)
+			jh.cache = jh.mkCache(sharedCacheKeySet, conf.CacheSize)
+		}
+
+		if len(jh.cacheUsage) > 0 {
+			go jh.reportCacheUsage(ctx, cacheReportInterval)
+		}
+
+		if hasRemoteKeySets && conf.PrefetchEnabled() {
+			go jh.prefetch(ctx)
 		}
 	}
 
 	return jh
 }
 
-func (j *jwtHelper) extract(ctx context.Context, auxJWT *requestv1.AuxData_JWT) (map[string]*structpb.Value, error) {
+// prefetch warms up the remote keysets in the background and logs the outcome.
+func (j *jwtHelper) prefetch(ctx context.Context) {
+	log := logging.FromContext(ctx).Named("auxdata")
+	if err := j.WarmUp(ctx); err != nil {
+		log.Warn("Failed to prefetch remote keysets: they will be fetched again when they are used", zap.Error(err))
+		return
+	}
+
+	log.Debug("Prefetched remote keysets")
+}
+
+// WarmUp fetches all the remote keysets so that the first requests using them don't have to wait for them to be fetched.
+// The keysets are fetched concurrently and the returned error combines all the failures. It gives up after prefetchTimeout.
+func (j *jwtHelper) WarmUp(ctx context.Context) error {
+	ctx, cancelFn := context.WithTimeout(ctx, prefetchTimeout)
+	defer cancelFn()
+
+	ids := make([]string, 0, len(j.keySets))
+	for id, ks := range j.keySets {
+		if _, ok := ks.(*remoteKeySet); ok {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+
+	errs := make([]error, len(ids))
+	var wg sync.WaitGroup
+	for i, id := range ids {
+		rks, _ := j.keySets[id].(*remoteKeySet)
+		wg.Add(1)
+		go func(i int, id string) {
+			defer wg.Done()
+			if err := rks.refresh(ctx); err != nil {
+				errs[i] = fmt.Errorf("keyset '%s': %w", id, err)
+			}
+		}(i, id)
+	}
+	wg.Wait()
+
+	return multierr.Combine(errs...)
+}
+
+// Healthy returns an error if any of the remote keysets cannot be used to verify tokens. A keyset is unhealthy if it is
+// misconfigured, if it has never been fetched successfully despite trying, or if it has kept failing to refresh for more
+// than staleRefreshIntervals refresh intervals since it was last fetched. Keysets that have not been used yet are healthy.
+// This doesn't perform any network I/O so it is safe to call from a readiness probe.
+func (j *jwtHelper) Healthy() error {
+	ids := make([]string, 0, len(j.keySets))
+	for id, ks := range j.keySets {
+		if _, ok := ks.(*remoteKeySet); ok {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+
+	var errs error
+	now := time.Now()
+	for _, id := range ids {
+		rks, _ := j.keySets[id].(*remoteKeySet)
+		if err := rks.healthy(now); err != nil {
+			errs = multierr.Append(errs, fmt.Errorf("keyset '%s': %w", id, err))
+		}
+	}
+
+	return errs
+}
+
+func (j *jwtHelper) extract(ctx context.Context, auxJWT *requestv1.AuxData_JWT, opts ...ExtractOpt) (map[string]*structpb.Value, error) {
 	if auxJWT == nil || auxJWT.Token == "" {
+		if j.requireToken {
+			err := jwtError{reason: ErrJWTMissing, cause: errors.New("request does not include a token")}
+			recordFailure(err)
+			return nil, err
+		}
 		return nil, nil
 	}
 
+	return j.verifyToken(ctx, auxJWT.Token, auxJWT.KeySetId, opts...)
+}
+
+// extractNamespaced returns a copy of the given claims with the claims of each of the given tokens added under the namespace
+// the token is keyed by. The namespaces are chosen by the caller rather than derived from the tokens, so they are only
+// checked for invalid characters and not against the reserved namespaces.
+func (j *jwtHelper) extractNamespaced(ctx context.Context, claims map[string]*structpb.Value, tokens map[string]*requestv1.AuxData_JWT, opts ...ExtractOpt) (map[string]*structpb.Value, error) {
+	namespaces := make([]string, 0, len(tokens))
+	for ns := range tokens {
+		namespaces = append(namespaces, ns)
+	}
+	sort.Strings(namespaces)
+
+	// the claims may be shared with the cache so they are copied instead of being modified
+	out := make(map[string]*structpb.Value, len(claims)+len(tokens))
+	for k, v := range claims {
+		out[k] = v
+	}
+
+	for _, ns := range namespaces {
+		if err := checkNamespace(ns, nil); err != nil {
+			recordFailure(err)
+			return nil, err
+		}
+
+		if _, ok := out[ns]; ok {
+			err := jwtError{reason: ErrJWTInvalidNamespace, cause: fmt.Errorf("namespace %q is already used by a claim of the jwt token", ns)}
+			recordFailure(err)
+			return nil, err
+		}
+
+		auxJWT := tokens[ns]
+		if auxJWT.GetToken() == "" {
+			err := jwtError{reason: ErrJWTMissing, cause: fmt.Errorf("no token for namespace %q", ns)}
+			recordFailure(err)
+			return nil, err
+		}
+
+		nsClaims, err := j.verifyToken(ctx, auxJWT.Token, auxJWT.KeySetId, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to extract JWT for namespace %q: %w", ns, err)
+		}
+
+		out[ns] = structpb.NewStructValue(&structpb.Struct{Fields: nsClaims})
+	}
+
+	return out, nil
+}
+
+func (j *jwtHelper) verifyToken(ctx context.Context, rawToken, requestedKeySetID string, opts ...ExtractOpt) (map[string]*structpb.Value, error) {
+	token, err := ParseBearerToken(rawToken)
+	if err != nil {
+		err = jwtError{reason: ErrJWTMalformed, cause: err}
+		recordFailure(err)
+		return nil, err
+	}
+
+	eo := mkExtractOptions(opts)
+	if err := j.checkAudience(eo.audience); err != nil {
+		recordFailure(err)
+		return nil, err
+	}
+
 	ctx, span := tracing.StartSpan(ctx, "aux_data.ExtractJWT")
 	defer span.End()
 
-	cacheKey := ""
-	if j.cache != nil {
-		if lastIdx := strings.LastIndexByte(auxJWT.Token, '.'); lastIdx > 0 {
-			// use the token signature as the cache key
-			cacheKey = auxJWT.Token[lastIdx:]
+	// the rest of the verification (including the cache key) works on the inner token
+	if isEncrypted(token) {
+		if token, err = j.decrypt(ctx, token, requestedKeySetID); err != nil {
+			recordFailure(err)
+			return nil, err
 		}
 	}
 
-	parseOpts, err := j.parseOptions(ctx, auxJWT, cacheKey)
+	keySetID, err := j.resolveKeySet(token, requestedKeySetID)
+	if errors.Is(err, errNoKeySetToVerify) && j.resolveByKeyID {
+		keySetID, err = j.keySetForKeyID(ctx, token, true)
+	}
 	if err != nil {
+		recordFailure(err)
 		return nil, err
 	}
 
-	return j.doExtract(ctx, auxJWT, parseOpts, cacheKey)
-}
-
-func (j *jwtHelper) parseOptions(ctx context.Context, auxJWT *requestv1.AuxData_JWT, cacheKey string) ([]jwt.ParseOption, error) {
-	if !j.verify {
-		return []jwt.ParseOption{jwt.WithVerify(false), jwt.WithValidate(true)}, nil
+	if j.requireVerified && !j.verifies(keySetID) {
+		err := jwtError{reason: ErrJWTUnverified, cause: fmt.Errorf("keyset '%s': %w", keySetID, errUnverifiedKeySet)}
+		recordFailure(err)
+		return nil, err
 	}
 
-	// Check whether this token has already been verified
-	if cacheKey != "" {
-		if _, err := j.cache.GetIFPresent(cacheKey); err == nil {
-			cacheHit()
-			return []jwt.ParseOption{jwt.WithVerify(false), jwt.WithValidate(true)}, nil
+	var msg *jws.Message
+	if j.verifies(keySetID) {
+		if msg, err = jws.ParseString(token); err != nil {
+			err = jwtError{reason: ErrJWTMalformed, cause: fmt.Errorf("failed to parse JWS: %w", err)}
+			recordFailure(err)
+			return nil, err
+		}
+
+		if err := j.checkAlgorithm(msg, keySetID); err != nil {
+			recordFailure(err)
+			return nil, err
 		}
-		cacheMiss()
 	}
 
-	var ks keySet
+	cacheKey := ""
+	if msg != nil && j.cacheFor(keySetID) != nil {
+		cacheKey = mkCacheKey(keySetID, keyIDOf(msg), token)
+	}
 
-	// if keyset ID is not provided and we only have one keyset configured, use that as the default.
-	if auxJWT.KeySetId == "" {
-		if len(j.keySets) != 1 {
-			return nil, errNoKeySetToVerify
-		}
+	parseOpts, err := j.parseOptions(ctx, keySetID, cacheKey, msg, eo)
+	if err != nil {
+		recordFailure(err)
+		return nil, err
+	}
+
+	jwtPBMap, err := j.doExtract(ctx, token, keySetID, parseOpts, cacheKey)
+	if err != nil {
+		recordFailure(err)
+		return nil, err
+	}
+
+	namespaced, err := j.namespace(jwtPBMap)
+	if err != nil {
+		recordFailure(err)
+		return nil, err
+	}
+
+	return namespaced, nil
+}
+
+// namespace places the claims under the namespace given by the value of the first claim listed in namespaceClaims
+// that the token has. Tokens that have none of them are rejected, because their claims would otherwise end up at the
+// top level where policies expect the claims of trusted tokens. The claims are returned unchanged if no namespaceClaims
+// are configured.
+func (j *jwtHelper) namespace(claims map[string]*structpb.Value) (map[string]*structpb.Value, error) {
+	if len(j.namespaceClaims) == 0 {
+		return claims, nil
+	}
 
-		for _, ksDef := range j.keySets {
-			ks = ksDef
+	for _, claim := range j.namespaceClaims {
+		v, ok := claims[claim]
+		if !ok {
+			continue
 		}
-	} else { // use the keyset specified in the request
-		ksDef, ok := j.keySets[auxJWT.KeySetId]
+
+		ns, ok := v.GetKind().(*structpb.Value_StringValue)
 		if !ok {
-			return nil, fmt.Errorf("keyset not found: %s", auxJWT.KeySetId)
+			return nil, jwtError{reason: ErrJWTInvalidNamespace, cause: fmt.Errorf("namespace claim %q is not a string", claim)}
 		}
-		ks = ksDef
+
+		if err := checkNamespace(ns.StringValue, j.reservedNS); err != nil {
+			return nil, err
+		}
+
+		// the claims may be shared with the cache so they are wrapped instead of being modified
+		return map[string]*structpb.Value{ns.StringValue: structpb.NewStructValue(&structpb.Struct{Fields: claims})}, nil
 	}
 
-	jwks, err := ks.keySet(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("failed to retrieve keyset: %w", err)
+	return nil, jwtError{reason: ErrJWTInvalidNamespace, cause: fmt.Errorf("token has none of the namespace claims %q", j.namespaceClaims)}
+}
+
+// checkAudience checks whether the audience requested for a single extraction is in the configured allowlist.
+func (j *jwtHelper) checkAudience(audience string) error {
+	if audience == "" {
+		return nil
+	}
+
+	if _, ok := j.requestAudiences[audience]; !ok {
+		return jwtError{reason: ErrJWTAudienceNotAllowed, cause: fmt.Errorf("audience %q is not in the allowlist", audience)}
 	}
 
-	return []jwt.ParseOption{jwt.WithKeySet(jwks), jwt.WithValidate(true)}, nil
+	return nil
 }
 
-func (j *jwtHelper) doExtract(ctx context.Context, auxJWT *requestv1.AuxData_JWT, parseOpts []jwt.ParseOption, cacheKey string) (map[string]*structpb.Value, error) {
-	token, err := jwt.ParseString(auxJWT.Token, parseOpts...)
+// isEncrypted returns true if the token looks like a JWE in compact serialization.
+func isEncrypted(token string) bool {
+	return strings.Count(token, ".") == jweSegments-1
+}
+
+// decrypt returns the JWS wrapped in the given JWE.
+// If the request specifies a keyset, only the decryption keys of that keyset are used. Otherwise, the decryption keys
+// of all keysets are tried in turn because the keyset that verifies the token can only be determined after it is decrypted.
+func (j *jwtHelper) decrypt(ctx context.Context, token, keySetID string) (string, error) {
+	var keySetIDs []string
+	if keySetID != "" {
+		if _, ok := j.decryptKeys[keySetID]; !ok {
+			return "", jwtError{reason: ErrJWTDecryptionFailed, cause: fmt.Errorf("keyset %q does not have decryption keys", keySetID)}
+		}
+		keySetIDs = []string{keySetID}
+	} else {
+		for id := range j.decryptKeys {
+			keySetIDs = append(keySetIDs, id)
+		}
+		sort.Strings(keySetIDs)
+	}
+
+	if len(keySetIDs) == 0 {
+		return "", jwtError{reason: ErrJWTDecryptionFailed, cause: errors.New("no decryption keys configured")}
+	}
+
+	msg, err := jwe.Parse([]byte(token))
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse JWT: %w", err)
+		return "", jwtError{reason: ErrJWTMalformed, cause: fmt.Errorf("failed to parse JWE: %w", err)}
 	}
 
-	if cacheKey != "" {
-		expiry := defaultCacheExpiry
-		if exp := time.Until(token.Expiration()); exp > 0 {
-			expiry = exp
+	alg := msg.ProtectedHeaders().Algorithm()
+	kid := msg.ProtectedHeaders().KeyID()
+
+	var errs error
+	for _, id := range keySetIDs {
+		ks, err := j.decryptKeys[id].keySet(ctx)
+		if err != nil {
+			errs = multierr.Append(errs, fmt.Errorf("failed to retrieve decryption keys of keyset %q: %w", id, err))
+			continue
+		}
+
+		payload, err := decryptWithKeySet(token, alg, kid, ks)
+		if err != nil {
+			errs = multierr.Append(errs, fmt.Errorf("keyset %q: %w", id, err))
+			continue
 		}
 
-		_ = j.cache.SetWithExpire(cacheKey, cacheEntry, expiry)
+		return string(payload), nil
 	}
 
-	jwtPBMap := make(map[string]*structpb.Value)
-	for iter := token.Iterate(ctx); iter.Next(ctx); {
-		p := iter.Pair()
-		key, ok := p.Key.(string)
-		if !ok {
-			logging.FromContext(ctx).Named("auxdata").
-				Warn("Ignoring JWT key-value pair because the key is not a string", zap.Any("pair", p), zap.Error(err))
+	return "", jwtError{reason: ErrJWTDecryptionFailed, cause: errs}
+}
+
+// decryptWithKeySet tries each key of the keyset that matches the key ID of the JWE (if any) until one succeeds.
+// The key encryption algorithm is taken from the JWE header because keys loaded from PEM files do not have one.
+func decryptWithKeySet(token string, alg jwa.KeyEncryptionAlgorithm, kid string, ks jwk.Set) ([]byte, error) {
+	for i := 0; i < ks.Len(); i++ {
+		key, _ := ks.Key(i)
+		if usage := key.KeyUsage(); usage != "" && usage != jwk.ForEncryption.String() {
+			continue
 		}
 
-		value, err := util.ToStructPB(p.Value)
-		if err != nil {
-			logging.FromContext(ctx).Named("auxdata").
-				Warn("Ignoring JWT key-value pair because the value is not in a known format", zap.Any("pair", p), zap.Error(err))
+		if kid != "" && key.KeyID() != "" && key.KeyID() != kid {
 			continue
 		}
 
-		jwtPBMap[key] = value
+		var raw any
+		if err := key.Raw(&raw); err != nil {
+			continue
+		}
+
+		if payload, err := jwe.Decrypt([]byte(token), jwe.WithKey(alg, raw)); err == nil {
+			return payload, nil
+		}
 	}
 
-	return jwtPBMap, nil
+	return nil, errors.New("no matching key could decrypt the token")
 }
 
-type keySet interface {
-	keySet(context.Context) (jwk.Set, error)
+// verifies returns true if the signatures of the tokens resolved to the given keyset are verified.
+func (j *jwtHelper) verifies(keySetID string) bool {
+	if !j.verify {
+		return false
+	}
+
+	_, unverified := j.keySets[keySetID].(unverifiedKeySet)
+	return !unverified
 }
 
-// remoteKeySet holds an auto-refreshing remote keyset.
-type remoteKeySet struct {
-	*jwk.Cache
-	url string
+// checkAlgorithm makes sure that the token is signed with one of the algorithms allowed by the keyset.
+// The algorithm is read from the token header before the signature is verified so that a token is rejected
+// even if the keyset contains a key that would accept it.
+func (j *jwtHelper) checkAlgorithm(msg *jws.Message, keySetID string) error {
+	allowed := j.allowedAlgs[keySetID]
+	for _, sig := range msg.Signatures() {
+		alg := sig.ProtectedHeaders().Algorithm()
+		if _, ok := allowed[alg]; !ok {
+			return jwtError{reason: ErrJWTAlgorithmNotAllowed, cause: fmt.Errorf("algorithm %q is not allowed by keyset %q", alg, keySetID)}
+		}
+	}
+
+	return nil
 }
 
-func newRemoteKeySet(cache *jwk.Cache, src *RemoteSource) *remoteKeySet {
-	if src.RefreshInterval > 0 {
-		_ = cache.Register(src.URL, jwk.WithRefreshInterval(src.RefreshInterval))
-	} else {
-		_ = cache.Register(src.URL)
+// checkKeyAlgorithm makes sure that the algorithm in the token header matches the algorithm declared by the alg field of the
+// key that the token refers to. Large keysets often contain keys for different algorithms, and a key must only be used with
+// the algorithm it was issued for. Keys that don't declare an algorithm are only restricted by the algorithms allowed by the keyset.
+func checkKeyAlgorithm(msg *jws.Message, jwks jwk.Set, useDefault bool) error {
+	for _, sig := range msg.Signatures() {
+		headers := sig.ProtectedHeaders()
+
+		var key jwk.Key
+		var ok bool
+		if kid := headers.KeyID(); kid != "" {
+			key, ok = jwks.LookupKeyID(kid)
+		} else if useDefault && jwks.Len() == 1 {
+			key, ok = jwks.Key(0)
+		}
+
+		// if there's no matching key, the signature verification fails anyway
+		if !ok {
+			continue
+		}
+
+		if keyAlg := key.Algorithm().String(); keyAlg != "" && keyAlg != headers.Algorithm().String() {
+			return jwtError{reason: ErrJWTAlgorithmNotAllowed, cause: fmt.Errorf("algorithm %q does not match algorithm %q of key %q", headers.Algorithm(), keyAlg, key.KeyID())}
+		}
 	}
 
-	return &remoteKeySet{Cache: cache, url: src.URL}
+	return nil
 }
 
-func (rks *remoteKeySet) keySet(ctx context.Context) (jwk.Set, error) {
-	return rks.Get(ctx, rks.url)
+// keyIDOf returns the key ID from the header of the first signature of the given message, or an empty string if there isn't one.
+func keyIDOf(msg *jws.Message) string {
+	if sigs := msg.Signatures(); len(sigs) > 0 {
+		return sigs[0].ProtectedHeaders().KeyID()
+	}
+
+	return ""
 }
 
-// localKeySet represents a keyset defined manually through the configuration.
-type localKeySet func(context.Context) (jwk.Set, error)
+// resolveKeySet determines the ID of the keyset that should be used to verify the given token.
+// It only consults the configuration and never fetches the keyset or verifies the token.
+// If verification is disabled, the keyset ID provided in the request (if any) is returned as-is.
+func (j *jwtHelper) resolveKeySet(token, keySetID string) (string, error) {
+	if !j.verify {
+		return keySetID, nil
+	}
 
-func newLocalKeySet(src *LocalSource) localKeySet {
-	if src.Data != "" {
-		kbytes, err := base64.StdEncoding.DecodeString(src.Data)
-		if err != nil {
-			return func(context.Context) (jwk.Set, error) {
-				return nil, fmt.Errorf("failed to apply base64 decoder to key data: %w", err)
-			}
+	// if keyset ID is not provided and keysets are mapped to issuers, use the keyset of the token issuer.
+	if keySetID == "" && len(j.issuerKeySets) > 0 {
+		return j.keySetForIssuer(token)
+	}
+
+	// if keyset ID is not provided and we only have one keyset configured, use that as the default.
+	if keySetID == "" {
+		if len(j.keySets) != 1 {
+			return "", jwtError{reason: ErrJWTUnknownKeySet, cause: errNoKeySetToVerify}
 		}
 
-		ks, err := jwk.Parse(kbytes, jwk.WithPEM(src.PEM))
-		if err != nil {
-			return func(context.Context) (jwk.Set, error) {
-				return nil, fmt.Errorf("failed to parse key data: %w", err)
-			}
+		var defaultID string
+		for id := range j.keySets {
+			defaultID = id
 		}
 
-		return func(context.Context) (jwk.Set, error) { return ks, nil }
+		return defaultID, nil
 	}
 
-	ks, err := jwk.ReadFile(src.File, jwk.WithPEM(src.PEM))
+	// use the keyset specified in the request
+	if _, ok := j.keySets[keySetID]; !ok {
+		return "", jwtError{reason: ErrJWTUnknownKeySet, cause: fmt.Errorf("keyset not found: %s", keySetID)}
+	}
+
+	return keySetID, nil
+}
+
+// resolveKeySetLocally determines the ID of the keyset that verifyToken would use for the given token without fetching
+// any remote keysets. Bearer tokens and JWE-wrapped tokens are handled in the same way as verifyToken does.
+func (j *jwtHelper) resolveKeySetLocally(ctx context.Context, rawToken, keySetID string) (string, error) {
+	token, err := ParseBearerToken(rawToken)
 	if err != nil {
-		return func(context.Context) (jwk.Set, error) {
-			return nil, fmt.Errorf("failed to read keyset from '%s': %w", src.File, err)
+		return "", jwtError{reason: ErrJWTMalformed, cause: err}
+	}
+
+	if isEncrypted(token) {
+		if token, err = j.decrypt(ctx, token, keySetID); err != nil {
+			return "", err
 		}
 	}
 
-	return func(context.Context) (jwk.Set, error) { return ks, nil }
+	resolved, err := j.resolveKeySet(token, keySetID)
+	if errors.Is(err, errNoKeySetToVerify) && j.resolveByKeyID {
+		return j.keySetForKeyID(ctx, token, false)
+	}
+
+	return resolved, err
 }
 
-func (lks localKeySet) keySet(ctx context.Context) (jwk.Set, error) {
-	if lks == nil {
-		return nil, errNilLocalKeySet
+// keySetForIssuer returns the ID of the keyset configured for the issuer of the given token.
+// The issuer is read from the unverified token, so it must only be used to select the keyset for verifying the token.
+// The keyset's issuer is then enforced during validation, which guarantees that a token cannot claim an issuer
+// other than the one that signed it.
+func (j *jwtHelper) keySetForIssuer(token string) (string, error) {
+	unverified, err := jwt.ParseString(token, jwt.WithVerify(false), jwt.WithValidate(false))
+	if err != nil {
+		return "", jwtError{reason: ErrJWTMalformed, cause: fmt.Errorf("failed to parse JWT: %w", err)}
 	}
 
-	return lks(ctx)
-}
+	iss := unverified.Issuer()
+	if iss == "" {
+		return "", jwtError{reason: ErrJWTNoKeySetForIssuer, cause: errors.New("token does not have an issuer")}
+	}
 
-func mkCache(size int) gcache.Cache {
-	_ = stats.RecordWithTags(context.Background(),
-		[]tag.Mutator{tag.Upsert(metrics.KeyCacheKind, cacheKind)},
-		metrics.CacheMaxSize.M(int64(size)),
-	)
+	keySetID, ok := j.issuerKeySets[iss]
+	if !ok {
+		return "", jwtError{reason: ErrJWTNoKeySetForIssuer, cause: fmt.Errorf("issuer %q does not match any keyset", iss)}
+	}
 
-	gauge := metrics.MakeCacheGauge(cacheKind)
-	return gcache.New(size).
-		ARC().
-		AddedFunc(func(_, _ any) {
+	return keySetID, nil
+}
+
+// keySetForKeyID returns the ID of the only keyset that contains the key referenced by the kid header of the given token.
+// Unlike resolveKeySet, it has to retrieve all the keysets. The token is still verified by the keyset that is found,
+// so the restrictions of that keyset (such as the allowed algorithms and the issuer) apply as usual.
+func (j *jwtHelper) keySetForKeyID(ctx context.Context, token string, fetchRemote bool) (string, error) {
+	msg, err := jws.ParseString(token)
+	if err != nil {
+		return "", jwtError{reason: ErrJWTMalformed, cause: fmt.Errorf("failed to parse JWS: %w", err)}
+	}
+
+	kid := keyIDOf(msg)
+	if kid == "" {
+		return "", jwtError{reason: ErrJWTNoKeySetForKeyID, cause: errors.New("token does not have a key ID")}
+	}
+
+	keySetIDs := make([]string, 0, len(j.keySets))
+	for id := range j.keySets {
+		// the key ID of an unverified token could be chosen to match any key, so unverified keysets are never selected this way
+		if j.verifies(id) {
+			keySetIDs = append(keySetIDs, id)
+		}
+	}
+	sort.Strings(keySetIDs)
+
+	if !fetchRemote {
+		for _, id := range keySetIDs {
+			if _, ok := j.keySets[id].(*remoteKeySet); ok {
+				return "", ErrKeyIDResolutionRequiresFetch
+			}
+		}
+	}
+
+	var matches []string
+	var errs error
+	for _, id := range keySetIDs {
+		jwks, err := j.keySets[id].keySet(ctx)
+		if err != nil {
+			errs = multierr.Append(errs, fmt.Errorf("failed to retrieve keyset %q: %w", id, err))
+			continue
+		}
+
+		if _, ok := jwks.LookupKeyID(kid); ok {
+			matches = append(matches, id)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		if errs != nil {
+			return "", jwtError{reason: ErrJWTKeySetUnavailable, cause: errs}
+		}
+		return "", jwtError{reason: ErrJWTNoKeySetForKeyID, cause: fmt.Errorf("key ID %q does not match any keyset", kid)}
+	case 1:
+		return matches[0], nil
+	default:
+		return "", jwtError{reason: ErrJWTNoKeySetForKeyID, cause: fmt.Errorf("key ID %q matches multiple keysets: %s", kid, strings.Join(matches, ", "))}
+	}
+}
+
+func (j *jwtHelper) parseOptions(ctx context.Context, keySetID, cacheKey string, msg *jws.Message, eo *extractOptions) ([]jwt.ParseOption, error) {
+	// claims are validated on every request (including cache hits) because a cached token is only known to have a valid signature
+	validateOpts := make([]jwt.ParseOption, 0, len(j.claimValidators)+3) //nolint:gomnd
+	if j.clockSkew > 0 {
+		validateOpts = append(validateOpts, jwt.WithAcceptableSkew(j.clockSkew))
+	}
+
+	if iss, ok := j.keySetIssuers[keySetID]; ok {
+		validateOpts = append(validateOpts, jwt.WithIssuer(iss))
+	}
+
+	for _, v := range j.claimValidators {
+		validateOpts = append(validateOpts, jwt.WithValidator(v))
+	}
+
+	// the audience requested for this extraction can differ between requests for the same token
+	if eo != nil && eo.audience != "" {
+		validateOpts = append(validateOpts, jwt.WithAudience(eo.audience))
+	}
+
+	if !j.verifies(keySetID) {
+		return append([]jwt.ParseOption{jwt.WithVerify(false), jwt.WithValidate(true)}, validateOpts...), nil
+	}
+
+	// Check whether this token has already been verified
+	if cacheKey != "" {
+		if _, err := j.cacheFor(keySetID).GetIFPresent(cacheKey); err == nil {
+			cacheHit(keySetID)
+			return append([]jwt.ParseOption{jwt.WithVerify(false), jwt.WithValidate(true)}, validateOpts...), nil
+		}
+		cacheMiss(keySetID)
+	}
+
+	jwks, err := j.keySets[keySetID].keySet(ctx)
+	if err != nil {
+		return nil, jwtError{reason: ErrJWTKeySetUnavailable, cause: fmt.Errorf("failed to retrieve keyset: %w", err)}
+	}
+
+	var keySetOpts []any
+	_, useDefault := j.keySets[keySetID].(symmetricKeySet)
+	if useDefault {
+		// tokens signed with a shared secret often don't have a key ID
+		keySetOpts = append(keySetOpts, jws.WithUseDefault(true))
+	}
+
+	if err := checkKeyAlgorithm(msg, jwks, useDefault); err != nil {
+		return nil, err
+	}
+
+	return append([]jwt.ParseOption{jwt.WithKeySet(jwks, keySetOpts...), jwt.WithValidate(true)}, validateOpts...), nil
+}
+
+func (j *jwtHelper) doExtract(ctx context.Context, rawToken, keySetID string, parseOpts []jwt.ParseOption, cacheKey string) (map[string]*structpb.Value, error) {
+	token, err := jwt.ParseString(rawToken, parseOpts...)
+	if err != nil {
+		err = fmt.Errorf("failed to parse JWT: %w", err)
+		if reason := parseFailureReason(rawToken, err); reason != nil {
+			return nil, jwtError{reason: reason, cause: err}
+		}
+		return nil, err
+	}
+
+	for _, claim := range j.requiredClaims[keySetID] {
+		if v, ok := lookupClaim(token, claim); !ok || isEmptyClaim(v) {
+			return nil, jwtError{reason: ErrJWTMissingClaim, cause: fmt.Errorf("required claim %q is missing or empty", claim)}
+		}
+	}
+
+	for _, validate := range j.validators[keySetID] {
+		if err := validate(token); err != nil {
+			return nil, jwtError{reason: ErrJWTRejectedByValidator, cause: err}
+		}
+	}
+
+	if cacheKey != "" {
+		if expiry, ok := j.cacheExpiry(token); ok {
+			_ = j.cacheFor(keySetID).SetWithExpire(cacheKey, cacheEntry, expiry)
+		}
+	}
+
+	filter, hasFilter := j.claimFilters[keySetID]
+	jwtPBMap := make(map[string]*structpb.Value)
+	numClaims := 0
+	for iter := token.Iterate(ctx); iter.Next(ctx); {
+		numClaims++
+		if j.maxClaims > 0 && numClaims > j.maxClaims {
+			if !j.truncateClaims {
+				return nil, jwtError{reason: ErrJWTTooManyClaims, cause: fmt.Errorf("token has more than %d claims", j.maxClaims)}
+			}
+
+			logging.FromContext(ctx).Named("auxdata").
+				Warn("Ignoring JWT claims exceeding the configured maximum", zap.Int("maxClaims", j.maxClaims))
+			break
+		}
+
+		p := iter.Pair()
+		key, ok := p.Key.(string)
+		if !ok {
+			logging.FromContext(ctx).Named("auxdata").
+				Warn("Ignoring JWT key-value pair because the key is not a string", zap.Any("pair", p), zap.Error(err))
+		}
+
+		if hasFilter && !filter.mayInclude(key) {
+			continue
+		}
+
+		value, err := util.ToStructPB(p.Value)
+		if err != nil {
+			logging.FromContext(ctx).Named("auxdata").
+				Warn("Ignoring JWT key-value pair because the value is not in a known format", zap.Any("pair", p), zap.Error(err))
+			continue
+		}
+
+		jwtPBMap[key] = value
+	}
+
+	if hasFilter {
+		jwtPBMap = filter.apply(jwtPBMap)
+	}
+
+	if j.indexArrays {
+		indexArrays(jwtPBMap)
+	}
+
+	return jwtPBMap, nil
+}
+
+// parseFailureReason determines why the given token could not be parsed. It returns nil for validation failures
+// that don't have a dedicated reason. The claims obtained by parsing the token again without verification are only
+// used to tell apart a malformed token from a token that failed verification and are never trusted.
+func parseFailureReason(token string, err error) error {
+	if jwt.IsValidationError(err) {
+		if errors.Is(err, jwt.ErrTokenExpired()) {
+			return ErrJWTExpired
+		}
+
+		return nil
+	}
+
+	if _, perr := jwt.ParseString(token, jwt.WithVerify(false), jwt.WithValidate(false)); perr != nil {
+		return ErrJWTMalformed
+	}
+
+	return ErrJWTBadSignature
+}
+
+// cacheFor returns the cache of verified tokens for the given keyset, or nil if caching is disabled.
+// Keysets without a dedicated cache share the global cache.
+func (j *jwtHelper) cacheFor(keySetID string) gcache.Cache {
+	if c, ok := j.keySetCaches[keySetID]; ok {
+		return c
+	}
+
+	return j.cache
+}
+
+// mkCacheKey returns the key used to cache the verification result of the given token, or an empty string if the token is malformed.
+// The keyset ID is included because the shared cache holds tokens verified by different keysets, and the key ID is included
+// so that the entries of keysets with many keys are kept apart by key. The whole token is hashed (rather than just using its
+// signature) so that a cache hit always refers to the exact same header and claims.
+func mkCacheKey(keySetID, kid, token string) string {
+	if strings.LastIndexByte(token, '.') <= 0 {
+		return ""
+	}
+
+	sum := sha256.Sum256([]byte(token))
+	return keySetID + ":" + kid + ":" + hex.EncodeToString(sum[:])
+}
+
+// cacheExpiry returns how long the verified token can be cached for, and false if it should not be cached.
+// Tokens accepted within the clock skew tolerance are only cached until the tolerance runs out.
+func (j *jwtHelper) cacheExpiry(token jwt.Token) (time.Duration, bool) {
+	exp := token.Expiration()
+	if exp.IsZero() {
+		return defaultCacheExpiry, true
+	}
+
+	expiry := time.Until(exp) + j.clockSkew
+	return expiry, expiry > 0
+}
+
+// claimFilter restricts the claims extracted from a token to the included claims minus the excluded ones.
+// Claims are referenced by dotted paths (e.g. "resource_access.myapp.roles"). At each level, a claim whose name
+// matches the remainder of the path exactly takes precedence, so that claim names containing dots can be referenced as well.
+type claimFilter struct {
+	include []string
+	exclude []string
+}
+
+// mayInclude returns true if the top-level claim with the given name is included, or contains an included claim.
+func (cf claimFilter) mayInclude(name string) bool {
+	if len(cf.include) == 0 {
+		return true
+	}
+
+	for _, path := range cf.include {
+		if path == name || strings.HasPrefix(path, name+".") {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (cf claimFilter) apply(claims map[string]*structpb.Value) map[string]*structpb.Value {
+	out := claims
+	if len(cf.include) > 0 {
+		out = make(map[string]*structpb.Value, len(cf.include))
+		for _, path := range cf.include {
+			pickClaim(out, claims, path)
+		}
+	}
+
+	for _, path := range cf.exclude {
+		dropClaim(out, path)
+	}
+
+	return out
+}
+
+// pickClaim copies the claim at the given path from src to dest, creating the enclosing objects as needed.
+func pickClaim(dest, src map[string]*structpb.Value, path string) bool {
+	if v, ok := src[path]; ok {
+		dest[path] = v
+		return true
+	}
+
+	head, rest, ok := strings.Cut(path, ".")
+	if !ok {
+		return false
+	}
+
+	srcObj := src[head].GetStructValue()
+	if srcObj == nil {
+		return false
+	}
+
+	destObj := dest[head].GetStructValue()
+	created := destObj == nil
+	if created {
+		destObj = &structpb.Struct{Fields: make(map[string]*structpb.Value)}
+	}
+
+	if !pickClaim(destObj.Fields, srcObj.Fields, rest) {
+		return false
+	}
+
+	if created {
+		dest[head] = structpb.NewStructValue(destObj)
+	}
+
+	return true
+}
+
+// dropClaim removes the claim at the given path.
+func dropClaim(claims map[string]*structpb.Value, path string) {
+	if _, ok := claims[path]; ok {
+		delete(claims, path)
+		return
+	}
+
+	head, rest, ok := strings.Cut(path, ".")
+	if !ok {
+		return
+	}
+
+	if obj := claims[head].GetStructValue(); obj != nil {
+		dropClaim(obj.Fields, rest)
+	}
+}
+
+// lookupClaim returns the value of the claim at the given dotted path.
+// As with claimFilter, a claim whose name matches the remainder of the path exactly takes precedence at each level.
+func lookupClaim(token jwt.Token, path string) (any, bool) {
+	if v, ok := token.Get(path); ok {
+		return v, true
+	}
+
+	head, rest, ok := strings.Cut(path, ".")
+	if !ok {
+		return nil, false
+	}
+
+	v, ok := token.Get(head)
+	if !ok {
+		return nil, false
+	}
+
+	for {
+		obj, ok := v.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+
+		if v, ok = obj[rest]; ok {
+			return v, true
+		}
+
+		head, rest, ok = strings.Cut(rest, ".")
+		if !ok {
+			return nil, false
+		}
+
+		if v, ok = obj[head]; !ok {
+			return nil, false
+		}
+	}
+}
+
+// isEmptyClaim returns true if the claim value is null, an empty string or an empty array or object.
+func isEmptyClaim(v any) bool {
+	if v == nil {
+		return true
+	}
+
+	switch rv := reflect.ValueOf(v); rv.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+		return rv.Len() == 0
+	default:
+		return false
+	}
+}
+
+// oneOfValidator returns a validator that checks whether at least one of the values of the given claim is acceptable.
+func oneOfValidator(claim string, acceptable []string, values func(jwt.Token) []string) jwt.Validator {
+	acceptableSet := make(map[string]struct{}, len(acceptable))
+	for _, a := range acceptable {
+		acceptableSet[a] = struct{}{}
+	}
+
+	return jwt.ValidatorFunc(func(_ context.Context, t jwt.Token) jwt.ValidationError {
+		for _, v := range values(t) {
+			if _, ok := acceptableSet[v]; ok {
+				return nil
+			}
+		}
+
+		return jwt.NewValidationError(fmt.Errorf("%q claim does not contain an acceptable value", claim))
+	})
+}
+
+// indexArrays adds an entry for each element of the list values in the given map, keyed by the name of the list
+// and the index of the element (e.g. "roles.0"). Lists nested in maps or other lists are indexed as well.
+// Existing entries always take precedence over the generated ones.
+func indexArrays(m map[string]*structpb.Value) {
+	indexed := make(map[string]*structpb.Value)
+	for name, v := range m {
+		if s := v.GetStructValue(); s != nil {
+			indexArrays(s.Fields)
+		}
+
+		addIndexedElements(indexed, name, v.GetListValue())
+	}
+
+	for key, v := range indexed {
+		if _, ok := m[key]; !ok {
+			m[key] = v
+		}
+	}
+}
+
+func addIndexedElements(indexed map[string]*structpb.Value, prefix string, list *structpb.ListValue) {
+	for i, elem := range list.GetValues() {
+		if s := elem.GetStructValue(); s != nil {
+			indexArrays(s.Fields)
+		}
+
+		key := prefix + "." + strconv.Itoa(i)
+		indexed[key] = elem
+		addIndexedElements(indexed, key, elem.GetListValue())
+	}
+}
+
+type keySet interface {
+	keySet(context.Context) (jwk.Set, error)
+}
+
+// remoteKeySet holds an auto-refreshing remote keyset.
+type remoteKeySet struct {
+	*jwk.Cache
+	err             error
+	health          *keySetHealth
+	url             string
+	refreshInterval time.Duration
+}
+
+func newRemoteKeySet(ctx context.Context, cache *jwk.Cache, src *RemoteSource) *remoteKeySet {
+	return newRemoteKeySetWithHealth(ctx, cache, src, &keySetHealth{})
+}
+
+// newRemoteKeySetWithHealth creates a remote keyset that records the outcome of fetching the keyset in the given health tracker.
+// The tracker is passed in so that it can also receive the background refresh errors reported by the cache.
+func newRemoteKeySetWithHealth(ctx context.Context, cache *jwk.Cache, src *RemoteSource, health *keySetHealth) *remoteKeySet {
+	// the interval derived from the response headers isn't known in advance, so assume the longest likely interval
+	refreshInterval := src.RefreshInterval
+	if refreshInterval <= 0 {
+		refreshInterval = defaultRemoteRefreshInterval
+		if src.MinRefreshInterval > refreshInterval {
+			refreshInterval = src.MinRefreshInterval
+		}
+	}
+
+	opts := []jwk.RegisterOption{
+		jwk.WithPostFetcher(jwk.PostFetchFunc(func(_ string, set jwk.Set) (jwk.Set, error) {
+			health.success()
+			return set, nil
+		})),
+	}
+	if src.RefreshInterval > 0 {
+		opts = append(opts, jwk.WithRefreshInterval(src.RefreshInterval))
+	}
+
+	if src.MinRefreshInterval > 0 {
+		opts = append(opts, jwk.WithMinRefreshInterval(src.MinRefreshInterval))
+	}
+
+	if src.TLS != nil || src.Timeout > 0 {
+		client, err := newRemoteHTTPClient(src)
+		if err != nil {
+			return &remoteKeySet{Cache: cache, url: src.URL, err: err, health: health, refreshInterval: refreshInterval}
+		}
+		opts = append(opts, jwk.WithHTTPClient(client))
+	}
+
+	_ = cache.Register(src.URL, opts...)
+
+	if src.RefreshJitter > 0 {
+		go refreshAfterJitter(ctx, cache, src.URL, src.RefreshJitter)
+	}
+
+	return &remoteKeySet{Cache: cache, url: src.URL, health: health, refreshInterval: refreshInterval}
+}
+
+// refreshAfterJitter refreshes the keyset after a random delay. Subsequent refreshes are scheduled relative to
+// the last refresh, so this shifts the refresh schedule of each instance by a different amount.
+func refreshAfterJitter(ctx context.Context, cache *jwk.Cache, url string, jitter time.Duration) {
+	timer := time.NewTimer(time.Duration(rand.Int63n(int64(jitter)))) //nolint:gosec
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return
+	case <-timer.C:
+		if _, err := cache.Refresh(ctx, url); err != nil {
+			logging.FromContext(ctx).Named("auxdata").Warn("Error refreshing keyset", zap.String("url", url), zap.Error(err))
+		}
+	}
+}
+
+func newRemoteHTTPClient(src *RemoteSource) (*http.Client, error) {
+	client := &http.Client{Timeout: src.Timeout}
+	if src.TLS == nil {
+		return client, nil
+	}
+
+	tlsConf := util.DefaultTLSConfig()
+	if src.TLS.CACert != "" {
+		bs, err := os.ReadFile(src.TLS.CACert)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load CA certificate from %s: %w", src.TLS.CACert, err)
+		}
+
+		certPool := x509.NewCertPool()
+		if ok := certPool.AppendCertsFromPEM(bs); !ok {
+			return nil, errors.New("failed to append CA certificates to the pool")
+		}
+
+		tlsConf.RootCAs = certPool
+	}
+
+	if src.TLS.ClientCert != "" && src.TLS.ClientKey != "" {
+		certificate, err := tls.LoadX509KeyPair(src.TLS.ClientCert, src.TLS.ClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate and key from [%s, %s]: %w", src.TLS.ClientCert, src.TLS.ClientKey, err)
+		}
+
+		tlsConf.Certificates = []tls.Certificate{certificate}
+	}
+
+	transport, ok := http.DefaultTransport.(*http.Transport)
+	if !ok {
+		return nil, errors.New("unexpected default HTTP transport")
+	}
+
+	transport = transport.Clone()
+	transport.TLSClientConfig = tlsConf
+	client.Transport = transport
+
+	return client, nil
+}
+
+// refresh fetches the keyset from the remote source, regardless of whether it has been fetched before.
+func (rks *remoteKeySet) refresh(ctx context.Context) error {
+	if rks.err != nil {
+		return rks.err
+	}
+
+	_, err := rks.Refresh(ctx, rks.url)
+	if err != nil {
+		rks.health.failure(err)
+	}
+	return err
+}
+
+func (rks *remoteKeySet) keySet(ctx context.Context) (jwk.Set, error) {
+	if rks.err != nil {
+		return nil, rks.err
+	}
+
+	ks, err := rks.Get(ctx, rks.url)
+	if err != nil {
+		rks.health.failure(err)
+	}
+	return ks, err
+}
+
+func (rks *remoteKeySet) healthy(now time.Time) error {
+	if rks.err != nil {
+		return rks.err
+	}
+
+	return rks.health.check(now, staleRefreshIntervals*rks.refreshInterval)
+}
+
+// keySetHealth tracks the outcome of the attempts to fetch a remote keyset.
+type keySetHealth struct {
+	lastSuccess time.Time
+	lastFailure time.Time
+	lastErr     error
+	mu          sync.RWMutex
+}
+
+func (h *keySetHealth) success() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.lastSuccess = time.Now()
+}
+
+func (h *keySetHealth) failure(err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.lastFailure = time.Now()
+	h.lastErr = err
+}
+
+// check returns an error if the latest attempt to fetch the keyset failed and either no attempt has ever succeeded
+// or the last successful attempt is older than maxAge.
+func (h *keySetHealth) check(now time.Time, maxAge time.Duration) error {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	if h.lastErr == nil || h.lastFailure.Before(h.lastSuccess) {
+		return nil
+	}
+
+	if h.lastSuccess.IsZero() {
+		return fmt.Errorf("keyset has never been fetched successfully: %w", h.lastErr)
+	}
+
+	if age := now.Sub(h.lastSuccess); age > maxAge {
+		return fmt.Errorf("keyset has not been refreshed successfully for %s: %w", age.Round(time.Second), h.lastErr)
+	}
+
+	return nil
+}
+
+// localKeySet represents a keyset defined manually through the configuration.
+type localKeySet func(context.Context) (jwk.Set, error)
+
+func newLocalKeySet(src *LocalSource) localKeySet {
+	if src.File != "" {
+		return newLocalFileKeySet(src).keySet
+	}
+
+	ks, err := loadLocalKeySet(src)
+	if err != nil {
+		return func(context.Context) (jwk.Set, error) { return nil, err }
+	}
+
+	return func(context.Context) (jwk.Set, error) { return ks, nil }
+}
+
+// loadLocalKeySet reads the keyset and applies the options of the source to it.
+func loadLocalKeySet(src *LocalSource) (jwk.Set, error) {
+	ks, err := readLocalKeySet(src)
+	if err != nil {
+		return nil, err
+	}
+
+	if src.PublicKeysOnly {
+		return publicKeysOf(ks)
+	}
+
+	return ks, nil
+}
+
+// localFileKeySet is a local keyset read from a file. The file is read again when its modification time or size changes,
+// so that keys can be rotated without restarting the server.
+type localFileKeySet struct {
+	modTime time.Time
+	ks      jwk.Set
+	err     error
+	src     *LocalSource
+	size    int64
+	mu      sync.Mutex
+}
+
+func newLocalFileKeySet(src *LocalSource) *localFileKeySet {
+	lfks := &localFileKeySet{src: src}
+	lfks.ks, lfks.err = loadLocalKeySet(src)
+	if fi, err := os.Stat(src.File); err == nil {
+		lfks.modTime = fi.ModTime()
+		lfks.size = fi.Size()
+	}
+
+	return lfks
+}
+
+func (lfks *localFileKeySet) keySet(ctx context.Context) (jwk.Set, error) {
+	lfks.mu.Lock()
+	defer lfks.mu.Unlock()
+
+	lfks.reloadIfChanged(ctx)
+	if lfks.ks == nil {
+		return nil, lfks.err
+	}
+
+	return lfks.ks, nil
+}
+
+// reloadIfChanged reads the file again if it has been modified since it was last read.
+// If the modified file can't be loaded, the previously loaded keyset is kept.
+func (lfks *localFileKeySet) reloadIfChanged(ctx context.Context) {
+	fi, err := os.Stat(lfks.src.File)
+	if err != nil || (fi.ModTime().Equal(lfks.modTime) && fi.Size() == lfks.size) {
+		return
+	}
+
+	lfks.modTime = fi.ModTime()
+	lfks.size = fi.Size()
+
+	ks, err := loadLocalKeySet(lfks.src)
+	if err != nil {
+		lfks.err = err
+		if lfks.ks != nil {
+			logging.FromContext(ctx).Named("auxdata").Warn("Failed to reload keyset: using the previously loaded keys", zap.String("file", lfks.src.File), zap.Error(err))
+		}
+		return
+	}
+
+	lfks.ks = ks
+	lfks.err = nil
+}
+
+func readLocalKeySet(src *LocalSource) (jwk.Set, error) {
+	if src.Data != "" {
+		kbytes, err := base64.StdEncoding.DecodeString(src.Data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to apply base64 decoder to key data: %w", err)
+		}
+
+		ks, err := jwk.Parse(kbytes, jwk.WithPEM(src.PEM))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse key data: %w", err)
+		}
+
+		return ks, nil
+	}
+
+	ks, err := jwk.ReadFile(src.File, jwk.WithPEM(src.PEM))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read keyset from '%s': %w", src.File, err)
+	}
+
+	return ks, nil
+}
+
+// publicKeysOf returns a keyset containing the public keys of the given keyset.
+// Duplicates (such as a private key and its certificate) are removed.
+func publicKeysOf(ks jwk.Set) (jwk.Set, error) {
+	pub, err := jwk.PublicSetOf(ks)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract public keys: %w", err)
+	}
+
+	out := jwk.NewSet()
+	seen := make(map[string]struct{}, pub.Len())
+	for i := 0; i < pub.Len(); i++ {
+		key, _ := pub.Key(i)
+		tp, err := key.Thumbprint(crypto.SHA256)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compute key thumbprint: %w", err)
+		}
+
+		if _, ok := seen[string(tp)]; ok {
+			continue
+		}
+		seen[string(tp)] = struct{}{}
+
+		if err := out.AddKey(key); err != nil {
+			return nil, fmt.Errorf("failed to add public key: %w", err)
+		}
+	}
+
+	return out, nil
+}
+
+func (lks localKeySet) keySet(ctx context.Context) (jwk.Set, error) {
+	if lks == nil {
+		return nil, errNilLocalKeySet
+	}
+
+	return lks(ctx)
+}
+
+// unverifiedKeySet is the keyset of tokens that are trusted without verifying their signatures.
+type unverifiedKeySet struct{}
+
+func (unverifiedKeySet) keySet(context.Context) (jwk.Set, error) {
+	return nil, errUnverifiedKeySet
+}
+
+// symmetricKeySet represents a keyset containing a single shared secret for verifying HMAC signed tokens.
+type symmetricKeySet func(context.Context) (jwk.Set, error)
+
+func newSymmetricKeySet(src *SymmetricSource) symmetricKeySet {
+	ks, err := readSymmetricKeySet(src)
+	if err != nil {
+		return func(context.Context) (jwk.Set, error) { return nil, err }
+	}
+
+	return func(context.Context) (jwk.Set, error) { return ks, nil }
+}
+
+func readSymmetricKeySet(src *SymmetricSource) (jwk.Set, error) {
+	var secret []byte
+	if src.Secret != "" {
+		s, err := base64.StdEncoding.DecodeString(src.Secret)
+		if err != nil {
+			return nil, fmt.Errorf("failed to apply base64 decoder to secret: %w", err)
+		}
+		secret = s
+	} else {
+		s, err := os.ReadFile(src.File)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read secret from '%s': %w", src.File, err)
+		}
+		// editors usually add a trailing newline, which is never part of the secret
+		secret = bytes.TrimRight(s, "\r\n")
+	}
+
+	if len(secret) == 0 {
+		return nil, errEmptySecret
+	}
+
+	alg := src.Algorithm
+	if alg == "" {
+		alg = defaultHMACAlgorithm
+	}
+
+	sigAlg, ok := hmacAlgorithms[alg]
+	if !ok {
+		return nil, fmt.Errorf("unsupported symmetric algorithm '%s'", alg)
+	}
+
+	key, err := jwk.FromRaw(secret)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create key from secret: %w", err)
+	}
+
+	if err := key.Set(jwk.AlgorithmKey, sigAlg); err != nil {
+		return nil, fmt.Errorf("failed to set key algorithm: %w", err)
+	}
+
+	if src.KeyID != "" {
+		if err := key.Set(jwk.KeyIDKey, src.KeyID); err != nil {
+			return nil, fmt.Errorf("failed to set key ID: %w", err)
+		}
+	}
+
+	ks := jwk.NewSet()
+	if err := ks.AddKey(key); err != nil {
+		return nil, fmt.Errorf("failed to add key: %w", err)
+	}
+
+	return ks, nil
+}
+
+func (sks symmetricKeySet) keySet(ctx context.Context) (jwk.Set, error) {
+	if sks == nil {
+		return nil, errNilSymmetricKeySet
+	}
+
+	return sks(ctx)
+}
+
+// cacheUsage tracks the usage of a token cache between reports.
+type cacheUsage struct {
+	cache     gcache.Cache
+	keySetID  string
+	maxSize   int
+	evictions atomic.Int64
+}
+
+func (j *jwtHelper) mkCache(keySetID string, size int) gcache.Cache {
+	_ = stats.RecordWithTags(context.Background(),
+		[]tag.Mutator{tag.Upsert(metrics.KeyCacheKind, cacheKind), tag.Upsert(metrics.KeyCacheKeySet, keySetID)},
+		metrics.CacheMaxSize.M(int64(size)),
+	)
+
+	usage := &cacheUsage{keySetID: keySetID, maxSize: size}
+	gauge := metrics.MakeCacheGauge(cacheKind)
+	policy, ok := cachePolicies[j.cachePolicy]
+	if !ok {
+		policy = cachePolicies[defaultCachePolicy]
+	}
+
+	usage.cache = policy(gcache.New(size)).
+		AddedFunc(func(_, _ any) {
 			gauge.Add(1)
 		}).
 		EvictedFunc(func(_, _ any) {
 			gauge.Add(-1)
+			usage.evictions.Add(1)
+			_ = stats.RecordWithTags(context.Background(),
+				[]tag.Mutator{tag.Upsert(metrics.KeyCacheKind, cacheKind), tag.Upsert(metrics.KeyCacheKeySet, keySetID)},
+				metrics.CacheEvictionCount.M(1),
+			)
 		}).Build()
+
+	j.cacheUsage = append(j.cacheUsage, usage)
+	return usage.cache
+}
+
+// cacheSize returns the number of tokens in the cache used by the given keyset.
+func (j *jwtHelper) cacheSize(keySetID string) int {
+	cache := j.cacheFor(keySetID)
+	if cache == nil {
+		return 0
+	}
+
+	return cache.Len(false)
+}
+
+// reportCacheUsage periodically records the size of the caches and logs the number of evictions since the last report
+// so that operators can tell whether the caches are large enough.
+func (j *jwtHelper) reportCacheUsage(ctx context.Context, interval time.Duration) {
+	log := logging.FromContext(ctx).Named("auxdata")
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			j.recordCacheUsage(log)
+		}
+	}
+}
+
+func (j *jwtHelper) recordCacheUsage(log *zap.Logger) {
+	for _, usage := range j.cacheUsage {
+		size := usage.cache.Len(false)
+		evictions := usage.evictions.Swap(0)
+
+		_ = stats.RecordWithTags(context.Background(),
+			[]tag.Mutator{tag.Upsert(metrics.KeyCacheKind, cacheKind), tag.Upsert(metrics.KeyCacheKeySet, usage.keySetID)},
+			metrics.CacheSize.M(int64(size)),
+		)
+
+		log.Debug("JWT cache usage",
+			zap.String("keyset", usage.keySetID),
+			zap.Int("size", size),
+			zap.Int("maxSize", usage.maxSize),
+			zap.Int64("evictions", evictions),
+		)
+	}
+}
+
+// recordFailure records the failure reason of the given error (if it has one) in the failure metric.
+func recordFailure(err error) {
+	var je jwtError
+	if !errors.As(err, &je) {
+		return
+	}
+
+	reason, ok := failureReasons[je.reason]
+	if !ok {
+		return
+	}
+
+	_ = stats.RecordWithTags(context.Background(),
+		[]tag.Mutator{tag.Upsert(metrics.KeyAuxDataFailureReason, reason)},
+		metrics.AuxDataJWTFailureCount.M(1),
+	)
 }
 
-func cacheHit() {
+func cacheHit(keySetID string) {
 	_ = stats.RecordWithTags(context.Background(),
-		[]tag.Mutator{tag.Upsert(metrics.KeyCacheKind, cacheKind), tag.Upsert(metrics.KeyCacheResult, "hit")},
+		[]tag.Mutator{tag.Upsert(metrics.KeyCacheKind, cacheKind), tag.Upsert(metrics.KeyCacheKeySet, keySetID), tag.Upsert(metrics.KeyCacheResult, "hit")},
 		metrics.CacheAccessCount.M(1),
 	)
 }
 
-func cacheMiss() {
+func cacheMiss(keySetID string) {
 	_ = stats.RecordWithTags(context.Background(),
-		[]tag.Mutator{tag.Upsert(metrics.KeyCacheKind, cacheKind), tag.Upsert(metrics.KeyCacheResult, "miss")},
+		[]tag.Mutator{tag.Upsert(metrics.KeyCacheKind, cacheKind), tag.Upsert(metrics.KeyCacheKeySet, keySetID), tag.Upsert(metrics.KeyCacheResult, "miss")},
 		metrics.CacheAccessCount.M(1),
 	)
 }
@@ -5,24 +5,39 @@ package auxdata
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	cryptorand "crypto/rand"
+	"crypto/rsa"
 	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
 	"fmt"
+	"math/rand"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/lestrrat-go/jwx/v2/jwa"
+	"github.com/lestrrat-go/jwx/v2/jwe"
 	"github.com/lestrrat-go/jwx/v2/jwk"
+	"github.com/lestrrat-go/jwx/v2/jws"
 	"github.com/lestrrat-go/jwx/v2/jwt"
 	"github.com/stretchr/testify/require"
+	"go.opencensus.io/stats/view"
+	"go.uber.org/zap"
 	"google.golang.org/protobuf/testing/protocmp"
 	"google.golang.org/protobuf/types/known/structpb"
 
 	requestv1 "github.com/cerbos/cerbos/api/genpb/cerbos/request/v1"
+	"github.com/cerbos/cerbos/internal/observability/metrics"
 	"github.com/cerbos/cerbos/internal/test"
 )
 
@@ -80,7 +95,7 @@ func TestKeySet(t *testing.T) {
 				ctx, cancelFn := context.WithTimeout(context.Background(), 1*time.Second)
 				defer cancelFn()
 
-				rks := newRemoteKeySet(jwk.NewCache(ctx), conf)
+				rks := newRemoteKeySet(ctx, jwk.NewCache(ctx), conf)
 				ks, err := rks.keySet(ctx)
 
 				require.NoError(t, err)
@@ -91,6 +106,292 @@ func TestKeySet(t *testing.T) {
 	}
 }
 
+func TestRemoteKeySet_TLS(t *testing.T) {
+	keysDir := test.PathToDir(t, "auxdata")
+
+	ts := httptest.NewTLSServer(http.FileServer(http.Dir(keysDir)))
+	t.Cleanup(ts.Close)
+
+	caCert := filepath.Join(t.TempDir(), "ca.crt")
+	require.NoError(t, os.WriteFile(caCert, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ts.Certificate().Raw}), 0o600))
+
+	testCases := []struct {
+		name    string
+		tls     *RemoteTLSConf
+		wantErr bool
+	}{
+		{name: "custom_ca", tls: &RemoteTLSConf{CACert: caCert}},
+		{name: "untrusted", wantErr: true},
+		{name: "missing_ca", tls: &RemoteTLSConf{CACert: filepath.Join(t.TempDir(), "missing.crt")}, wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancelFn := context.WithTimeout(context.Background(), 1*time.Second)
+			defer cancelFn()
+
+			conf := &RemoteSource{URL: fmt.Sprintf("%s/verify_key.jwk", ts.URL), TLS: tc.tls, Timeout: 1 * time.Second}
+			rks := newRemoteKeySet(ctx, jwk.NewCache(ctx), conf)
+			ks, err := rks.keySet(ctx)
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.True(t, ks.Len() > 0)
+		})
+	}
+}
+
+func TestRemoteKeySet_RefreshJitter(t *testing.T) {
+	keysDir := test.PathToDir(t, "auxdata")
+
+	var fetches int32
+	fileServer := http.FileServer(http.Dir(keysDir))
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&fetches, 1)
+		fileServer.ServeHTTP(w, r)
+	}))
+	t.Cleanup(ts.Close)
+
+	ctx, cancelFn := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancelFn()
+
+	conf := &RemoteSource{URL: fmt.Sprintf("%s/verify_key.jwk", ts.URL), RefreshJitter: 10 * time.Millisecond}
+	rks := newRemoteKeySet(ctx, jwk.NewCache(ctx), conf)
+
+	ks, err := rks.keySet(ctx)
+	require.NoError(t, err)
+	require.True(t, ks.Len() > 0)
+
+	require.Eventually(t, func() bool { return atomic.LoadInt32(&fetches) >= 2 }, 2*time.Second, 10*time.Millisecond)
+}
+
+func TestWarmUp(t *testing.T) {
+	keysDir := test.PathToDir(t, "auxdata")
+
+	var fetches int32
+	fileServer := http.FileServer(http.Dir(keysDir))
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&fetches, 1)
+		fileServer.ServeHTTP(w, r)
+	}))
+	t.Cleanup(ts.Close)
+
+	mkConf := func(prefetch bool, url string) *JWTConf {
+		return &JWTConf{
+			Prefetch: &prefetch,
+			KeySets:  []JWTKeySet{{ID: "remote", Remote: &RemoteSource{URL: url}}},
+		}
+	}
+
+	t.Run("prefetch_enabled", func(t *testing.T) {
+		ctx, cancelFn := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancelFn()
+
+		atomic.StoreInt32(&fetches, 0)
+		newJWTHelper(ctx, mkConf(true, fmt.Sprintf("%s/verify_key.jwk", ts.URL)), nil)
+		require.Eventually(t, func() bool { return atomic.LoadInt32(&fetches) >= 1 }, 2*time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("prefetch_disabled", func(t *testing.T) {
+		ctx, cancelFn := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancelFn()
+
+		atomic.StoreInt32(&fetches, 0)
+		jh := newJWTHelper(ctx, mkConf(false, fmt.Sprintf("%s/verify_key.jwk", ts.URL)), nil)
+		require.Never(t, func() bool { return atomic.LoadInt32(&fetches) > 0 }, 200*time.Millisecond, 10*time.Millisecond)
+
+		require.NoError(t, jh.WarmUp(ctx))
+		require.Equal(t, int32(1), atomic.LoadInt32(&fetches))
+	})
+
+	t.Run("fetch_failure", func(t *testing.T) {
+		ctx, cancelFn := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancelFn()
+
+		jh := newJWTHelper(ctx, mkConf(false, fmt.Sprintf("%s/missing.jwk", ts.URL)), nil)
+		err := jh.WarmUp(ctx)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "keyset 'remote'")
+	})
+}
+
+func TestHealthy(t *testing.T) {
+	keysDir := test.PathToDir(t, "auxdata")
+	ts := httptest.NewServer(http.FileServer(http.Dir(keysDir)))
+	t.Cleanup(ts.Close)
+
+	mkHelper := func(ctx context.Context, url string) *jwtHelper {
+		prefetch := false
+		return newJWTHelper(ctx, &JWTConf{
+			Prefetch: &prefetch,
+			KeySets:  []JWTKeySet{{ID: "remote", Remote: &RemoteSource{URL: url, RefreshInterval: time.Minute}}},
+		}, nil)
+	}
+
+	t.Run("not_fetched", func(t *testing.T) {
+		ctx, cancelFn := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancelFn()
+
+		jh := mkHelper(ctx, fmt.Sprintf("%s/verify_key.jwk", ts.URL))
+		require.NoError(t, jh.Healthy())
+	})
+
+	t.Run("fetched", func(t *testing.T) {
+		ctx, cancelFn := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancelFn()
+
+		jh := mkHelper(ctx, fmt.Sprintf("%s/verify_key.jwk", ts.URL))
+		require.NoError(t, jh.WarmUp(ctx))
+		require.NoError(t, jh.Healthy())
+	})
+
+	t.Run("never_fetched", func(t *testing.T) {
+		ctx, cancelFn := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancelFn()
+
+		jh := mkHelper(ctx, fmt.Sprintf("%s/missing.jwk", ts.URL))
+		require.Error(t, jh.WarmUp(ctx))
+
+		err := jh.Healthy()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "keyset 'remote'")
+		require.Contains(t, err.Error(), "never been fetched")
+	})
+
+	t.Run("stale", func(t *testing.T) {
+		now := time.Now()
+		refreshErr := errors.New("connection refused")
+
+		testCases := []struct {
+			name        string
+			lastSuccess time.Time
+			lastFailure time.Time
+			wantErr     bool
+		}{
+			{name: "recovered", lastSuccess: now.Add(-time.Minute), lastFailure: now.Add(-time.Hour)},
+			{name: "failing_within_threshold", lastSuccess: now.Add(-2 * time.Minute), lastFailure: now.Add(-time.Minute)},
+			{name: "failing_beyond_threshold", lastSuccess: now.Add(-4 * time.Minute), lastFailure: now.Add(-time.Minute), wantErr: true},
+		}
+
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.name, func(t *testing.T) {
+				h := &keySetHealth{lastSuccess: tc.lastSuccess, lastFailure: tc.lastFailure, lastErr: refreshErr}
+				err := h.check(now, staleRefreshIntervals*time.Minute)
+				if tc.wantErr {
+					require.ErrorIs(t, err, refreshErr)
+					return
+				}
+				require.NoError(t, err)
+			})
+		}
+	})
+}
+
+func TestLocalKeySet_PublicKeysOnly(t *testing.T) {
+	keysDir := test.PathToDir(t, filepath.Join("auxdata", "keys"))
+
+	testCases := []struct {
+		file    string
+		wantLen int
+	}{
+		{file: "ec.pem", wantLen: 1},
+		{file: "rsa.pem", wantLen: 1},
+		// contains a private key and its certificate
+		{file: "ec_with_cert.pem", wantLen: 1},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.file, func(t *testing.T) {
+			src := &LocalSource{File: filepath.Join(keysDir, tc.file), PEM: true}
+
+			ks, err := newLocalKeySet(src).keySet(context.Background())
+			require.NoError(t, err)
+			require.True(t, hasPrivateKey(t, ks), "Expected the keyset to contain private keys without the option")
+
+			src.PublicKeysOnly = true
+			ks, err = newLocalKeySet(src).keySet(context.Background())
+			require.NoError(t, err)
+			require.Equal(t, tc.wantLen, ks.Len())
+			require.False(t, hasPrivateKey(t, ks), "Private key material remains in the keyset")
+		})
+	}
+}
+
+func TestLocalKeySet_Reload(t *testing.T) {
+	keysDir := test.PathToDir(t, "auxdata")
+	keyFile := filepath.Join(t.TempDir(), "keys.jwk")
+
+	copyKey := func(t *testing.T, src string, modTime time.Time) {
+		t.Helper()
+
+		data, err := os.ReadFile(src)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(keyFile, data, 0o600))
+		require.NoError(t, os.Chtimes(keyFile, modTime, modTime))
+	}
+
+	now := time.Now()
+	copyKey(t, filepath.Join(keysDir, "keys", "ec.jwk"), now.Add(-1*time.Hour))
+
+	conf := &JWTConf{
+		KeySets: []JWTKeySet{
+			{
+				ID:    "local_file",
+				Local: &LocalSource{File: keyFile},
+			},
+		},
+	}
+
+	ctx, cancelFn := context.WithCancel(context.Background())
+	t.Cleanup(cancelFn)
+
+	jh := newJWTHelper(ctx, conf, nil)
+	input := &requestv1.AuxData_JWT{Token: mkSignedToken(t, now.Add(1*time.Hour))}
+
+	_, err := jh.extract(context.Background(), input)
+	require.Error(t, err, "Token should not be verified by the initial key")
+
+	copyKey(t, filepath.Join(keysDir, "verify_key.jwk"), now)
+
+	have, err := jh.extract(context.Background(), input)
+	require.NoError(t, err, "Token should be verified by the new key")
+	require.NotNil(t, have)
+
+	t.Run("invalid_file_keeps_previous_keys", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(keyFile, []byte("not a keyset"), 0o600))
+		require.NoError(t, os.Chtimes(keyFile, now.Add(1*time.Hour), now.Add(1*time.Hour)))
+
+		ks, err := jh.keySets["local_file"].keySet(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, 1, ks.Len())
+	})
+
+}
+
+func hasPrivateKey(t *testing.T, ks jwk.Set) bool {
+	t.Helper()
+
+	for i := 0; i < ks.Len(); i++ {
+		key, _ := ks.Key(i)
+
+		var raw any
+		require.NoError(t, key.Raw(&raw))
+		switch raw.(type) {
+		case *ecdsa.PublicKey, *rsa.PublicKey:
+		default:
+			return true
+		}
+	}
+
+	return false
+}
+
 func findKeys(t *testing.T, keysDir string) []string {
 	t.Helper()
 
@@ -135,7 +436,7 @@ func TestExtract_MultipleKeySets(t *testing.T) {
 	ctx, cancelFn := context.WithCancel(context.Background())
 	t.Cleanup(cancelFn)
 
-	jh := newJWTHelper(ctx, conf)
+	jh := newJWTHelper(ctx, conf, nil)
 	expiry := time.Now().Add(1 * time.Hour)
 
 	tokens := []struct {
@@ -226,7 +527,7 @@ func TestExtract_SingleKeySet(t *testing.T) {
 	ctx, cancelFn := context.WithCancel(context.Background())
 	t.Cleanup(cancelFn)
 
-	jh := newJWTHelper(ctx, conf)
+	jh := newJWTHelper(ctx, conf, nil)
 	expiry := time.Now().Add(1 * time.Hour)
 
 	tokens := []struct {
@@ -274,7 +575,7 @@ func TestExtract_NoKeySets(t *testing.T) {
 	ctx, cancelFn := context.WithCancel(context.Background())
 	t.Cleanup(cancelFn)
 
-	jh := newJWTHelper(ctx, nil)
+	jh := newJWTHelper(ctx, nil, nil)
 
 	tokens := []struct {
 		token string
@@ -308,28 +609,1449 @@ func TestExtract_NoKeySets(t *testing.T) {
 	}
 }
 
-func mkSignedToken(t *testing.T, expiry time.Time) string {
-	t.Helper()
+func TestExtract_CustomValidator(t *testing.T) {
+	keysDir := test.PathToDir(t, "auxdata")
 
-	token := jwt.New()
-	require.NoError(t, token.Set(jwt.IssuerKey, "cerbos-test-suite"))
-	require.NoError(t, token.Set(jwt.AudienceKey, "cerbos-jwt-tests"))
-	require.NoError(t, token.Set(jwt.ExpirationKey, expiry))
-	require.NoError(t, token.Set("customString", "foobar"))
-	require.NoError(t, token.Set("customInt", 42))
-	require.NoError(t, token.Set("customArray", []string{"A", "B", "C"}))
-	require.NoError(t, token.Set("customMap", map[string]any{"A": "AA", "B": "BB", "C": "CC"}))
+	conf := &JWTConf{
+		KeySets: []JWTKeySet{
+			{
+				ID:    "local_file",
+				Local: &LocalSource{File: filepath.Join(keysDir, "verify_key.jwk")},
+			},
+		},
+		CacheSize: defaultCacheSize,
+	}
 
-	keyData, err := os.ReadFile(filepath.Join(test.PathToDir(t, "auxdata"), "signing_key.jwk"))
-	require.NoError(t, err)
+	errBadCustomString := errors.New("unexpected value for customString")
+	validator := func(token jwt.Token) error {
+		v, ok := token.Get("customString")
+		if !ok || v != "foobar" {
+			return errBadCustomString
+		}
+		return nil
+	}
 
-	keySet, err := jwk.ParseKey(keyData)
-	require.NoError(t, err)
+	ctx, cancelFn := context.WithCancel(context.Background())
+	t.Cleanup(cancelFn)
 
-	tokenBytes, err := jwt.Sign(token, jwt.WithKey(jwa.ES384, keySet))
+	t.Run("accepted", func(t *testing.T) {
+		jh := newJWTHelper(ctx, conf, mkOptions([]Opt{WithKeySetValidator("local_file", validator)}))
+		token := mkSignedToken(t, time.Now().Add(1*time.Hour))
+
+		// extract twice to exercise both the cache miss and cache hit paths
+		for i := 0; i < 2; i++ {
+			have, err := jh.extract(context.Background(), &requestv1.AuxData_JWT{Token: token})
+			require.NoError(t, err)
+			require.NotEmpty(t, have)
+		}
+	})
+
+	t.Run("rejected", func(t *testing.T) {
+		rejectAll := func(jwt.Token) error { return errBadCustomString }
+		jh := newJWTHelper(ctx, conf, mkOptions([]Opt{WithKeySetValidator("local_file", validator), WithKeySetValidator("local_file", rejectAll)}))
+		token := mkSignedToken(t, time.Now().Add(1*time.Hour))
+
+		for i := 0; i < 2; i++ {
+			_, err := jh.extract(context.Background(), &requestv1.AuxData_JWT{Token: token})
+			require.Error(t, err)
+			require.ErrorIs(t, err, ErrJWTRejectedByValidator)
+			require.ErrorIs(t, err, errBadCustomString)
+		}
+	})
+
+	t.Run("other_keyset", func(t *testing.T) {
+		rejectAll := func(jwt.Token) error { return errBadCustomString }
+		jh := newJWTHelper(ctx, conf, mkOptions([]Opt{WithKeySetValidator("other", rejectAll)}))
+
+		_, err := jh.extract(context.Background(), &requestv1.AuxData_JWT{Token: mkSignedToken(t, time.Now().Add(1*time.Hour))})
+		require.NoError(t, err)
+	})
+}
+
+func TestExtract_MaxClaims(t *testing.T) {
+	ctx, cancelFn := context.WithCancel(context.Background())
+	t.Cleanup(cancelFn)
+
+	// the test token has 7 claims
+	token := mkSignedToken(t, time.Now().Add(1*time.Hour))
+
+	t.Run("within_limit", func(t *testing.T) {
+		jh := newJWTHelper(ctx, &JWTConf{DisableVerification: true, MaxClaims: 7}, nil)
+		have, err := jh.extract(context.Background(), &requestv1.AuxData_JWT{Token: token})
+		require.NoError(t, err)
+		require.Len(t, have, 7)
+	})
+
+	t.Run("rejected", func(t *testing.T) {
+		jh := newJWTHelper(ctx, &JWTConf{DisableVerification: true, MaxClaims: 3}, nil)
+		_, err := jh.extract(context.Background(), &requestv1.AuxData_JWT{Token: token})
+		require.Error(t, err)
+		require.ErrorIs(t, err, ErrJWTTooManyClaims)
+	})
+
+	t.Run("truncated", func(t *testing.T) {
+		jh := newJWTHelper(ctx, &JWTConf{DisableVerification: true, MaxClaims: 3, TruncateClaims: true}, nil)
+		have, err := jh.extract(context.Background(), &requestv1.AuxData_JWT{Token: token})
+		require.NoError(t, err)
+		require.Len(t, have, 3)
+	})
+
+	t.Run("unlimited", func(t *testing.T) {
+		jh := newJWTHelper(ctx, &JWTConf{DisableVerification: true, MaxClaims: -1}, nil)
+		have, err := jh.extract(context.Background(), &requestv1.AuxData_JWT{Token: token})
+		require.NoError(t, err)
+		require.Len(t, have, 7)
+	})
+}
+
+func TestExtract_SymmetricKeySet(t *testing.T) {
+	secret := []byte("cerbos-jwt-tests-shared-secret")
+	expiry := time.Now().Add(1 * time.Hour)
+
+	mkHMACToken := func(t *testing.T, alg jwa.SignatureAlgorithm, secret []byte, kid string) string {
+		t.Helper()
+
+		key, err := jwk.FromRaw(secret)
+		require.NoError(t, err)
+		if kid != "" {
+			require.NoError(t, key.Set(jwk.KeyIDKey, kid))
+		}
+
+		token := jwt.New()
+		require.NoError(t, token.Set(jwt.IssuerKey, "cerbos-test-suite"))
+		require.NoError(t, token.Set(jwt.ExpirationKey, expiry))
+
+		tokenBytes, err := jwt.Sign(token, jwt.WithKey(alg, key))
+		require.NoError(t, err)
+
+		return string(tokenBytes)
+	}
+
+	secretFile := filepath.Join(t.TempDir(), "secret")
+	require.NoError(t, os.WriteFile(secretFile, append(secret, '\n'), 0o600))
+
+	conf := &Conf{JWT: &JWTConf{
+		KeySets: []JWTKeySet{
+			{ID: "secret", Symmetric: &SymmetricSource{Secret: base64.StdEncoding.EncodeToString(secret)}},
+			{ID: "secret_file", Symmetric: &SymmetricSource{File: secretFile, Algorithm: "HS512", KeyID: "kid1"}},
+		},
+	}}
+	require.NoError(t, conf.Validate())
+
+	ctx, cancelFn := context.WithCancel(context.Background())
+	t.Cleanup(cancelFn)
+
+	jh := newJWTHelper(ctx, conf.JWT, nil)
+
+	testCases := []struct {
+		wantErrIs error
+		name      string
+		keySetID  string
+		token     string
+		wantErr   bool
+	}{
+		{name: "valid", keySetID: "secret", token: mkHMACToken(t, jwa.HS256, secret, "")},
+		{name: "valid_with_kid", keySetID: "secret_file", token: mkHMACToken(t, jwa.HS512, secret, "kid1")},
+		{name: "wrong_secret", keySetID: "secret", token: mkHMACToken(t, jwa.HS256, []byte("wrong"), ""), wantErr: true},
+		{name: "wrong_algorithm", keySetID: "secret_file", token: mkHMACToken(t, jwa.HS256, secret, "kid1"), wantErr: true, wantErrIs: ErrJWTAlgorithmNotAllowed},
+		{name: "wrong_algorithm_without_kid", keySetID: "secret", token: mkHMACToken(t, jwa.HS512, secret, ""), wantErr: true, wantErrIs: ErrJWTAlgorithmNotAllowed},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			// extract twice to exercise the cache
+			for i := 0; i < 2; i++ {
+				have, err := jh.extract(context.Background(), &requestv1.AuxData_JWT{Token: tc.token, KeySetId: tc.keySetID})
+				if tc.wantErr {
+					require.Error(t, err)
+					if tc.wantErrIs != nil {
+						require.ErrorIs(t, err, tc.wantErrIs)
+					} else {
+						require.Contains(t, err.Error(), "failed to parse JWT")
+					}
+					continue
+				}
+
+				require.NoError(t, err)
+				require.Equal(t, "cerbos-test-suite", have["iss"].GetStringValue())
+			}
+		})
+	}
+}
+
+func TestExtract_KeyIDRouting(t *testing.T) {
+	// the keyset contains keys for different algorithms, and one key that doesn't declare an algorithm
+	jwks := jwk.NewSet()
+	mkKey := func(t *testing.T, kid string, curve elliptic.Curve, alg jwa.SignatureAlgorithm) *ecdsa.PrivateKey {
+		t.Helper()
+
+		privateKey, err := ecdsa.GenerateKey(curve, cryptorand.Reader)
+		require.NoError(t, err)
+
+		key, err := jwk.FromRaw(privateKey.Public())
+		require.NoError(t, err)
+		require.NoError(t, key.Set(jwk.KeyIDKey, kid))
+		if alg != "" {
+			require.NoError(t, key.Set(jwk.AlgorithmKey, alg))
+		}
+		require.NoError(t, jwks.AddKey(key))
+
+		return privateKey
+	}
+
+	keys := map[string]*ecdsa.PrivateKey{
+		"es256":   mkKey(t, "es256", elliptic.P256(), jwa.ES256),
+		"es256_2": mkKey(t, "es256_2", elliptic.P256(), jwa.ES256),
+		"es384":   mkKey(t, "es384", elliptic.P384(), jwa.ES384),
+		"es512":   mkKey(t, "es512", elliptic.P521(), jwa.ES512),
+		"no_alg":  mkKey(t, "no_alg", elliptic.P256(), ""),
+	}
+
+	jwksData, err := json.Marshal(jwks)
 	require.NoError(t, err)
 
-	return string(tokenBytes)
+	jwksFile := filepath.Join(t.TempDir(), "keys.jwks")
+	require.NoError(t, os.WriteFile(jwksFile, jwksData, 0o600))
+
+	// the raw key is used for signing so that the key ID is only set by the header
+	mkToken := func(t *testing.T, key *ecdsa.PrivateKey, alg jwa.SignatureAlgorithm, kid string) string {
+		t.Helper()
+
+		token := jwt.New()
+		require.NoError(t, token.Set(jwt.IssuerKey, "cerbos-test-suite"))
+		require.NoError(t, token.Set(jwt.ExpirationKey, time.Now().Add(1*time.Hour)))
+
+		headers := jws.NewHeaders()
+		require.NoError(t, headers.Set(jws.KeyIDKey, kid))
+
+		tokenBytes, err := jwt.Sign(token, jwt.WithKey(alg, key, jws.WithProtectedHeaders(headers)))
+		require.NoError(t, err)
+
+		return string(tokenBytes)
+	}
+
+	ctx, cancelFn := context.WithCancel(context.Background())
+	t.Cleanup(cancelFn)
+
+	jh := newJWTHelper(ctx, &JWTConf{
+		KeySets:   []JWTKeySet{{ID: "multi", Local: &LocalSource{File: jwksFile}}},
+		CacheSize: defaultCacheSize,
+	}, nil)
+
+	testCases := []struct {
+		wantErrIs error
+		name      string
+		token     string
+		wantErr   bool
+	}{
+		{name: "es256", token: mkToken(t, keys["es256"], jwa.ES256, "es256")},
+		{name: "es384", token: mkToken(t, keys["es384"], jwa.ES384, "es384")},
+		{name: "es512", token: mkToken(t, keys["es512"], jwa.ES512, "es512")},
+		{name: "signed_by_other_key", token: mkToken(t, keys["es256"], jwa.ES256, "es256_2"), wantErr: true},
+		{name: "kid_of_other_key", token: mkToken(t, keys["es384"], jwa.ES384, "es256"), wantErr: true, wantErrIs: ErrJWTAlgorithmNotAllowed},
+		{name: "alg_disagrees_with_key", token: mkToken(t, keys["no_alg"], jwa.ES256, "es384"), wantErr: true, wantErrIs: ErrJWTAlgorithmNotAllowed},
+		{name: "unknown_kid", token: mkToken(t, keys["es256"], jwa.ES256, "unknown"), wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			// extract twice to exercise the cache
+			for i := 0; i < 2; i++ {
+				have, err := jh.extract(context.Background(), &requestv1.AuxData_JWT{Token: tc.token})
+				if tc.wantErr {
+					require.Error(t, err)
+					if tc.wantErrIs != nil {
+						require.ErrorIs(t, err, tc.wantErrIs)
+					}
+					continue
+				}
+
+				require.NoError(t, err)
+				require.Equal(t, "cerbos-test-suite", have["iss"].GetStringValue())
+			}
+		})
+	}
+
+	t.Run("cached_per_kid", func(t *testing.T) {
+		token := mkToken(t, keys["es384"], jwa.ES384, "es384")
+		_, err := jh.extract(context.Background(), &requestv1.AuxData_JWT{Token: token})
+		require.NoError(t, err)
+		require.True(t, jh.cache.Has(mkCacheKey("multi", "es384", token)))
+	})
+}
+
+func TestExtract_RequiredClaims(t *testing.T) {
+	secret := []byte("cerbos-jwt-tests-shared-secret")
+
+	mkToken := func(t *testing.T, claims map[string]any) string {
+		t.Helper()
+
+		key, err := jwk.FromRaw(secret)
+		require.NoError(t, err)
+
+		token := jwt.New()
+		require.NoError(t, token.Set(jwt.ExpirationKey, time.Now().Add(1*time.Hour)))
+		for k, v := range claims {
+			require.NoError(t, token.Set(k, v))
+		}
+
+		tokenBytes, err := jwt.Sign(token, jwt.WithKey(jwa.HS256, key))
+		require.NoError(t, err)
+
+		return string(tokenBytes)
+	}
+
+	conf := &Conf{JWT: &JWTConf{
+		KeySets: []JWTKeySet{
+			{
+				ID:             "secret",
+				RequiredClaims: []string{"sub", "tenant.id"},
+				Symmetric:      &SymmetricSource{Secret: base64.StdEncoding.EncodeToString(secret)},
+			},
+		},
+	}}
+	require.NoError(t, conf.Validate())
+
+	ctx, cancelFn := context.WithCancel(context.Background())
+	t.Cleanup(cancelFn)
+
+	jh := newJWTHelper(ctx, conf.JWT, nil)
+
+	testCases := []struct {
+		claims      map[string]any
+		name        string
+		wantMissing string
+	}{
+		{name: "present", claims: map[string]any{"sub": "harry", "tenant": map[string]any{"id": "acme"}}},
+		{name: "dotted_name", claims: map[string]any{"sub": "harry", "tenant.id": "acme"}},
+		{name: "missing", claims: map[string]any{"tenant": map[string]any{"id": "acme"}}, wantMissing: "sub"},
+		{name: "empty_string", claims: map[string]any{"sub": "", "tenant": map[string]any{"id": "acme"}}, wantMissing: "sub"},
+		{name: "missing_nested", claims: map[string]any{"sub": "harry", "tenant": map[string]any{"name": "acme"}}, wantMissing: "tenant.id"},
+		{name: "empty_nested", claims: map[string]any{"sub": "harry", "tenant": map[string]any{"id": []string{}}}, wantMissing: "tenant.id"},
+		{name: "not_an_object", claims: map[string]any{"sub": "harry", "tenant": "acme"}, wantMissing: "tenant.id"},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			token := mkToken(t, tc.claims)
+			// extract twice to check the claims on the cached path as well
+			for i := 0; i < 2; i++ {
+				have, err := jh.extract(context.Background(), &requestv1.AuxData_JWT{Token: token, KeySetId: "secret"})
+				if tc.wantMissing != "" {
+					require.ErrorIs(t, err, ErrJWTMissingClaim)
+					require.Contains(t, err.Error(), fmt.Sprintf("%q", tc.wantMissing))
+					continue
+				}
+
+				require.NoError(t, err)
+				require.Equal(t, "harry", have["sub"].GetStringValue())
+			}
+		})
+	}
+}
+
+func TestExtract_IndexArrayClaims(t *testing.T) {
+	ctx, cancelFn := context.WithCancel(context.Background())
+	t.Cleanup(cancelFn)
+
+	expiry := time.Now().Add(1 * time.Hour)
+	token := mkSignedToken(t, expiry)
+
+	jh := newJWTHelper(ctx, &JWTConf{DisableVerification: true, IndexArrayClaims: true}, nil)
+	have, err := jh.extract(context.Background(), &requestv1.AuxData_JWT{Token: token})
+	require.NoError(t, err)
+
+	want := mkExpectedTokenData(t, expiry)
+	want["aud.0"] = structpb.NewStringValue("cerbos-jwt-tests")
+	want["customArray.0"] = structpb.NewStringValue("A")
+	want["customArray.1"] = structpb.NewStringValue("B")
+	want["customArray.2"] = structpb.NewStringValue("C")
+
+	require.Empty(t, cmp.Diff(want, have, protocmp.Transform()))
+}
+
+func TestIndexArrays(t *testing.T) {
+	mkValue := func(t *testing.T, v any) *structpb.Value {
+		t.Helper()
+
+		value, err := structpb.NewValue(v)
+		require.NoError(t, err)
+		return value
+	}
+
+	have := map[string]*structpb.Value{
+		"roles":   mkValue(t, []any{"admin", "user"}),
+		"matrix":  mkValue(t, []any{[]any{"a"}, map[string]any{"tags": []any{"x"}}}),
+		"org":     mkValue(t, map[string]any{"teams": []any{"red"}}),
+		"roles.1": mkValue(t, "original"),
+	}
+
+	indexArrays(have)
+
+	want := map[string]*structpb.Value{
+		"roles":      mkValue(t, []any{"admin", "user"}),
+		"roles.0":    mkValue(t, "admin"),
+		"roles.1":    mkValue(t, "original"),
+		"matrix":     mkValue(t, []any{[]any{"a"}, map[string]any{"tags": []any{"x"}, "tags.0": "x"}}),
+		"matrix.0":   mkValue(t, []any{"a"}),
+		"matrix.0.0": mkValue(t, "a"),
+		"matrix.1":   mkValue(t, map[string]any{"tags": []any{"x"}, "tags.0": "x"}),
+		"org":        mkValue(t, map[string]any{"teams": []any{"red"}, "teams.0": "red"}),
+	}
+
+	require.Empty(t, cmp.Diff(want, have, protocmp.Transform()))
+}
+
+func TestExtract_AcceptableClaims(t *testing.T) {
+	keysDir := test.PathToDir(t, "auxdata")
+	token := mkSignedToken(t, time.Now().Add(1*time.Hour))
+
+	ctx, cancelFn := context.WithCancel(context.Background())
+	t.Cleanup(cancelFn)
+
+	testCases := []struct {
+		name      string
+		issuers   []string
+		audiences []string
+		wantErr   bool
+	}{
+		{name: "unconstrained"},
+		{name: "acceptable", issuers: []string{"other-issuer", "cerbos-test-suite"}, audiences: []string{"cerbos-jwt-tests"}},
+		{name: "unacceptable_issuer", issuers: []string{"other-issuer"}, wantErr: true},
+		{name: "unacceptable_audience", audiences: []string{"other-service"}, wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			for _, verify := range []bool{true, false} {
+				conf := &JWTConf{
+					KeySets:             []JWTKeySet{{ID: "local", Local: &LocalSource{File: filepath.Join(keysDir, "verify_key.jwk")}}},
+					DisableVerification: !verify,
+					CacheSize:           defaultCacheSize,
+					AcceptableIssuers:   tc.issuers,
+					AcceptableAudiences: tc.audiences,
+				}
+
+				jh := newJWTHelper(ctx, conf, nil)
+				if verify {
+					// simulate a cache hit to make sure that the claims are still validated
+					require.NoError(t, jh.cacheFor("local").Set(mkCacheKey("local", signingKeyID, token), cacheEntry))
+				}
+
+				_, err := jh.extract(context.Background(), &requestv1.AuxData_JWT{Token: token})
+				if tc.wantErr {
+					require.Error(t, err, "verify=%t", verify)
+					continue
+				}
+
+				require.NoError(t, err, "verify=%t", verify)
+			}
+		})
+	}
+}
+
+func TestExtract_ClockSkew(t *testing.T) {
+	keysDir := test.PathToDir(t, "auxdata")
+	token := mkSignedToken(t, time.Now().Add(-10*time.Second))
+
+	ctx, cancelFn := context.WithCancel(context.Background())
+	t.Cleanup(cancelFn)
+
+	for _, verify := range []bool{true, false} {
+		mkConf := func(skew time.Duration) *JWTConf {
+			return &JWTConf{
+				KeySets:             []JWTKeySet{{ID: "local", Local: &LocalSource{File: filepath.Join(keysDir, "verify_key.jwk")}}},
+				DisableVerification: !verify,
+				CacheSize:           defaultCacheSize,
+				ClockSkew:           skew,
+			}
+		}
+
+		_, err := newJWTHelper(ctx, mkConf(0), nil).extract(context.Background(), &requestv1.AuxData_JWT{Token: token})
+		require.Error(t, err, "verify=%t", verify)
+
+		_, err = newJWTHelper(ctx, mkConf(time.Minute), nil).extract(context.Background(), &requestv1.AuxData_JWT{Token: token})
+		require.NoError(t, err, "verify=%t", verify)
+	}
+}
+
+func TestKeySetCaches(t *testing.T) {
+	keysDir := test.PathToDir(t, "auxdata")
+	localKeySet := func(id string, cacheSize int) JWTKeySet {
+		return JWTKeySet{ID: id, CacheSize: cacheSize, Local: &LocalSource{File: filepath.Join(keysDir, "verify_key.jwk")}}
+	}
+
+	ctx, cancelFn := context.WithCancel(context.Background())
+	t.Cleanup(cancelFn)
+
+	jh := newJWTHelper(ctx, &JWTConf{
+		CacheSize: defaultCacheSize,
+		KeySets:   []JWTKeySet{localKeySet("shared", 0), localKeySet("dedicated", 16), localKeySet("uncached", -1)},
+	}, nil)
+
+	require.Same(t, jh.cache, jh.cacheFor("shared"))
+	require.NotNil(t, jh.cacheFor("dedicated"))
+	require.NotSame(t, jh.cache, jh.cacheFor("dedicated"))
+	require.Nil(t, jh.cacheFor("uncached"))
+
+	token := mkSignedToken(t, time.Now().Add(1*time.Hour))
+	for _, keySetID := range []string{"shared", "dedicated", "uncached"} {
+		_, err := jh.extract(context.Background(), &requestv1.AuxData_JWT{Token: token, KeySetId: keySetID})
+		require.NoError(t, err)
+	}
+
+	// the same token verified by different keysets must not share a cache entry
+	require.Equal(t, 1, jh.cache.Len(false))
+	require.True(t, jh.cache.Has(mkCacheKey("shared", signingKeyID, token)))
+	require.False(t, jh.cache.Has(mkCacheKey("dedicated", signingKeyID, token)))
+	require.True(t, jh.cacheFor("dedicated").Has(mkCacheKey("dedicated", signingKeyID, token)))
+}
+
+func TestMkCacheKey(t *testing.T) {
+	token := mkSignedToken(t, time.Now().Add(1*time.Hour))
+	parts := strings.Split(token, ".")
+	require.Len(t, parts, 3)
+
+	// same signature with a different payload
+	payload := base64.RawURLEncoding.EncodeToString([]byte(`{"iss":"cerbos-test-suite","sub":"admin"}`))
+	forged := strings.Join([]string{parts[0], payload, parts[2]}, ".")
+
+	require.Equal(t, mkCacheKey("local", signingKeyID, token), mkCacheKey("local", signingKeyID, token))
+	require.NotEqual(t, mkCacheKey("local", signingKeyID, token), mkCacheKey("local", signingKeyID, forged))
+	require.NotEqual(t, mkCacheKey("local", signingKeyID, token), mkCacheKey("other", signingKeyID, token))
+	require.NotEqual(t, mkCacheKey("local", signingKeyID, token), mkCacheKey("local", "other", token))
+	require.Empty(t, mkCacheKey("local", signingKeyID, "malformed"))
+
+	ctx, cancelFn := context.WithCancel(context.Background())
+	t.Cleanup(cancelFn)
+
+	keysDir := test.PathToDir(t, "auxdata")
+	jh := newJWTHelper(ctx, &JWTConf{
+		CacheSize: defaultCacheSize,
+		KeySets:   []JWTKeySet{{ID: "local", Local: &LocalSource{File: filepath.Join(keysDir, "verify_key.jwk")}}},
+	}, nil)
+
+	_, err := jh.extract(context.Background(), &requestv1.AuxData_JWT{Token: token})
+	require.NoError(t, err)
+
+	// the forged token must be verified instead of hitting the cache entry of the genuine one
+	_, err = jh.extract(context.Background(), &requestv1.AuxData_JWT{Token: forged})
+	require.ErrorIs(t, err, ErrJWTBadSignature)
+}
+
+func TestCacheUsage(t *testing.T) {
+	keysDir := test.PathToDir(t, "auxdata")
+
+	ctx, cancelFn := context.WithCancel(context.Background())
+	t.Cleanup(cancelFn)
+
+	jh := newJWTHelper(ctx, &JWTConf{
+		KeySets: []JWTKeySet{{ID: "local", CacheSize: 1, Local: &LocalSource{File: filepath.Join(keysDir, "verify_key.jwk")}}},
+	}, nil)
+	require.Len(t, jh.cacheUsage, 1)
+	require.Equal(t, 0, jh.cacheSize("local"))
+
+	for _, expiry := range []time.Duration{1 * time.Hour, 2 * time.Hour} {
+		token := mkSignedToken(t, time.Now().Add(expiry))
+		_, err := jh.extract(context.Background(), &requestv1.AuxData_JWT{Token: token})
+		require.NoError(t, err)
+	}
+
+	require.Equal(t, 1, jh.cacheSize("local"))
+	require.Equal(t, int64(1), jh.cacheUsage[0].evictions.Load())
+
+	jh.recordCacheUsage(zap.NewNop())
+	require.Equal(t, int64(0), jh.cacheUsage[0].evictions.Load(), "Evictions should be reset after each report")
+}
+
+func TestCachePolicy(t *testing.T) {
+	for policy := range cachePolicies {
+		policy := policy
+		t.Run(policy, func(t *testing.T) {
+			jh := &jwtHelper{cachePolicy: policy}
+			cache := jh.mkCache("test", 2)
+			for _, key := range []string{"a", "b", "c"} {
+				require.NoError(t, cache.Set(key, struct{}{}))
+			}
+
+			require.Equal(t, 2, cache.Len(false))
+			require.Equal(t, int64(1), jh.cacheUsage[0].evictions.Load())
+		})
+	}
+}
+
+// BenchmarkCachePolicy compares the hit rates of the cache policies when the tokens are accessed following a Zipf
+// distribution, where a few tokens account for most of the requests (such as service accounts) while most tokens are rarely seen.
+func BenchmarkCachePolicy(b *testing.B) {
+	const (
+		cacheSize = 256
+		numTokens = 4096
+	)
+
+	for _, policy := range []string{"arc", "lfu", "lru"} {
+		policy := policy
+		b.Run(policy, func(b *testing.B) {
+			jh := &jwtHelper{cachePolicy: policy}
+			cache := jh.mkCache("bench", cacheSize)
+			zipf := rand.NewZipf(rand.New(rand.NewSource(1)), 1.1, 1, numTokens-1) //nolint:gosec
+
+			hits := 0
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				key := zipf.Uint64()
+				if _, err := cache.Get(key); err == nil {
+					hits++
+					continue
+				}
+				_ = cache.Set(key, struct{}{})
+			}
+
+			b.ReportMetric(100*float64(hits)/float64(b.N), "hit%")
+		})
+	}
+}
+
+func TestExtract_ClaimFilters(t *testing.T) {
+	keysDir := test.PathToDir(t, "auxdata")
+	expiry := time.Now().Add(1 * time.Hour)
+	token := mkSignedToken(t, expiry)
+	all := mkExpectedTokenData(t, expiry)
+
+	ctx, cancelFn := context.WithCancel(context.Background())
+	t.Cleanup(cancelFn)
+
+	mkStruct := func(t *testing.T, m map[string]any) *structpb.Value {
+		t.Helper()
+
+		s, err := structpb.NewStruct(m)
+		require.NoError(t, err)
+		return structpb.NewStructValue(s)
+	}
+
+	testCases := []struct {
+		want    map[string]*structpb.Value
+		name    string
+		include []string
+		exclude []string
+	}{
+		{
+			name: "no_filter",
+			want: all,
+		},
+		{
+			name:    "include",
+			include: []string{"iss", "customInt", "customMap.A", "customMap.C", "customMap.missing", "missing"},
+			want: map[string]*structpb.Value{
+				"iss":       all["iss"],
+				"customInt": all["customInt"],
+				"customMap": mkStruct(t, map[string]any{"A": "AA", "C": "CC"}),
+			},
+		},
+		{
+			name:    "exclude",
+			exclude: []string{"iss", "aud", "exp", "customArray", "customMap.B", "missing.path"},
+			want: map[string]*structpb.Value{
+				"customString": all["customString"],
+				"customInt":    all["customInt"],
+				"customMap":    mkStruct(t, map[string]any{"A": "AA", "C": "CC"}),
+			},
+		},
+		{
+			name:    "include_and_exclude",
+			include: []string{"iss", "customMap"},
+			exclude: []string{"customMap.A"},
+			want: map[string]*structpb.Value{
+				"iss":       all["iss"],
+				"customMap": mkStruct(t, map[string]any{"B": "BB", "C": "CC"}),
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			for _, verify := range []bool{true, false} {
+				conf := &JWTConf{
+					KeySets: []JWTKeySet{{
+						ID:            "local",
+						Local:         &LocalSource{File: filepath.Join(keysDir, "verify_key.jwk")},
+						Claims:        tc.include,
+						ExcludeClaims: tc.exclude,
+					}},
+					DisableVerification: !verify,
+				}
+
+				have, err := newJWTHelper(ctx, conf, nil).extract(context.Background(), &requestv1.AuxData_JWT{Token: token, KeySetId: "local"})
+				require.NoError(t, err)
+				require.Empty(t, cmp.Diff(tc.want, have, protocmp.Transform()), "verify=%t", verify)
+			}
+		})
+	}
+}
+
+func TestClaimFilter_DottedNames(t *testing.T) {
+	roles := structpb.NewStringValue("admin")
+	claims := map[string]*structpb.Value{
+		"https://domain.tld/roles": roles,
+		"sub":                      structpb.NewStringValue("alice"),
+	}
+
+	cf := claimFilter{include: []string{"https://domain.tld/roles"}}
+	require.True(t, cf.mayInclude("https://domain.tld/roles"))
+	require.False(t, cf.mayInclude("sub"))
+	require.Equal(t, map[string]*structpb.Value{"https://domain.tld/roles": roles}, cf.apply(claims))
+}
+
+func TestCacheExpiry(t *testing.T) {
+	mkToken := func(t *testing.T, expiry time.Time) jwt.Token {
+		t.Helper()
+
+		token := jwt.New()
+		if !expiry.IsZero() {
+			require.NoError(t, token.Set(jwt.ExpirationKey, expiry))
+		}
+		return token
+	}
+
+	jh := &jwtHelper{clockSkew: time.Minute}
+
+	have, ok := jh.cacheExpiry(mkToken(t, time.Time{}))
+	require.True(t, ok)
+	require.Equal(t, defaultCacheExpiry, have)
+
+	have, ok = jh.cacheExpiry(mkToken(t, time.Now().Add(time.Hour)))
+	require.True(t, ok)
+	require.InDelta(t, time.Hour+time.Minute, have, float64(time.Second))
+
+	// within the clock skew tolerance
+	have, ok = jh.cacheExpiry(mkToken(t, time.Now().Add(-30*time.Second)))
+	require.True(t, ok)
+	require.InDelta(t, 30*time.Second, have, float64(time.Second))
+
+	_, ok = jh.cacheExpiry(mkToken(t, time.Now().Add(-2*time.Minute)))
+	require.False(t, ok)
+}
+
+func TestExtract_RequestAudience(t *testing.T) {
+	keysDir := test.PathToDir(t, "auxdata")
+
+	conf := &JWTConf{
+		KeySets: []JWTKeySet{
+			{
+				ID:    "local_file",
+				Local: &LocalSource{File: filepath.Join(keysDir, "verify_key.jwk")},
+			},
+		},
+		CacheSize:        defaultCacheSize,
+		RequestAudiences: []string{"cerbos-jwt-tests", "other-tenant"},
+	}
+
+	ctx, cancelFn := context.WithCancel(context.Background())
+	t.Cleanup(cancelFn)
+
+	jh := newJWTHelper(ctx, conf, nil)
+	input := &requestv1.AuxData_JWT{Token: mkSignedToken(t, time.Now().Add(1*time.Hour))}
+
+	t.Run("matching_audience", func(t *testing.T) {
+		// extract twice to exercise both the cache miss and cache hit paths
+		for i := 0; i < 2; i++ {
+			have, err := jh.extract(context.Background(), input, WithAudience("cerbos-jwt-tests"))
+			require.NoError(t, err)
+			require.NotEmpty(t, have)
+		}
+	})
+
+	t.Run("mismatched_audience", func(t *testing.T) {
+		// the token is cached at this point but the audience must still be validated
+		_, err := jh.extract(context.Background(), input, WithAudience("other-tenant"))
+		require.Error(t, err)
+	})
+
+	t.Run("audience_not_in_allowlist", func(t *testing.T) {
+		_, err := jh.extract(context.Background(), input, WithAudience("arbitrary"))
+		require.Error(t, err)
+		require.ErrorIs(t, err, ErrJWTAudienceNotAllowed)
+	})
+
+	t.Run("no_audience", func(t *testing.T) {
+		have, err := jh.extract(context.Background(), input)
+		require.NoError(t, err)
+		require.NotEmpty(t, have)
+	})
+}
+
+func TestResolveKeySet(t *testing.T) {
+	keysDir := test.PathToDir(t, "auxdata")
+	localKeySet := func(id string) JWTKeySet {
+		return JWTKeySet{ID: id, Local: &LocalSource{File: filepath.Join(keysDir, "verify_key.jwk")}}
+	}
+
+	ctx, cancelFn := context.WithCancel(context.Background())
+	t.Cleanup(cancelFn)
+
+	single := newJWTHelper(ctx, &JWTConf{KeySets: []JWTKeySet{localKeySet("ks1")}}, nil)
+	multiple := newJWTHelper(ctx, &JWTConf{KeySets: []JWTKeySet{localKeySet("ks1"), localKeySet("ks2")}}, nil)
+	noVerify := newJWTHelper(ctx, &JWTConf{DisableVerification: true}, nil)
+
+	testCases := []struct {
+		name     string
+		helper   *jwtHelper
+		keySetID string
+		want     string
+		wantErr  bool
+	}{
+		{name: "single/default", helper: single, want: "ks1"},
+		{name: "single/explicit", helper: single, keySetID: "ks1", want: "ks1"},
+		{name: "single/unknown", helper: single, keySetID: "blah", wantErr: true},
+		{name: "multiple/explicit", helper: multiple, keySetID: "ks2", want: "ks2"},
+		{name: "multiple/missing", helper: multiple, wantErr: true},
+		{name: "no_verify/missing", helper: noVerify},
+		{name: "no_verify/explicit", helper: noVerify, keySetID: "ks1", want: "ks1"},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			have, err := tc.helper.resolveKeySet("not.a.token", tc.keySetID)
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.want, have)
+		})
+	}
+}
+
+func TestResolveJWTKeySet(t *testing.T) {
+	keysDir := test.PathToDir(t, "auxdata")
+	decryptKey := filepath.Join(keysDir, "keys", "rsa.jwk")
+	localKeySet := JWTKeySet{ID: "local", Local: &LocalSource{File: filepath.Join(keysDir, "verify_key.jwk")}, Decrypt: &LocalSource{File: decryptKey}}
+	secretKeySet := JWTKeySet{ID: "secret", Symmetric: &SymmetricSource{Secret: base64.StdEncoding.EncodeToString([]byte("cerbos-jwt-tests-shared-secret")), KeyID: "secret1"}}
+
+	var remoteFetches int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		atomic.AddInt32(&remoteFetches, 1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	t.Cleanup(ts.Close)
+	remoteKeySet := JWTKeySet{ID: "remote", Remote: &RemoteSource{URL: ts.URL}}
+
+	ctx, cancelFn := context.WithCancel(context.Background())
+	t.Cleanup(cancelFn)
+
+	prefetch := false
+	local := &AuxData{jwt: newJWTHelper(ctx, &JWTConf{ResolveKeySetByKeyID: true, KeySets: []JWTKeySet{secretKeySet, localKeySet}}, nil)}
+	withRemote := &AuxData{jwt: newJWTHelper(ctx, &JWTConf{ResolveKeySetByKeyID: true, Prefetch: &prefetch, KeySets: []JWTKeySet{localKeySet, remoteKeySet}}, nil)}
+
+	token := mkSignedToken(t, time.Now().Add(1*time.Hour))
+
+	testCases := []struct {
+		name     string
+		auxData  *AuxData
+		token    string
+		keySetID string
+		want     string
+		wantErr  error
+	}{
+		{name: "key_id", auxData: local, token: token, want: "local"},
+		{name: "bearer", auxData: local, token: "Bearer " + token, want: "local"},
+		{name: "encrypted", auxData: local, token: mkEncryptedToken(t, token, decryptKey), want: "local"},
+		{name: "explicit", auxData: local, token: token, keySetID: "secret", want: "secret"},
+		{name: "unsupported_scheme", auxData: local, token: "Basic dXNlcjpwYXNzd29yZA==", wantErr: ErrJWTMalformed},
+		{name: "key_id_with_remote", auxData: withRemote, token: token, wantErr: ErrKeyIDResolutionRequiresFetch},
+		{name: "explicit_remote", auxData: withRemote, token: token, keySetID: "remote", want: "remote"},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			have, err := tc.auxData.ResolveJWTKeySet(&requestv1.AuxData_JWT{Token: tc.token, KeySetId: tc.keySetID})
+			if tc.wantErr != nil {
+				require.ErrorIs(t, err, tc.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.want, have)
+		})
+	}
+
+	require.Zero(t, atomic.LoadInt32(&remoteFetches), "Remote keysets should not be fetched")
+}
+
+func TestExtract_IssuerKeySets(t *testing.T) {
+	keysDir := test.PathToDir(t, "auxdata")
+	issuerKeySet := func(id, issuer string) JWTKeySet {
+		return JWTKeySet{ID: id, Issuer: issuer, Local: &LocalSource{File: filepath.Join(keysDir, "verify_key.jwk")}}
+	}
+
+	ctx, cancelFn := context.WithCancel(context.Background())
+	t.Cleanup(cancelFn)
+
+	// mkSignedToken issues tokens from cerbos-test-suite
+	token := mkSignedToken(t, time.Now().Add(1*time.Hour))
+
+	matching := newJWTHelper(ctx, &JWTConf{KeySets: []JWTKeySet{issuerKeySet("ks1", "other-issuer"), issuerKeySet("ks2", "cerbos-test-suite")}}, nil)
+	notMatching := newJWTHelper(ctx, &JWTConf{KeySets: []JWTKeySet{issuerKeySet("ks1", "other-issuer")}}, nil)
+
+	t.Run("matching_issuer", func(t *testing.T) {
+		keySetID, err := matching.resolveKeySet(token, "")
+		require.NoError(t, err)
+		require.Equal(t, "ks2", keySetID)
+
+		have, err := matching.extract(context.Background(), &requestv1.AuxData_JWT{Token: token})
+		require.NoError(t, err)
+		require.Equal(t, "cerbos-test-suite", have["iss"].GetStringValue())
+	})
+
+	t.Run("no_matching_issuer", func(t *testing.T) {
+		_, err := notMatching.extract(context.Background(), &requestv1.AuxData_JWT{Token: token})
+		require.ErrorIs(t, err, ErrJWTNoKeySetForIssuer)
+	})
+
+	t.Run("explicit_keyset_enforces_issuer", func(t *testing.T) {
+		_, err := matching.extract(context.Background(), &requestv1.AuxData_JWT{Token: token, KeySetId: "ks1"})
+		require.Error(t, err)
+	})
+
+	t.Run("invalid_token", func(t *testing.T) {
+		_, err := matching.extract(context.Background(), &requestv1.AuxData_JWT{Token: "not.a.token"})
+		require.Error(t, err)
+		require.NotErrorIs(t, err, ErrJWTNoKeySetForIssuer)
+	})
+}
+
+func TestExtract_KeyIDKeySets(t *testing.T) {
+	keysDir := test.PathToDir(t, "auxdata")
+	localKeySet := func(id string) JWTKeySet {
+		return JWTKeySet{ID: id, Local: &LocalSource{File: filepath.Join(keysDir, "verify_key.jwk")}}
+	}
+	secretKeySet := JWTKeySet{ID: "secret", Symmetric: &SymmetricSource{Secret: base64.StdEncoding.EncodeToString([]byte("cerbos-jwt-tests-shared-secret")), KeyID: "secret1"}}
+
+	ctx, cancelFn := context.WithCancel(context.Background())
+	t.Cleanup(cancelFn)
+
+	token := mkSignedToken(t, time.Now().Add(1*time.Hour))
+
+	t.Run("matching_key_id", func(t *testing.T) {
+		jh := newJWTHelper(ctx, &JWTConf{ResolveKeySetByKeyID: true, KeySets: []JWTKeySet{secretKeySet, localKeySet("local")}}, nil)
+		have, err := jh.extract(context.Background(), &requestv1.AuxData_JWT{Token: token})
+		require.NoError(t, err)
+		require.Equal(t, "cerbos-test-suite", have["iss"].GetStringValue())
+	})
+
+	t.Run("ambiguous_key_id", func(t *testing.T) {
+		jh := newJWTHelper(ctx, &JWTConf{ResolveKeySetByKeyID: true, KeySets: []JWTKeySet{localKeySet("local1"), localKeySet("local2")}}, nil)
+		_, err := jh.extract(context.Background(), &requestv1.AuxData_JWT{Token: token})
+		require.ErrorIs(t, err, ErrJWTNoKeySetForKeyID)
+	})
+
+	t.Run("allowed_algorithms_of_matching_keyset", func(t *testing.T) {
+		local := localKeySet("local")
+		local.AllowedAlgorithms = []string{"RS256"}
+		jh := newJWTHelper(ctx, &JWTConf{ResolveKeySetByKeyID: true, KeySets: []JWTKeySet{secretKeySet, local}}, nil)
+		_, err := jh.extract(context.Background(), &requestv1.AuxData_JWT{Token: token})
+		require.ErrorIs(t, err, ErrJWTAlgorithmNotAllowed)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		jh := newJWTHelper(ctx, &JWTConf{KeySets: []JWTKeySet{secretKeySet, localKeySet("local")}}, nil)
+		_, err := jh.extract(context.Background(), &requestv1.AuxData_JWT{Token: token})
+		require.ErrorIs(t, err, errNoKeySetToVerify)
+		require.ErrorIs(t, err, ErrJWTUnknownKeySet)
+	})
+}
+
+func TestExtract_BearerScheme(t *testing.T) {
+	keysDir := test.PathToDir(t, "auxdata")
+
+	ctx, cancelFn := context.WithCancel(context.Background())
+	t.Cleanup(cancelFn)
+
+	jh := newJWTHelper(ctx, &JWTConf{KeySets: []JWTKeySet{{ID: "local", Local: &LocalSource{File: filepath.Join(keysDir, "verify_key.jwk")}}}}, nil)
+	input := &requestv1.AuxData_JWT{Token: "Bearer " + mkSignedToken(t, time.Now().Add(1*time.Hour))}
+
+	have, err := jh.extract(context.Background(), input)
+	require.NoError(t, err)
+	require.Equal(t, "cerbos-test-suite", have["iss"].GetStringValue())
+	require.True(t, strings.HasPrefix(input.Token, "Bearer "), "Input should not be modified")
+}
+
+func TestExtract_EncryptedToken(t *testing.T) {
+	keysDir := test.PathToDir(t, "auxdata")
+	decryptKey := filepath.Join(keysDir, "keys", "rsa.jwk")
+
+	ctx, cancelFn := context.WithCancel(context.Background())
+	t.Cleanup(cancelFn)
+
+	jh := newJWTHelper(ctx, &JWTConf{
+		CacheSize: defaultCacheSize,
+		KeySets: []JWTKeySet{
+			{ID: "local", Local: &LocalSource{File: filepath.Join(keysDir, "verify_key.jwk")}, Decrypt: &LocalSource{File: decryptKey}},
+			{ID: "secret", Symmetric: &SymmetricSource{Secret: base64.StdEncoding.EncodeToString([]byte("cerbos-jwt-tests-shared-secret"))}},
+		},
+	}, nil)
+
+	expiry := time.Now().Add(1 * time.Hour)
+	inner := mkSignedToken(t, expiry)
+	token := mkEncryptedToken(t, inner, decryptKey)
+
+	t.Run("requested_keyset", func(t *testing.T) {
+		have, err := jh.extract(context.Background(), &requestv1.AuxData_JWT{Token: token, KeySetId: "local"})
+		require.NoError(t, err)
+		require.Empty(t, cmp.Diff(mkExpectedTokenData(t, expiry), have, protocmp.Transform()))
+		require.True(t, jh.cache.Has(mkCacheKey("local", signingKeyID, inner)), "Cache key should be derived from the inner token")
+	})
+
+	t.Run("no_decryption_keys", func(t *testing.T) {
+		_, err := jh.extract(context.Background(), &requestv1.AuxData_JWT{Token: token, KeySetId: "secret"})
+		require.ErrorIs(t, err, ErrJWTDecryptionFailed)
+	})
+
+	t.Run("wrong_key", func(t *testing.T) {
+		other := mkEncryptedToken(t, inner, filepath.Join(keysDir, "keys", "ec.jwk"))
+		_, err := jh.extract(context.Background(), &requestv1.AuxData_JWT{Token: other, KeySetId: "local"})
+		require.ErrorIs(t, err, ErrJWTDecryptionFailed)
+	})
+
+	t.Run("malformed", func(t *testing.T) {
+		_, err := jh.extract(context.Background(), &requestv1.AuxData_JWT{Token: "not.a.valid.jwe.token", KeySetId: "local"})
+		require.ErrorIs(t, err, ErrJWTMalformed)
+	})
+}
+
+func TestExtract_AllowedAlgorithms(t *testing.T) {
+	keysDir := test.PathToDir(t, "auxdata")
+	localKeySet := func(id string, algs ...string) JWTKeySet {
+		return JWTKeySet{ID: id, AllowedAlgorithms: algs, Local: &LocalSource{File: filepath.Join(keysDir, "verify_key.jwk")}}
+	}
+
+	ctx, cancelFn := context.WithCancel(context.Background())
+	t.Cleanup(cancelFn)
+
+	jh := newJWTHelper(ctx, &JWTConf{KeySets: []JWTKeySet{localKeySet("default"), localKeySet("es384", "ES384"), localKeySet("rs256", "RS256")}}, nil)
+	token := mkSignedToken(t, time.Now().Add(1*time.Hour))
+
+	t.Run("default", func(t *testing.T) {
+		_, err := jh.extract(context.Background(), &requestv1.AuxData_JWT{Token: token, KeySetId: "default"})
+		require.NoError(t, err)
+	})
+
+	t.Run("allowed", func(t *testing.T) {
+		_, err := jh.extract(context.Background(), &requestv1.AuxData_JWT{Token: token, KeySetId: "es384"})
+		require.NoError(t, err)
+	})
+
+	t.Run("not_allowed", func(t *testing.T) {
+		_, err := jh.extract(context.Background(), &requestv1.AuxData_JWT{Token: token, KeySetId: "rs256"})
+		require.ErrorIs(t, err, ErrJWTAlgorithmNotAllowed)
+	})
+
+	t.Run("none", func(t *testing.T) {
+		header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none","typ":"JWT"}`))
+		payload := base64.RawURLEncoding.EncodeToString([]byte(`{"iss":"cerbos-test-suite"}`))
+		_, err := jh.extract(context.Background(), &requestv1.AuxData_JWT{Token: header + "." + payload + ".", KeySetId: "default"})
+		require.ErrorIs(t, err, ErrJWTAlgorithmNotAllowed)
+	})
+}
+
+func TestExtract_KeySetDisableVerification(t *testing.T) {
+	keysDir := test.PathToDir(t, "auxdata")
+
+	ctx, cancelFn := context.WithCancel(context.Background())
+	t.Cleanup(cancelFn)
+
+	jh := newJWTHelper(ctx, &JWTConf{
+		CacheSize: defaultCacheSize,
+		KeySets: []JWTKeySet{
+			{ID: "internal", DisableVerification: true},
+			{ID: "partner", Local: &LocalSource{File: filepath.Join(keysDir, "verify_key.jwk")}},
+		},
+	}, nil)
+
+	token := mkSignedToken(t, time.Now().Add(1*time.Hour))
+	parts := strings.Split(token, ".")
+	forged := strings.Join([]string{parts[0], base64.RawURLEncoding.EncodeToString([]byte(`{"iss":"cerbos-test-suite","sub":"admin"}`)), parts[2]}, ".")
+
+	t.Run("unverified_keyset", func(t *testing.T) {
+		have, err := jh.extract(context.Background(), &requestv1.AuxData_JWT{Token: forged, KeySetId: "internal"})
+		require.NoError(t, err)
+		require.Equal(t, "admin", have["sub"].GetStringValue())
+		require.Equal(t, 0, jh.cacheSize("internal"), "Unverified tokens should not be cached")
+	})
+
+	t.Run("claims_still_validated", func(t *testing.T) {
+		_, err := jh.extract(context.Background(), &requestv1.AuxData_JWT{Token: mkSignedToken(t, time.Now().Add(-1*time.Hour)), KeySetId: "internal"})
+		require.ErrorIs(t, err, ErrJWTExpired)
+	})
+
+	t.Run("verified_keyset", func(t *testing.T) {
+		_, err := jh.extract(context.Background(), &requestv1.AuxData_JWT{Token: token, KeySetId: "partner"})
+		require.NoError(t, err)
+
+		_, err = jh.extract(context.Background(), &requestv1.AuxData_JWT{Token: forged, KeySetId: "partner"})
+		require.ErrorIs(t, err, ErrJWTBadSignature)
+	})
+
+	t.Run("not_resolved_by_key_id", func(t *testing.T) {
+		jh := newJWTHelper(ctx, &JWTConf{
+			ResolveKeySetByKeyID: true,
+			KeySets: []JWTKeySet{
+				{ID: "internal", DisableVerification: true},
+				{ID: "partner", Local: &LocalSource{File: filepath.Join(keysDir, "verify_key.jwk")}},
+			},
+		}, nil)
+
+		_, err := jh.extract(context.Background(), &requestv1.AuxData_JWT{Token: forged})
+		require.ErrorIs(t, err, ErrJWTBadSignature)
+	})
+}
+
+func TestExtract_Require(t *testing.T) {
+	keysDir := test.PathToDir(t, "auxdata")
+
+	ctx, cancelFn := context.WithCancel(context.Background())
+	t.Cleanup(cancelFn)
+
+	jh := newJWTHelper(ctx, &JWTConf{
+		CacheSize:       defaultCacheSize,
+		RequireToken:    true,
+		RequireVerified: true,
+		KeySets: []JWTKeySet{
+			{ID: "internal", DisableVerification: true},
+			{ID: "partner", Local: &LocalSource{File: filepath.Join(keysDir, "verify_key.jwk")}},
+		},
+	}, nil)
+	ad := &AuxData{jwt: jh}
+
+	token := mkSignedToken(t, time.Now().Add(1*time.Hour))
+
+	t.Run("verified", func(t *testing.T) {
+		have, err := jh.extract(context.Background(), &requestv1.AuxData_JWT{Token: token, KeySetId: "partner"})
+		require.NoError(t, err)
+		require.NotEmpty(t, have)
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		_, err := jh.extract(context.Background(), nil)
+		require.ErrorIs(t, err, ErrJWTMissing)
+
+		_, err = jh.extract(context.Background(), &requestv1.AuxData_JWT{KeySetId: "partner"})
+		require.ErrorIs(t, err, ErrJWTMissing)
+
+		_, err = ad.Extract(context.Background(), nil)
+		require.ErrorIs(t, err, ErrJWTMissing)
+	})
+
+	t.Run("unverified", func(t *testing.T) {
+		_, err := jh.extract(context.Background(), &requestv1.AuxData_JWT{Token: token, KeySetId: "internal"})
+		require.ErrorIs(t, err, ErrJWTUnverified)
+		require.NotErrorIs(t, err, ErrJWTMissing)
+	})
+
+	t.Run("not_required", func(t *testing.T) {
+		ad := &AuxData{jwt: newJWTHelper(ctx, &JWTConf{KeySets: []JWTKeySet{{ID: "internal", DisableVerification: true}}}, nil)}
+
+		have, err := ad.Extract(context.Background(), nil)
+		require.NoError(t, err)
+		require.Nil(t, have)
+
+		_, err = ad.jwt.extract(context.Background(), &requestv1.AuxData_JWT{Token: token, KeySetId: "internal"})
+		require.NoError(t, err)
+	})
+}
+
+func TestExtract_FailureReasons(t *testing.T) {
+	keysDir := test.PathToDir(t, "auxdata")
+
+	require.NoError(t, view.Register(metrics.AuxDataJWTFailureCountView))
+	t.Cleanup(func() { view.Unregister(metrics.AuxDataJWTFailureCountView) })
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	t.Cleanup(ts.Close)
+
+	ctx, cancelFn := context.WithCancel(context.Background())
+	t.Cleanup(cancelFn)
+
+	prefetch := false
+	jh := newJWTHelper(ctx, &JWTConf{
+		KeySets: []JWTKeySet{
+			{ID: "local", Local: &LocalSource{File: filepath.Join(keysDir, "verify_key.jwk")}},
+			{ID: "secret", Symmetric: &SymmetricSource{Secret: base64.StdEncoding.EncodeToString([]byte("cerbos-jwt-tests-shared-secret"))}},
+			{ID: "unavailable", Remote: &RemoteSource{URL: ts.URL}},
+		},
+		Prefetch: &prefetch,
+	}, nil)
+
+	testCases := []struct {
+		name     string
+		keySetID string
+		token    string
+		want     error
+	}{
+		{name: "expired", keySetID: "local", token: mkSignedToken(t, time.Now().Add(-1*time.Hour)), want: ErrJWTExpired},
+		{name: "bad_signature", keySetID: "secret", token: mkSignedToken(t, time.Now().Add(1*time.Hour)), want: ErrJWTBadSignature},
+		{name: "malformed", keySetID: "local", token: "not.a.token", want: ErrJWTMalformed},
+		{name: "unknown_keyset", keySetID: "blah", token: mkSignedToken(t, time.Now().Add(1*time.Hour)), want: ErrJWTUnknownKeySet},
+		{name: "no_keyset", token: mkSignedToken(t, time.Now().Add(1*time.Hour)), want: ErrJWTUnknownKeySet},
+		{name: "unsupported_scheme", keySetID: "local", token: "Basic dXNlcjpwYXNzd29yZA==", want: ErrJWTMalformed},
+		{name: "keyset_unavailable", keySetID: "unavailable", token: mkSignedToken(t, time.Now().Add(1*time.Hour)), want: ErrJWTKeySetUnavailable},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := jh.extract(context.Background(), &requestv1.AuxData_JWT{Token: tc.token, KeySetId: tc.keySetID})
+			require.ErrorIs(t, err, tc.want)
+		})
+	}
+
+	t.Run("metric", func(t *testing.T) {
+		rows, err := view.RetrieveData(metrics.AuxDataJWTFailureCountView.Name)
+		require.NoError(t, err)
+
+		have := make(map[string]int64)
+		for _, row := range rows {
+			count, ok := row.Data.(*view.CountData)
+			require.True(t, ok)
+			for _, tg := range row.Tags {
+				if tg.Key == metrics.KeyAuxDataFailureReason {
+					have[tg.Value] = count.Value
+				}
+			}
+		}
+
+		for _, tc := range testCases {
+			reason, ok := failureReasons[tc.want]
+			require.True(t, ok, "no metric tag for %v", tc.want)
+			require.Positive(t, have[reason], "failure reason %q not recorded", reason)
+		}
+	})
+}
+
+func TestExtract_NamespacedTokens(t *testing.T) {
+	keysDir := test.PathToDir(t, "auxdata")
+
+	ctx, cancelFn := context.WithCancel(context.Background())
+	t.Cleanup(cancelFn)
+
+	ad := &AuxData{jwt: newJWTHelper(ctx, &JWTConf{KeySets: []JWTKeySet{
+		{ID: "local", Local: &LocalSource{File: filepath.Join(keysDir, "verify_key.jwk")}},
+		{ID: "secret", Symmetric: &SymmetricSource{Secret: base64.StdEncoding.EncodeToString([]byte("cerbos-jwt-tests-shared-secret"))}},
+	}}, nil)}
+
+	expiry := time.Now().Add(1 * time.Hour)
+	userToken := mkSignedToken(t, expiry)
+	serviceToken := mkSignedToken(t, expiry.Add(1*time.Hour))
+
+	t.Run("valid", func(t *testing.T) {
+		have, err := ad.Extract(context.Background(), &requestv1.AuxData{
+			Jwts: map[string]*requestv1.AuxData_JWT{
+				"user":    {Token: userToken, KeySetId: "local"},
+				"service": {Token: serviceToken, KeySetId: "local"},
+			},
+		})
+		require.NoError(t, err)
+		require.Len(t, have.Jwt, 2)
+
+		// both tokens have an exp claim, which is kept apart in each namespace
+		require.Empty(t, cmp.Diff(mkExpectedTokenData(t, expiry), have.Jwt["user"].GetStructValue().GetFields(), protocmp.Transform()))
+		require.Equal(t, expiry.Add(1*time.Hour).UTC().Format(time.RFC3339), have.Jwt["service"].GetStructValue().GetFields()["exp"].GetStringValue())
+	})
+
+	t.Run("with_jwt", func(t *testing.T) {
+		have, err := ad.Extract(context.Background(), &requestv1.AuxData{
+			Jwt:  &requestv1.AuxData_JWT{Token: userToken, KeySetId: "local"},
+			Jwts: map[string]*requestv1.AuxData_JWT{"service": {Token: serviceToken, KeySetId: "local"}},
+		})
+		require.NoError(t, err)
+
+		want := mkExpectedTokenData(t, expiry)
+		require.Equal(t, want["customString"].GetStringValue(), have.Jwt["customString"].GetStringValue())
+		require.Contains(t, have.Jwt, "service")
+	})
+
+	t.Run("namespace_used_by_claim", func(t *testing.T) {
+		_, err := ad.Extract(context.Background(), &requestv1.AuxData{
+			Jwt:  &requestv1.AuxData_JWT{Token: userToken, KeySetId: "local"},
+			Jwts: map[string]*requestv1.AuxData_JWT{"customString": {Token: serviceToken, KeySetId: "local"}},
+		})
+		require.ErrorIs(t, err, ErrJWTInvalidNamespace)
+	})
+
+	t.Run("invalid_namespace", func(t *testing.T) {
+		_, err := ad.Extract(context.Background(), &requestv1.AuxData{
+			Jwts: map[string]*requestv1.AuxData_JWT{"user.admin": {Token: userToken, KeySetId: "local"}},
+		})
+		require.ErrorIs(t, err, ErrJWTInvalidNamespace)
+	})
+
+	t.Run("invalid_token", func(t *testing.T) {
+		_, err := ad.Extract(context.Background(), &requestv1.AuxData{
+			Jwts: map[string]*requestv1.AuxData_JWT{
+				"user":    {Token: userToken, KeySetId: "local"},
+				"service": {Token: serviceToken, KeySetId: "secret"},
+			},
+		})
+		require.ErrorIs(t, err, ErrJWTBadSignature)
+	})
+}
+
+func TestExtract_NamespaceClaims(t *testing.T) {
+	keysDir := test.PathToDir(t, "auxdata")
+
+	ctx, cancelFn := context.WithCancel(context.Background())
+	t.Cleanup(cancelFn)
+
+	expiry := time.Now().Add(1 * time.Hour)
+	token := mkSignedToken(t, expiry)
+	wantClaims := mkExpectedTokenData(t, expiry)
+
+	extract := func(t *testing.T, namespaceClaims, reservedNamespaces []string) (map[string]*structpb.Value, error) {
+		t.Helper()
+
+		jh := newJWTHelper(ctx, &JWTConf{
+			KeySets:            []JWTKeySet{{ID: "local", Local: &LocalSource{File: filepath.Join(keysDir, "verify_key.jwk")}}},
+			NamespaceClaims:    namespaceClaims,
+			ReservedNamespaces: reservedNamespaces,
+		}, nil)
+
+		return jh.extract(context.Background(), &requestv1.AuxData_JWT{Token: token, KeySetId: "local"})
+	}
+
+	t.Run("namespaced", func(t *testing.T) {
+		have, err := extract(t, []string{"customString"}, nil)
+		require.NoError(t, err)
+		require.Len(t, have, 1)
+		require.Empty(t, cmp.Diff(wantClaims, have["foobar"].GetStructValue().GetFields(), protocmp.Transform()))
+	})
+
+	t.Run("first_claim_present", func(t *testing.T) {
+		have, err := extract(t, []string{"tokenUse", "customString", "iss"}, nil)
+		require.NoError(t, err)
+		require.Contains(t, have, "foobar")
+	})
+
+	t.Run("no_namespace_claim", func(t *testing.T) {
+		_, err := extract(t, []string{"tokenUse"}, nil)
+		require.ErrorIs(t, err, ErrJWTInvalidNamespace)
+		require.ErrorContains(t, err, "none of the namespace claims")
+	})
+
+	t.Run("no_namespace_claims_configured", func(t *testing.T) {
+		have, err := extract(t, nil, nil)
+		require.NoError(t, err)
+		require.Empty(t, cmp.Diff(wantClaims, have, protocmp.Transform()))
+	})
+
+	t.Run("not_a_string", func(t *testing.T) {
+		_, err := extract(t, []string{"customInt"}, nil)
+		require.ErrorIs(t, err, ErrJWTInvalidNamespace)
+	})
+
+	t.Run("invalid_namespace", func(t *testing.T) {
+		// the exp claim is extracted as a timestamp string, which contains characters that are not allowed in namespaces
+		_, err := extract(t, []string{"exp"}, nil)
+		require.ErrorIs(t, err, ErrJWTInvalidNamespace)
+		require.ErrorContains(t, err, "invalid characters")
+	})
+
+	t.Run("reserved_namespace", func(t *testing.T) {
+		_, err := extract(t, []string{"customString"}, []string{"FooBar"})
+		require.ErrorIs(t, err, ErrJWTInvalidNamespace)
+		require.ErrorContains(t, err, "reserved")
+	})
+}
+
+func TestCheckNamespace(t *testing.T) {
+	reserved := map[string]struct{}{"trusted": {}}
+
+	testCases := []struct {
+		namespace string
+		wantErr   bool
+	}{
+		{namespace: "user"},
+		{namespace: "service_account-1"},
+		{namespace: "Trusted", wantErr: true},
+		{namespace: "trusted", wantErr: true},
+		{namespace: "", wantErr: true},
+		{namespace: "1user", wantErr: true},
+		{namespace: "user.admin", wantErr: true},
+		{namespace: "user admin", wantErr: true},
+		{namespace: strings.Repeat("a", maxNamespaceLen)},
+		{namespace: strings.Repeat("a", maxNamespaceLen+1), wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.namespace, func(t *testing.T) {
+			err := checkNamespace(tc.namespace, reserved)
+			if tc.wantErr {
+				require.ErrorIs(t, err, ErrJWTInvalidNamespace)
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+// signingKeyID is the key ID of the key used by mkSignedToken.
+const signingKeyID = "19LfZatEdg83YNc5r23guMJqrn4="
+
+func mkSignedToken(t *testing.T, expiry time.Time) string {
+	t.Helper()
+
+	token := jwt.New()
+	require.NoError(t, token.Set(jwt.IssuerKey, "cerbos-test-suite"))
+	require.NoError(t, token.Set(jwt.AudienceKey, "cerbos-jwt-tests"))
+	require.NoError(t, token.Set(jwt.ExpirationKey, expiry))
+	require.NoError(t, token.Set("customString", "foobar"))
+	require.NoError(t, token.Set("customInt", 42))
+	require.NoError(t, token.Set("customArray", []string{"A", "B", "C"}))
+	require.NoError(t, token.Set("customMap", map[string]any{"A": "AA", "B": "BB", "C": "CC"}))
+
+	keyData, err := os.ReadFile(filepath.Join(test.PathToDir(t, "auxdata"), "signing_key.jwk"))
+	require.NoError(t, err)
+
+	keySet, err := jwk.ParseKey(keyData)
+	require.NoError(t, err)
+
+	tokenBytes, err := jwt.Sign(token, jwt.WithKey(jwa.ES384, keySet))
+	require.NoError(t, err)
+
+	return string(tokenBytes)
+}
+
+func mkEncryptedToken(t *testing.T, token, keyFile string) string {
+	t.Helper()
+
+	keyData, err := os.ReadFile(keyFile)
+	require.NoError(t, err)
+
+	key, err := jwk.ParseKey(keyData)
+	require.NoError(t, err)
+
+	pubKey, err := key.PublicKey()
+	require.NoError(t, err)
+
+	alg := jwa.RSA_OAEP_256
+	if pubKey.KeyType() == jwa.EC {
+		alg = jwa.ECDH_ES_A256KW
+	}
+
+	encrypted, err := jwe.Encrypt([]byte(token), jwe.WithKey(alg, pubKey))
+	require.NoError(t, err)
+
+	return string(encrypted)
 }
 
 func mkExpectedTokenData(t *testing.T, expiry time.Time) map[string]*structpb.Value {
@@ -0,0 +1,34 @@
+// Copyright 2021-2022 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package auxdata
+
+import (
+	"context"
+
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// Verifier verifies JWTs using the configured keysets, independently of the API request types.
+// Tokens are verified exactly as they would be for a request to the policy decision point,
+// using the same keyset resolution, cache, validations and metrics.
+type Verifier struct {
+	jwt *jwtHelper
+}
+
+// NewVerifier creates a standalone verifier from the given configuration.
+func NewVerifier(ctx context.Context, conf *Conf, opts ...Opt) *Verifier {
+	return &Verifier{jwt: newJWTHelper(ctx, conf.JWT, mkOptions(opts))}
+}
+
+// Verifier returns a verifier that shares its keysets and cache with this AuxData instance.
+func (ad *AuxData) Verifier() *Verifier {
+	return &Verifier{jwt: ad.jwt}
+}
+
+// Verify verifies the token using the keyset with the given ID and returns its claims.
+// If the keyset ID is empty, the keyset is resolved the same way as for a request without a keyset ID.
+// The token can optionally be preceded by the bearer scheme.
+func (v *Verifier) Verify(ctx context.Context, token, keySetID string, opts ...ExtractOpt) (map[string]*structpb.Value, error) {
+	return v.jwt.verifyToken(ctx, token, keySetID, opts...)
+}
@@ -0,0 +1,60 @@
+// Copyright 2021-2022 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package auxdata
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cerbos/cerbos/internal/test"
+)
+
+func TestVerifier(t *testing.T) {
+	keysDir := test.PathToDir(t, "auxdata")
+
+	ctx, cancelFn := context.WithCancel(context.Background())
+	t.Cleanup(cancelFn)
+
+	conf := &Conf{
+		JWT: &JWTConf{
+			KeySets: []JWTKeySet{
+				{ID: "local_file", Local: &LocalSource{File: filepath.Join(keysDir, "verify_key.jwk")}},
+			},
+			CacheSize: defaultCacheSize,
+		},
+	}
+
+	v := NewVerifier(ctx, conf)
+
+	t.Run("valid_token", func(t *testing.T) {
+		have, err := v.Verify(context.Background(), mkSignedToken(t, time.Now().Add(1*time.Hour)), "local_file")
+		require.NoError(t, err)
+		require.Equal(t, "foobar", have["customString"].GetStringValue())
+	})
+
+	t.Run("default_keyset", func(t *testing.T) {
+		have, err := v.Verify(context.Background(), "Bearer "+mkSignedToken(t, time.Now().Add(1*time.Hour)), "")
+		require.NoError(t, err)
+		require.Equal(t, "foobar", have["customString"].GetStringValue())
+	})
+
+	t.Run("expired_token", func(t *testing.T) {
+		_, err := v.Verify(context.Background(), mkSignedToken(t, time.Now().Add(-1*time.Hour)), "")
+		require.ErrorIs(t, err, ErrJWTExpired)
+	})
+
+	t.Run("empty_token", func(t *testing.T) {
+		_, err := v.Verify(context.Background(), "", "")
+		require.ErrorIs(t, err, ErrJWTMalformed)
+	})
+
+	t.Run("shared_with_auxdata", func(t *testing.T) {
+		ad := NewFromConf(ctx, conf)
+		require.Same(t, ad.jwt, ad.Verifier().jwt)
+	})
+}
@@ -3,6 +3,8 @@
 
 package compile
 
+import "github.com/cerbos/cerbos/internal/config"
+
 const (
 	confKey          = "compile"
 	defaultCacheSize = 1024
@@ -14,6 +16,10 @@ type Conf struct {
 	CacheSize uint `yaml:"cacheSize" conf:",example=1024"`
 }
 
+func init() {
+	config.RegisterSection(&Conf{})
+}
+
 func (c *Conf) Key() string {
 	return confKey
 }
@@ -0,0 +1,88 @@
+// Copyright 2021-2022 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+var byteSizeType = reflect.TypeOf(ByteSize(0))
+
+var byteSizeUnits = map[string]uint64{
+	"":    1,
+	"b":   1,
+	"kb":  1000,
+	"mb":  1000 * 1000,
+	"gb":  1000 * 1000 * 1000,
+	"tb":  1000 * 1000 * 1000 * 1000,
+	"kib": 1 << 10,
+	"mib": 1 << 20,
+	"gib": 1 << 30,
+	"tib": 1 << 40,
+}
+
+// ByteSize is a number of bytes. In addition to plain numbers, it can be configured using human-friendly strings with
+// decimal (KB, MB, GB, TB) or binary (KiB, MiB, GiB, TiB) units, such as "512KB" or "4MiB". Units are case-insensitive.
+type ByteSize uint64
+
+// ParseByteSize parses a number of bytes with an optional unit.
+func ParseByteSize(s string) (ByteSize, error) {
+	trimmed := strings.TrimSpace(s)
+	numEnd := strings.IndexFunc(trimmed, func(r rune) bool { return (r < '0' || r > '9') && r != '.' })
+	if numEnd < 0 {
+		numEnd = len(trimmed)
+	}
+
+	num, unit := trimmed[:numEnd], strings.ToLower(strings.TrimSpace(trimmed[numEnd:]))
+	multiplier, ok := byteSizeUnits[unit]
+	if !ok || num == "" {
+		return 0, fmt.Errorf("invalid byte size %q: must be a number optionally followed by one of B, KB, MB, GB, TB, KiB, MiB, GiB or TiB", s)
+	}
+
+	if !strings.Contains(num, ".") {
+		n, err := strconv.ParseUint(num, 10, 64)
+		if err != nil || (n != 0 && multiplier > math.MaxUint64/n) {
+			return 0, fmt.Errorf("invalid byte size %q: value is out of range", s)
+		}
+		return ByteSize(n * multiplier), nil
+	}
+
+	f, err := strconv.ParseFloat(num, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid byte size %q: %w", s, err)
+	}
+
+	size := f * float64(multiplier)
+	if size >= math.MaxUint64 {
+		return 0, fmt.Errorf("invalid byte size %q: value is out of range", s)
+	}
+
+	return ByteSize(size), nil
+}
+
+// UnmarshalYAML accepts both numbers and strings with units.
+func (b *ByteSize) UnmarshalYAML(unmarshal func(any) error) error {
+	var n uint64
+	if err := unmarshal(&n); err == nil {
+		*b = ByteSize(n)
+		return nil
+	}
+
+	var s string
+	if err := unmarshal(&s); err != nil {
+		return fmt.Errorf("invalid byte size: %w", err)
+	}
+
+	size, err := ParseByteSize(s)
+	if err != nil {
+		return err
+	}
+
+	*b = size
+	return nil
+}
@@ -0,0 +1,90 @@
+// Copyright 2021-2022 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseByteSize(t *testing.T) {
+	testCases := []struct {
+		input   string
+		want    ByteSize
+		wantErr bool
+	}{
+		{input: "0", want: 0},
+		{input: "1024", want: 1024},
+		{input: "512B", want: 512},
+		{input: "512KB", want: 512_000},
+		{input: "4MiB", want: 4 << 20},
+		{input: "4mib", want: 4 << 20},
+		{input: " 2 GB ", want: 2_000_000_000},
+		{input: "1.5GiB", want: 3 << 29},
+		{input: "1TiB", want: 1 << 40},
+		{input: "", wantErr: true},
+		{input: "MiB", wantErr: true},
+		{input: "-1MiB", wantErr: true},
+		{input: "4XB", wantErr: true},
+		{input: "1.2.3MB", wantErr: true},
+		{input: "99999999999TiB", wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.input, func(t *testing.T) {
+			have, err := ParseByteSize(tc.input)
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.want, have)
+		})
+	}
+}
+
+func TestHumanFriendlyValues(t *testing.T) {
+	type section struct {
+		Interval  time.Duration `yaml:"interval"`
+		Timeout   time.Duration `yaml:"timeout"`
+		MaxSize   ByteSize      `yaml:"maxSize"`
+		PlainSize ByteSize      `yaml:"plainSize"`
+		Quoted    ByteSize      `yaml:"quoted"`
+	}
+
+	conf := `
+app:
+  interval: 1h30m
+  timeout: 10m
+  maxSize: 4MiB
+  plainSize: 1024
+  quoted: "2048"
+`
+
+	w, err := WrapperFromReader(strings.NewReader(conf), nil)
+	require.NoError(t, err)
+
+	var have section
+	require.NoError(t, w.Get("app", &have))
+	require.Equal(t, section{
+		Interval:  90 * time.Minute,
+		Timeout:   10 * time.Minute,
+		MaxSize:   4 << 20,
+		PlainSize: 1024,
+		Quoted:    2048,
+	}, have)
+
+	t.Run("invalid_byte_size", func(t *testing.T) {
+		w, err := WrapperFromReader(strings.NewReader("app:\n  maxSize: lots\n"), nil)
+		require.NoError(t, err)
+
+		var have section
+		require.Error(t, w.Get("app", &have))
+	})
+}
@@ -12,10 +12,31 @@ import (
 	"sync"
 
 	"go.uber.org/config"
+	"go.uber.org/multierr"
+	"go.uber.org/zap"
 )
 
 var ErrConfigNotLoaded = errors.New("config not loaded")
 
+// MissingEnvVarsError is returned when the config references environment variables that are not set and don't have a default value.
+type MissingEnvVarsError struct {
+	cause error
+	Names []string
+}
+
+func (e *MissingEnvVarsError) Error() string {
+	const hint = "Config values containing '$' are interpreted as environment variables. Use '$$' to escape literal '$' values"
+	if len(e.Names) == 1 {
+		return fmt.Sprintf("required environment variable %s is not set. %s", e.Names[0], hint)
+	}
+
+	return fmt.Sprintf("required environment variables %s are not set. %s", strings.Join(e.Names, ", "), hint)
+}
+
+func (e *MissingEnvVarsError) Unwrap() error {
+	return e.cause
+}
+
 var conf = &Wrapper{}
 
 type Section interface {
@@ -30,8 +51,82 @@ type Validator interface {
 	Validate() error
 }
 
+// Deprecation describes a section key that is no longer supported.
+type Deprecation struct {
+	// Replacement is the key that supersedes the deprecated key. Empty if the key has been removed without a replacement.
+	Replacement string
+	// CopyValue applies the value of the deprecated key to the replacement key if the latter is not set.
+	CopyValue bool
+}
+
+// Deprecator is implemented by sections that have deprecated keys.
+// Keys are relative to the section and nested keys are separated by dots.
+type Deprecator interface {
+	DeprecatedKeys() map[string]Deprecation
+}
+
 // Load loads the config file at the given path.
 func Load(confFile string, overrides map[string]any) error {
+	if err := checkConfFile(confFile); err != nil {
+		return err
+	}
+
+	src, err := fileSource(confFile)
+	if err != nil {
+		return err
+	}
+
+	sources, err := withOverrides([]config.YAMLOption{src}, overrides)
+	if err != nil {
+		return err
+	}
+
+	if err := doLoad(sources...); err != nil {
+		return err
+	}
+
+	recordLoad([]string{confFile}, "", overrides)
+	return nil
+}
+
+// LoadFiles loads the config files at the given paths in order. Values from later files override the values from earlier files,
+// with maps merged recursively. The overrides are applied last.
+//
+// Lists in the overrides replace the lists defined by the files, unless the key ends with a plus sign: the list under the key
+// "allowedOrigins+" is appended to the "allowedOrigins" list defined by the files. This applies to all the functions that accept overrides.
+func LoadFiles(paths []string, overrides map[string]any) error {
+	if len(paths) == 0 {
+		return errors.New("no config files specified")
+	}
+
+	sources := make([]config.YAMLOption, 0, len(paths)+1)
+	for _, p := range paths {
+		if err := checkConfFile(p); err != nil {
+			return err
+		}
+
+		src, err := fileSource(p)
+		if err != nil {
+			return err
+		}
+
+		sources = append(sources, src)
+	}
+
+	sources, err := withOverrides(sources, overrides)
+	if err != nil {
+		return err
+	}
+
+	if err := doLoad(sources...); err != nil {
+		return err
+	}
+
+	recordLoad(paths, "", overrides)
+	return nil
+}
+
+func checkConfFile(confFile string) error {
 	finfo, err := os.Stat(confFile)
 	if err != nil {
 		return fmt.Errorf("failed to stat %s: %w", confFile, err)
@@ -41,17 +136,28 @@ func Load(confFile string, overrides map[string]any) error {
 		return fmt.Errorf("config file path is a directory: %s", confFile)
 	}
 
-	return doLoad(config.File(confFile), config.Static(overrides))
+	return nil
 }
 
 func LoadReader(reader io.Reader, overrides map[string]any) error {
-	return doLoad(config.Source(reader), config.Static(overrides))
+	src, err := readerSource(reader)
+	if err != nil {
+		return err
+	}
+
+	sources, err := withOverrides([]config.YAMLOption{src}, overrides)
+	if err != nil {
+		return err
+	}
+
+	return doLoad(sources...)
 }
 
 func LoadMap(m map[string]any) error {
 	return doLoad(config.Static(m))
 }
 
+// doLoad replaces the global configuration. The sections are validated by the components that use them.
 func doLoad(sources ...config.YAMLOption) error {
 	provider, err := mkProvider(sources...)
 	if err != nil {
@@ -62,11 +168,36 @@ func doLoad(sources ...config.YAMLOption) error {
 	return nil
 }
 
+// doReload replaces the global configuration of a running process. Unlike doLoad, the registered sections are
+// validated first because the components have already been started and won't get a chance to reject invalid values.
+func doReload(sources ...config.YAMLOption) error {
+	provider, err := mkProvider(sources...)
+	if err != nil {
+		return err
+	}
+
+	return conf.loadProvider(provider)
+}
+
 func mkProvider(sources ...config.YAMLOption) (config.Provider, error) {
-	opts := append(sources, config.Expand(os.LookupEnv)) //nolint:gocritic
+	// keep track of the variables that are not set so that the missing one can be reported by name
+	var unset []string
+	lookup := func(name string) (string, bool) {
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			unset = append(unset, name)
+		}
+		return value, ok
+	}
+
+	opts := append(sources, config.Expand(lookup)) //nolint:gocritic
 	provider, err := config.NewYAML(opts...)
 	if err != nil {
 		if strings.Contains(err.Error(), "couldn't expand environment") {
+			// expansion stops at the first variable that is not set and doesn't have a default value, so it is the last one looked up
+			if len(unset) > 0 {
+				return nil, &MissingEnvVarsError{Names: unset[len(unset)-1:], cause: err}
+			}
 			return nil, fmt.Errorf("error loading configuration due to unknown environment variable. Config values containing '$' are interpreted as environment variables. Use '$$' to escape literal '$' values: [%w]", err)
 		}
 		return nil, fmt.Errorf("failed to load config: %w", err)
@@ -91,8 +222,55 @@ func GetSection(section Section) error {
 	return conf.GetSection(section)
 }
 
+// GetAs returns a new value of type T populated with the configuration at the given key.
+// Like Get, the defaults are set and the value is validated if *T implements Defaulter and Validator.
+func GetAs[T any](key string) (T, error) {
+	var out T
+	if err := conf.Get(key, &out); err != nil {
+		var zero T
+		return zero, err
+	}
+
+	return out, nil
+}
+
+// GetSectionAs returns a new, populated config section. The type parameter is the section struct rather than the pointer
+// that implements Section, so that the section can be allocated. For example: config.GetSectionAs[server.Conf]().
+func GetSectionAs[T any, PT interface {
+	*T
+	Section
+}]() (PT, error) {
+	out := PT(new(T))
+	if err := conf.GetSection(out); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+// ValidateAll populates and validates all the given sections using the global config wrapper. See Wrapper.ValidateAll.
+func ValidateAll(sections ...Section) error {
+	return conf.ValidateAll(sections...)
+}
+
+// SetDefaultsValidation configures whether the global config wrapper validates sections populated solely from defaults.
+// See Wrapper.SetDefaultsValidation.
+func SetDefaultsValidation(enabled bool) {
+	conf.SetDefaultsValidation(enabled)
+}
+
 func WrapperFromReader(reader io.Reader, overrides map[string]any) (*Wrapper, error) {
-	return newWrapper(config.Source(reader), config.Static(overrides))
+	src, err := readerSource(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	sources, err := withOverrides([]config.YAMLOption{src}, overrides)
+	if err != nil {
+		return nil, err
+	}
+
+	return newWrapper(sources...)
 }
 
 func WrapperFromMap(m map[string]any) (*Wrapper, error) {
@@ -109,8 +287,26 @@ func newWrapper(sources ...config.YAMLOption) (*Wrapper, error) {
 }
 
 type Wrapper struct {
-	provider config.Provider
-	mu       sync.RWMutex
+	provider         config.Provider
+	env              map[string]any
+	reloadSubs       []reloadSub
+	mu               sync.RWMutex
+	nextSubID        uint64
+	validateDefaults bool
+}
+
+type reloadSub struct {
+	fn func()
+	id uint64
+}
+
+// SetDefaultsValidation configures whether Validate is called on sections that are populated solely from defaults because no configuration has been loaded.
+// This is disabled by default. Enabling it helps catch default values that violate the validation rules of their own section.
+func (w *Wrapper) SetDefaultsValidation(enabled bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.validateDefaults = enabled
 }
 
 func (w *Wrapper) Get(key string, out any) error {
@@ -118,12 +314,19 @@ func (w *Wrapper) Get(key string, out any) error {
 	defer w.mu.RUnlock()
 
 	if w.provider == nil {
-		if d, ok := out.(Defaulter); ok {
-			d.SetDefaults()
-			return nil
+		d, ok := out.(Defaulter)
+		if !ok {
+			return ErrConfigNotLoaded
 		}
 
-		return ErrConfigNotLoaded
+		d.SetDefaults()
+		if v, ok := out.(Validator); ok && w.validateDefaults {
+			if err := v.Validate(); err != nil {
+				return fmt.Errorf("invalid defaults for %q: %w", key, err)
+			}
+		}
+
+		return nil
 	}
 
 	// set defaults if any are specified
@@ -131,7 +334,11 @@ func (w *Wrapper) Get(key string, out any) error {
 		d.SetDefaults()
 	}
 
-	if err := w.provider.Get(key).Populate(out); err != nil {
+	if err := populate(key, w.provider.Get(key), out); err != nil {
+		return err
+	}
+
+	if err := w.populateFromEnv(key, out); err != nil {
 		return err
 	}
 
@@ -143,13 +350,116 @@ func (w *Wrapper) Get(key string, out any) error {
 	return nil
 }
 
+// populate populates out from the given value after removing (and warning about) any deprecated keys.
+func populate(key string, value config.Value, out any) error {
+	d, ok := out.(Deprecator)
+	if !ok {
+		return value.Populate(out)
+	}
+
+	var raw map[string]any
+	if err := value.Populate(&raw); err != nil {
+		return err
+	}
+	raw, _ = normalize(raw).(map[string]any)
+
+	found := false
+	log := zap.L().Named("config")
+	for oldKey, dep := range d.DeprecatedKeys() {
+		v, ok := deletePath(raw, oldKey)
+		if !ok {
+			continue
+		}
+
+		found = true
+		if dep.Replacement == "" {
+			log.Warn("Ignoring deprecated configuration key", zap.String("key", key+pathSep+oldKey))
+			continue
+		}
+
+		log.Warn("Configuration key is deprecated", zap.String("key", key+pathSep+oldKey), zap.String("replacement", key+pathSep+dep.Replacement))
+		if _, exists := getPath(raw, dep.Replacement); dep.CopyValue && !exists {
+			setPath(raw, dep.Replacement, v)
+		}
+	}
+
+	if !found {
+		return value.Populate(out)
+	}
+
+	provider, err := config.NewYAML(config.Static(raw))
+	if err != nil {
+		return err
+	}
+
+	return provider.Get(config.Root).Populate(out)
+}
+
 func (w *Wrapper) GetSection(section Section) error {
 	return w.Get(section.Key(), section)
 }
 
+// ValidateAll populates and validates all the given sections, returning the combination of all errors encountered
+// instead of stopping at the first one. This makes it possible to report all configuration problems at once.
+func (w *Wrapper) ValidateAll(sections ...Section) error {
+	var errs error
+	for _, section := range sections {
+		if err := w.GetSection(section); err != nil {
+			errs = multierr.Append(errs, fmt.Errorf("invalid configuration for %q: %w", section.Key(), err))
+		}
+	}
+
+	return errs
+}
+
+// loadProvider replaces the current provider with the given one if all the registered sections are valid.
+// Otherwise, the current provider stays in place and the validation errors are returned.
+func (w *Wrapper) loadProvider(provider config.Provider) error {
+	w.mu.RLock()
+	candidate := &Wrapper{provider: provider, env: w.env, validateDefaults: w.validateDefaults}
+	w.mu.RUnlock()
+
+	if err := candidate.ValidateAll(registeredSections()...); err != nil {
+		return fmt.Errorf("rejected invalid configuration: %w", err)
+	}
+
+	w.replaceProvider(provider)
+	return nil
+}
+
 func (w *Wrapper) replaceProvider(provider config.Provider) {
+	w.mu.Lock()
+	w.provider = provider
+	subs := make([]reloadSub, len(w.reloadSubs))
+	copy(subs, w.reloadSubs)
+	w.mu.Unlock()
+
+	// callbacks are invoked without holding the lock so that they can call Get to fetch the new values
+	for _, sub := range subs {
+		sub.fn()
+	}
+}
+
+// OnReload registers a callback that is invoked after a new configuration has been loaded, so that components that cache
+// configuration values can fetch them again. Callbacks are invoked in the order they were registered.
+// The returned function removes the callback and is safe to call more than once.
+func (w *Wrapper) OnReload(fn func()) (unsubscribe func()) {
 	w.mu.Lock()
 	defer w.mu.Unlock()
 
-	w.provider = provider
+	w.nextSubID++
+	id := w.nextSubID
+	w.reloadSubs = append(w.reloadSubs, reloadSub{id: id, fn: fn})
+
+	return func() {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+
+		for i, sub := range w.reloadSubs {
+			if sub.id == id {
+				w.reloadSubs = append(w.reloadSubs[:i:i], w.reloadSubs[i+1:]...)
+				return
+			}
+		}
+	}
 }
@@ -4,16 +4,28 @@
 package config_test
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	"go.uber.org/multierr"
 
+	// Register the sections validated on reload.
+	_ "github.com/cerbos/cerbos/internal/audit"
+	_ "github.com/cerbos/cerbos/internal/auxdata"
+	_ "github.com/cerbos/cerbos/internal/compile"
 	"github.com/cerbos/cerbos/internal/config"
+	_ "github.com/cerbos/cerbos/internal/engine"
+	_ "github.com/cerbos/cerbos/internal/observability/tracing"
+	_ "github.com/cerbos/cerbos/internal/schema"
 	"github.com/cerbos/cerbos/internal/server"
+	_ "github.com/cerbos/cerbos/internal/storage"
+	_ "github.com/cerbos/cerbos/internal/telemetry"
 )
 
 var errTestValidate = errors.New("validation error")
@@ -113,6 +125,262 @@ func TestOverride(t *testing.T) {
 	})
 }
 
+func TestLoadFiles(t *testing.T) {
+	overlay := filepath.Join(t.TempDir(), "overlay.yaml")
+	require.NoError(t, os.WriteFile(overlay, []byte("server:\n  listenAddr: \":7777\"\n  tls:\n    key: overlayKey\n"), 0o600))
+
+	overrides := map[string]any{"server": map[string]any{"tls": map[string]any{"certificate": "newCert"}}}
+	require.NoError(t, config.LoadFiles([]string{filepath.Join("testdata", "test_load.yaml"), overlay}, overrides))
+
+	var haveServer Server
+	require.NoError(t, config.GetSection(&haveServer))
+	require.Equal(t, Server{
+		DataDir:    fmt.Sprintf("%s/tmp", os.Getenv("HOME")),
+		ListenAddr: ":7777",
+		TLS: &TLS{
+			Certificate: "newCert",
+			Key:         "overlayKey",
+		},
+	}, haveServer)
+
+	t.Run("missing_file", func(t *testing.T) {
+		require.Error(t, config.LoadFiles([]string{filepath.Join("testdata", "test_load.yaml"), filepath.Join("testdata", "missing.yaml")}, nil))
+	})
+
+	t.Run("directory", func(t *testing.T) {
+		require.Error(t, config.LoadFiles([]string{"testdata"}, nil))
+	})
+
+	t.Run("no_files", func(t *testing.T) {
+		require.Error(t, config.LoadFiles(nil, nil))
+	})
+}
+
+func TestLoadProfile(t *testing.T) {
+	confFile := filepath.Join("testdata", "test_profiles.yaml")
+	homeDataDir := fmt.Sprintf("%s/tmp", os.Getenv("HOME"))
+
+	testCases := []struct {
+		overrides map[string]any
+		want      Server
+		name      string
+		profile   string
+	}{
+		{
+			name:    "dev",
+			profile: "dev",
+			want:    Server{DataDir: homeDataDir, ListenAddr: ":6666", TLS: &TLS{Certificate: "cert", Key: "key"}},
+		},
+		{
+			name:    "prod",
+			profile: "prod",
+			want:    Server{DataDir: "/var/lib/cerbos", ListenAddr: ":9999", TLS: &TLS{Certificate: "prodCert", Key: "key"}},
+		},
+		{
+			name:      "prod_with_overrides",
+			profile:   "prod",
+			overrides: map[string]any{"server": map[string]any{"tls": map[string]any{"certificate": "newCert"}}},
+			want:      Server{DataDir: "/var/lib/cerbos", ListenAddr: ":9999", TLS: &TLS{Certificate: "newCert", Key: "key"}},
+		},
+		{
+			name:    "empty",
+			profile: "empty",
+			want:    Server{DataDir: homeDataDir, ListenAddr: ":9999", TLS: &TLS{Certificate: "cert", Key: "key"}},
+		},
+		{
+			name: "no_profile",
+			want: Server{DataDir: homeDataDir, ListenAddr: ":9999", TLS: &TLS{Certificate: "cert", Key: "key"}},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			require.NoError(t, config.LoadProfile(confFile, tc.profile, tc.overrides))
+
+			var haveServer Server
+			require.NoError(t, config.GetSection(&haveServer))
+			require.Equal(t, tc.want, haveServer)
+		})
+	}
+
+	t.Run("missing_profile", func(t *testing.T) {
+		err := config.LoadProfile(confFile, "staging", nil)
+		require.Error(t, err)
+		require.ErrorContains(t, err, `profile "staging" is not defined`)
+		require.ErrorContains(t, err, "[dev,empty,prod]")
+	})
+
+	t.Run("file_without_profiles", func(t *testing.T) {
+		require.Error(t, config.LoadProfile(filepath.Join("testdata", "test_load.yaml"), "dev", nil))
+	})
+}
+
+func TestDump(t *testing.T) {
+	w, err := config.WrapperFromMap(map[string]any{
+		"server": map[string]any{"tls": map[string]any{"key": "tlsKey"}},
+		"db":     map[string]any{"user": "cerbos", "password": "secret"},
+	})
+	require.NoError(t, err)
+
+	t.Run("redacted", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, w.Dump(&buf, config.WithSections(&Server{})))
+		require.YAMLEq(t, `
+db:
+  user: cerbos
+  password: <redacted>
+server:
+  dataDir: /tmp/data
+  listenAddr: ":6666"
+  tls:
+    certificate: ""
+    key: <redacted>
+`, buf.String())
+	})
+
+	t.Run("with_secrets", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, w.Dump(&buf, config.WithSecrets()))
+		require.YAMLEq(t, `
+db:
+  user: cerbos
+  password: secret
+server:
+  tls:
+    key: tlsKey
+`, buf.String())
+	})
+}
+
+func TestFileDirective(t *testing.T) {
+	dir := t.TempDir()
+	secretFile := filepath.Join(dir, "tls.key")
+	require.NoError(t, os.WriteFile(secretFile, []byte("  pa$$word\n"), 0o600))
+
+	t.Run("expanded", func(t *testing.T) {
+		conf := fmt.Sprintf("server:\n  tls:\n    key: ${file:%s}\n    certificate: $${file:%s}\n", secretFile, secretFile)
+		require.NoError(t, config.LoadReader(strings.NewReader(conf), nil))
+
+		var haveServer Server
+		require.NoError(t, config.GetSection(&haveServer))
+		require.Equal(t, &TLS{Key: "pa$$word", Certificate: fmt.Sprintf("${file:%s}", secretFile)}, haveServer.TLS)
+	})
+
+	t.Run("missing_file", func(t *testing.T) {
+		conf := fmt.Sprintf("server:\n  tls:\n    key: ${file:%s}\n", filepath.Join(dir, "missing.key"))
+		err := config.LoadReader(strings.NewReader(conf), nil)
+		require.Error(t, err)
+		require.ErrorIs(t, err, os.ErrNotExist)
+	})
+
+	t.Run("commented_out", func(t *testing.T) {
+		conf := fmt.Sprintf("server:\n  tls:\n    # key: ${file:%s}\n    key: ${file:%s}\n", filepath.Join(dir, "missing.key"), secretFile)
+		require.NoError(t, config.LoadReader(strings.NewReader(conf), nil))
+
+		var haveServer Server
+		require.NoError(t, config.GetSection(&haveServer))
+		require.Equal(t, "pa$$word", haveServer.TLS.Key)
+	})
+
+	t.Run("yaml_syntax", func(t *testing.T) {
+		certFile := filepath.Join(dir, "tls.crt")
+		require.NoError(t, os.WriteFile(certFile, []byte("-----BEGIN CERTIFICATE-----\nkey: value # not a comment\n-----END CERTIFICATE-----\n"), 0o600))
+
+		conf := fmt.Sprintf("server:\n  tls:\n    certificate: ${file:%s}\n    key: key\n", certFile)
+		require.NoError(t, config.LoadReader(strings.NewReader(conf), nil))
+
+		var haveServer Server
+		require.NoError(t, config.GetSection(&haveServer))
+		require.Equal(t, &TLS{Key: "key", Certificate: "-----BEGIN CERTIFICATE-----\nkey: value # not a comment\n-----END CERTIFICATE-----"}, haveServer.TLS)
+	})
+}
+
+func TestSecretDirective(t *testing.T) {
+	t.Setenv("CERBOS_TEST_SECRETS", `{"tlsKey": "pa$$word", "tlsCert": "cert"}`)
+	config.SetSecretsEnv("CERBOS_TEST_SECRETS")
+	t.Cleanup(func() { config.SetSecretsEnv("") })
+
+	t.Run("expanded", func(t *testing.T) {
+		conf := "server:\n  tls:\n    key: ${secret:tlsKey}\n    certificate: $${secret:tlsCert}\n"
+		require.NoError(t, config.LoadReader(strings.NewReader(conf), nil))
+
+		var haveServer Server
+		require.NoError(t, config.GetSection(&haveServer))
+		require.Equal(t, &TLS{Key: "pa$$word", Certificate: "${secret:tlsCert}"}, haveServer.TLS)
+	})
+
+	t.Run("yaml_syntax", func(t *testing.T) {
+		t.Setenv("CERBOS_TEST_SECRETS", `{"tlsKey": "line: one\n# line two"}`)
+
+		conf := "server:\n  tls:\n    # certificate: ${secret:missing}\n    key: ${secret:tlsKey}\n"
+		require.NoError(t, config.LoadReader(strings.NewReader(conf), nil))
+
+		var haveServer Server
+		require.NoError(t, config.GetSection(&haveServer))
+		require.Equal(t, &TLS{Key: "line: one\n# line two"}, haveServer.TLS)
+	})
+
+	t.Run("undefined_secret", func(t *testing.T) {
+		conf := "server:\n  tls:\n    key: ${secret:missing}\n"
+		err := config.LoadReader(strings.NewReader(conf), nil)
+		require.Error(t, err)
+		require.ErrorContains(t, err, `secret "missing" is not defined`)
+	})
+
+	t.Run("invalid_secrets", func(t *testing.T) {
+		t.Setenv("CERBOS_TEST_SECRETS", "tlsKey=secret")
+
+		conf := "server:\n  tls:\n    key: ${secret:tlsKey}\n"
+		err := config.LoadReader(strings.NewReader(conf), nil)
+		require.Error(t, err)
+		require.ErrorContains(t, err, "does not contain a JSON object")
+	})
+
+	t.Run("not_configured", func(t *testing.T) {
+		config.SetSecretsEnv("")
+		t.Cleanup(func() { config.SetSecretsEnv("CERBOS_TEST_SECRETS") })
+		t.Setenv(config.SecretsEnvVar, "")
+
+		err := config.LoadReader(strings.NewReader("server:\n  tls:\n    key: ${secret:tlsKey}\n"), nil)
+		require.Error(t, err)
+		require.ErrorContains(t, err, "secrets environment variable is not configured")
+
+		require.NoError(t, config.LoadReader(strings.NewReader("server:\n  tls:\n    key: $${secret:tlsKey}\n"), nil))
+	})
+}
+
+func TestMissingEnvVars(t *testing.T) {
+	t.Run("missing", func(t *testing.T) {
+		conf := "server:\n  dataDir: ${CERBOS_TEST_UNSET_DATA_DIR}\n  listenAddr: ${CERBOS_TEST_UNSET_LISTEN_ADDR::9999}\n"
+		err := config.LoadReader(strings.NewReader(conf), nil)
+		require.Error(t, err)
+		require.ErrorContains(t, err, "required environment variable CERBOS_TEST_UNSET_DATA_DIR is not set")
+
+		var missingErr *config.MissingEnvVarsError
+		require.ErrorAs(t, err, &missingErr)
+		require.Equal(t, []string{"CERBOS_TEST_UNSET_DATA_DIR"}, missingErr.Names)
+	})
+
+	t.Run("missing_after_default", func(t *testing.T) {
+		conf := "server:\n  dataDir: ${CERBOS_TEST_UNSET_DATA_DIR:/tmp/data}\n  listenAddr: ${CERBOS_TEST_UNSET_LISTEN_ADDR}\n"
+		err := config.LoadReader(strings.NewReader(conf), nil)
+
+		var missingErr *config.MissingEnvVarsError
+		require.ErrorAs(t, err, &missingErr)
+		require.Equal(t, []string{"CERBOS_TEST_UNSET_LISTEN_ADDR"}, missingErr.Names)
+	})
+
+	t.Run("default", func(t *testing.T) {
+		conf := "server:\n  listenAddr: ${CERBOS_TEST_UNSET_LISTEN_ADDR::9999}\n"
+		require.NoError(t, config.LoadReader(strings.NewReader(conf), nil))
+
+		var haveServer Server
+		require.NoError(t, config.GetSection(&haveServer))
+		require.Equal(t, ":9999", haveServer.ListenAddr)
+	})
+}
+
 func TestDefaults(t *testing.T) {
 	require.NoError(t, config.Load(filepath.Join("testdata", "test_defaults.yaml"), nil))
 
@@ -126,6 +394,38 @@ func TestDefaults(t *testing.T) {
 	require.Equal(t, wantServer, haveServer)
 }
 
+func TestGetAs(t *testing.T) {
+	require.NoError(t, config.Load(filepath.Join("testdata", "test_defaults.yaml"), nil))
+
+	wantServer := Server{
+		DataDir:    "/tmp/data",
+		ListenAddr: ":9999",
+	}
+
+	t.Run("GetAs", func(t *testing.T) {
+		haveServer, err := config.GetAs[Server]("server")
+		require.NoError(t, err)
+		require.Equal(t, wantServer, haveServer)
+	})
+
+	t.Run("GetSectionAs", func(t *testing.T) {
+		haveServer, err := config.GetSectionAs[Server]()
+		require.NoError(t, err)
+		require.Equal(t, &wantServer, haveServer)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		require.NoError(t, config.Load(filepath.Join("testdata", "test_validate.yaml"), nil))
+
+		_, err := config.GetAs[Server]("server")
+		require.ErrorIs(t, err, errTestValidate)
+
+		haveServer, err := config.GetSectionAs[Server]()
+		require.ErrorIs(t, err, errTestValidate)
+		require.Nil(t, haveServer)
+	})
+}
+
 func TestValidate(t *testing.T) {
 	require.NoError(t, config.Load(filepath.Join("testdata", "test_validate.yaml"), nil))
 
@@ -135,6 +435,101 @@ func TestValidate(t *testing.T) {
 	require.ErrorIs(t, err, errTestValidate)
 }
 
+func TestDefaultsValidation(t *testing.T) {
+	w := &config.Wrapper{}
+
+	t.Run("disabled", func(t *testing.T) {
+		var have InvalidDefaults
+		require.NoError(t, w.Get("invalid", &have))
+		require.Equal(t, "xxx", have.Value)
+	})
+
+	t.Run("enabled", func(t *testing.T) {
+		w.SetDefaultsValidation(true)
+		t.Cleanup(func() { w.SetDefaultsValidation(false) })
+
+		var have InvalidDefaults
+		require.ErrorIs(t, w.Get("invalid", &have), errTestValidate)
+
+		var haveServer Server
+		require.NoError(t, w.Get("server", &haveServer))
+	})
+
+	t.Run("not_a_defaulter", func(t *testing.T) {
+		var have TLS
+		require.ErrorIs(t, w.Get("tls", &have), config.ErrConfigNotLoaded)
+	})
+}
+
+var errTestStorage = errors.New("storage validation error")
+
+type Storage struct {
+	Driver string `yaml:"driver"`
+}
+
+func (s *Storage) Key() string {
+	return "storage"
+}
+
+func (s *Storage) Validate() error {
+	if s.Driver == "" {
+		return errTestStorage
+	}
+
+	return nil
+}
+
+func TestValidateAll(t *testing.T) {
+	t.Run("all_invalid", func(t *testing.T) {
+		w, err := config.WrapperFromMap(map[string]any{
+			"server":  map[string]any{"dataDir": "xxx"},
+			"storage": map[string]any{"driver": ""},
+		})
+		require.NoError(t, err)
+
+		err = w.ValidateAll(&Server{}, &Storage{})
+		require.ErrorIs(t, err, errTestValidate)
+		require.ErrorIs(t, err, errTestStorage)
+		require.Len(t, multierr.Errors(err), 2)
+	})
+
+	t.Run("some_invalid", func(t *testing.T) {
+		w, err := config.WrapperFromMap(map[string]any{
+			"server":  map[string]any{"dataDir": "/data"},
+			"storage": map[string]any{"driver": ""},
+		})
+		require.NoError(t, err)
+
+		var server Server
+		err = w.ValidateAll(&server, &Storage{})
+		require.ErrorIs(t, err, errTestStorage)
+		require.NotErrorIs(t, err, errTestValidate)
+		require.Equal(t, "/data", server.DataDir)
+	})
+
+	t.Run("valid", func(t *testing.T) {
+		w, err := config.WrapperFromMap(map[string]any{"storage": map[string]any{"driver": "disk"}})
+		require.NoError(t, err)
+		require.NoError(t, w.ValidateAll(&Server{}, &Storage{}))
+	})
+}
+
+type InvalidDefaults struct {
+	Value string `yaml:"value"`
+}
+
+func (i *InvalidDefaults) SetDefaults() {
+	i.Value = "xxx"
+}
+
+func (i *InvalidDefaults) Validate() error {
+	if i.Value == "xxx" {
+		return errTestValidate
+	}
+
+	return nil
+}
+
 func TestCerbosConfig(t *testing.T) {
 	t.Run("valid_server_conf", func(t *testing.T) {
 		require.NoError(t, config.Load(filepath.Join("testdata", "valid_server_conf.yaml"), nil))
@@ -151,6 +546,109 @@ func TestCerbosConfig(t *testing.T) {
 	})
 }
 
+type Deprecated struct {
+	TLS        *TLS   `yaml:"tls"`
+	ListenAddr string `yaml:"listenAddr"`
+	DataDir    string `yaml:"dataDir"`
+}
+
+func (d *Deprecated) Key() string {
+	return "deprecated"
+}
+
+func (d *Deprecated) DeprecatedKeys() map[string]config.Deprecation {
+	return map[string]config.Deprecation{
+		"addr":     {Replacement: "listenAddr", CopyValue: true},
+		"tls.cert": {Replacement: "tls.certificate", CopyValue: true},
+		"dir":      {Replacement: "dataDir"},
+		"obsolete": {},
+	}
+}
+
+func TestReloadValidatesRegisteredSections(t *testing.T) {
+	require.NoError(t, config.LoadMap(map[string]any{"server": map[string]any{"httpListenAddr": ":3592"}}))
+
+	requireListenAddr := func(t *testing.T, want string) {
+		t.Helper()
+		var serverConf server.Conf
+		require.NoError(t, config.GetSection(&serverConf))
+		require.Equal(t, want, serverConf.HTTPListenAddr)
+	}
+
+	// every case changes httpListenAddr so that accepting the reload would be noticed
+	changedServer := func(kv ...string) map[string]any {
+		m := map[string]any{"httpListenAddr": ":4000"}
+		for i := 0; i < len(kv); i += 2 {
+			m[kv[i]] = kv[i+1]
+		}
+		return m
+	}
+
+	testCases := []struct {
+		conf map[string]any
+		name string
+	}{
+		{name: "server", conf: map[string]any{"server": changedServer("udsFileMode", "abc")}},
+		{name: "server_unknown_key", conf: map[string]any{"server": changedServer("httpListnAddr", ":4001")}},
+		{name: "auxData", conf: map[string]any{"server": changedServer(), "auxData": map[string]any{"jwt": map[string]any{"cachePolicy": "fifo"}}}},
+		{name: "engine", conf: map[string]any{"server": changedServer(), "engine": map[string]any{"defaultPolicyVersion": " "}}},
+		{name: "tracing", conf: map[string]any{"server": changedServer(), "tracing": map[string]any{"exporter": "jaeger"}}},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			require.Error(t, config.ReloadMap(tc.conf))
+			requireListenAddr(t, ":3592")
+		})
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		require.NoError(t, config.ReloadMap(map[string]any{"server": changedServer()}))
+		requireListenAddr(t, ":4000")
+	})
+}
+
+func TestDeprecatedKeys(t *testing.T) {
+	testCases := []struct {
+		conf map[string]any
+		want Deprecated
+		name string
+	}{
+		{
+			name: "no deprecated keys",
+			conf: map[string]any{"listenAddr": ":6666", "tls": map[string]any{"certificate": "cert"}},
+			want: Deprecated{ListenAddr: ":6666", TLS: &TLS{Certificate: "cert"}},
+		},
+		{
+			name: "deprecated keys are copied",
+			conf: map[string]any{"addr": ":6666", "tls": map[string]any{"cert": "cert", "key": "key"}},
+			want: Deprecated{ListenAddr: ":6666", TLS: &TLS{Certificate: "cert", Key: "key"}},
+		},
+		{
+			name: "replacement keys take precedence",
+			conf: map[string]any{"addr": ":6666", "listenAddr": ":7777"},
+			want: Deprecated{ListenAddr: ":7777"},
+		},
+		{
+			name: "deprecated keys without copy are ignored",
+			conf: map[string]any{"dir": "/tmp", "obsolete": true},
+			want: Deprecated{},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			require.NoError(t, config.LoadMap(map[string]any{"deprecated": tc.conf}))
+
+			var have Deprecated
+			require.NoError(t, config.GetSection(&have))
+			require.Equal(t, tc.want, have)
+		})
+	}
+}
+
 func TestStrictParsing(t *testing.T) {
 	testCases := []struct {
 		conf    map[string]any
@@ -181,6 +679,18 @@ func TestStrictParsing(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "undeclared nested field",
+			conf: map[string]any{
+				"server": map[string]any{
+					"listenAddr": ":6666",
+					"tls": map[string]any{
+						"certficate": "newCert",
+					},
+				},
+			},
+			wantErr: true,
+		},
 		{
 			name: "wrong case for field",
 			conf: map[string]any{
@@ -210,3 +720,208 @@ func TestStrictParsing(t *testing.T) {
 		})
 	}
 }
+
+func TestOverrideAppend(t *testing.T) {
+	t.Setenv("CERBOS_TEST_ORIGIN", "env.example.com")
+
+	base := `
+app:
+  origins:
+    - a.example.com
+    - ${CERBOS_TEST_ORIGIN}
+    - $$literal.example.com
+  keySets:
+    - id: ks1
+      urls: [https://one.example.com]
+  name: test
+`
+
+	type keySet struct {
+		ID   string   `yaml:"id"`
+		URLs []string `yaml:"urls"`
+	}
+
+	t.Run("nested_lists", func(t *testing.T) {
+		overrides := map[string]any{
+			"app": map[string]any{
+				"origins+": []string{"b.example.com"},
+				"keySets+": []any{
+					map[string]any{"id": "ks2", "urls": []any{"https://two.example.com"}},
+				},
+				"hosts+": []any{"localhost"},
+				"name":   "overridden",
+			},
+		}
+
+		require.NoError(t, config.LoadReader(strings.NewReader(base), overrides))
+
+		var haveOrigins []string
+		require.NoError(t, config.Get("app.origins", &haveOrigins))
+		require.Equal(t, []string{"a.example.com", "env.example.com", "$literal.example.com", "b.example.com"}, haveOrigins)
+
+		var haveKeySets []keySet
+		require.NoError(t, config.Get("app.keySets", &haveKeySets))
+		require.Equal(t, []keySet{
+			{ID: "ks1", URLs: []string{"https://one.example.com"}},
+			{ID: "ks2", URLs: []string{"https://two.example.com"}},
+		}, haveKeySets)
+
+		var haveHosts []string
+		require.NoError(t, config.Get("app.hosts", &haveHosts))
+		require.Equal(t, []string{"localhost"}, haveHosts)
+
+		var haveName string
+		require.NoError(t, config.Get("app.name", &haveName))
+		require.Equal(t, "overridden", haveName)
+	})
+
+	t.Run("replace_without_suffix", func(t *testing.T) {
+		overrides := map[string]any{"app": map[string]any{"origins": []any{"b.example.com"}}}
+		require.NoError(t, config.LoadReader(strings.NewReader(base), overrides))
+
+		var haveOrigins []string
+		require.NoError(t, config.Get("app.origins", &haveOrigins))
+		require.Equal(t, []string{"b.example.com"}, haveOrigins)
+	})
+
+	t.Run("multiple_files", func(t *testing.T) {
+		dir := t.TempDir()
+		baseFile := filepath.Join(dir, "base.yaml")
+		require.NoError(t, os.WriteFile(baseFile, []byte(base), 0o600))
+		overlay := filepath.Join(dir, "overlay.yaml")
+		require.NoError(t, os.WriteFile(overlay, []byte("app:\n  origins: [c.example.com]\n"), 0o600))
+
+		overrides := map[string]any{"app": map[string]any{"origins+": []any{"d.example.com"}}}
+		require.NoError(t, config.LoadFiles([]string{baseFile, overlay}, overrides))
+
+		var haveOrigins []string
+		require.NoError(t, config.Get("app.origins", &haveOrigins))
+		require.Equal(t, []string{"c.example.com", "d.example.com"}, haveOrigins)
+	})
+
+	t.Run("wrapper", func(t *testing.T) {
+		overrides := map[string]any{"app": map[string]any{"origins+": []any{"b.example.com"}}}
+		w, err := config.WrapperFromReader(strings.NewReader(base), overrides)
+		require.NoError(t, err)
+
+		var haveOrigins []string
+		require.NoError(t, w.Get("app.origins", &haveOrigins))
+		require.Len(t, haveOrigins, 4)
+	})
+
+	testCases := []struct {
+		overrides map[string]any
+		name      string
+	}{
+		{
+			name:      "not_a_list_in_base",
+			overrides: map[string]any{"app": map[string]any{"name+": []any{"x"}}},
+		},
+		{
+			name:      "not_a_list_in_override",
+			overrides: map[string]any{"app": map[string]any{"origins+": "b.example.com"}},
+		},
+		{
+			name:      "replaced_and_appended",
+			overrides: map[string]any{"app": map[string]any{"origins": []any{"b.example.com"}, "origins+": []any{"c.example.com"}}},
+		},
+		{
+			name:      "missing_key_name",
+			overrides: map[string]any{"app": map[string]any{"+": []any{"b.example.com"}}},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			require.Error(t, config.LoadReader(strings.NewReader(base), tc.overrides))
+		})
+	}
+}
+
+func TestParseOverrides(t *testing.T) {
+	testCases := []struct {
+		want    map[string]any
+		name    string
+		pairs   []string
+		wantErr bool
+	}{
+		{
+			name:  "nested_keys",
+			pairs: []string{"server.httpListenAddr=:3593", "server.adminAPI.enabled=true", "storage.driver=sqlite3"},
+			want: map[string]any{
+				"server": map[string]any{
+					"httpListenAddr": ":3593",
+					"adminAPI":       map[string]any{"enabled": true},
+				},
+				"storage": map[string]any{"driver": "sqlite3"},
+			},
+		},
+		{
+			name:  "type_inference",
+			pairs: []string{"a.bool=false", "a.int=42", "a.negative=-1", "a.duration=30s", "a.null=null"},
+			want: map[string]any{
+				"a": map[string]any{"bool": false, "int": int64(42), "negative": int64(-1), "duration": "30s", "null": nil},
+			},
+		},
+		{
+			name:  "multiple_assignments",
+			pairs: []string{"a.b=1,a.c=x"},
+			want:  map[string]any{"a": map[string]any{"b": int64(1), "c": "x"}},
+		},
+		{
+			name:  "escaped_values",
+			pairs: []string{`a.list=x\,y`, `a.braces=\{x\}`, `a\.b=c`},
+			want: map[string]any{
+				"a":   map[string]any{"list": "x,y", "braces": "{x}"},
+				"a.b": "c",
+			},
+		},
+		{
+			name:  "lists",
+			pairs: []string{"server.cors.allowedOrigins+={a.example.com,b.example.com}", "a.numbers={1,2}"},
+			want: map[string]any{
+				"server": map[string]any{"cors": map[string]any{"allowedOrigins+": []any{"a.example.com", "b.example.com"}}},
+				"a":      map[string]any{"numbers": []any{int64(1), int64(2)}},
+			},
+		},
+		{
+			name:  "repeated_keys",
+			pairs: []string{"a.b=1", "a.c=x", "a.b=2"},
+			want:  map[string]any{"a": map[string]any{"b": int64(2), "c": "x"}},
+		},
+		{
+			name:  "value_with_equals",
+			pairs: []string{"storage.sqlite3.dsn=file:cerbos.db?mode=rwc"},
+			want:  map[string]any{"storage": map[string]any{"sqlite3": map[string]any{"dsn": "file:cerbos.db?mode=rwc"}}},
+		},
+		{name: "missing_value", pairs: []string{"server.httpListenAddr"}, wantErr: true},
+		{name: "empty_segment", pairs: []string{"server..httpListenAddr=:3593"}, wantErr: true},
+		{name: "scalar_then_map", pairs: []string{"a.b=1", "a.b.c=2"}, wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			have, err := config.ParseOverrides(tc.pairs)
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.want, have)
+		})
+	}
+
+	t.Run("load", func(t *testing.T) {
+		overrides, err := config.ParseOverrides([]string{"server.listenAddr=:6666", "server.tls.certificate=newCert"})
+		require.NoError(t, err)
+		require.NoError(t, config.Load(filepath.Join("testdata", "test_load.yaml"), overrides))
+
+		var have Server
+		require.NoError(t, config.GetSection(&have))
+		require.Equal(t, ":6666", have.ListenAddr)
+		require.Equal(t, "newCert", have.TLS.Certificate)
+	})
+}
@@ -0,0 +1,251 @@
+// Copyright 2021-2022 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"time"
+
+	"go.uber.org/config"
+	"gopkg.in/yaml.v2"
+)
+
+const redactedValue = "<redacted>"
+
+// secretKeyFragments are the fragments of keys whose values are considered to be secrets.
+var secretKeyFragments = []string{"secret", "password", "token", "key"}
+
+type dumpOptions struct {
+	sections    []Section
+	showSecrets bool
+}
+
+// DumpOpt configures Dump.
+type DumpOpt func(*dumpOptions)
+
+// WithSections includes the given sections in the dump, populated with their defaults and the loaded configuration.
+// Without it, only the values that were explicitly configured are included.
+func WithSections(sections ...Section) DumpOpt {
+	return func(o *dumpOptions) {
+		o.sections = append(o.sections, sections...)
+	}
+}
+
+// WithSecrets disables the redaction of values whose keys look like they contain secrets.
+func WithSecrets() DumpOpt {
+	return func(o *dumpOptions) {
+		o.showSecrets = true
+	}
+}
+
+// Dump writes the effective configuration of the global config wrapper as YAML. See Wrapper.Dump.
+func Dump(out io.Writer, opts ...DumpOpt) error {
+	return conf.Dump(out, opts...)
+}
+
+// Dump writes the effective configuration as YAML, after environment variables are expanded and overrides are applied.
+// Values of keys containing secret, password, token or key are redacted unless the WithSecrets option is used.
+func (w *Wrapper) Dump(out io.Writer, opts ...DumpOpt) error {
+	o := dumpOptions{}
+	for _, optFn := range opts {
+		optFn(&o)
+	}
+
+	raw := make(map[string]any)
+	w.mu.RLock()
+	provider := w.provider
+	w.mu.RUnlock()
+
+	if provider != nil {
+		if err := provider.Get(config.Root).Populate(&raw); err != nil {
+			return fmt.Errorf("failed to read config: %w", err)
+		}
+		raw, _ = normalize(raw).(map[string]any)
+		if raw == nil {
+			raw = make(map[string]any)
+		}
+	}
+
+	for _, section := range o.sections {
+		if err := w.GetSection(section); err != nil {
+			return fmt.Errorf("failed to read config section %q: %w", section.Key(), err)
+		}
+
+		sectionMap, err := toMap(section)
+		if err != nil {
+			return fmt.Errorf("failed to convert config section %q: %w", section.Key(), err)
+		}
+
+		existing, _ := getPath(raw, section.Key())
+		existingMap, _ := existing.(map[string]any)
+		setPath(raw, section.Key(), mergeMaps(existingMap, sectionMap))
+	}
+
+	if !o.showSecrets {
+		redact(raw, false)
+	}
+
+	outBytes, err := yaml.Marshal(raw)
+	if err != nil {
+		return fmt.Errorf("failed to marshal config: %w", err)
+	}
+
+	_, err = out.Write(outBytes)
+	return err
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// toMap converts the given section to a map using the same keys as the YAML decoder.
+// Durations are converted to strings so that they are displayed the same way they are configured.
+func toMap(section Section) (map[string]any, error) {
+	m, ok := toValue(reflect.ValueOf(section)).(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("unsupported section type %T", section)
+	}
+
+	return m, nil
+}
+
+func toValue(v reflect.Value) any {
+	if !v.IsValid() {
+		return nil
+	}
+
+	if v.Type() == durationType {
+		return time.Duration(v.Int()).String()
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return toValue(v.Elem())
+	case reflect.Struct:
+		m := make(map[string]any)
+		addStructFields(m, v)
+		return m
+	case reflect.Map:
+		if v.IsNil() {
+			return nil
+		}
+
+		m := make(map[string]any, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			m[fmt.Sprintf("%v", iter.Key().Interface())] = toValue(iter.Value())
+		}
+		return m
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return nil
+		}
+
+		arr := make([]any, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			arr[i] = toValue(v.Index(i))
+		}
+		return arr
+	default:
+		return v.Interface()
+	}
+}
+
+func addStructFields(m map[string]any, v reflect.Value) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, opts, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+		if name == "-" {
+			continue
+		}
+
+		fv := v.Field(i)
+		if strings.Contains(opts, "inline") {
+			if fv.Kind() == reflect.Ptr {
+				if fv.IsNil() {
+					continue
+				}
+				fv = fv.Elem()
+			}
+
+			if fv.Kind() == reflect.Struct {
+				addStructFields(m, fv)
+				continue
+			}
+		}
+
+		if name == "" {
+			name = strings.ToLower(field.Name)
+		}
+
+		if strings.Contains(opts, "omitempty") && fv.IsZero() {
+			continue
+		}
+
+		m[name] = toValue(fv)
+	}
+}
+
+// mergeMaps recursively merges src into dst, with the values from src taking precedence.
+func mergeMaps(dst, src map[string]any) map[string]any {
+	if dst == nil {
+		dst = make(map[string]any, len(src))
+	}
+
+	for k, v := range src {
+		srcMap, srcIsMap := v.(map[string]any)
+		dstMap, dstIsMap := dst[k].(map[string]any)
+		if srcIsMap && dstIsMap {
+			dst[k] = mergeMaps(dstMap, srcMap)
+			continue
+		}
+
+		dst[k] = v
+	}
+
+	return dst
+}
+
+// redact replaces the non-empty scalar values of keys that look like secrets. Nested maps are redacted based on their own keys.
+func redact(v any, sensitive bool) any {
+	switch t := v.(type) {
+	case map[string]any:
+		for k, val := range t {
+			t[k] = redact(val, isSecretKey(k))
+		}
+		return t
+	case []any:
+		for i, val := range t {
+			t[i] = redact(val, sensitive)
+		}
+		return t
+	case nil:
+		return nil
+	default:
+		if sensitive && fmt.Sprintf("%v", t) != "" {
+			return redactedValue
+		}
+		return t
+	}
+}
+
+func isSecretKey(key string) bool {
+	k := strings.ToLower(key)
+	for _, fragment := range secretKeyFragments {
+		if strings.Contains(k, fragment) {
+			return true
+		}
+	}
+
+	return false
+}
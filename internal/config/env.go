@@ -0,0 +1,237 @@
+// Copyright 2021-2022 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go.uber.org/config"
+)
+
+const envSep = "_"
+
+// LoadFromEnv loads configuration from the environment variables starting with the given prefix.
+// The rest of the variable name is split on underscores to obtain the path to the key. For example, with the prefix CERBOS,
+// CERBOS_SERVER_HTTPLISTENADDR sets server.httpListenAddr. Environment variable names are case-insensitive, so they are matched
+// against the keys declared by each section when it is read. Values are converted to the type of the field they are assigned to
+// and lists are comma-separated.
+//
+// It can be used on its own or alongside Load, LoadReader and LoadMap: the values from the environment take precedence over
+// the values from files, readers and overrides.
+func LoadFromEnv(prefix string) error {
+	return conf.loadFromEnv(prefix, os.Environ())
+}
+
+func (w *Wrapper) loadFromEnv(prefix string, environ []string) error {
+	env, err := envToMap(prefix, environ)
+	if err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.provider == nil {
+		provider, err := mkProvider(config.Static(map[string]any{}))
+		if err != nil {
+			return err
+		}
+		w.provider = provider
+	}
+
+	w.env = env
+	return nil
+}
+
+// envToMap converts the environment variables with the given prefix to a nested map with lowercase keys.
+func envToMap(prefix string, environ []string) (map[string]any, error) {
+	prefix = strings.TrimSuffix(prefix, envSep)
+	if prefix == "" {
+		return nil, errors.New("environment variable prefix must not be empty")
+	}
+	prefix += envSep
+
+	// sort the variables so that the outcome is deterministic when a variable clashes with a nested one
+	sorted := make([]string, len(environ))
+	copy(sorted, environ)
+	sort.Strings(sorted)
+
+	env := make(map[string]any)
+	for _, kv := range sorted {
+		name, value, ok := strings.Cut(kv, "=")
+		if !ok || !strings.HasPrefix(name, prefix) {
+			continue
+		}
+
+		keys := strings.Split(strings.ToLower(strings.TrimPrefix(name, prefix)), envSep)
+		valid := true
+		for _, k := range keys {
+			if k == "" {
+				valid = false
+				break
+			}
+		}
+
+		if !valid {
+			continue
+		}
+
+		setPath(env, strings.Join(keys, pathSep), value)
+	}
+
+	return env, nil
+}
+
+// populateFromEnv overlays the values from the environment on top of the already populated section.
+func (w *Wrapper) populateFromEnv(key string, out any) error {
+	if len(w.env) == 0 {
+		return nil
+	}
+
+	v, ok := getPath(w.env, strings.ToLower(key))
+	if !ok {
+		return nil
+	}
+
+	resolved, err := resolveEnvValue(v, reflect.TypeOf(out))
+	if err != nil {
+		return fmt.Errorf("invalid environment configuration for %q: %w", key, err)
+	}
+
+	provider, err := config.NewYAML(config.Static(resolved))
+	if err != nil {
+		return err
+	}
+
+	return provider.Get(config.Root).Populate(out)
+}
+
+// resolveEnvValue converts the lowercase keys of the environment map to the keys declared by the target type
+// and the string values to the type of the target field.
+func resolveEnvValue(v any, t reflect.Type) (any, error) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if m, ok := v.(map[string]any); ok {
+		switch t.Kind() {
+		case reflect.Struct:
+			return resolveEnvStruct(m, t)
+		case reflect.Map:
+			out := make(map[string]any, len(m))
+			for k, mv := range m {
+				rv, err := resolveEnvValue(mv, t.Elem())
+				if err != nil {
+					return nil, fmt.Errorf("%s: %w", k, err)
+				}
+				out[k] = rv
+			}
+			return out, nil
+		default:
+			// leave it to Populate to report the mismatch
+			return m, nil
+		}
+	}
+
+	s, ok := v.(string)
+	if !ok {
+		return v, nil
+	}
+
+	// durations and byte sizes are parsed from strings when the section is populated
+	if t == durationType || t == byteSizeType {
+		return s, nil
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return strconv.ParseBool(s)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.ParseInt(s, 10, 64)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.ParseUint(s, 10, 64)
+	case reflect.Float32, reflect.Float64:
+		return strconv.ParseFloat(s, 64)
+	case reflect.Slice, reflect.Array:
+		parts := strings.Split(s, ",")
+		out := make([]any, len(parts))
+		for i, p := range parts {
+			rv, err := resolveEnvValue(strings.TrimSpace(p), t.Elem())
+			if err != nil {
+				return nil, err
+			}
+			out[i] = rv
+		}
+		return out, nil
+	default:
+		return s, nil
+	}
+}
+
+func resolveEnvStruct(m map[string]any, t reflect.Type) (map[string]any, error) {
+	fields := make(map[string]reflect.StructField)
+	collectYAMLFields(fields, t)
+
+	out := make(map[string]any, len(m))
+	for k, v := range m {
+		field, ok := fields[k]
+		if !ok {
+			// keep unknown keys so that strict parsing reports them
+			out[k] = v
+			continue
+		}
+
+		name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+		if name == "" {
+			name = strings.ToLower(field.Name)
+		}
+
+		rv, err := resolveEnvValue(v, field.Type)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		out[name] = rv
+	}
+
+	return out, nil
+}
+
+// collectYAMLFields indexes the fields of the struct by the lowercase version of their YAML key, including inlined fields.
+func collectYAMLFields(fields map[string]reflect.StructField, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, opts, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+		if name == "-" {
+			continue
+		}
+
+		if strings.Contains(opts, "inline") {
+			ft := field.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+
+			if ft.Kind() == reflect.Struct {
+				collectYAMLFields(fields, ft)
+				continue
+			}
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+
+		fields[strings.ToLower(name)] = field
+	}
+}
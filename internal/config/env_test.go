@@ -0,0 +1,101 @@
+// Copyright 2021-2022 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type envTestSection struct {
+	TLS            *envTestTLS       `yaml:"tls"`
+	Labels         map[string]string `yaml:"labels"`
+	HTTPListenAddr string            `yaml:"httpListenAddr"`
+	AllowedOrigins []string          `yaml:"allowedOrigins"`
+	Timeout        time.Duration     `yaml:"timeout"`
+	MaxMsgSize     ByteSize          `yaml:"maxMsgSize"`
+	MaxConns       int               `yaml:"maxConns"`
+	Debug          bool              `yaml:"debug"`
+}
+
+type envTestTLS struct {
+	Cert string `yaml:"cert"`
+	Key  string `yaml:"key"`
+}
+
+func (s *envTestSection) Key() string {
+	return "server"
+}
+
+func (s *envTestSection) SetDefaults() {
+	s.HTTPListenAddr = ":3592"
+	s.MaxConns = 10
+}
+
+func TestLoadFromEnv(t *testing.T) {
+	environ := []string{
+		"CERBOS_SERVER_HTTPLISTENADDR=:4000",
+		"CERBOS_SERVER_ALLOWEDORIGINS=a.example.com, b.example.com",
+		"CERBOS_SERVER_TIMEOUT=5s",
+		"CERBOS_SERVER_MAXMSGSIZE=4MiB",
+		"CERBOS_SERVER_DEBUG=true",
+		"CERBOS_SERVER_TLS_KEY=envKey",
+		"CERBOS_SERVER_LABELS_TEAM=policy",
+		"CERBOS__SERVER_DEBUG=false",
+		"OTHER_SERVER_MAXCONNS=99",
+	}
+
+	t.Run("env_only", func(t *testing.T) {
+		w := &Wrapper{}
+		require.NoError(t, w.loadFromEnv("CERBOS", environ))
+
+		var have envTestSection
+		require.NoError(t, w.GetSection(&have))
+		require.Equal(t, envTestSection{
+			TLS:            &envTestTLS{Key: "envKey"},
+			Labels:         map[string]string{"team": "policy"},
+			HTTPListenAddr: ":4000",
+			AllowedOrigins: []string{"a.example.com", "b.example.com"},
+			Timeout:        5 * time.Second,
+			MaxMsgSize:     4 << 20,
+			MaxConns:       10,
+			Debug:          true,
+		}, have)
+	})
+
+	t.Run("with_file", func(t *testing.T) {
+		w, err := WrapperFromReader(strings.NewReader("server:\n  maxConns: 20\n  tls:\n    cert: fileCert\n    key: fileKey\n"), nil)
+		require.NoError(t, err)
+		require.NoError(t, w.loadFromEnv("CERBOS_", environ))
+
+		var have envTestSection
+		require.NoError(t, w.GetSection(&have))
+		require.Equal(t, 20, have.MaxConns)
+		require.Equal(t, ":4000", have.HTTPListenAddr)
+		require.Equal(t, &envTestTLS{Cert: "fileCert", Key: "envKey"}, have.TLS)
+	})
+
+	t.Run("invalid_value", func(t *testing.T) {
+		w := &Wrapper{}
+		require.NoError(t, w.loadFromEnv("CERBOS", []string{"CERBOS_SERVER_MAXCONNS=lots"}))
+
+		var have envTestSection
+		require.Error(t, w.GetSection(&have))
+	})
+
+	t.Run("unknown_key", func(t *testing.T) {
+		w := &Wrapper{}
+		require.NoError(t, w.loadFromEnv("CERBOS", []string{"CERBOS_SERVER_WIBBLE=wobble"}))
+
+		var have envTestSection
+		require.Error(t, w.GetSection(&have))
+	})
+
+	t.Run("empty_prefix", func(t *testing.T) {
+		require.Error(t, (&Wrapper{}).loadFromEnv("", environ))
+	})
+}
@@ -0,0 +1,251 @@
+// Copyright 2021-2022 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+
+	"go.uber.org/config"
+	"go.uber.org/multierr"
+	"gopkg.in/yaml.v3"
+)
+
+// SecretsEnvVar is the environment variable that names the environment variable holding the secrets referenced by
+// ${secret:NAME} directives, unless a different one is set using SetSecretsEnv.
+const SecretsEnvVar = "CERBOS_SECRETS_ENV"
+
+var (
+	// fileDirective matches ${file:/path/to/file} along with any preceding dollar signs so that escaped directives can be detected.
+	fileDirective = regexp.MustCompile(`(\$*)\$\{file:([^}]+)\}`)
+	// secretDirective matches ${secret:NAME} along with any preceding dollar signs so that escaped directives can be detected.
+	secretDirective = regexp.MustCompile(`(\$*)\$\{secret:([^}]+)\}`)
+
+	secretsEnvMu sync.RWMutex
+	secretsEnv   string
+)
+
+// SetSecretsEnv sets the name of the environment variable holding the JSON object used to resolve ${secret:NAME} directives.
+// NAME is resolved to the value of the field with the same name. If it is not set, the name is read from SecretsEnvVar.
+func SetSecretsEnv(name string) {
+	secretsEnvMu.Lock()
+	defer secretsEnvMu.Unlock()
+
+	secretsEnv = name
+}
+
+func getSecretsEnv() string {
+	secretsEnvMu.RLock()
+	defer secretsEnvMu.RUnlock()
+
+	if secretsEnv != "" {
+		return secretsEnv
+	}
+
+	return os.Getenv(SecretsEnvVar)
+}
+
+// fileSource reads the given config file and expands the ${file:...} and ${secret:...} directives in it.
+func fileSource(confFile string) (config.YAMLOption, error) {
+	contents, err := os.ReadFile(confFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", confFile, err)
+	}
+
+	return expandedSource(contents)
+}
+
+// readerSource reads the config from the given reader and expands the ${file:...} and ${secret:...} directives in it.
+func readerSource(reader io.Reader) (config.YAMLOption, error) {
+	contents, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	return expandedSource(contents)
+}
+
+// expandedSource expands the ${file:...} and ${secret:...} directives in the scalar values of the given config.
+// The config is parsed first so that directives in comments are ignored, and the expanded values are encoded again
+// instead of being inserted as text so that values containing newlines or YAML syntax don't change the structure.
+func expandedSource(contents []byte) (config.YAMLOption, error) {
+	if !hasDirective(fileDirective, contents) && !hasDirective(secretDirective, contents) {
+		return config.Source(bytes.NewReader(contents)), nil
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(contents, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse config: %w", err)
+	}
+
+	values := scalarValues(&doc, nil)
+	if err := expandFileDirectives(values); err != nil {
+		return nil, err
+	}
+
+	if err := expandSecretDirectives(values); err != nil {
+		return nil, err
+	}
+
+	expanded, err := yaml.Marshal(&doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode expanded config: %w", err)
+	}
+
+	return config.Source(bytes.NewReader(expanded)), nil
+}
+
+// scalarValues appends the scalar values (but not the mapping keys) found under the given node to out.
+func scalarValues(node *yaml.Node, out []*yaml.Node) []*yaml.Node {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		return append(out, node)
+	case yaml.MappingNode:
+		for i := 1; i < len(node.Content); i += 2 {
+			out = scalarValues(node.Content[i], out)
+		}
+	case yaml.DocumentNode, yaml.SequenceNode:
+		for _, n := range node.Content {
+			out = scalarValues(n, out)
+		}
+	}
+
+	return out
+}
+
+// expandFileDirectives replaces ${file:/path/to/file} with the trimmed contents of the file.
+// This happens before environment variables are expanded, so dollar signs in the file contents are escaped
+// and $${file:...} is left alone to be unescaped along with the rest of the config.
+func expandFileDirectives(values []*yaml.Node) error {
+	err := expandDirectives(fileDirective, values, func(path string) (string, error) {
+		secret, err := os.ReadFile(path)
+		if err != nil {
+			return "", err
+		}
+
+		return string(bytes.TrimSpace(secret)), nil
+	})
+	if err != nil {
+		return fmt.Errorf("error loading configuration due to unreadable file referenced by a ${file:...} directive. Use '$$' to escape literal '$' values: [%w]", err)
+	}
+
+	return nil
+}
+
+// expandSecretDirectives replaces ${secret:NAME} with the NAME field of the JSON object held by the secrets environment variable.
+// String fields are inserted as they are and other fields are inserted as JSON. Like ${file:...}, this happens before
+// environment variables are expanded.
+func expandSecretDirectives(values []*yaml.Node) error {
+	// the secrets are only required if there are directives to resolve
+	required := false
+	for _, v := range values {
+		if hasDirective(secretDirective, []byte(v.Value)) {
+			required = true
+			break
+		}
+	}
+
+	if !required {
+		return nil
+	}
+
+	secrets, err := loadSecrets()
+	if err != nil {
+		return fmt.Errorf("error loading configuration due to unavailable secrets referenced by a ${secret:...} directive: %w", err)
+	}
+
+	err = expandDirectives(secretDirective, values, func(name string) (string, error) {
+		raw, ok := secrets[name]
+		if !ok {
+			return "", fmt.Errorf("secret %q is not defined", name)
+		}
+
+		var str string
+		if err := json.Unmarshal(raw, &str); err == nil {
+			return str, nil
+		}
+
+		return string(raw), nil
+	})
+	if err != nil {
+		return fmt.Errorf("error loading configuration due to undefined secret referenced by a ${secret:...} directive. Use '$$' to escape literal '$' values: [%w]", err)
+	}
+
+	return nil
+}
+
+// loadSecrets reads the JSON object held by the secrets environment variable.
+func loadSecrets() (map[string]json.RawMessage, error) {
+	envVar := getSecretsEnv()
+	if envVar == "" {
+		return nil, fmt.Errorf("secrets environment variable is not configured: set %s to the name of the environment variable holding the secrets", SecretsEnvVar)
+	}
+
+	value, ok := os.LookupEnv(envVar)
+	if !ok {
+		return nil, fmt.Errorf("secrets environment variable %s is not set", envVar)
+	}
+
+	secrets := make(map[string]json.RawMessage)
+	if err := json.Unmarshal([]byte(value), &secrets); err != nil {
+		return nil, fmt.Errorf("secrets environment variable %s does not contain a JSON object: %w", envVar, err)
+	}
+
+	return secrets, nil
+}
+
+// hasDirective returns true if the contents contain an unescaped match of the directive.
+func hasDirective(directive *regexp.Regexp, contents []byte) bool {
+	for _, m := range directive.FindAllSubmatch(contents, -1) {
+		if len(m[1])%2 == 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// expandDirectives replaces the unescaped matches of the directive in the given scalar values with the value returned by
+// resolve for the captured argument. Dollar signs in the resolved values are escaped so that they are not expanded again
+// along with the environment variables.
+func expandDirectives(directive *regexp.Regexp, values []*yaml.Node, resolve func(string) (string, error)) error {
+	var errs error
+	for _, node := range values {
+		expanded := directive.ReplaceAllStringFunc(node.Value, func(match string) string {
+			m := directive.FindStringSubmatch(match)
+			dollars, arg := m[1], m[2]
+
+			// an odd number of preceding dollar signs means that the directive is escaped
+			if len(dollars)%2 == 1 {
+				return match
+			}
+
+			value, err := resolve(arg)
+			if err != nil {
+				errs = multierr.Append(errs, err)
+				return match
+			}
+
+			return dollars + strings.ReplaceAll(value, "$", "$$")
+		})
+
+		if expanded == node.Value {
+			continue
+		}
+
+		node.Value = expanded
+		// unquoted values are resolved again so that, for example, a secret holding a number populates a numeric field
+		if node.Style == 0 {
+			node.Tag = ""
+		}
+	}
+
+	return errs
+}
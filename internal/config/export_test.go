@@ -0,0 +1,11 @@
+// Copyright 2021-2022 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import "go.uber.org/config"
+
+// ReloadMap reloads the global configuration from the given map in the same way as Watch reloads a changed file.
+func ReloadMap(m map[string]any) error {
+	return doReload(config.Static(m))
+}
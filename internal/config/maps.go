@@ -0,0 +1,85 @@
+// Copyright 2021-2022 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+const pathSep = "."
+
+// normalize converts the map[any]any values produced by the YAML decoder to map[string]any recursively.
+func normalize(v any) any {
+	switch t := v.(type) {
+	case map[string]any:
+		for k, v := range t {
+			t[k] = normalize(v)
+		}
+		return t
+	case map[any]any:
+		m := make(map[string]any, len(t))
+		for k, v := range t {
+			m[fmt.Sprintf("%v", k)] = normalize(v)
+		}
+		return m
+	case []any:
+		for i, v := range t {
+			t[i] = normalize(v)
+		}
+		return t
+	default:
+		return v
+	}
+}
+
+// getPath returns the value at the given dot-separated path.
+func getPath(m map[string]any, path string) (any, bool) {
+	keys := strings.Split(path, pathSep)
+	for _, k := range keys[:len(keys)-1] {
+		next, ok := m[k].(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		m = next
+	}
+
+	v, ok := m[keys[len(keys)-1]]
+	return v, ok
+}
+
+// setPath sets the value at the given dot-separated path, creating intermediate maps as necessary.
+func setPath(m map[string]any, path string, value any) {
+	keys := strings.Split(path, pathSep)
+	for _, k := range keys[:len(keys)-1] {
+		next, ok := m[k].(map[string]any)
+		if !ok {
+			next = make(map[string]any)
+			m[k] = next
+		}
+		m = next
+	}
+
+	m[keys[len(keys)-1]] = value
+}
+
+// deletePath removes the value at the given dot-separated path and returns it.
+func deletePath(m map[string]any, path string) (any, bool) {
+	keys := strings.Split(path, pathSep)
+	for _, k := range keys[:len(keys)-1] {
+		next, ok := m[k].(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		m = next
+	}
+
+	last := keys[len(keys)-1]
+	v, ok := m[last]
+	if ok {
+		delete(m, last)
+	}
+
+	return v, ok
+}
@@ -0,0 +1,138 @@
+// Copyright 2021-2022 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"go.uber.org/config"
+	"helm.sh/helm/v3/pkg/strvals"
+)
+
+// appendSuffix marks an override key whose list is appended to the list defined at the same path by the configuration
+// files instead of replacing it. For example, --set=server.cors.allowedOrigins+={a.example.com} adds an allowed origin.
+const appendSuffix = "+"
+
+// ParseOverrides converts the values of the --set flag into the nested map expected by Load. Values use the same syntax
+// as the --set flag of Helm: keys are separated by dots (server.httpListenAddr=:3593), several assignments can be
+// separated by commas, lists are wrapped in braces (server.cors.allowedOrigins={a.example.com,b.example.com}) and
+// commas, dots or braces that are part of a key or value are escaped with a backslash. Values are converted to booleans,
+// integers or null where possible. Durations such as 30s are kept as strings because they are decoded when they are
+// populated into duration fields. When a key is repeated, the last value wins.
+func ParseOverrides(pairs []string) (map[string]any, error) {
+	out := make(map[string]any)
+	for _, pair := range pairs {
+		if err := strvals.ParseInto(pair, out); err != nil {
+			return nil, fmt.Errorf("invalid config override %q: %w", pair, err)
+		}
+	}
+
+	return out, nil
+}
+
+// withOverrides returns the given sources followed by the source for the overrides.
+// Override keys ending with appendSuffix are resolved against the values defined by the given sources.
+func withOverrides(sources []config.YAMLOption, overrides map[string]any) ([]config.YAMLOption, error) {
+	if !hasAppendKeys(overrides) {
+		return append(sources, config.Static(overrides)), nil
+	}
+
+	// environment variables are not expanded here because the resolved lists are expanded along with the rest of the config
+	base, err := config.NewYAML(sources...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	resolved, err := resolveAppends(base, "", overrides)
+	if err != nil {
+		return nil, fmt.Errorf("invalid config override: %w", err)
+	}
+
+	return append(sources, config.Static(resolved)), nil
+}
+
+func hasAppendKeys(m map[string]any) bool {
+	for k, v := range m {
+		if strings.HasSuffix(k, appendSuffix) {
+			return true
+		}
+
+		if child, ok := normalize(v).(map[string]any); ok && hasAppendKeys(child) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// resolveAppends returns a copy of the overrides in which each list under a key ending with appendSuffix is replaced by
+// the concatenation of the list at the same path in the base configuration and the override list.
+func resolveAppends(base config.Provider, prefix string, overrides map[string]any) (map[string]any, error) {
+	out := make(map[string]any, len(overrides))
+	for k, v := range overrides {
+		path := joinPath(prefix, k)
+		name := strings.TrimSuffix(k, appendSuffix)
+		if name == k {
+			if child, ok := normalize(v).(map[string]any); ok {
+				resolved, err := resolveAppends(base, path, child)
+				if err != nil {
+					return nil, err
+				}
+				v = resolved
+			}
+
+			out[k] = v
+			continue
+		}
+
+		if name == "" {
+			return nil, fmt.Errorf("missing key name before %q in %q", appendSuffix, path)
+		}
+
+		if _, ok := overrides[name]; ok {
+			return nil, fmt.Errorf("%q cannot be both replaced and appended to", joinPath(prefix, name))
+		}
+
+		values, ok := toList(v)
+		if !ok {
+			return nil, fmt.Errorf("value of %q must be a list", path)
+		}
+
+		var list []any
+		if current := base.Get(joinPath(prefix, name)); current.HasValue() {
+			if err := current.Populate(&list); err != nil {
+				return nil, fmt.Errorf("cannot append to %q because it is not a list: %w", joinPath(prefix, name), err)
+			}
+		}
+
+		out[name] = append(list, values...)
+	}
+
+	return out, nil
+}
+
+// toList converts a slice of any type to []any.
+func toList(v any) ([]any, bool) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice {
+		return nil, false
+	}
+
+	list := make([]any, rv.Len())
+	for i := range list {
+		list[i] = normalize(rv.Index(i).Interface())
+	}
+
+	return list, true
+}
+
+func joinPath(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+
+	return prefix + pathSep + key
+}
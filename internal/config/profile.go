@@ -0,0 +1,102 @@
+// Copyright 2021-2022 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"go.uber.org/config"
+)
+
+// profilesKey is the top-level key containing the named profiles.
+const profilesKey = "profiles"
+
+// LoadProfile loads the config file at the given path with the named profile activated.
+// The profile is the sub-tree under profiles.<profile> and it is merged over the root of the config file, so the keys
+// it doesn't define keep the values defined at the root. The overrides are applied last. An empty profile name is
+// equivalent to calling Load.
+//
+//	server:
+//	  httpListenAddr: ":3592"
+//	profiles:
+//	  dev:
+//	    server:
+//	      httpListenAddr: ":9999"
+func LoadProfile(confFile, profile string, overrides map[string]any) error {
+	if err := checkConfFile(confFile); err != nil {
+		return err
+	}
+
+	sources, err := profileSources(confFile, profile)
+	if err != nil {
+		return err
+	}
+
+	sources, err = withOverrides(sources, overrides)
+	if err != nil {
+		return err
+	}
+
+	if err := doLoad(sources...); err != nil {
+		return err
+	}
+
+	recordLoad([]string{confFile}, profile, overrides)
+	return nil
+}
+
+// profileSources returns the sources for the config file with the named profile promoted to the root.
+func profileSources(confFile, profile string) ([]config.YAMLOption, error) {
+	src, err := fileSource(confFile)
+	if err != nil {
+		return nil, err
+	}
+
+	if profile == "" {
+		return []config.YAMLOption{src}, nil
+	}
+
+	// environment variables are not expanded here because the sources are expanded when the config is loaded
+	base, err := config.NewYAML(src)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	var root map[string]any
+	if err := base.Get(config.Root).Populate(&root); err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	root, _ = normalize(root).(map[string]any)
+
+	profiles, _ := root[profilesKey].(map[string]any)
+	p, ok := profiles[profile]
+	if !ok {
+		return nil, fmt.Errorf("profile %q is not defined in %s: available profiles are [%s]", profile, confFile, strings.Join(profileNames(profiles), ","))
+	}
+
+	overlay, ok := p.(map[string]any)
+	if !ok && p != nil {
+		return nil, fmt.Errorf("profile %q in %s must be a map", profile, confFile)
+	}
+
+	delete(root, profilesKey)
+	sources := []config.YAMLOption{config.Static(root)}
+	if overlay != nil {
+		sources = append(sources, config.Static(overlay))
+	}
+
+	return sources, nil
+}
+
+func profileNames(profiles map[string]any) []string {
+	names := make([]string, 0, len(profiles))
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
@@ -0,0 +1,54 @@
+// Copyright 2021-2022 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+)
+
+// registry keeps track of the sections that must be valid for a configuration to be loaded.
+var registry = struct {
+	sections map[string]reflect.Type
+	mu       sync.RWMutex
+}{sections: make(map[string]reflect.Type)}
+
+// RegisterSection registers sections that are validated whenever the configuration is reloaded by Watch.
+// If any of them fail to populate or validate, the reload fails and the current configuration stays in place.
+// Packages owning a section that is always used by the server should register it from an init function.
+// Sections must be pointers to structs. Registering a section with the same key again replaces the previous registration.
+func RegisterSection(sections ...Section) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	for _, s := range sections {
+		t := reflect.TypeOf(s)
+		if t.Kind() != reflect.Ptr || t.Elem().Kind() != reflect.Struct {
+			panic(fmt.Errorf("config section %q must be a pointer to a struct", s.Key()))
+		}
+
+		registry.sections[s.Key()] = t.Elem()
+	}
+}
+
+// registeredSections returns new instances of the registered sections.
+func registeredSections() []Section {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+
+	keys := make([]string, 0, len(registry.sections))
+	for k := range registry.sections {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	sections := make([]Section, len(keys))
+	for i, k := range keys {
+		sections[i], _ = reflect.New(registry.sections[k]).Interface().(Section)
+	}
+
+	return sections
+}
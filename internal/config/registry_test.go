@@ -0,0 +1,66 @@
+// Copyright 2021-2022 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/config"
+)
+
+var errRegistryTest = errors.New("listen address must not be empty")
+
+type registryTestSection struct {
+	ListenAddr string `yaml:"listenAddr"`
+}
+
+func (s *registryTestSection) Key() string {
+	return "server"
+}
+
+func (s *registryTestSection) Validate() error {
+	if s.ListenAddr == "" {
+		return errRegistryTest
+	}
+
+	return nil
+}
+
+func TestLoadProviderRejectsInvalidConfig(t *testing.T) {
+	registry.mu.Lock()
+	saved := registry.sections
+	registry.sections = make(map[string]reflect.Type)
+	registry.mu.Unlock()
+
+	t.Cleanup(func() {
+		registry.mu.Lock()
+		registry.sections = saved
+		registry.mu.Unlock()
+	})
+
+	RegisterSection(&registryTestSection{})
+
+	mkTestProvider := func(t *testing.T, m map[string]any) config.Provider {
+		t.Helper()
+		p, err := mkProvider(config.Static(m))
+		require.NoError(t, err)
+		return p
+	}
+
+	w := &Wrapper{}
+	require.NoError(t, w.loadProvider(mkTestProvider(t, map[string]any{"server": map[string]any{"listenAddr": ":3592"}})))
+
+	err := w.loadProvider(mkTestProvider(t, map[string]any{"server": map[string]any{"listenAddr": ""}}))
+	require.ErrorIs(t, err, errRegistryTest)
+
+	err = w.loadProvider(mkTestProvider(t, map[string]any{"server": map[string]any{"listnAddr": ":4000"}}))
+	require.Error(t, err)
+
+	var have registryTestSection
+	require.NoError(t, w.GetSection(&have))
+	require.Equal(t, ":3592", have.ListenAddr)
+}
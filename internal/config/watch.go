@@ -0,0 +1,289 @@
+// Copyright 2021-2022 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rjeczalik/notify"
+	"go.uber.org/config"
+	"go.uber.org/zap"
+)
+
+// defaultWatchDebounce is the amount of time to wait for the file to settle down after a change before reloading it.
+// Editors and Kubernetes ConfigMap updates usually produce a burst of events for a single change.
+const defaultWatchDebounce = 2 * time.Second
+
+// loaded keeps track of the files, profile and overrides used by the last call to Load, LoadProfile or LoadFiles so that
+// they can be re-applied on reload.
+var loaded = struct {
+	overrides map[string]any
+	profile   string
+	files     []string
+	mu        sync.RWMutex
+}{}
+
+func recordLoad(files []string, profile string, overrides map[string]any) {
+	loaded.mu.Lock()
+	defer loaded.mu.Unlock()
+
+	loaded.files = files
+	loaded.profile = profile
+	loaded.overrides = overrides
+}
+
+// loadOptionsFor returns the profile and overrides to re-apply when the given files are reloaded.
+// It returns an error if the files are only a part of the set loaded together by LoadFiles, because reloading them
+// on their own would replace the configuration with a partial one.
+func loadOptionsFor(files []string) (string, map[string]any, error) {
+	loaded.mu.RLock()
+	defer loaded.mu.RUnlock()
+
+	want := absPaths(files)
+	have := absPaths(loaded.files)
+	if strings.Join(want, "\n") == strings.Join(have, "\n") {
+		return loaded.profile, loaded.overrides, nil
+	}
+
+	if len(have) > 1 {
+		for _, w := range want {
+			for _, h := range have {
+				if w == h {
+					return "", nil, fmt.Errorf("config was loaded from [%s]: use WatchFiles to watch all of them", strings.Join(loaded.files, ","))
+				}
+			}
+		}
+	}
+
+	return "", nil, nil
+}
+
+func absPaths(paths []string) []string {
+	out := make([]string, len(paths))
+	for i, p := range paths {
+		if abs, err := filepath.Abs(p); err == nil {
+			out[i] = abs
+		} else {
+			out[i] = p
+		}
+	}
+
+	return out
+}
+
+// Watch reloads the global configuration whenever the given config file changes, until the context is cancelled.
+// The directory containing the file is watched as well so that files replaced by swapping symlinks (such as Kubernetes
+// ConfigMap volumes) are picked up. Successive changes are debounced and the file is only reloaded if its contents changed.
+// The profile and overrides passed to Load or LoadProfile for the same file are re-applied. onReload (if not nil) is called after each successful
+// reload so that sections can be re-fetched. If the new configuration cannot be parsed or any of the sections registered
+// with RegisterSection is invalid, the error is logged and the current configuration is kept.
+// If the config was loaded from several files using LoadFiles, use WatchFiles instead.
+func Watch(ctx context.Context, confFile string, onReload func()) error {
+	return watch(ctx, []string{confFile}, defaultWatchDebounce, onReload)
+}
+
+// WatchFiles is like Watch for the config files loaded together by LoadFiles. All the files are reloaded in order whenever
+// any of them changes, and the overrides passed to LoadFiles are re-applied. The files must be the same as the ones
+// passed to LoadFiles, in the same order.
+func WatchFiles(ctx context.Context, paths []string, onReload func()) error {
+	if len(paths) == 0 {
+		return errors.New("no config files specified")
+	}
+
+	return watch(ctx, paths, defaultWatchDebounce, onReload)
+}
+
+// WatchFileChanges calls onChange whenever the contents of any of the given files change, until the context is cancelled.
+// It watches the files the same way as Watch, without reloading the global configuration, so that other configuration
+// files (such as the cerbosctl settings) can be reloaded when they change. If onChange returns an error, the error is
+// logged and onChange is called again on the next change even if the contents are the same as before.
+func WatchFileChanges(ctx context.Context, paths []string, onChange func() error) error {
+	if len(paths) == 0 {
+		return errors.New("no files specified")
+	}
+
+	return watchFiles(ctx, paths, defaultWatchDebounce, onChange)
+}
+
+func watch(ctx context.Context, confFiles []string, debounce time.Duration, onReload func()) error {
+	if _, _, err := loadOptionsFor(confFiles); err != nil {
+		return err
+	}
+
+	return watchFiles(ctx, confFiles, debounce, func() error {
+		if err := reloadFiles(confFiles); err != nil {
+			return err
+		}
+
+		if onReload != nil {
+			onReload()
+		}
+
+		return nil
+	})
+}
+
+// reloadFiles replaces the global configuration with the contents of the given files.
+func reloadFiles(confFiles []string) error {
+	profile, overrides, err := loadOptionsFor(confFiles)
+	if err != nil {
+		return err
+	}
+
+	sources, err := reloadSources(confFiles, profile)
+	if err != nil {
+		return err
+	}
+
+	// lists appended to by the overrides are resolved again against the lists defined by the new contents
+	sources, err = withOverrides(sources, overrides)
+	if err != nil {
+		return err
+	}
+
+	return doReload(sources...)
+}
+
+func watchFiles(ctx context.Context, paths []string, debounce time.Duration, onChange func() error) error {
+	fw := &fileWatch{
+		paths:     paths,
+		log:       zap.L().Named("config.watch").With(zap.Strings("files", paths)),
+		onChange:  onChange,
+		watchChan: make(chan notify.EventInfo, 8*len(paths)), //nolint:gomnd
+		debounce:  debounce,
+	}
+
+	var err error
+	if fw.checksum, err = checksumFiles(paths); err != nil {
+		return err
+	}
+
+	dirs := make(map[string]struct{}, len(paths))
+	for _, path := range paths {
+		absPath, err := filepath.Abs(path)
+		if err != nil {
+			notify.Stop(fw.watchChan)
+			return fmt.Errorf("failed to determine absolute path of %s: %w", path, err)
+		}
+
+		dir := filepath.Dir(absPath)
+		if _, ok := dirs[dir]; ok {
+			continue
+		}
+		dirs[dir] = struct{}{}
+
+		if err := notify.Watch(dir, fw.watchChan, notify.All); err != nil {
+			notify.Stop(fw.watchChan)
+			return fmt.Errorf("failed to watch %s: %w", path, err)
+		}
+	}
+
+	go fw.handleEvents(ctx)
+
+	return nil
+}
+
+type fileWatch struct {
+	log       *zap.Logger
+	onChange  func() error
+	watchChan chan notify.EventInfo
+	paths     []string
+	checksum  []byte
+	debounce  time.Duration
+}
+
+func (fw *fileWatch) handleEvents(ctx context.Context) {
+	timer := time.NewTimer(fw.debounce)
+	timer.Stop()
+
+	defer func() {
+		timer.Stop()
+		notify.Stop(fw.watchChan)
+	}()
+
+	fw.log.Info("Watching config files for changes")
+
+	for {
+		select {
+		case <-ctx.Done():
+			fw.log.Info("Stopped watching config files for changes")
+			return
+		case <-fw.watchChan:
+			// restart the countdown on every event so that the file is only reloaded once it has settled down
+			if !timer.Stop() {
+				select {
+				case <-timer.C:
+				default:
+				}
+			}
+			timer.Reset(fw.debounce)
+		case <-timer.C:
+			fw.reload()
+		}
+	}
+}
+
+func (fw *fileWatch) reload() {
+	checksum, err := checksumFiles(fw.paths)
+	if err != nil {
+		fw.log.Warn("Failed to read config file", zap.Error(err))
+		return
+	}
+
+	if bytes.Equal(checksum, fw.checksum) {
+		return
+	}
+
+	if err := fw.onChange(); err != nil {
+		fw.log.Warn("Ignoring invalid config file change", zap.Error(err))
+		return
+	}
+
+	fw.checksum = checksum
+	fw.log.Info("Reloaded config files")
+}
+
+// reloadSources returns the sources for the given files. Profiles can only be used with a single file.
+func reloadSources(confFiles []string, profile string) ([]config.YAMLOption, error) {
+	if len(confFiles) == 1 {
+		return profileSources(confFiles[0], profile)
+	}
+
+	sources := make([]config.YAMLOption, 0, len(confFiles)+1)
+	for _, confFile := range confFiles {
+		src, err := fileSource(confFile)
+		if err != nil {
+			return nil, err
+		}
+
+		sources = append(sources, src)
+	}
+
+	return sources, nil
+}
+
+// checksumFiles returns the checksum of the combined contents of the given files.
+func checksumFiles(paths []string) ([]byte, error) {
+	h := sha256.New()
+	for _, path := range paths {
+		contents, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+
+		sum := sha256.Sum256(contents)
+		h.Write(sum[:])
+	}
+
+	return h.Sum(nil), nil
+}
@@ -0,0 +1,198 @@
+// Copyright 2021-2022 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/config"
+)
+
+func TestWatch(t *testing.T) {
+	const (
+		debounce = 50 * time.Millisecond
+		timeout  = 5 * time.Second
+	)
+
+	writeConf := func(t *testing.T, path, listenAddr string) {
+		t.Helper()
+		require.NoError(t, os.WriteFile(path, []byte("server:\n  httpListenAddr: "+listenAddr+"\n"), 0o600))
+	}
+
+	getValue := func(t *testing.T, key string) string {
+		t.Helper()
+		var v string
+		require.NoError(t, Get(key, &v))
+		return v
+	}
+
+	t.Run("file_change", func(t *testing.T) {
+		dir := t.TempDir()
+		confFile := filepath.Join(dir, "config.yaml")
+		writeConf(t, confFile, ":3592")
+		require.NoError(t, Load(confFile, map[string]any{"server": map[string]any{"grpcListenAddr": ":9999"}}))
+
+		ctx, cancelFn := context.WithCancel(context.Background())
+		t.Cleanup(cancelFn)
+
+		reloaded := make(chan struct{}, 1)
+		require.NoError(t, watch(ctx, []string{confFile}, debounce, func() { reloaded <- struct{}{} }))
+
+		// successive writes should only trigger a single reload
+		writeConf(t, confFile, ":4000")
+		writeConf(t, confFile, ":4001")
+
+		select {
+		case <-reloaded:
+		case <-time.After(timeout):
+			t.Fatal("Timed out waiting for reload")
+		}
+
+		require.Equal(t, ":4001", getValue(t, "server.httpListenAddr"))
+		require.Equal(t, ":9999", getValue(t, "server.grpcListenAddr"), "Overrides should be re-applied")
+
+		select {
+		case <-reloaded:
+			t.Fatal("Unexpected second reload")
+		case <-time.After(10 * debounce):
+		}
+
+		// invalid changes are ignored
+		require.NoError(t, os.WriteFile(confFile, []byte("server: ${NO_SUCH_VARIABLE_FOR_CERBOS_TESTS}\n"), 0o600))
+		select {
+		case <-reloaded:
+			t.Fatal("Unexpected reload of invalid config")
+		case <-time.After(10 * debounce):
+		}
+		require.Equal(t, ":4001", getValue(t, "server.httpListenAddr"))
+	})
+
+	t.Run("symlink_swap", func(t *testing.T) {
+		// mimic the way Kubernetes updates ConfigMap volumes
+		dir := t.TempDir()
+		writeVersion := func(version, listenAddr string) {
+			versionDir := filepath.Join(dir, version)
+			require.NoError(t, os.Mkdir(versionDir, 0o700))
+			writeConf(t, filepath.Join(versionDir, "config.yaml"), listenAddr)
+
+			tmpLink := filepath.Join(dir, "..data_tmp")
+			require.NoError(t, os.Symlink(version, tmpLink))
+			require.NoError(t, os.Rename(tmpLink, filepath.Join(dir, "..data")))
+		}
+
+		writeVersion("v1", ":3592")
+		confFile := filepath.Join(dir, "config.yaml")
+		if err := os.Symlink(filepath.Join("..data", "config.yaml"), confFile); err != nil {
+			t.Skipf("Symbolic links are not supported: %v", err)
+		}
+		require.NoError(t, Load(confFile, nil))
+
+		ctx, cancelFn := context.WithCancel(context.Background())
+		t.Cleanup(cancelFn)
+
+		reloaded := make(chan struct{}, 1)
+		require.NoError(t, watch(ctx, []string{confFile}, debounce, func() { reloaded <- struct{}{} }))
+
+		writeVersion("v2", ":5000")
+
+		select {
+		case <-reloaded:
+		case <-time.After(timeout):
+			t.Fatal("Timed out waiting for reload")
+		}
+
+		require.Equal(t, ":5000", getValue(t, "server.httpListenAddr"))
+	})
+
+	t.Run("load_files", func(t *testing.T) {
+		dir := t.TempDir()
+		baseFile := filepath.Join(dir, "base.yaml")
+		require.NoError(t, os.WriteFile(baseFile, []byte("server:\n  httpListenAddr: \":3592\"\n  adminAPI:\n    enabled: false\n"), 0o600))
+		overlayFile := filepath.Join(dir, "overlay.yaml")
+		writeConf(t, overlayFile, ":4000")
+
+		files := []string{baseFile, overlayFile}
+		require.NoError(t, LoadFiles(files, map[string]any{"server": map[string]any{"grpcListenAddr": ":9999"}}))
+
+		ctx, cancelFn := context.WithCancel(context.Background())
+		t.Cleanup(cancelFn)
+
+		// watching only some of the files would drop the rest of them on reload
+		require.Error(t, watch(ctx, []string{overlayFile}, debounce, nil))
+
+		reloaded := make(chan struct{}, 1)
+		require.NoError(t, watch(ctx, files, debounce, func() { reloaded <- struct{}{} }))
+
+		require.NoError(t, os.WriteFile(baseFile, []byte("server:\n  httpListenAddr: \":3592\"\n  adminAPI:\n    enabled: true\n"), 0o600))
+
+		select {
+		case <-reloaded:
+		case <-time.After(timeout):
+			t.Fatal("Timed out waiting for reload")
+		}
+
+		var adminEnabled bool
+		require.NoError(t, Get("server.adminAPI.enabled", &adminEnabled))
+		require.True(t, adminEnabled)
+		require.Equal(t, ":4000", getValue(t, "server.httpListenAddr"), "Overlay should be re-applied")
+		require.Equal(t, ":9999", getValue(t, "server.grpcListenAddr"), "Overrides should be re-applied")
+
+		writeConf(t, overlayFile, ":4001")
+
+		select {
+		case <-reloaded:
+		case <-time.After(timeout):
+			t.Fatal("Timed out waiting for reload")
+		}
+
+		require.Equal(t, ":4001", getValue(t, "server.httpListenAddr"))
+		require.NoError(t, Get("server.adminAPI.enabled", &adminEnabled))
+		require.True(t, adminEnabled, "Base file should be re-applied")
+	})
+}
+
+func TestOnReload(t *testing.T) {
+	newProvider := func(t *testing.T, listenAddr string) config.Provider {
+		t.Helper()
+		p, err := config.NewYAML(config.Static(map[string]any{"server": map[string]any{"httpListenAddr": listenAddr}}))
+		require.NoError(t, err)
+		return p
+	}
+
+	w := &Wrapper{}
+	var calls []string
+	subscribe := func(name string) func() {
+		return w.OnReload(func() {
+			// calling Get from a callback must not deadlock
+			var v string
+			require.NoError(t, w.Get("server.httpListenAddr", &v))
+			calls = append(calls, name+"="+v)
+		})
+	}
+
+	unsubscribeA := subscribe("a")
+	unsubscribeB := subscribe("b")
+	t.Cleanup(unsubscribeB)
+
+	w.replaceProvider(newProvider(t, ":3592"))
+	require.Equal(t, []string{"a=:3592", "b=:3592"}, calls)
+
+	unsubscribeA()
+	unsubscribeA()
+	calls = nil
+
+	w.replaceProvider(newProvider(t, ":4000"))
+	require.Equal(t, []string{"b=:4000"}, calls)
+
+	unsubscribeB()
+	calls = nil
+
+	w.replaceProvider(newProvider(t, ":5000"))
+	require.Empty(t, calls)
+}
@@ -23,6 +23,10 @@ type Conf struct {
 	NumWorkers           uint   `yaml:"numWorkers" conf:",ignore"`
 }
 
+func init() {
+	config.RegisterSection(&Conf{})
+}
+
 func (c *Conf) Key() string {
 	return confKey
 }
@@ -32,8 +32,12 @@ func init() {
 }
 
 var (
+	KeyAuxDataFailureReason = tag.MustNewKey("reason")
+	KeyCacheKeySet          = tag.MustNewKey("keyset")
 	KeyCacheKind            = tag.MustNewKey("kind")
 	KeyCacheResult          = tag.MustNewKey("result")
+	KeyCodecDirection       = tag.MustNewKey("direction")
+	KeyCodecMessageType     = tag.MustNewKey("message_type")
 	KeyCompileStatus        = tag.MustNewKey("status")
 	KeyEngineDecisionStatus = tag.MustNewKey("status")
 	KeyEnginePlanStatus     = tag.MustNewKey("status")
@@ -42,6 +46,18 @@ var (
 )
 
 var (
+	AuxDataJWTFailureCount = stats.Int64(
+		"cerbos.dev/aux_data/jwt_failure_count",
+		"Number of JWTs rejected during auxiliary data extraction",
+		stats.UnitDimensionless,
+	)
+
+	AuxDataJWTFailureCountView = &view.View{
+		Measure:     AuxDataJWTFailureCount,
+		TagKeys:     []tag.Key{KeyAuxDataFailureReason},
+		Aggregation: view.Count(),
+	}
+
 	CacheAccessCount = stats.Int64(
 		"cerbos.dev/cache/access_count",
 		"Counter of cache access",
@@ -50,7 +66,19 @@ var (
 
 	CacheAccessCountView = &view.View{
 		Measure:     CacheAccessCount,
-		TagKeys:     []tag.Key{KeyCacheKind, KeyCacheResult},
+		TagKeys:     []tag.Key{KeyCacheKind, KeyCacheKeySet, KeyCacheResult},
+		Aggregation: view.Count(),
+	}
+
+	CacheEvictionCount = stats.Int64(
+		"cerbos.dev/cache/eviction_count",
+		"Counter of entries evicted from the cache",
+		stats.UnitDimensionless,
+	)
+
+	CacheEvictionCountView = &view.View{
+		Measure:     CacheEvictionCount,
+		TagKeys:     []tag.Key{KeyCacheKind, KeyCacheKeySet},
 		Aggregation: view.Count(),
 	}
 
@@ -62,10 +90,46 @@ var (
 
 	CacheMaxSizeView = &view.View{
 		Measure:     CacheMaxSize,
-		TagKeys:     []tag.Key{KeyCacheKind},
+		TagKeys:     []tag.Key{KeyCacheKind, KeyCacheKeySet},
+		Aggregation: view.LastValue(),
+	}
+
+	CacheSize = stats.Int64(
+		"cerbos.dev/cache/size",
+		"Number of entries in the cache",
+		stats.UnitDimensionless,
+	)
+
+	CacheSizeView = &view.View{
+		Measure:     CacheSize,
+		TagKeys:     []tag.Key{KeyCacheKind, KeyCacheKeySet},
 		Aggregation: view.LastValue(),
 	}
 
+	CodecErrorCount = stats.Int64(
+		"cerbos.dev/server/codec_error_count",
+		"Number of gRPC messages that could not be serialized or deserialized",
+		stats.UnitDimensionless,
+	)
+
+	CodecErrorCountView = &view.View{
+		Measure:     CodecErrorCount,
+		TagKeys:     []tag.Key{KeyCodecDirection, KeyCodecMessageType},
+		Aggregation: view.Count(),
+	}
+
+	CodecFallbackCount = stats.Int64(
+		"cerbos.dev/server/codec_fallback_count",
+		"Number of gRPC messages serialized or deserialized without the optimized VT methods",
+		stats.UnitDimensionless,
+	)
+
+	CodecFallbackCountView = &view.View{
+		Measure:     CodecFallbackCount,
+		TagKeys:     []tag.Key{KeyCodecDirection, KeyCodecMessageType},
+		Aggregation: view.Count(),
+	}
+
 	CompileDuration = stats.Float64(
 		"cerbos.dev/compiler/compile_duration",
 		"Time to compile a set of policies",
@@ -162,8 +226,13 @@ var (
 )
 
 var DefaultCerbosViews = []*view.View{
+	AuxDataJWTFailureCountView,
 	CacheAccessCountView,
+	CacheEvictionCountView,
 	CacheMaxSizeView,
+	CacheSizeView,
+	CodecErrorCountView,
+	CodecFallbackCountView,
 	CompileDurationView,
 	EngineCheckLatencyView,
 	EngineCheckBatchSizeView,
@@ -6,6 +6,8 @@ package tracing
 import (
 	"errors"
 	"fmt"
+
+	"github.com/cerbos/cerbos/internal/config"
 )
 
 const (
@@ -52,6 +54,10 @@ type OTLPConf struct {
 	CollectorEndpoint string `yaml:"collectorEndpoint" conf:",example=\"otel:4317\""`
 }
 
+func init() {
+	config.RegisterSection(&Conf{})
+}
+
 func (c *Conf) Key() string {
 	return confKey
 }
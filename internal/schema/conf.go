@@ -19,6 +19,10 @@ type Conf struct {
 	CacheSize uint `yaml:"cacheSize" conf:",example=1024"`
 }
 
+func init() {
+	config.RegisterSection(&Conf{})
+}
+
 func (c *Conf) Key() string {
 	return confKey
 }
@@ -4,26 +4,165 @@
 package server
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"strings"
+	"sync/atomic"
 
 	vtgrpc "github.com/planetscale/vtprotobuf/codec/grpc"
+	"go.opencensus.io/stats"
+	"go.opencensus.io/tag"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/encoding"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 
 	// Import the default grpc encoding to ensure that it gets replaced by this codec.
 	_ "google.golang.org/grpc/encoding/proto"
+	"google.golang.org/protobuf/encoding/protojson"
 	"google.golang.org/protobuf/proto"
+
+	"github.com/cerbos/cerbos/internal/observability/metrics"
 )
 
-const name = "proto"
+const (
+	name     = "proto"
+	jsonName = "json"
+	rawName  = "proto-raw"
+
+	rawContentType = "application/grpc+" + rawName
+)
 
 func init() {
 	// Register the codec to use VT where possible for optimized marshaling/unmarshaling.
+	// The registered codecs are shared by every gRPC server and client in the process, so they don't enforce any message
+	// size limits. gRPC enforces the limits of each server, which reports the rejected messages using MessageSizeLimits.
 	encoding.RegisterCodec(Codec{vtcodec: vtgrpc.Codec{}})
+	// Register the JSON codec for clients that use the application/grpc+json content type.
+	encoding.RegisterCodec(JSONCodec{})
+	// Register the raw codec for clients that use the application/grpc+proto-raw content type.
+	// Servers that don't enable the raw codec reject those clients using RawCodecDisabledUnaryServerInterceptor.
+	encoding.RegisterCodec(RawCodec{codec: Codec{vtcodec: vtgrpc.Codec{}}})
+}
+
+// MessageSizeLimits are the maximum sizes of the messages handled by a codec or a server.
+// A codec checks them before doing any work to fail fast with a clear error instead of allocating memory for a message
+// that is going to be rejected. A server relies on gRPC to enforce them and only uses them to explain the rejections.
+// A limit that is not greater than zero disables the corresponding check.
+type MessageSizeLimits struct {
+	maxRecvBytes atomic.Int64
+	maxSendBytes atomic.Int64
+}
+
+func NewMessageSizeLimits(maxRecvBytes, maxSendBytes int) *MessageSizeLimits {
+	l := &MessageSizeLimits{}
+	l.Set(maxRecvBytes, maxSendBytes)
+	return l
+}
+
+func (l *MessageSizeLimits) Set(maxRecvBytes, maxSendBytes int) {
+	l.maxRecvBytes.Store(int64(maxRecvBytes))
+	l.maxSendBytes.Store(int64(maxSendBytes))
+}
+
+// limit returns the limit for the given direction, or zero if there is no limit.
+func (l *MessageSizeLimits) limit(direction CodecDirection) int64 {
+	if l == nil {
+		return 0
+	}
+
+	if direction == CodecMarshal {
+		return l.maxSendBytes.Load()
+	}
+
+	return l.maxRecvBytes.Load()
+}
+
+// StreamServerInterceptor reports the messages of a stream that gRPC rejects for exceeding the size limits with a
+// MessageSizeError explaining how to raise the limits. gRPC enforces the limits itself, so the interceptor doesn't do any
+// work for the messages within the limits. The messages of unary RPCs are received before and sent after the interceptors
+// run, so their limits are reported with the errors produced by gRPC.
+func (l *MessageSizeLimits) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, &limitedServerStream{ServerStream: ss, limits: l})
+	}
+}
+
+type limitedServerStream struct {
+	grpc.ServerStream
+	limits *MessageSizeLimits
+}
+
+func (s *limitedServerStream) SendMsg(m any) error {
+	return s.limits.toMessageSizeError(CodecMarshal, m, s.ServerStream.SendMsg(m))
+}
+
+func (s *limitedServerStream) RecvMsg(m any) error {
+	return s.limits.toMessageSizeError(CodecUnmarshal, m, s.ServerStream.RecvMsg(m))
+}
+
+// toMessageSizeError converts a ResourceExhausted error returned by gRPC for a message exceeding the limit for the given
+// direction to a CodecError. The size of a received message is unknown because gRPC rejects it before reading it.
+func (l *MessageSizeLimits) toMessageSizeError(direction CodecDirection, v any, err error) error {
+	limit := l.limit(direction)
+	if err == nil || limit <= 0 || status.Code(err) != codes.ResourceExhausted {
+		return err
+	}
+
+	var ce *CodecError
+	if errors.As(err, &ce) {
+		return err
+	}
+
+	mse := &MessageSizeError{Direction: direction, Limit: int(limit)}
+	if direction == CodecMarshal {
+		mse.Size = messageSize(v)
+	}
+
+	return newCodecError(direction, v, mse)
+}
+
+// checkMessage returns a CodecError if the message exceeds the limit for the given direction.
+// The size of the message is only computed if there is a limit to check.
+func (l *MessageSizeLimits) checkMessage(direction CodecDirection, v any) error {
+	if l.limit(direction) <= 0 {
+		return nil
+	}
+
+	if err := l.check(direction, messageSize(v)); err != nil {
+		return newCodecError(direction, v, err)
+	}
+
+	return nil
+}
+
+// check returns an error if a message of the given size exceeds the limit for the given direction.
+func (l *MessageSizeLimits) check(direction CodecDirection, size int) error {
+	limit := l.limit(direction)
+	if limit <= 0 || int64(size) <= limit {
+		return nil
+	}
+
+	return &MessageSizeError{Direction: direction, Size: size, Limit: int(limit)}
 }
 
 // Codec implements the grpc Codec interface to delegate encoding to VT where possible.
+//
+// Compression is independent of the codec: gRPC compresses the bytes produced by Marshal before writing them to the wire
+// and decompresses them before calling Unmarshal. The server responds with the same compressor used by the client for the
+// request, so clients that send `grpc-encoding: gzip` receive gzip-compressed responses. This is worthwhile for large
+// responses such as batch checks or query plans but adds CPU overhead that outweighs the savings for small messages, so
+// responses smaller than 1KiB are sent uncompressed.
 type Codec struct {
 	vtcodec vtgrpc.Codec
+	limits  *MessageSizeLimits
+}
+
+// NewCodec creates a codec that rejects messages exceeding the given limits. The limits can be nil to accept any size.
+func NewCodec(limits *MessageSizeLimits) Codec {
+	return Codec{vtcodec: vtgrpc.Codec{}, limits: limits}
 }
 
 func (c Codec) Name() string {
@@ -31,25 +170,336 @@ func (c Codec) Name() string {
 }
 
 func (c Codec) Marshal(v any) ([]byte, error) {
+	// computing the size walks the whole message, so checkMessage avoids it unless there is a limit to check
+	if err := c.limits.checkMessage(CodecMarshal, v); err != nil {
+		return nil, err
+	}
+
 	if b, err := c.vtcodec.Marshal(v); err == nil {
 		return b, nil
 	}
 
-	vv, ok := v.(proto.Message)
-	if !ok {
-		return nil, fmt.Errorf("failed to marshal, message is %T, want proto.Message", v)
+	b, err := marshalFallback(v)
+	if err != nil {
+		return nil, newCodecError(CodecMarshal, v, err)
 	}
-	return proto.Marshal(vv)
+
+	return b, nil
 }
 
 func (c Codec) Unmarshal(data []byte, v any) error {
+	if err := c.limits.check(CodecUnmarshal, len(data)); err != nil {
+		return newCodecError(CodecUnmarshal, v, err)
+	}
+
 	if err := c.vtcodec.Unmarshal(data, v); err == nil {
 		return nil
 	}
 
+	if err := unmarshalFallback(data, v); err != nil {
+		return newCodecError(CodecUnmarshal, v, err)
+	}
+
+	return nil
+}
+
+// gogoMarshaler is implemented by messages generated by gogoproto with the marshaler plugin.
+// It is preferred over legacyMarshaler because the size of the message is known before marshaling.
+type gogoMarshaler interface {
+	Size() int
+	MarshalTo(data []byte) (int, error)
+}
+
+// legacyMarshaler is implemented by messages that predate the current protobuf API, as expected by the original gRPC proto codec.
+type legacyMarshaler interface {
+	Marshal() ([]byte, error)
+}
+
+// legacyUnmarshaler is implemented by the messages generated by gogoproto and by the messages that predate the current protobuf API.
+type legacyUnmarshaler interface {
+	Unmarshal(data []byte) error
+}
+
+// marshalFallback marshals a message that is not handled by VT.
+// The interfaces are attempted in order: proto.Message, gogoMarshaler and legacyMarshaler.
+func marshalFallback(v any) ([]byte, error) {
+	switch m := v.(type) {
+	case proto.Message:
+		recordFallback(CodecMarshal, v)
+		return proto.Marshal(m)
+	case gogoMarshaler:
+		recordFallback(CodecMarshal, v)
+		b := make([]byte, m.Size())
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	case legacyMarshaler:
+		recordFallback(CodecMarshal, v)
+		return m.Marshal()
+	default:
+		return nil, fmt.Errorf("failed to marshal, message is %T, want proto.Message", v)
+	}
+}
+
+// unmarshalFallback unmarshals a message that is not handled by VT.
+// The interfaces are attempted in order: proto.Message and legacyUnmarshaler.
+func unmarshalFallback(data []byte, v any) error {
+	switch m := v.(type) {
+	case proto.Message:
+		recordFallback(CodecUnmarshal, v)
+		return proto.Unmarshal(data, m)
+	case legacyUnmarshaler:
+		recordFallback(CodecUnmarshal, v)
+		return m.Unmarshal(data)
+	default:
+		return fmt.Errorf("failed to unmarshal, message is %T, want proto.Message", v)
+	}
+}
+
+// JSONCodec implements the grpc Codec interface using the protobuf JSON encoding.
+// Clients can select it by setting the content-subtype to json (content type application/grpc+json).
+// The size of a JSON message is only known once it has been marshaled, so outgoing messages are checked after marshaling.
+type JSONCodec struct {
+	limits *MessageSizeLimits
+}
+
+// NewJSONCodec creates a JSON codec that rejects messages exceeding the given limits. The limits can be nil to accept any size.
+func NewJSONCodec(limits *MessageSizeLimits) JSONCodec {
+	return JSONCodec{limits: limits}
+}
+
+func (JSONCodec) Name() string {
+	return jsonName
+}
+
+func (jc JSONCodec) Marshal(v any) ([]byte, error) {
 	vv, ok := v.(proto.Message)
 	if !ok {
-		return fmt.Errorf("failed to unmarshal, message is %T, want proto.Message", v)
+		return nil, newCodecError(CodecMarshal, v, fmt.Errorf("failed to marshal, message is %T, want proto.Message", v))
 	}
-	return proto.Unmarshal(data, vv)
+
+	b, err := protojson.Marshal(vv)
+	if err != nil {
+		return nil, newCodecError(CodecMarshal, v, err)
+	}
+
+	if err := jc.limits.check(CodecMarshal, len(b)); err != nil {
+		return nil, newCodecError(CodecMarshal, v, err)
+	}
+
+	return b, nil
+}
+
+func (jc JSONCodec) Unmarshal(data []byte, v any) error {
+	if err := jc.limits.check(CodecUnmarshal, len(data)); err != nil {
+		return newCodecError(CodecUnmarshal, v, err)
+	}
+
+	vv, ok := v.(proto.Message)
+	if !ok {
+		return newCodecError(CodecUnmarshal, v, fmt.Errorf("failed to unmarshal, message is %T, want proto.Message", v))
+	}
+
+	if err := protojson.Unmarshal(data, vv); err != nil {
+		return newCodecError(CodecUnmarshal, v, err)
+	}
+
+	return nil
+}
+
+// RawMessage is a message that has already been serialized in the protobuf wire format.
+type RawMessage []byte
+
+// RawCodec implements the grpc Codec interface for messages that have already been serialized, such as those forwarded by a proxy.
+// Clients can select it by setting the content-subtype to proto-raw (content type application/grpc+proto-raw).
+// Values of type []byte or *RawMessage are passed through without being decoded and encoded again, and any other messages
+// are handled by Codec.
+type RawCodec struct {
+	codec Codec
+}
+
+// NewRawCodec creates a raw codec that rejects messages exceeding the given limits. The limits can be nil to accept any size.
+func NewRawCodec(limits *MessageSizeLimits) RawCodec {
+	return RawCodec{codec: NewCodec(limits)}
+}
+
+func (RawCodec) Name() string {
+	return rawName
+}
+
+func (rc RawCodec) Marshal(v any) ([]byte, error) {
+	var b []byte
+	switch m := v.(type) {
+	case []byte:
+		b = m
+	case *RawMessage:
+		if m != nil {
+			b = *m
+		}
+	default:
+		return rc.codec.Marshal(v)
+	}
+
+	if err := rc.codec.limits.check(CodecMarshal, len(b)); err != nil {
+		return nil, newCodecError(CodecMarshal, v, err)
+	}
+
+	return b, nil
+}
+
+func (rc RawCodec) Unmarshal(data []byte, v any) error {
+	var dest *[]byte
+	switch m := v.(type) {
+	case *[]byte:
+		dest = m
+	case *RawMessage:
+		dest = (*[]byte)(m)
+	default:
+		return rc.codec.Unmarshal(data, v)
+	}
+
+	if err := rc.codec.limits.check(CodecUnmarshal, len(data)); err != nil {
+		return newCodecError(CodecUnmarshal, v, err)
+	}
+
+	if dest == nil {
+		return newCodecError(CodecUnmarshal, v, errors.New("failed to unmarshal, destination is nil"))
+	}
+
+	// gRPC may reuse the buffer after Unmarshal returns, so the data must be copied.
+	*dest = append((*dest)[:0], data...)
+	return nil
+}
+
+// RawCodecDisabledUnaryServerInterceptor rejects the requests that use the proto-raw content-subtype. It is installed on
+// the servers that don't enable the raw codec. gRPC selects the codec of a request from the registry shared by every
+// server in the process, so the raw codec is always registered and each server decides whether to accept it.
+// The request has already been unmarshaled when the interceptor runs, but the raw codec handles the messages of the
+// Cerbos services in the same way as Codec.
+func RawCodecDisabledUnaryServerInterceptor(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	if err := checkRawCodecDisabled(ctx); err != nil {
+		return nil, err
+	}
+
+	return handler(ctx, req)
+}
+
+// RawCodecDisabledStreamServerInterceptor rejects the streams that use the proto-raw content-subtype.
+func RawCodecDisabledStreamServerInterceptor(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if err := checkRawCodecDisabled(ss.Context()); err != nil {
+		return err
+	}
+
+	return handler(srv, ss)
+}
+
+func checkRawCodecDisabled(ctx context.Context) error {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil
+	}
+
+	for _, ct := range md.Get("content-type") {
+		if strings.EqualFold(ct, rawContentType) {
+			return status.Errorf(codes.Unimplemented, "content type %s is disabled: set server.advanced.grpc.enableRawCodec to true to enable it", rawContentType)
+		}
+	}
+
+	return nil
+}
+
+type CodecDirection string
+
+const (
+	CodecMarshal   CodecDirection = "marshal"
+	CodecUnmarshal CodecDirection = "unmarshal"
+)
+
+// CodecError is returned by the codec when a message cannot be serialized or deserialized.
+// Use errors.As to distinguish it from errors returned by the application.
+type CodecError struct {
+	Err         error
+	Direction   CodecDirection
+	MessageType string
+}
+
+func newCodecError(direction CodecDirection, v any, err error) *CodecError {
+	ce := &CodecError{Direction: direction, MessageType: fmt.Sprintf("%T", v), Err: err}
+
+	_ = stats.RecordWithTags(context.Background(),
+		[]tag.Mutator{tag.Upsert(metrics.KeyCodecDirection, string(direction)), tag.Upsert(metrics.KeyCodecMessageType, ce.MessageType)},
+		metrics.CodecErrorCount.M(1),
+	)
+
+	return ce
+}
+
+// messageSize returns the size of the message in the protobuf wire format without marshaling it, or zero if the size cannot be determined.
+func messageSize(v any) int {
+	switch m := v.(type) {
+	case interface{ SizeVT() int }:
+		return m.SizeVT()
+	case proto.Message:
+		return proto.Size(m)
+	case gogoMarshaler:
+		return m.Size()
+	default:
+		return 0
+	}
+}
+
+// recordFallback counts the messages that are not handled by VT so that the types lacking VT support can be identified.
+func recordFallback(direction CodecDirection, v any) {
+	_ = stats.RecordWithTags(context.Background(),
+		[]tag.Mutator{tag.Upsert(metrics.KeyCodecDirection, string(direction)), tag.Upsert(metrics.KeyCodecMessageType, fmt.Sprintf("%T", v))},
+		metrics.CodecFallbackCount.M(1),
+	)
+}
+
+func (ce *CodecError) Error() string {
+	return fmt.Sprintf("codec failed to %s %s: %v", ce.Direction, ce.MessageType, ce.Err)
+}
+
+func (ce *CodecError) Unwrap() error {
+	return ce.Err
+}
+
+// GRPCStatus converts the error to a gRPC status so that serialization failures are reported with a specific message.
+// Failing to unmarshal a request is the fault of the client, whereas failing to marshal a response is an internal error.
+// Messages exceeding the size limits are reported with the same code used by gRPC when it enforces the limits itself.
+func (ce *CodecError) GRPCStatus() *status.Status {
+	code := codes.Internal
+	if ce.Direction == CodecUnmarshal {
+		code = codes.InvalidArgument
+	}
+
+	var mse *MessageSizeError
+	if errors.As(ce.Err, &mse) {
+		code = codes.ResourceExhausted
+	}
+
+	return status.New(code, ce.Error())
+}
+
+// MessageSizeError is wrapped by CodecError when a message exceeds the configured size limit.
+// The size is zero if it is unknown.
+type MessageSizeError struct {
+	Direction CodecDirection
+	Size      int
+	Limit     int
+}
+
+func (mse *MessageSizeError) Error() string {
+	size := ""
+	if mse.Size > 0 {
+		size = fmt.Sprintf(" of %d bytes", mse.Size)
+	}
+
+	if mse.Direction == CodecMarshal {
+		return fmt.Sprintf("response%s exceeds the maximum of %d bytes: increase server.advanced.grpc.maxSendMsgSizeBytes to send larger responses", size, mse.Limit)
+	}
+
+	return fmt.Sprintf("request%s exceeds the maximum of %d bytes: split the request into smaller batches or increase server.advanced.grpc.maxRecvMsgSizeBytes", size, mse.Limit)
 }
@@ -0,0 +1,656 @@
+// Copyright 2021-2022 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opencensus.io/stats/view"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/encoding"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	effectv1 "github.com/cerbos/cerbos/api/genpb/cerbos/effect/v1"
+	enginev1 "github.com/cerbos/cerbos/api/genpb/cerbos/engine/v1"
+	policyv1 "github.com/cerbos/cerbos/api/genpb/cerbos/policy/v1"
+	requestv1 "github.com/cerbos/cerbos/api/genpb/cerbos/request/v1"
+	responsev1 "github.com/cerbos/cerbos/api/genpb/cerbos/response/v1"
+	"github.com/cerbos/cerbos/internal/observability/metrics"
+)
+
+func TestCodec(t *testing.T) {
+	c := Codec{}
+
+	t.Run("roundtrip", func(t *testing.T) {
+		in := &requestv1.CheckResourcesRequest{RequestId: "test"}
+		b, err := c.Marshal(in)
+		require.NoError(t, err)
+
+		out := &requestv1.CheckResourcesRequest{}
+		require.NoError(t, c.Unmarshal(b, out))
+		require.Equal(t, in.RequestId, out.RequestId)
+	})
+
+	t.Run("fallback", func(t *testing.T) {
+		in := structpb.NewStringValue("test")
+		b, err := c.Marshal(in)
+		require.NoError(t, err)
+
+		out := &structpb.Value{}
+		require.NoError(t, c.Unmarshal(b, out))
+		require.Equal(t, in.GetStringValue(), out.GetStringValue())
+	})
+
+	t.Run("fallback_metric", func(t *testing.T) {
+		require.NoError(t, view.Register(metrics.CodecFallbackCountView))
+		t.Cleanup(func() { view.Unregister(metrics.CodecFallbackCountView) })
+
+		b, err := c.Marshal(structpb.NewBoolValue(true))
+		require.NoError(t, err)
+		require.NoError(t, c.Unmarshal(b, &structpb.Value{}))
+
+		_, err = c.Marshal(&requestv1.CheckResourcesRequest{RequestId: "test"})
+		require.NoError(t, err)
+
+		require.Equal(t, map[string]int64{
+			"marshal:*structpb.Value":   1,
+			"unmarshal:*structpb.Value": 1,
+		}, fallbackCounts(t))
+	})
+
+	t.Run("marshal_error", func(t *testing.T) {
+		_, err := c.Marshal("not a message")
+
+		var ce *CodecError
+		require.True(t, errors.As(err, &ce))
+		require.Equal(t, CodecMarshal, ce.Direction)
+		require.Equal(t, "string", ce.MessageType)
+		require.Equal(t, codes.Internal, status.Code(err))
+	})
+
+	t.Run("unmarshal_error", func(t *testing.T) {
+		err := c.Unmarshal([]byte{0xff, 0xff}, &structpb.Value{})
+
+		var ce *CodecError
+		require.True(t, errors.As(err, &ce))
+		require.Equal(t, CodecUnmarshal, ce.Direction)
+		require.Equal(t, "*structpb.Value", ce.MessageType)
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
+
+// TestCodecVTMessages guards against messages losing their VT methods (for example, if the protobufs are regenerated
+// without the VT plugin) because the fallback is considerably slower.
+func TestCodecVTMessages(t *testing.T) {
+	require.NoError(t, view.Register(metrics.CodecFallbackCountView))
+	t.Cleanup(func() { view.Unregister(metrics.CodecFallbackCountView) })
+
+	c := Codec{}
+	for _, tc := range vtTestMessages() {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			b, err := c.Marshal(tc.msg)
+			require.NoError(t, err)
+
+			out := tc.msg.ProtoReflect().New().Interface()
+			require.NoError(t, c.Unmarshal(b, out))
+			require.True(t, proto.Equal(tc.msg, out), "roundtrip mismatch")
+		})
+	}
+
+	require.Empty(t, fallbackCounts(t), "messages should be handled by VT instead of the fallback")
+}
+
+// fallbackCounts returns the number of messages handled by the fallback, keyed by direction and message type.
+// The fallback count view must be registered by the caller.
+func fallbackCounts(t *testing.T) map[string]int64 {
+	t.Helper()
+
+	rows, err := view.RetrieveData(metrics.CodecFallbackCountView.Name)
+	require.NoError(t, err)
+
+	have := make(map[string]int64)
+	for _, row := range rows {
+		var direction, messageType string
+		for _, tg := range row.Tags {
+			switch tg.Key {
+			case metrics.KeyCodecDirection:
+				direction = tg.Value
+			case metrics.KeyCodecMessageType:
+				messageType = tg.Value
+			}
+		}
+
+		count, ok := row.Data.(*view.CountData)
+		require.True(t, ok)
+		have[direction+":"+messageType] = count.Value
+	}
+
+	return have
+}
+
+type vtTestMessage struct {
+	msg  proto.Message
+	name string
+}
+
+// vtTestMessages returns a representative set of the requests and responses handled by the server.
+func vtTestMessages() []vtTestMessage {
+	principal := &enginev1.Principal{
+		Id:            "john",
+		PolicyVersion: "default",
+		Roles:         []string{"employee", "manager"},
+		Attr: map[string]*structpb.Value{
+			"department": structpb.NewStringValue("marketing"),
+			"geography":  structpb.NewStringValue("GB"),
+			"team":       structpb.NewStringValue("design"),
+		},
+	}
+
+	auxData := &requestv1.AuxData{Jwt: &requestv1.AuxData_JWT{Token: "eyJhbGciOiJFUzM4NCIsImtpZCI6IjE5TGZaYXRFZGc4M1lOYzVyMjNndU1KcXJuND0iLCJ0eXAiOiJKV1QifQ.eyJhdWQiOlsiY2VyYm9zLWp3dC10ZXN0cyJdLCJjdXN0b21BcnJheSI6WyJBIiwiQiIsIkMiXX0.signature", KeySetId: "default"}}
+
+	resources := make([]*requestv1.CheckResourcesRequest_ResourceEntry, 10)
+	for i := range resources {
+		resources[i] = &requestv1.CheckResourcesRequest_ResourceEntry{
+			Actions: []string{"view:public", "approve", "create", "delete"},
+			Resource: &enginev1.Resource{
+				Kind:          "leave_request",
+				PolicyVersion: "default",
+				Id:            fmt.Sprintf("XX%03d", i),
+				Scope:         "acme.hr.uk",
+				Attr: map[string]*structpb.Value{
+					"owner":      structpb.NewStringValue("john"),
+					"department": structpb.NewStringValue("marketing"),
+					"days":       structpb.NewNumberValue(5),
+				},
+			},
+		}
+	}
+
+	return []vtTestMessage{
+		{
+			name: "CheckResourcesRequest",
+			msg:  &requestv1.CheckResourcesRequest{RequestId: "test", IncludeMeta: true, Principal: principal, Resources: resources, AuxData: auxData},
+		},
+		{
+			name: "CheckResourcesResponse",
+			msg:  checkResourcesResponse(len(resources)),
+		},
+		{
+			name: "PlanResourcesRequest",
+			msg: &requestv1.PlanResourcesRequest{
+				RequestId: "test",
+				Action:    "view",
+				Principal: principal,
+				Resource: &enginev1.PlanResourcesInput_Resource{
+					Kind:          "leave_request",
+					PolicyVersion: "default",
+					Attr:          map[string]*structpb.Value{"department": structpb.NewStringValue("marketing")},
+				},
+				AuxData: auxData,
+			},
+		},
+		{
+			name: "PlanResourcesResponse",
+			msg: &responsev1.PlanResourcesResponse{
+				RequestId:     "test",
+				Action:        "view",
+				ResourceKind:  "leave_request",
+				PolicyVersion: "default",
+				Filter:        &enginev1.PlanResourcesFilter{Kind: enginev1.PlanResourcesFilter_KIND_ALWAYS_ALLOWED},
+			},
+		},
+		{
+			name: "AddOrUpdatePolicyRequest",
+			msg: &requestv1.AddOrUpdatePolicyRequest{
+				Policies: []*policyv1.Policy{
+					{
+						ApiVersion: "api.cerbos.dev/v1",
+						PolicyType: &policyv1.Policy_ResourcePolicy{
+							ResourcePolicy: &policyv1.ResourcePolicy{
+								Resource: "leave_request",
+								Version:  "default",
+								Rules: []*policyv1.ResourceRule{
+									{Actions: []string{"view", "create"}, Roles: []string{"employee"}, Effect: effectv1.Effect_EFFECT_ALLOW},
+									{Actions: []string{"approve"}, Roles: []string{"manager"}, Effect: effectv1.Effect_EFFECT_ALLOW},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "ServerInfoResponse",
+			msg:  &responsev1.ServerInfoResponse{Version: "0.0.0", Commit: "unknown", BuildDate: "2022-01-01T00:00:00Z"},
+		},
+	}
+}
+
+func BenchmarkCodec(b *testing.B) {
+	c := Codec{}
+	for _, tc := range vtTestMessages() {
+		tc := tc
+		data, err := c.Marshal(tc.msg)
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		b.Run(tc.name+"/marshal", func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := c.Marshal(tc.msg); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.ReportMetric(float64(len(data)), "bytes/msg")
+		})
+
+		b.Run(tc.name+"/unmarshal", func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if err := c.Unmarshal(data, tc.msg.ProtoReflect().New().Interface()); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.ReportMetric(float64(len(data)), "bytes/msg")
+		})
+	}
+}
+
+func TestCodecLegacyMessages(t *testing.T) {
+	c := Codec{}
+	data := []byte{0x0a, 0x04, 't', 'e', 's', 't'}
+
+	t.Run("gogo", func(t *testing.T) {
+		in := &gogoTestMessage{legacyTestMessage: legacyTestMessage{data: data}}
+		b, err := c.Marshal(in)
+		require.NoError(t, err)
+		require.Equal(t, data, b)
+		require.Equal(t, "MarshalTo", in.calls[len(in.calls)-1], "MarshalTo should be used")
+		require.NotContains(t, in.calls, "Marshal", "MarshalTo should be preferred over Marshal")
+
+		out := &gogoTestMessage{}
+		require.NoError(t, c.Unmarshal(b, out))
+		require.Equal(t, []string{"Unmarshal"}, out.calls)
+		require.Equal(t, data, out.data)
+	})
+
+	t.Run("legacy", func(t *testing.T) {
+		in := &legacyTestMessage{data: data}
+		b, err := c.Marshal(in)
+		require.NoError(t, err)
+		require.Equal(t, data, b)
+		require.Equal(t, []string{"Marshal"}, in.calls)
+
+		out := &legacyTestMessage{}
+		require.NoError(t, c.Unmarshal(b, out))
+		require.Equal(t, []string{"Unmarshal"}, out.calls)
+		require.Equal(t, data, out.data)
+	})
+
+	t.Run("proto_preferred", func(t *testing.T) {
+		in := &protoTestMessage{Value: structpb.NewStringValue("test")}
+		b, err := c.Marshal(in)
+		require.NoError(t, err)
+		require.Empty(t, in.calls, "proto.Message should be preferred over the legacy interfaces")
+
+		out := &protoTestMessage{Value: &structpb.Value{}}
+		require.NoError(t, c.Unmarshal(b, out))
+		require.Empty(t, out.calls, "proto.Message should be preferred over the legacy interfaces")
+		require.Equal(t, "test", out.GetStringValue())
+	})
+
+	t.Run("legacy_marshal_error", func(t *testing.T) {
+		_, err := c.Marshal(&legacyTestMessage{err: errors.New("boom")})
+
+		var ce *CodecError
+		require.True(t, errors.As(err, &ce))
+		require.Equal(t, "*server.legacyTestMessage", ce.MessageType)
+		require.ErrorContains(t, err, "boom")
+	})
+}
+
+// legacyTestMessage implements the legacy marshaling interfaces and records the methods called by the codec.
+type legacyTestMessage struct {
+	err   error
+	calls []string
+	data  []byte
+}
+
+func (m *legacyTestMessage) Marshal() ([]byte, error) {
+	m.calls = append(m.calls, "Marshal")
+	return m.data, m.err
+}
+
+func (m *legacyTestMessage) Unmarshal(data []byte) error {
+	m.calls = append(m.calls, "Unmarshal")
+	m.data = append([]byte(nil), data...)
+	return m.err
+}
+
+// gogoTestMessage implements the interfaces of a message generated by gogoproto with the marshaler plugin.
+type gogoTestMessage struct {
+	legacyTestMessage
+}
+
+func (m *gogoTestMessage) Size() int {
+	m.calls = append(m.calls, "Size")
+	return len(m.data)
+}
+
+func (m *gogoTestMessage) MarshalTo(data []byte) (int, error) {
+	m.calls = append(m.calls, "MarshalTo")
+	return copy(data, m.data), m.err
+}
+
+// protoTestMessage is a proto.Message that also implements the legacy interfaces.
+type protoTestMessage struct {
+	*structpb.Value
+	legacyTestMessage
+}
+
+func TestCodecMessageSizeLimits(t *testing.T) {
+	msg := &requestv1.CheckResourcesRequest{RequestId: strings.Repeat("x", 1024)}
+	size := msg.SizeVT()
+
+	testCases := []struct {
+		codec encoding.Codec
+		name  string
+		limit int
+	}{
+		{name: "proto", codec: NewCodec(NewMessageSizeLimits(size, size)), limit: size},
+		{name: "json", codec: NewJSONCodec(NewMessageSizeLimits(size+64, size+64)), limit: size + 64},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Run("within_limit", func(t *testing.T) {
+				b, err := tc.codec.Marshal(msg)
+				require.NoError(t, err)
+				require.NoError(t, tc.codec.Unmarshal(b, &requestv1.CheckResourcesRequest{}))
+			})
+
+			t.Run("unmarshal_just_over_limit", func(t *testing.T) {
+				err := tc.codec.Unmarshal(make([]byte, tc.limit+1), &requestv1.CheckResourcesRequest{})
+				requireMessageSizeError(t, err, CodecUnmarshal, tc.limit+1)
+				require.Contains(t, err.Error(), "maxRecvMsgSizeBytes")
+			})
+
+			t.Run("marshal_over_limit", func(t *testing.T) {
+				big := &requestv1.CheckResourcesRequest{RequestId: msg.RequestId + strings.Repeat("x", 128)}
+				_, err := tc.codec.Marshal(big)
+				requireMessageSizeError(t, err, CodecMarshal, -1)
+				require.Contains(t, err.Error(), "maxSendMsgSizeBytes")
+			})
+		})
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		c := NewCodec(NewMessageSizeLimits(0, 0))
+		b, err := c.Marshal(msg)
+		require.NoError(t, err)
+		require.NoError(t, c.Unmarshal(b, &requestv1.CheckResourcesRequest{}))
+	})
+}
+
+func TestMessageSizeLimitsInterceptor(t *testing.T) {
+	big := &responsev1.CheckResourcesResponse{RequestId: strings.Repeat("x", 1024)}
+	limits := NewMessageSizeLimits(512, big.SizeVT()-1)
+	interceptor := limits.StreamServerInterceptor()
+	info := &grpc.StreamServerInfo{FullMethod: "/cerbos.svc.v1.CerbosAdminService/ListAuditLogEntries"}
+
+	run := func(err error, handler func(grpc.ServerStream) error) error {
+		return interceptor(nil, &erroringServerStream{err: err}, info, func(_ any, ss grpc.ServerStream) error { return handler(ss) })
+	}
+
+	t.Run("send_over_limit", func(t *testing.T) {
+		err := run(status.Error(codes.ResourceExhausted, "grpc: trying to send message larger than max"), func(ss grpc.ServerStream) error {
+			return ss.SendMsg(big)
+		})
+		requireMessageSizeError(t, err, CodecMarshal, big.SizeVT())
+		require.Contains(t, err.Error(), "maxSendMsgSizeBytes")
+	})
+
+	t.Run("recv_over_limit", func(t *testing.T) {
+		err := run(status.Error(codes.ResourceExhausted, "grpc: received message larger than max"), func(ss grpc.ServerStream) error {
+			return ss.RecvMsg(&requestv1.ListAuditLogEntriesRequest{})
+		})
+		requireMessageSizeError(t, err, CodecUnmarshal, 0)
+		require.Contains(t, err.Error(), "maxRecvMsgSizeBytes")
+	})
+
+	t.Run("other_errors", func(t *testing.T) {
+		wantErr := status.Error(codes.Unavailable, "transport is closing")
+		err := run(wantErr, func(ss grpc.ServerStream) error { return ss.SendMsg(big) })
+		require.Equal(t, wantErr, err)
+
+		require.NoError(t, run(nil, func(ss grpc.ServerStream) error { return ss.SendMsg(big) }))
+	})
+}
+
+// erroringServerStream returns the given error from SendMsg and RecvMsg.
+type erroringServerStream struct {
+	grpc.ServerStream
+	err error
+}
+
+func (s *erroringServerStream) SendMsg(any) error {
+	return s.err
+}
+
+func (s *erroringServerStream) RecvMsg(any) error {
+	return s.err
+}
+
+func TestRawCodec(t *testing.T) {
+	msg := &requestv1.CheckResourcesRequest{RequestId: "test"}
+	data, err := msg.MarshalVT()
+	require.NoError(t, err)
+
+	t.Run("passthrough", func(t *testing.T) {
+		c := NewRawCodec(nil)
+
+		b, err := c.Marshal(data)
+		require.NoError(t, err)
+		require.Equal(t, data, b)
+
+		raw := RawMessage(data)
+		b, err = c.Marshal(&raw)
+		require.NoError(t, err)
+		require.Equal(t, data, b)
+
+		var out RawMessage
+		require.NoError(t, c.Unmarshal(data, &out))
+		require.Equal(t, RawMessage(data), out)
+
+		var outBytes []byte
+		require.NoError(t, c.Unmarshal(data, &outBytes))
+		require.Equal(t, data, outBytes)
+
+		outBytes[0]++
+		require.NotEqual(t, data[0], outBytes[0], "Unmarshaled bytes should not alias the input")
+	})
+
+	t.Run("messages", func(t *testing.T) {
+		c := NewRawCodec(nil)
+
+		b, err := c.Marshal(msg)
+		require.NoError(t, err)
+
+		out := &requestv1.CheckResourcesRequest{}
+		require.NoError(t, c.Unmarshal(b, out))
+		require.Equal(t, msg.RequestId, out.RequestId)
+	})
+
+	t.Run("message_size_limits", func(t *testing.T) {
+		c := NewRawCodec(NewMessageSizeLimits(len(data)-1, len(data)-1))
+
+		_, err := c.Marshal(data)
+		requireMessageSizeError(t, err, CodecMarshal, len(data))
+
+		var out RawMessage
+		requireMessageSizeError(t, c.Unmarshal(data, &out), CodecUnmarshal, len(data))
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		info := &grpc.UnaryServerInfo{FullMethod: "/cerbos.svc.v1.CerbosService/CheckResources"}
+		handler := func(context.Context, any) (any, error) { return msg, nil }
+
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("content-type", "application/grpc+proto-raw"))
+		_, err := RawCodecDisabledUnaryServerInterceptor(ctx, msg, info, handler)
+		require.Equal(t, codes.Unimplemented, status.Code(err))
+		require.Contains(t, err.Error(), "enableRawCodec")
+
+		ctx = metadata.NewIncomingContext(context.Background(), metadata.Pairs("content-type", "application/grpc+proto"))
+		resp, err := RawCodecDisabledUnaryServerInterceptor(ctx, msg, info, handler)
+		require.NoError(t, err)
+		require.Same(t, msg, resp)
+	})
+
+	t.Run("registered", func(t *testing.T) {
+		require.Equal(t, rawName, encoding.GetCodec(rawName).Name())
+	})
+}
+
+func requireMessageSizeError(t *testing.T, err error, direction CodecDirection, wantSize int) {
+	t.Helper()
+
+	var mse *MessageSizeError
+	require.True(t, errors.As(err, &mse))
+	require.Equal(t, direction, mse.Direction)
+	if wantSize == 0 {
+		require.Zero(t, mse.Size, "Size should be unknown")
+	} else {
+		require.Greater(t, mse.Size, mse.Limit)
+	}
+	if wantSize > 0 {
+		require.Equal(t, wantSize, mse.Size)
+	}
+	require.Equal(t, codes.ResourceExhausted, status.Code(err))
+}
+
+func TestJSONCodec(t *testing.T) {
+	c := JSONCodec{}
+
+	t.Run("registered", func(t *testing.T) {
+		require.IsType(t, Codec{}, encoding.GetCodec("proto"))
+		require.IsType(t, JSONCodec{}, encoding.GetCodec("json"))
+	})
+
+	t.Run("roundtrip", func(t *testing.T) {
+		in := &requestv1.CheckResourcesRequest{RequestId: "test"}
+		b, err := c.Marshal(in)
+		require.NoError(t, err)
+		require.JSONEq(t, `{"requestId":"test"}`, string(b))
+
+		out := &requestv1.CheckResourcesRequest{}
+		require.NoError(t, c.Unmarshal(b, out))
+		require.Equal(t, in.RequestId, out.RequestId)
+	})
+
+	t.Run("marshal_error", func(t *testing.T) {
+		_, err := c.Marshal("not a message")
+		require.Equal(t, codes.Internal, status.Code(err))
+	})
+
+	t.Run("unmarshal_error", func(t *testing.T) {
+		err := c.Unmarshal([]byte(`{"wibble": 1}`), &requestv1.CheckResourcesRequest{})
+
+		var ce *CodecError
+		require.True(t, errors.As(err, &ce))
+		require.Equal(t, CodecUnmarshal, ce.Direction)
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
+
+func BenchmarkCodecCompression(b *testing.B) {
+	const numResources = 500
+
+	resp := checkResourcesResponse(numResources)
+
+	c := Codec{}
+	gz := encoding.GetCompressor("gzip")
+	if gz == nil {
+		b.Fatal("gzip compressor is not registered")
+	}
+
+	b.Run("uncompressed", func(b *testing.B) {
+		var size int
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			data, err := c.Marshal(resp)
+			if err != nil {
+				b.Fatal(err)
+			}
+			size = len(data)
+		}
+		b.ReportMetric(float64(size), "bytes/msg")
+	})
+
+	b.Run("gzip", func(b *testing.B) {
+		var buf bytes.Buffer
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			data, err := c.Marshal(resp)
+			if err != nil {
+				b.Fatal(err)
+			}
+
+			buf.Reset()
+			w, err := gz.Compress(&buf)
+			if err != nil {
+				b.Fatal(err)
+			}
+
+			if _, err := w.Write(data); err != nil {
+				b.Fatal(err)
+			}
+
+			if err := w.Close(); err != nil {
+				b.Fatal(err)
+			}
+		}
+		b.ReportMetric(float64(buf.Len()), "bytes/msg")
+	})
+}
+
+func checkResourcesResponse(numResources int) *responsev1.CheckResourcesResponse {
+	resp := &responsev1.CheckResourcesResponse{RequestId: "bench", Results: make([]*responsev1.CheckResourcesResponse_ResultEntry, numResources)}
+	for i := 0; i < numResources; i++ {
+		resp.Results[i] = &responsev1.CheckResourcesResponse_ResultEntry{
+			Resource: &responsev1.CheckResourcesResponse_ResultEntry_Resource{
+				Id:            fmt.Sprintf("XX%03d", i),
+				Kind:          "leave_request",
+				PolicyVersion: "default",
+				Scope:         "acme.hr.uk",
+			},
+			Actions: map[string]effectv1.Effect{
+				"view:public": effectv1.Effect_EFFECT_ALLOW,
+				"approve":     effectv1.Effect_EFFECT_DENY,
+				"create":      effectv1.Effect_EFFECT_ALLOW,
+				"delete":      effectv1.Effect_EFFECT_DENY,
+			},
+			Meta: &responsev1.CheckResourcesResponse_ResultEntry_Meta{
+				Actions: map[string]*responsev1.CheckResourcesResponse_ResultEntry_Meta_EffectMeta{
+					"view:public": {MatchedPolicy: "resource.leave_request.vdefault/acme.hr.uk", MatchedScope: "acme.hr"},
+					"approve":     {MatchedPolicy: "resource.leave_request.vdefault/acme.hr.uk", MatchedScope: "acme"},
+				},
+			},
+		}
+	}
+
+	return resp
+}
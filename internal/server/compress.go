@@ -0,0 +1,82 @@
+// Copyright 2021-2022 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"bytes"
+	"io"
+
+	"google.golang.org/grpc/encoding"
+	"google.golang.org/grpc/encoding/gzip"
+)
+
+// minCompressedSize is the minimum size of a message compressed by the registered gzip compressor.
+// The CPU overhead of compressing smaller messages outweighs the savings.
+const minCompressedSize = 1024
+
+func init() {
+	// Replace the gzip compressor registered by gRPC with one that leaves small messages uncompressed.
+	// The compressor is shared by every gRPC server and client in the process. Leaving a message uncompressed is always
+	// valid, so clients that opt into gzip compression are not affected by the threshold.
+	encoding.RegisterCompressor(thresholdCompressor{Compressor: encoding.GetCompressor(gzip.Name), minSize: minCompressedSize})
+}
+
+// thresholdCompressor only compresses messages of at least the minimum size.
+//
+// gRPC v1.50 has no API to choose the compressor of a response: the server compresses every response if the client
+// sent a gzip-compressed request, and compressors are registered for the whole process. Each message carries its own
+// compression flag though, and gRPC sets the flag to uncompressed when the compressor doesn't write any output. The
+// compressor therefore buffers the message and only compresses it on Close if it is large enough.
+// TestThresholdCompressorWireFormat checks the flag on the wire because gRPC doesn't document this behaviour.
+type thresholdCompressor struct {
+	encoding.Compressor
+	minSize int
+}
+
+func (tc thresholdCompressor) Compress(w io.Writer) (io.WriteCloser, error) {
+	if tc.minSize <= 0 {
+		return tc.Compressor.Compress(w)
+	}
+
+	return &thresholdWriter{out: w, compressor: tc.Compressor, minSize: tc.minSize}, nil
+}
+
+// DecompressedSize lets gRPC check the size limit of a compressed message before decompressing it, if the wrapped
+// compressor supports it.
+func (tc thresholdCompressor) DecompressedSize(buf []byte) int {
+	if sizer, ok := tc.Compressor.(interface{ DecompressedSize([]byte) int }); ok {
+		return sizer.DecompressedSize(buf)
+	}
+
+	return -1
+}
+
+type thresholdWriter struct {
+	out        io.Writer
+	compressor encoding.Compressor
+	buf        bytes.Buffer
+	minSize    int
+}
+
+func (tw *thresholdWriter) Write(p []byte) (int, error) {
+	return tw.buf.Write(p)
+}
+
+func (tw *thresholdWriter) Close() error {
+	if tw.buf.Len() < tw.minSize {
+		return nil
+	}
+
+	z, err := tw.compressor.Compress(tw.out)
+	if err != nil {
+		return err
+	}
+
+	if _, err := z.Write(tw.buf.Bytes()); err != nil {
+		_ = z.Close()
+		return err
+	}
+
+	return z.Close()
+}
@@ -0,0 +1,184 @@
+// Copyright 2021-2022 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/http2"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/encoding"
+	"google.golang.org/grpc/encoding/gzip"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestThresholdCompressor(t *testing.T) {
+	t.Run("registered", func(t *testing.T) {
+		require.IsType(t, thresholdCompressor{}, encoding.GetCompressor(gzip.Name))
+		require.Equal(t, gzip.Name, encoding.GetCompressor(gzip.Name).Name())
+	})
+
+	gz := encoding.GetCompressor(gzip.Name).(thresholdCompressor).Compressor //nolint:forcetypeassert
+
+	compress := func(t *testing.T, c encoding.Compressor, msg []byte) []byte {
+		t.Helper()
+
+		var buf bytes.Buffer
+		w, err := c.Compress(&buf)
+		require.NoError(t, err)
+		_, err = w.Write(msg)
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+
+		return buf.Bytes()
+	}
+
+	c := thresholdCompressor{Compressor: gz, minSize: 64}
+
+	t.Run("below_threshold", func(t *testing.T) {
+		// gRPC sends the message uncompressed if the compressor doesn't write any output
+		require.Empty(t, compress(t, c, []byte(strings.Repeat("x", 63))))
+	})
+
+	t.Run("above_threshold", func(t *testing.T) {
+		msg := []byte(strings.Repeat("x", 1024))
+		compressed := compress(t, c, msg)
+		require.NotEmpty(t, compressed)
+		require.Less(t, len(compressed), len(msg))
+
+		r, err := c.Decompress(bytes.NewReader(compressed))
+		require.NoError(t, err)
+		have, err := io.ReadAll(r)
+		require.NoError(t, err)
+		require.Equal(t, msg, have)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		require.NotEmpty(t, compress(t, thresholdCompressor{Compressor: gz}, []byte("x")))
+	})
+}
+
+// TestThresholdCompressorWireFormat checks the compression flag of the messages sent by a server to a client that
+// requested gzip compression. It relies on gRPC setting the flag to uncompressed when the compressor doesn't write any
+// output, which is not part of the documented API.
+func TestThresholdCompressorWireFormat(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	rec := &recordingListener{Listener: lis}
+	srv := grpc.NewServer()
+	srv.RegisterService(&echoServiceDesc, nil)
+	go func() { _ = srv.Serve(rec) }()
+
+	conn, err := grpc.Dial(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+
+	small := strings.Repeat("x", minCompressedSize/2)
+	large := strings.Repeat("x", minCompressedSize*4)
+	for _, msg := range []string{small, large} {
+		out := &wrapperspb.StringValue{}
+		require.NoError(t, conn.Invoke(context.Background(), "/cerbos.test.Echo/Echo", wrapperspb.String(msg), out, grpc.UseCompressor(gzip.Name)))
+		require.Equal(t, msg, out.Value)
+	}
+
+	require.NoError(t, conn.Close())
+	// stop the server to make sure that all the frames have been written before reading them
+	srv.Stop()
+
+	// each RPC uses a new stream, so the responses are the messages sent on the first and second client-initiated streams
+	messages := rec.grpcMessages(t)
+	require.Len(t, messages, 2)
+
+	const compressionFlagNone, compressionFlagMade = 0, 1
+	require.Equal(t, byte(compressionFlagNone), messages[1][0], "Small response should be uncompressed")
+	require.Equal(t, byte(compressionFlagMade), messages[3][0], "Large response should be compressed")
+}
+
+var echoServiceDesc = grpc.ServiceDesc{
+	ServiceName: "cerbos.test.Echo",
+	HandlerType: (*any)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Echo",
+			Handler: func(_ any, _ context.Context, dec func(any) error, _ grpc.UnaryServerInterceptor) (any, error) {
+				in := &wrapperspb.StringValue{}
+				if err := dec(in); err != nil {
+					return nil, err
+				}
+				return in, nil
+			},
+		},
+	},
+}
+
+// recordingListener records the bytes written by the server to the accepted connections.
+type recordingListener struct {
+	net.Listener
+	mu      sync.Mutex
+	written bytes.Buffer
+}
+
+func (rl *recordingListener) Accept() (net.Conn, error) {
+	c, err := rl.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+
+	return &recordingConn{Conn: c, rl: rl}, nil
+}
+
+// grpcMessages decodes the HTTP/2 frames written by the server and returns the headers of the gRPC messages keyed by stream ID.
+// A gRPC message header consists of the compression flag followed by the length of the message as a 4-byte big-endian integer.
+func (rl *recordingListener) grpcMessages(t *testing.T) map[uint32][]byte {
+	t.Helper()
+
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	data := make(map[uint32][]byte)
+	framer := http2.NewFramer(io.Discard, bytes.NewReader(rl.written.Bytes()))
+	for {
+		frame, err := framer.ReadFrame()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		require.NoError(t, err)
+
+		if df, ok := frame.(*http2.DataFrame); ok && len(df.Data()) > 0 {
+			data[df.StreamID] = append(data[df.StreamID], df.Data()...)
+		}
+	}
+
+	headers := make(map[uint32][]byte, len(data))
+	for streamID, d := range data {
+		require.GreaterOrEqual(t, len(d), 5)
+		require.Equal(t, len(d)-5, int(binary.BigEndian.Uint32(d[1:5])), "Stream %d should contain a single message", streamID)
+		headers[streamID] = d[:5]
+	}
+
+	return headers
+}
+
+type recordingConn struct {
+	net.Conn
+	rl *recordingListener
+}
+
+func (rc *recordingConn) Write(p []byte) (int, error) {
+	rc.rl.mu.Lock()
+	rc.rl.written.Write(p)
+	rc.rl.mu.Unlock()
+
+	return rc.Conn.Write(p)
+}
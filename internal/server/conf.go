@@ -7,6 +7,7 @@ import (
 	"encoding/base64"
 	"errors"
 	"fmt"
+	"math"
 	"strconv"
 	"time"
 
@@ -153,12 +154,20 @@ type AdvancedHTTPConf struct {
 }
 
 type AdvancedGRPCConf struct {
-	// MaxRecvMsgSizeBytes sets the maximum size of a single request message. Defaults to 4MiB. Affects performance and resource utilisation.
-	MaxRecvMsgSizeBytes uint `yaml:"maxRecvMsgSizeBytes" conf:",example=4194304"`
+	// MaxRecvMsgSizeBytes sets the maximum size of a single request message as a number of bytes or a size with a unit such as 4MiB. Defaults to 4MiB. Affects performance and resource utilisation.
+	MaxRecvMsgSizeBytes config.ByteSize `yaml:"maxRecvMsgSizeBytes" conf:",example=4MiB"`
+	// MaxSendMsgSizeBytes sets the maximum size of a single response message as a number of bytes or a size with a unit such as 4MiB. Defaults to the gRPC limit of 2GiB. Responses exceeding the limit fail with a ResourceExhausted error.
+	MaxSendMsgSizeBytes config.ByteSize `yaml:"maxSendMsgSizeBytes" conf:",example=4MiB"`
 	// MaxConnectionAge sets the maximum age of a connection.
 	MaxConnectionAge time.Duration `yaml:"maxConnectionAge" conf:",example=600s"`
 	// ConnectionTimeout sets the timeout for establishing a new connection.
 	ConnectionTimeout time.Duration `yaml:"connectionTimeout" conf:",example=60s"`
+	// EnableRawCodec accepts requests using the proto-raw content-subtype, which passes pre-serialized messages through without re-encoding them. Intended for proxies that forward already-serialized messages. Requests using the content-subtype are rejected if it is disabled.
+	EnableRawCodec bool `yaml:"enableRawCodec" conf:",example=false"`
+}
+
+func init() {
+	config.RegisterSection(&Conf{})
 }
 
 func (c *Conf) Key() string {
@@ -212,6 +221,14 @@ func (c *Conf) Validate() (errs error) {
 		errs = multierr.Append(errs, fmt.Errorf("invalid udsFileMode %q", c.UDSFileMode))
 	}
 
+	if c.Advanced.GRPC.MaxRecvMsgSizeBytes == 0 || c.Advanced.GRPC.MaxRecvMsgSizeBytes > math.MaxInt32 {
+		errs = multierr.Append(errs, fmt.Errorf("maxRecvMsgSizeBytes must be between 1 and %d", math.MaxInt32))
+	}
+
+	if c.Advanced.GRPC.MaxSendMsgSizeBytes > math.MaxInt32 {
+		errs = multierr.Append(errs, fmt.Errorf("maxSendMsgSizeBytes must not be greater than %d", math.MaxInt32))
+	}
+
 	if c.RequestLimits.MaxActionsPerResource < 1 || c.RequestLimits.MaxActionsPerResource > requestItemsMax {
 		errs = multierr.Append(errs, fmt.Errorf("maxActionsPerResource must be between 1 and %d", requestItemsMax))
 	}
@@ -120,6 +120,81 @@ func TestConfigValidate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "maxRecvMsgSizeBytes is zero",
+			conf: map[string]any{
+				"server": map[string]any{
+					"httpListenAddr": ":6666",
+					"grpcListenAddr": ":6667",
+					"advanced": map[string]any{
+						"grpc": map[string]any{
+							"maxRecvMsgSizeBytes": "0",
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "maxSendMsgSizeBytes is too large",
+			conf: map[string]any{
+				"server": map[string]any{
+					"httpListenAddr": ":6666",
+					"grpcListenAddr": ":6667",
+					"advanced": map[string]any{
+						"grpc": map[string]any{
+							"maxSendMsgSizeBytes": "4294967296",
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "message size limits",
+			conf: map[string]any{
+				"server": map[string]any{
+					"httpListenAddr": ":6666",
+					"grpcListenAddr": ":6667",
+					"advanced": map[string]any{
+						"grpc": map[string]any{
+							"maxRecvMsgSizeBytes": "16777216",
+							"maxSendMsgSizeBytes": "16777216",
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "message size limits with units",
+			conf: map[string]any{
+				"server": map[string]any{
+					"httpListenAddr": ":6666",
+					"grpcListenAddr": ":6667",
+					"advanced": map[string]any{
+						"grpc": map[string]any{
+							"maxRecvMsgSizeBytes": "16MiB",
+							"maxSendMsgSizeBytes": "1GB",
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "maxRecvMsgSizeBytes with unknown unit",
+			conf: map[string]any{
+				"server": map[string]any{
+					"httpListenAddr": ":6666",
+					"grpcListenAddr": ":6667",
+					"advanced": map[string]any{
+						"grpc": map[string]any{
+							"maxRecvMsgSizeBytes": "16XB",
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tc := range testCases {
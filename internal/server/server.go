@@ -407,6 +407,8 @@ func checkForUnsafeAdminCredentials(log *zap.Logger, passwordHash []byte) {
 func (s *Server) mkGRPCServer(log *zap.Logger, auditLog audit.Log) (*grpc.Server, error) {
 	payloadLog := zap.L().Named("payload")
 	telemetryInt := telemetry.Intercept()
+	// the limits are owned by this server because the registered codecs are shared with the gRPC clients in the process
+	msgSizeLimits := NewMessageSizeLimits(int(s.conf.Advanced.GRPC.MaxRecvMsgSizeBytes), int(s.conf.Advanced.GRPC.MaxSendMsgSizeBytes))
 
 	auditInterceptor, err := audit.NewUnaryInterceptor(auditLog, accessLogExclude)
 	if err != nil {
@@ -424,6 +426,7 @@ func (s *Server) mkGRPCServer(log *zap.Logger, auditLog audit.Log) (*grpc.Server
 				grpc_zap.WithMessageProducer(messageProducer),
 			),
 			grpc_zap.PayloadStreamServerInterceptor(payloadLog, payloadLoggingDecider(s.conf)),
+			msgSizeLimits.StreamServerInterceptor(),
 		),
 		grpc.ChainUnaryInterceptor(
 			grpc_recovery.UnaryServerInterceptor(),
@@ -445,6 +448,17 @@ func (s *Server) mkGRPCServer(log *zap.Logger, auditLog audit.Log) (*grpc.Server
 		grpc.UnknownServiceHandler(handleUnknownServices),
 	}
 
+	if maxSend := s.conf.Advanced.GRPC.MaxSendMsgSizeBytes; maxSend > 0 {
+		opts = append(opts, grpc.MaxSendMsgSize(int(maxSend)))
+	}
+
+	if !s.conf.Advanced.GRPC.EnableRawCodec {
+		opts = append(opts,
+			grpc.ChainStreamInterceptor(RawCodecDisabledStreamServerInterceptor),
+			grpc.ChainUnaryInterceptor(RawCodecDisabledUnaryServerInterceptor),
+		)
+	}
+
 	return grpc.NewServer(opts...), nil
 }
 
@@ -561,6 +575,13 @@ func (s *Server) mkGRPCConn(ctx context.Context) (*grpc.ClientConn, error) {
 		opts = append(opts, grpc.WithTransportCredentials(local.NewCredentials()))
 	}
 
+	// the gateway must be able to send requests and receive responses as large as the gRPC server allows
+	callOpts := []grpc.CallOption{grpc.MaxCallSendMsgSize(int(s.conf.Advanced.GRPC.MaxRecvMsgSizeBytes))}
+	if maxSend := s.conf.Advanced.GRPC.MaxSendMsgSizeBytes; maxSend > 0 {
+		callOpts = append(callOpts, grpc.MaxCallRecvMsgSize(int(maxSend)))
+	}
+	opts = append(opts, grpc.WithDefaultCallOptions(callOpts...))
+
 	grpcConn, err := grpc.DialContext(ctx, s.conf.GRPCListenAddr, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to dial gRPC: %w", err)
@@ -16,6 +16,7 @@ import (
 	"go.uber.org/multierr"
 	"go.uber.org/zap"
 	"gocloud.dev/blob"
+	"gocloud.dev/gcerrors"
 
 	"github.com/cerbos/cerbos/internal/util"
 )
@@ -35,15 +36,17 @@ type Cloner struct {
 	bucket *blob.Bucket
 	fsys   clonerFS
 	info   infoType // map[path]eTag
+	layout util.DirLayout
 }
 
 // NewCloner creates an object to clone the bucket and saves
-// supported files in the fsys.
-func NewCloner(bucket *blob.Bucket, fsys clonerFS) (*Cloner, error) {
+// the files that are indexed according to the given layout in the fsys.
+func NewCloner(bucket *blob.Bucket, fsys clonerFS, layout util.DirLayout) (*Cloner, error) {
 	c := &Cloner{
 		bucket: bucket,
 		log:    zap.S().Named("blob.cloner"),
 		fsys:   fsys,
+		layout: layout,
 	}
 
 	info, err := c.calculateInfo()
@@ -88,7 +91,7 @@ func (c *Cloner) Clone(ctx context.Context) (*CloneResult, error) {
 		}
 		file := strings.TrimPrefix(obj.Key, "/")
 		eTag := obj.MD5
-		if util.FileType(file) == util.FileTypeNotIndexed {
+		if !c.layout.FileType(file).IsIndexed() {
 			continue
 		}
 		info[file] = eTag
@@ -163,3 +166,19 @@ func (c *Cloner) calculateInfo() (infoType, error) {
 
 	return result, nil
 }
+
+// loadIgnoreMatcher reads the patterns from the ignore file at the root of the bucket.
+// It returns nil if the file does not exist.
+func loadIgnoreMatcher(ctx context.Context, bucket *blob.Bucket) (*util.IgnoreMatcher, error) {
+	r, err := bucket.NewReader(ctx, util.IgnoreFileName, nil)
+	if err != nil {
+		if gcerrors.Code(err) == gcerrors.NotFound {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("failed to read %s: %w", util.IgnoreFileName, err)
+	}
+	defer r.Close()
+
+	return util.ReadIgnoreMatcher(r)
+}
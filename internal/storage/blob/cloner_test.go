@@ -8,6 +8,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/require"
+
+	"github.com/cerbos/cerbos/internal/util"
 )
 
 func TestCloneResult(t *testing.T) {
@@ -18,7 +20,7 @@ func TestCloneResult(t *testing.T) {
 	ctx := context.Background()
 	dir := t.TempDir()
 	bucket := newMinioBucket(ctx, t, "policies")
-	cloner, err := NewCloner(bucket, storeFS{dir})
+	cloner, err := NewCloner(bucket, storeFS{dir}, util.DefaultDirLayout)
 	is.NoError(err)
 	result, err := cloner.Clone(ctx)
 	is.NoError(err)
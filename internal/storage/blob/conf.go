@@ -27,6 +27,8 @@ const (
 // Conf is required (if driver is set to 'blob') configuration for cloud storage driver.
 // +desc=This section is required only if storage.driver is blob.
 type Conf struct {
+	// DirLayout configures the names of the special directories containing schemas and test fixtures.
+	DirLayout *storage.DirLayoutConf `yaml:"dirLayout,omitempty" conf:",example=\n  schemasDirectory: _schemas\n  testDataDirectory: testdata"`
 	// DownloadTimeout specifies the timeout for downloading from cloud storage.
 	DownloadTimeout *time.Duration `yaml:"downloadTimeout,omitempty" conf:",example=30s"`
 	// RequestTimeout specifies the timeout for an HTTP request.
@@ -52,6 +54,10 @@ func (conf *Conf) Validate() error {
 		errs = append(errs, errors.New("bucket is required"))
 	}
 
+	if err := conf.DirLayout.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+
 	if conf.WorkDir == "" {
 		cacheDir, err := os.UserCacheDir()
 		if err != nil {
@@ -62,7 +62,18 @@ func init() {
 			return nil, err
 		}
 
-		c, err := NewCloner(bucket, storeFS{dir: conf.WorkDir})
+		ignoreCtx, cancelFunc := conf.getCloneCtx(ctx)
+		defer cancelFunc()
+
+		ignore, err := loadIgnoreMatcher(ignoreCtx, bucket)
+		if err != nil {
+			return nil, err
+		}
+
+		layout := conf.DirLayout.DirLayout()
+		layout.Ignore = ignore
+
+		c, err := NewCloner(bucket, storeFS{dir: conf.WorkDir}, layout)
 		if err != nil {
 			return nil, err
 		}
@@ -160,6 +171,7 @@ type Store struct {
 	idx    index.Index
 	cloner bucketCloner
 	fsys   fs.FS
+	layout util.DirLayout
 }
 
 func (s *Store) Subscribe(sub storage.Subscriber) {
@@ -171,6 +183,7 @@ func NewStore(ctx context.Context, conf *Conf, cloner bucketCloner) (*Store, err
 		log:                 zap.S().Named(DriverName).With("bucket", conf.Bucket, "workDir", conf.WorkDir),
 		conf:                conf,
 		cloner:              cloner,
+		layout:              conf.DirLayout.DirLayout(),
 		SubscriptionManager: storage.NewSubscriptionManager(ctx),
 	}
 
@@ -196,7 +209,7 @@ func (s *Store) init(ctx context.Context) error {
 	}
 
 	var err error
-	s.idx, err = index.Build(ctx, s.fsys, index.WithRootDir("."))
+	s.idx, err = index.Build(ctx, s.fsys, index.WithRootDir("."), index.WithDirLayout(s.layout))
 	if err != nil {
 		s.log.Errorw("Failed to build index", "error", err)
 		return err
@@ -236,7 +249,7 @@ func (s *Store) updateIndex(ctx context.Context) error {
 	var p *policyv1.Policy
 	var event storage.Event
 	for _, f := range changes.updateOrAdd {
-		if schemaFile, ok := util.RelativeSchemaPath(f); ok {
+		if schemaFile, ok := s.layout.RelativeSchemaPath(f); ok {
 			s.NotifySubscribers(storage.NewSchemaEvent(storage.EventAddOrUpdateSchema, schemaFile))
 			continue
 		}
@@ -254,7 +267,7 @@ func (s *Store) updateIndex(ctx context.Context) error {
 	}
 
 	for _, f := range changes.delete {
-		if schemaFile, ok := util.RelativeSchemaPath(f); ok {
+		if schemaFile, ok := s.layout.RelativeSchemaPath(f); ok {
 			s.NotifySubscribers(storage.NewSchemaEvent(storage.EventDeleteSchema, schemaFile))
 			continue
 		}
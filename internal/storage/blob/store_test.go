@@ -19,6 +19,7 @@ import (
 	"github.com/cerbos/cerbos/internal/storage/index"
 	"github.com/cerbos/cerbos/internal/storage/internal"
 	"github.com/cerbos/cerbos/internal/test"
+	"github.com/cerbos/cerbos/internal/util"
 )
 
 var keysInStore []string
@@ -68,7 +69,7 @@ func TestNewStore(t *testing.T) {
 
 		bucket, err := newBucket(ctx, conf)
 		must.NoError(err)
-		cloner, err := NewCloner(bucket, storeFS{dir})
+		cloner, err := NewCloner(bucket, storeFS{dir}, util.DefaultDirLayout)
 		must.NoError(err)
 		_, err = NewStore(ctx, conf, cloner)
 		must.NoError(err)
@@ -119,7 +120,7 @@ func mkStore(t *testing.T, dir string) (*Store, *blob.Bucket) {
 	conf := mkConf(t, dir, bucketName, endpoint)
 	bucket, err := newBucket(context.Background(), conf)
 	require.NoError(t, err)
-	cloner, err := NewCloner(bucket, storeFS{dir})
+	cloner, err := NewCloner(bucket, storeFS{dir}, util.DefaultDirLayout)
 	require.NoError(t, err)
 	store, err := NewStore(context.Background(), conf, cloner)
 	require.NoError(t, err)
@@ -222,7 +223,7 @@ func TestStore_AWSS3(t *testing.T) {
 
 	bucket, err := newBucket(ctx, conf)
 	must.NoError(err)
-	cloner, err := NewCloner(bucket, storeFS{dir})
+	cloner, err := NewCloner(bucket, storeFS{dir}, util.DefaultDirLayout)
 	must.NoError(err)
 	_, err = NewStore(ctx, conf, cloner)
 	must.NoError(err)
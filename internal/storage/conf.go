@@ -25,6 +25,10 @@ type confHolder struct {
 	Driver string `yaml:"driver" conf:"required,example=\"disk\""`
 }
 
+func init() {
+	config.RegisterSection(&Conf{})
+}
+
 func (c *Conf) Key() string {
 	return ConfKey
 }
@@ -0,0 +1,45 @@
+// Copyright 2021-2022 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package storage
+
+import (
+	"fmt"
+
+	"github.com/cerbos/cerbos/internal/util"
+)
+
+// DirLayoutConf holds the names of the special directories of a policy repository.
+type DirLayoutConf struct {
+	// SchemasDirectory is the name of the top-level directory containing schemas. Defaults to _schemas.
+	SchemasDirectory string `yaml:"schemasDirectory"`
+	// TestDataDirectory is the name of the directories containing test fixtures. Defaults to testdata.
+	TestDataDirectory string `yaml:"testDataDirectory"`
+}
+
+// DirLayout returns the layout with the configured directory names. Directories that are not configured keep their default names.
+func (dc *DirLayoutConf) DirLayout() util.DirLayout {
+	layout := util.DefaultDirLayout
+	if dc == nil {
+		return layout
+	}
+
+	if dc.SchemasDirectory != "" {
+		layout.SchemasDirectory = dc.SchemasDirectory
+	}
+
+	if dc.TestDataDirectory != "" {
+		layout.TestDataDirectory = dc.TestDataDirectory
+	}
+
+	return layout
+}
+
+// Validate checks that the configured directory names are valid.
+func (dc *DirLayoutConf) Validate() error {
+	if err := dc.DirLayout().Validate(); err != nil {
+		return fmt.Errorf("invalid dirLayout: %w", err)
+	}
+
+	return nil
+}
@@ -13,6 +13,8 @@ const confKey = storage.ConfKey + ".disk"
 // Conf is required (if driver is set to 'disk') configuration for disk storage driver.
 // +desc=This section is required only if storage.driver is disk.
 type Conf struct {
+	// DirLayout configures the names of the special directories containing schemas and test fixtures.
+	DirLayout *storage.DirLayoutConf `yaml:"dirLayout,omitempty" conf:",example=\n  schemasDirectory: _schemas\n  testDataDirectory: testdata"`
 	// Directory is the path on disk where policies are stored.
 	Directory string `yaml:"directory" conf:"required,example=pkg/test/testdata/store"`
 	// [DEPRECATED] ScratchDir is the directory to use for holding temporary data.
@@ -25,6 +27,10 @@ func (conf *Conf) Key() string {
 	return confKey
 }
 
+func (conf *Conf) Validate() error {
+	return conf.DirLayout.Validate()
+}
+
 func GetConf() (*Conf, error) {
 	conf := &Conf{}
 	err := config.GetSection(conf)
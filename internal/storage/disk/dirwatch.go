@@ -32,7 +32,7 @@ const (
 	defaultCooldownPeriod = 2 * time.Second
 )
 
-func watchDir(ctx context.Context, dir string, idx index.Index, sub *storage.SubscriptionManager, cooldownPeriod time.Duration) error {
+func watchDir(ctx context.Context, dir string, layout util.DirLayout, idx index.Index, sub *storage.SubscriptionManager, cooldownPeriod time.Duration) error {
 	resolved, err := filepath.EvalSymlinks(dir)
 	if err != nil {
 		return fmt.Errorf("could not resolve %s: %w", dir, err)
@@ -40,6 +40,7 @@ func watchDir(ctx context.Context, dir string, idx index.Index, sub *storage.Sub
 
 	dw := &dirWatch{
 		dir:                 resolved,
+		layout:              layout,
 		log:                 zap.S().Named("dir.watch").With("dir", dir),
 		idx:                 idx,
 		SubscriptionManager: sub,
@@ -65,6 +66,7 @@ type dirWatch struct {
 	eventBatch    map[string]struct{}
 	*storage.SubscriptionManager
 	dir            string
+	layout         util.DirLayout
 	cooldownPeriod time.Duration
 	mu             sync.RWMutex
 }
@@ -104,7 +106,7 @@ func (dw *dirWatch) processEvent(evtInfo notify.EventInfo) {
 
 	path = filepath.ToSlash(path)
 
-	if util.FileType(path) != util.FileTypeNotIndexed {
+	if dw.layout.FileType(path).IsIndexed() {
 		dw.mu.Lock()
 		dw.eventBatch[path] = struct{}{}
 		dw.lastEventTime = time.Now()
@@ -136,7 +138,7 @@ func (dw *dirWatch) triggerUpdate() {
 
 			if _, err := os.Stat(fullPath); errors.Is(err, os.ErrNotExist) {
 				dw.log.Debugw("Detected file removal", "file", f)
-				if sf, ok := util.RelativeSchemaPath(f); ok {
+				if sf, ok := dw.layout.RelativeSchemaPath(f); ok {
 					dw.NotifySubscribers(storage.NewSchemaEvent(storage.EventDeleteSchema, sf))
 					continue
 				}
@@ -153,7 +155,7 @@ func (dw *dirWatch) triggerUpdate() {
 			}
 
 			dw.log.Debugw("Detected file update", "file", f)
-			if sf, ok := util.RelativeSchemaPath(f); ok {
+			if sf, ok := dw.layout.RelativeSchemaPath(f); ok {
 				dw.NotifySubscribers(storage.NewSchemaEvent(storage.EventAddOrUpdateSchema, sf))
 				continue
 			}
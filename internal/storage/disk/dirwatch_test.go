@@ -20,6 +20,7 @@ import (
 	"github.com/cerbos/cerbos/internal/storage/index"
 	"github.com/cerbos/cerbos/internal/test"
 	"github.com/cerbos/cerbos/internal/test/mocks"
+	"github.com/cerbos/cerbos/internal/util"
 )
 
 const (
@@ -40,7 +41,7 @@ func TestDirWatch(t *testing.T) {
 		mockIdx := &mocks.Index{}
 		dir := t.TempDir()
 
-		require.NoError(t, watchDir(ctx, dir, mockIdx, subMgr, cooldownPeriod))
+		require.NoError(t, watchDir(ctx, dir, util.DefaultDirLayout, mockIdx, subMgr, cooldownPeriod))
 
 		haveEntries := make(chan index.Entry, 8)
 		mockIdx.On("AddOrUpdate", mock.Anything).Return(func(entry index.Entry) storage.Event {
@@ -70,6 +71,38 @@ func TestDirWatch(t *testing.T) {
 		checkEvents(t, timeOut, wantEvent)
 	})
 
+	t.Run("add_ignored_file", func(t *testing.T) {
+		ctx, cancelFunc := context.WithCancel(context.Background())
+		defer cancelFunc()
+
+		subMgr := storage.NewSubscriptionManager(ctx)
+		mockIdx := &mocks.Index{}
+		dir := t.TempDir()
+		require.NoError(t, os.Mkdir(filepath.Join(dir, "vendor"), 0o744))
+
+		ignore, err := util.NewIgnoreMatcher([]string{"vendor/"})
+		require.NoError(t, err)
+
+		layout := util.DefaultDirLayout
+		layout.Ignore = ignore
+		require.NoError(t, watchDir(ctx, dir, layout, mockIdx, subMgr, cooldownPeriod))
+
+		haveEntries := make(chan index.Entry, 8)
+		mockIdx.On("AddOrUpdate", mock.Anything).Return(func(entry index.Entry) storage.Event {
+			haveEntries <- entry
+			return storage.Event{Kind: storage.EventAddOrUpdatePolicy, PolicyID: entry.Policy.ID}
+		}, nil)
+
+		rp := policy.Wrap(test.GenResourcePolicy(test.NoMod()))
+		writePolicy(t, filepath.Join(dir, "vendor", "policy.yaml"), rp.Policy)
+
+		select {
+		case <-time.After(timeOut): // Wait time for events to be published.
+		case have := <-haveEntries:
+			require.Failf(t, "ignored file was indexed", "file: %s", have.File)
+		}
+	})
+
 	t.Run("delete_file", func(t *testing.T) {
 		// Add some files
 		dir := t.TempDir()
@@ -87,7 +120,7 @@ func TestDirWatch(t *testing.T) {
 		subMgr := storage.NewSubscriptionManager(ctx)
 		mockIdx := &mocks.Index{}
 
-		require.NoError(t, watchDir(ctx, dir, mockIdx, subMgr, cooldownPeriod))
+		require.NoError(t, watchDir(ctx, dir, util.DefaultDirLayout, mockIdx, subMgr, cooldownPeriod))
 
 		haveEntries := make(chan index.Entry, 8)
 		mockIdx.On("Delete", mock.Anything).Return(func(entry index.Entry) storage.Event {
@@ -124,7 +157,7 @@ func TestDirWatch(t *testing.T) {
 		dir := t.TempDir()
 		require.NoError(t, os.Mkdir(filepath.Join(dir, schema.Directory), 0o744))
 
-		require.NoError(t, watchDir(ctx, dir, mockIdx, subMgr, cooldownPeriod))
+		require.NoError(t, watchDir(ctx, dir, util.DefaultDirLayout, mockIdx, subMgr, cooldownPeriod))
 
 		checkEvents := storage.TestSubscription(subMgr)
 
@@ -146,7 +179,7 @@ func TestDirWatch(t *testing.T) {
 		schemaFile := filepath.Join(dir, schema.Directory, "test.json")
 		touch(t, schemaFile)
 
-		require.NoError(t, watchDir(ctx, dir, mockIdx, subMgr, cooldownPeriod))
+		require.NoError(t, watchDir(ctx, dir, util.DefaultDirLayout, mockIdx, subMgr, cooldownPeriod))
 
 		checkEvents := storage.TestSubscription(subMgr)
 
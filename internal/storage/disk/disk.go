@@ -7,7 +7,6 @@ import (
 	"context"
 	"fmt"
 	"io"
-	"os"
 	"path/filepath"
 
 	"github.com/cerbos/cerbos/internal/config"
@@ -15,6 +14,7 @@ import (
 	"github.com/cerbos/cerbos/internal/policy"
 	"github.com/cerbos/cerbos/internal/storage"
 	"github.com/cerbos/cerbos/internal/storage/index"
+	"github.com/cerbos/cerbos/internal/util"
 )
 
 const DriverName = "disk"
@@ -47,7 +47,13 @@ func NewStore(ctx context.Context, conf *Conf) (*Store, error) {
 		return nil, fmt.Errorf("failed to determine absolute path of directory [%s]: %w", conf.Directory, err)
 	}
 
-	idx, err := index.Build(ctx, os.DirFS(dir))
+	fsys := util.RootedDirFS(dir)
+	layout, err := conf.DirLayout.DirLayout().WithIgnoreFile(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	idx, err := index.Build(ctx, fsys, index.WithDirLayout(layout))
 	if err != nil {
 		return nil, err
 	}
@@ -58,7 +64,7 @@ func NewStore(ctx context.Context, conf *Conf) (*Store, error) {
 		SubscriptionManager: storage.NewSubscriptionManager(ctx),
 	}
 	if conf.WatchForChanges {
-		if err := watchDir(ctx, dir, s.idx, s.SubscriptionManager, defaultCooldownPeriod); err != nil {
+		if err := watchDir(ctx, dir, layout, s.idx, s.SubscriptionManager, defaultCooldownPeriod); err != nil {
 			return nil, err
 		}
 	}
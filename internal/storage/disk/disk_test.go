@@ -14,8 +14,11 @@ import (
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/cerbos/cerbos/internal/storage"
+	"github.com/cerbos/cerbos/internal/storage/index"
 	"github.com/cerbos/cerbos/internal/storage/internal"
 	"github.com/cerbos/cerbos/internal/test"
+	"github.com/cerbos/cerbos/internal/util"
 )
 
 func TestReloadable(t *testing.T) {
@@ -25,6 +28,46 @@ func TestReloadable(t *testing.T) {
 	internal.TestSuiteReloadable(store, mkAddFn(t, storeDir), mkDeleteFn(t, storeDir))(t)
 }
 
+func TestSymlinkEscapingDirectory(t *testing.T) {
+	outside := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(outside, "policy.yaml"), []byte("apiVersion: api.cerbos.dev/v1\n"), 0o600))
+
+	storeDir := t.TempDir()
+	if err := os.Symlink(filepath.Join(outside, "policy.yaml"), filepath.Join(storeDir, "policy.yaml")); err != nil {
+		t.Skipf("Symbolic links are not supported: %v", err)
+	}
+
+	_, err := NewStore(context.Background(), &Conf{Directory: storeDir})
+	require.Error(t, err)
+
+	buildErr := new(index.BuildError)
+	require.ErrorAs(t, err, &buildErr)
+	require.Len(t, buildErr.LoadFailures, 1)
+	require.Equal(t, "policy.yaml", buildErr.LoadFailures[0].File)
+	require.Contains(t, buildErr.LoadFailures[0].Error, util.ErrPathEscapesRoot.Error())
+}
+
+func TestDirLayout(t *testing.T) {
+	storeDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(storeDir, "cerbos_schemas"), 0o744))
+	require.NoError(t, os.WriteFile(filepath.Join(storeDir, "cerbos_schemas", "principal.json"), []byte("{}"), 0o600))
+	require.NoError(t, os.MkdirAll(filepath.Join(storeDir, "fixtures"), 0o744))
+	require.NoError(t, os.WriteFile(filepath.Join(storeDir, "fixtures", "principals.yaml"), []byte("principals: {}\n"), 0o600))
+
+	conf := &Conf{
+		Directory: storeDir,
+		DirLayout: &storage.DirLayoutConf{SchemasDirectory: "cerbos_schemas", TestDataDirectory: "fixtures"},
+	}
+	require.NoError(t, conf.Validate())
+
+	store, err := NewStore(context.Background(), conf)
+	require.NoError(t, err)
+
+	schemaIDs, err := store.ListSchemaIDs(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []string{"principal.json"}, schemaIDs)
+}
+
 func mkStore(t *testing.T, dir string) *Store {
 	t.Helper()
 
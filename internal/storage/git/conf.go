@@ -33,6 +33,8 @@ type Conf struct {
 	SSH *SSHAuth `yaml:"ssh,omitempty"`
 	// HTTPS holds auth details for the HTTPS protocol.
 	HTTPS *HTTPSAuth `yaml:"https,omitempty"`
+	// DirLayout configures the names of the special directories containing schemas and test fixtures.
+	DirLayout *storage.DirLayoutConf `yaml:"dirLayout,omitempty" conf:",example=\n  schemasDirectory: _schemas\n  testDataDirectory: testdata"`
 	// OperationTimeout specifies the timeout for git operations.
 	OperationTimeout *time.Duration `yaml:"operationTimeout,omitempty" conf:",example=60s"`
 	// Protocol is the Git protocol to use. Valid values are https, ssh, and file.
@@ -107,6 +109,10 @@ func (conf *Conf) Validate() error {
 		errs = append(errs, errors.New("git URL is required"))
 	}
 
+	if err := conf.DirLayout.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+
 	if conf.CheckoutDir == "" {
 		cacheDir, err := os.UserCacheDir()
 		if err != nil {
@@ -50,11 +50,12 @@ func init() {
 }
 
 type Store struct {
-	log  *zap.SugaredLogger
-	conf *Conf
-	idx  index.Index
-	repo *git.Repository
-	sf   singleflight.Group
+	log    *zap.SugaredLogger
+	conf   *Conf
+	idx    index.Index
+	repo   *git.Repository
+	layout util.DirLayout
+	sf     singleflight.Group
 	*storage.SubscriptionManager
 }
 
@@ -228,12 +229,19 @@ func (s *Store) loadAll(ctx context.Context) error {
 		policyDir = s.conf.SubDir
 	}
 
-	idx, err := index.Build(ctx, os.DirFS(s.conf.CheckoutDir), index.WithRootDir(policyDir))
+	fsys := os.DirFS(s.conf.CheckoutDir)
+	layout, err := s.conf.DirLayout.DirLayout().WithIgnoreFile(fsys, policyDir)
+	if err != nil {
+		return err
+	}
+
+	idx, err := index.Build(ctx, fsys, index.WithRootDir(policyDir), index.WithDirLayout(layout))
 	if err != nil {
 		return err
 	}
 
 	s.idx = idx
+	s.layout = layout
 
 	return nil
 }
@@ -449,9 +457,9 @@ func (s *Store) normalizePath(path string) (string, util.IndexedFileType) {
 		path = relativePath
 	}
 
-	fileType := util.FileType(path)
+	fileType := s.layout.FileType(path)
 	if fileType == util.FileTypeSchema {
-		path, _ = util.RelativeSchemaPath(path)
+		path, _ = s.layout.RelativeSchemaPath(path)
 	}
 
 	return path, fileType
@@ -7,7 +7,7 @@ import (
 	"context"
 	"fmt"
 	"io/fs"
-	"path"
+	"strings"
 
 	"go.opencensus.io/stats"
 	"go.uber.org/zap"
@@ -18,7 +18,6 @@ import (
 	"github.com/cerbos/cerbos/internal/namer"
 	"github.com/cerbos/cerbos/internal/observability/metrics"
 	"github.com/cerbos/cerbos/internal/policy"
-	"github.com/cerbos/cerbos/internal/schema"
 	"github.com/cerbos/cerbos/internal/util"
 )
 
@@ -37,6 +36,7 @@ func (ibe *BuildError) Error() string {
 
 type buildOptions struct {
 	rootDir              string
+	dirLayout            util.DirLayout
 	buildFailureLogLevel zapcore.Level
 }
 
@@ -54,10 +54,19 @@ func WithRootDir(rootDir string) BuildOpt {
 	}
 }
 
+// WithDirLayout sets the names of the special directories that are excluded from the index.
+// If the layout does not have an ignore matcher, the patterns are read from the ignore file in the root directory.
+func WithDirLayout(layout util.DirLayout) BuildOpt {
+	return func(o *buildOptions) {
+		o.dirLayout = layout
+	}
+}
+
 func mkBuildOpts(opts ...BuildOpt) buildOptions {
 	o := buildOptions{
 		buildFailureLogLevel: zap.ErrorLevel,
 		rootDir:              ".",
+		dirLayout:            util.DefaultDirLayout,
 	}
 
 	for _, optFn := range opts {
@@ -77,9 +86,14 @@ func build(ctx context.Context, fsys fs.FS, opts buildOptions) (Index, error) {
 		return nil, err
 	}
 
+	layout, err := opts.dirLayout.WithIgnoreFile(fsys, opts.rootDir)
+	if err != nil {
+		return nil, err
+	}
+
 	ib := newIndexBuilder()
 
-	err := fs.WalkDir(fsys, opts.rootDir, func(filePath string, d fs.DirEntry, err error) error {
+	err = fs.WalkDir(fsys, opts.rootDir, func(filePath string, d fs.DirEntry, err error) error {
 		if err := ctx.Err(); err != nil {
 			return err
 		}
@@ -89,9 +103,10 @@ func build(ctx context.Context, fsys fs.FS, opts buildOptions) (Index, error) {
 		}
 
 		if d.IsDir() {
-			if filePath == path.Join(opts.rootDir, schema.Directory) ||
-				d.Name() == util.TestDataDirectory ||
-				util.IsHidden(d.Name()) {
+			if (filePath != opts.rootDir && layout.IsSchemasDirectory(relativePath(opts.rootDir, filePath))) ||
+				d.Name() == layout.TestDataDirectory ||
+				util.IsHidden(d.Name()) ||
+				(filePath != opts.rootDir && layout.Ignore.Match(relativePath(opts.rootDir, filePath), true)) {
 				return fs.SkipDir
 			}
 
@@ -100,12 +115,14 @@ func build(ctx context.Context, fsys fs.FS, opts buildOptions) (Index, error) {
 
 		if !util.IsSupportedFileType(d.Name()) ||
 			util.IsSupportedTestFile(d.Name()) ||
-			util.IsHidden(d.Name()) {
+			util.IsHidden(d.Name()) ||
+			layout.Ignore.Match(relativePath(opts.rootDir, filePath), false) {
 			return nil
 		}
 
 		p := &policyv1.Policy{}
-		if err := util.LoadFromJSONOrYAML(fsys, filePath, p); err != nil {
+		// catch misnamed files (e.g. YAML in a .json file) even though the contents could be loaded
+		if err := util.LoadFromJSONOrYAML(fsys, filePath, p, util.WithFileFormatCheck()); err != nil {
 			ib.addLoadFailure(filePath, err)
 			return nil
 		}
@@ -263,7 +280,7 @@ func (idx *indexBuilder) build(fsys fs.FS, opts buildOptions) (*index, error) {
 		dependents:   idx.dependents,
 		dependencies: idx.dependencies,
 		buildOpts:    opts,
-		schemaLoader: NewSchemaLoader(fsys, opts.rootDir),
+		schemaLoader: newSchemaLoader(fsys, opts.dirLayout.ResolveSchemasDirectory(fsys, opts.rootDir)),
 		stats:        idx.stats.collate(),
 	}, nil
 }
@@ -304,6 +321,15 @@ func logBuildFailure(logger *zap.Logger, level zapcore.Level, err *BuildError) {
 	ce.Write(fields...)
 }
 
+// relativePath returns the given path relative to the root directory.
+func relativePath(root, filePath string) string {
+	if root == "." || root == "" {
+		return filePath
+	}
+
+	return strings.TrimPrefix(filePath, strings.TrimSuffix(root, "/")+"/")
+}
+
 func checkValidDir(fsys fs.FS, dir string) error {
 	finfo, err := fs.Stat(fsys, dir)
 	if err != nil {
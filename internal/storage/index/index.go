@@ -22,6 +22,7 @@ import (
 	"github.com/cerbos/cerbos/internal/observability/metrics"
 	"github.com/cerbos/cerbos/internal/policy"
 	"github.com/cerbos/cerbos/internal/storage"
+	"github.com/cerbos/cerbos/internal/util"
 	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
 )
 
@@ -154,7 +155,7 @@ func (idx *index) loadPolicy(id namer.ModuleID) (*policyv1.Policy, error) {
 		return nil, fmt.Errorf("policy id %q does not exist: %w", id.String(), ErrPolicyNotFound)
 	}
 
-	f, err := idx.fsys.Open(fileName)
+	f, err := util.SafeOpen(idx.fsys, fileName)
 	if err != nil {
 		return nil, err
 	}
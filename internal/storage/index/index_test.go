@@ -10,6 +10,7 @@ import (
 	"context"
 	"os"
 	"testing"
+	"testing/fstest"
 
 	"github.com/stretchr/testify/require"
 	"google.golang.org/protobuf/types/known/wrapperspb"
@@ -99,3 +100,57 @@ func TestIndexListSchemaIDs(t *testing.T) {
 		"resources/salary_record.json",
 	}, ids)
 }
+
+func TestIndexWithDirLayout(t *testing.T) {
+	ctx := context.Background()
+	policyData := []byte(`---
+apiVersion: api.cerbos.dev/v1
+principalPolicy:
+  principal: donald_duck
+  version: default
+  rules:
+    - resource: salary_record
+      actions:
+        - action: "*"
+          effect: EFFECT_DENY
+`)
+
+	fsys := fstest.MapFS{
+		"policies/policy.yaml":               {Data: policyData},
+		"policies/fixtures/principals.yaml":  {Data: []byte("principals: {}\n")},
+		"policies/schemas/principal.json":    {Data: []byte("{}\n")},
+		"policies/testdata/not_fixture.yaml": {Data: []byte("not a policy\n")},
+	}
+
+	_, err := index.Build(ctx, fsys, index.WithRootDir("policies"))
+	require.Error(t, err, "Files in the relocated directories should be indexed as policies with the default layout")
+
+	layout := util.DirLayout{SchemasDirectory: "schemas", TestDataDirectory: "fixtures"}
+	_, err = index.Build(ctx, fsys, index.WithRootDir("policies"), index.WithDirLayout(layout))
+	require.Error(t, err, "Files in the default testdata directory should be indexed as policies with a custom layout")
+
+	delete(fsys, "policies/testdata/not_fixture.yaml")
+	idx, err := index.Build(ctx, fsys,
+		index.WithRootDir("policies"),
+		index.WithDirLayout(layout),
+	)
+	require.NoError(t, err)
+
+	ids, err := idx.ListSchemaIDs(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []string{"principal.json"}, ids)
+}
+
+func TestIndexWithIgnoreFile(t *testing.T) {
+	ctx := context.Background()
+	fsys := fstest.MapFS{
+		"policies/.cerbosignore":         {Data: []byte("vendor/\n*.draft.yaml\n")},
+		"policies/vendor/example.yaml":   {Data: []byte("not a policy\n")},
+		"policies/policy.draft.yaml":     {Data: []byte("not a policy\n")},
+		"policies/_schemas/example.json": {Data: []byte("{}\n")},
+	}
+
+	idx, err := index.Build(ctx, fsys, index.WithRootDir("policies"))
+	require.NoError(t, err)
+	require.Empty(t, idx.GetFiles())
+}
@@ -20,7 +20,10 @@ type SchemaLoader struct {
 }
 
 func NewSchemaLoader(fsys fs.FS, rootDir string) *SchemaLoader {
-	schemaDir := filepath.Join(rootDir, schema.Directory)
+	return newSchemaLoader(fsys, filepath.Join(rootDir, schema.Directory))
+}
+
+func newSchemaLoader(fsys fs.FS, schemaDir string) *SchemaLoader {
 	schemaFS, err := fs.Sub(fsys, schemaDir)
 	if err != nil {
 		return &SchemaLoader{err: err}
@@ -62,5 +65,5 @@ func (sl *SchemaLoader) Load(_ context.Context, id string) (io.ReadCloser, error
 		return nil, sl.err
 	}
 
-	return sl.fsys.Open(id)
+	return util.SafeOpen(sl.fsys, id)
 }
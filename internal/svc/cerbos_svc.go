@@ -54,7 +54,7 @@ func (cs *CerbosService) PlanResources(ctx context.Context, request *requestv1.P
 	auxData, err := cs.auxData.Extract(ctx, request.AuxData)
 	if err != nil {
 		log.Error("Failed to extract auxData", zap.Error(err))
-		return nil, status.Error(codes.InvalidArgument, "failed to extract auxData")
+		return nil, auxDataError(err)
 	}
 
 	input := &enginev1.PlanResourcesInput{
@@ -107,7 +107,7 @@ func (cs *CerbosService) CheckResourceSet(ctx context.Context, req *requestv1.Ch
 	auxData, err := cs.auxData.Extract(ctx, req.AuxData)
 	if err != nil {
 		log.Error("Failed to extract auxData", zap.Error(err))
-		return nil, status.Error(codes.InvalidArgument, "failed to extract auxData")
+		return nil, auxDataError(err)
 	}
 
 	inputs := make([]*enginev1.CheckInput, len(req.Resource.Instances))
@@ -161,7 +161,7 @@ func (cs *CerbosService) CheckResourceBatch(ctx context.Context, req *requestv1.
 	auxData, err := cs.auxData.Extract(ctx, req.AuxData)
 	if err != nil {
 		log.Error("Failed to extract auxData", zap.Error(err))
-		return nil, status.Error(codes.InvalidArgument, "failed to extract auxData")
+		return nil, auxDataError(err)
 	}
 
 	inputs := make([]*enginev1.CheckInput, len(req.Resources))
@@ -218,7 +218,7 @@ func (cs *CerbosService) CheckResources(ctx context.Context, req *requestv1.Chec
 	auxData, err := cs.auxData.Extract(ctx, req.AuxData)
 	if err != nil {
 		log.Error("Failed to extract auxData", zap.Error(err))
-		return nil, status.Error(codes.InvalidArgument, "failed to extract auxData")
+		return nil, auxDataError(err)
 	}
 
 	inputs := make([]*enginev1.CheckInput, len(req.Resources))
@@ -284,6 +284,27 @@ func (cs *CerbosService) CheckResources(ctx context.Context, req *requestv1.Chec
 	return result, nil
 }
 
+// auxDataError converts an auxData extraction failure to a gRPC status that reflects the reason for the failure.
+func auxDataError(err error) error {
+	for _, reason := range []struct {
+		err  error
+		code codes.Code
+	}{
+		{err: auxdata.ErrJWTExpired, code: codes.Unauthenticated},
+		{err: auxdata.ErrJWTBadSignature, code: codes.Unauthenticated},
+		{err: auxdata.ErrJWTAlgorithmNotAllowed, code: codes.Unauthenticated},
+		{err: auxdata.ErrJWTMalformed, code: codes.InvalidArgument},
+		{err: auxdata.ErrJWTUnknownKeySet, code: codes.InvalidArgument},
+		{err: auxdata.ErrJWTKeySetUnavailable, code: codes.Unavailable},
+	} {
+		if errors.Is(err, reason.err) {
+			return status.Errorf(reason.code, "failed to extract auxData: %v", reason.err)
+		}
+	}
+
+	return status.Error(codes.InvalidArgument, "failed to extract auxData")
+}
+
 func (cs *CerbosService) checkNumResourcesLimit(n int) error {
 	if n > int(cs.reqLimits.MaxResourcesPerRequest) {
 		return status.Errorf(codes.InvalidArgument,
@@ -3,7 +3,11 @@
 
 package telemetry
 
-import "time"
+import (
+	"time"
+
+	"github.com/cerbos/cerbos/internal/config"
+)
 
 const (
 	confKey               = "telemetry"
@@ -20,6 +24,10 @@ type Conf struct {
 	ReportInterval time.Duration `yaml:"reportInterval" conf:",example=1h"`
 }
 
+func init() {
+	config.RegisterSection(&Conf{})
+}
+
 func (c *Conf) Key() string {
 	return confKey
 }
@@ -0,0 +1,239 @@
+// Copyright 2021-2022 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package util
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultMaxArchiveSize is the maximum uncompressed size of the archives read by ZipFS and TarGzFS.
+const DefaultMaxArchiveSize = 1024 * 1024 * 64 // 64MiB
+
+// ZipFS opens the zip archive at the given path and presents its contents as a file system.
+// Paths are "/"-separated and relative to the root of the archive, so the file system can be used with FileType and the other
+// helpers of this package just like a directory. Directories that don't have their own entries in the archive are synthesized.
+// The returned file system also implements io.Closer, which closes the underlying archive.
+// Because bundles might come from untrusted sources, ErrFileTooLarge is returned if an entry is larger than DefaultMaxFileSize
+// (or the limit set using WithMaxBytes) or if the uncompressed archive is larger than DefaultMaxArchiveSize.
+func ZipFS(path string, opts ...LoadOpt) (fs.FS, error) {
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open zip archive %s: %w", path, err)
+	}
+
+	if err := checkZipSize(zr, mkLoadOpts(opts...).maxBytes); err != nil {
+		_ = zr.Close()
+		return nil, fmt.Errorf("failed to open zip archive %s: %w", path, err)
+	}
+
+	return zr, nil
+}
+
+// checkZipSize checks the uncompressed sizes recorded in the headers of the archive entries against the limits.
+// Checking the headers is enough because reading an entry fails if it decompresses to more than its recorded size.
+func checkZipSize(zr *zip.ReadCloser, maxBytes int64) error {
+	var total uint64
+	for _, f := range zr.File {
+		if f.UncompressedSize64 > uint64(maxBytes) {
+			return fmt.Errorf("%w: %s is larger than the maximum size of %d bytes", ErrFileTooLarge, f.Name, maxBytes)
+		}
+
+		total += f.UncompressedSize64
+		if total > DefaultMaxArchiveSize {
+			return fmt.Errorf("%w: the maximum archive size is %d bytes", ErrFileTooLarge, DefaultMaxArchiveSize)
+		}
+	}
+
+	return nil
+}
+
+// TarGzFS reads the gzip-compressed tar archive (such as an OCI image layer) from the given reader into memory
+// and presents its contents as a file system. Like ZipFS, paths are "/"-separated and relative to the root of the archive.
+// The modes and modification times of the entries are preserved and missing parent directories are synthesized.
+// Entries other than regular files and directories (such as symbolic links) are ignored.
+// The same size limits as ZipFS apply.
+func TarGzFS(r io.Reader, opts ...LoadOpt) (fs.FS, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open gzip stream: %w", err)
+	}
+	defer gz.Close()
+
+	maxBytes := mkLoadOpts(opts...).maxBytes
+	fsys := memFS{".": {name: ".", mode: fs.ModeDir | 0o755}} //nolint:gomnd
+	tr := tar.NewReader(newMaxBytesReader(gz, DefaultMaxArchiveSize))
+	for {
+		hdr, err := tr.Next()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return fsys, nil
+			}
+			return nil, fmt.Errorf("failed to read tar archive: %w", err)
+		}
+
+		name, ok := archivePath(hdr.Name)
+		if !ok {
+			return nil, fmt.Errorf("invalid path in tar archive: %q", hdr.Name)
+		}
+
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if name == "." {
+				continue
+			}
+
+			if err := fsys.add(name, &memEntry{mode: fs.ModeDir | hdr.FileInfo().Mode().Perm(), modTime: hdr.ModTime}); err != nil {
+				return nil, err
+			}
+		case tar.TypeReg:
+			data, err := io.ReadAll(newMaxBytesReader(tr, maxBytes))
+			if err != nil {
+				return nil, fmt.Errorf("failed to read %s from tar archive: %w", hdr.Name, err)
+			}
+
+			if err := fsys.add(name, &memEntry{mode: hdr.FileInfo().Mode().Perm(), modTime: hdr.ModTime, data: data}); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+// archivePath converts the name of an archive entry to a path that is valid in a file system.
+// Absolute paths are treated as relative to the root of the archive and paths that escape the root are rejected.
+func archivePath(name string) (string, bool) {
+	p := path.Clean(strings.TrimLeft(name, "/"))
+	return p, fs.ValidPath(p)
+}
+
+// memFS is an in-memory, read-only file system keyed by the path of each entry.
+type memFS map[string]*memEntry
+
+func (m memFS) add(name string, entry *memEntry) error {
+	dir, base := path.Split(name)
+	dir = strings.TrimSuffix(dir, "/")
+	if dir == "" {
+		dir = "."
+	}
+
+	parent, ok := m[dir]
+	if !ok {
+		parent = &memEntry{mode: fs.ModeDir | 0o755} //nolint:gomnd
+		if err := m.add(dir, parent); err != nil {
+			return err
+		}
+	}
+
+	if !parent.IsDir() {
+		return fmt.Errorf("invalid path in tar archive: %q is not a directory", dir)
+	}
+
+	entry.name = base
+	if existing, ok := m[name]; ok {
+		if existing.IsDir() != entry.IsDir() {
+			return fmt.Errorf("conflicting entries for %q in tar archive", name)
+		}
+
+		// later entries override earlier ones, as they would when extracting the archive
+		if entry.IsDir() {
+			entry.children = existing.children
+		}
+		m[name] = entry
+		return nil
+	}
+
+	m[name] = entry
+	idx := sort.SearchStrings(parent.children, base)
+	parent.children = append(parent.children, "")
+	copy(parent.children[idx+1:], parent.children[idx:])
+	parent.children[idx] = base
+
+	return nil
+}
+
+func (m memFS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+
+	entry, ok := m[name]
+	if !ok {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+
+	if entry.IsDir() {
+		return &memDir{entry: entry, fsys: m, path: name}, nil
+	}
+
+	return &memFile{entry: entry, Reader: bytes.NewReader(entry.data)}, nil
+}
+
+// memEntry is a file or directory in a memFS. It implements both fs.FileInfo and fs.DirEntry.
+type memEntry struct {
+	modTime  time.Time
+	name     string
+	data     []byte
+	children []string
+	mode     fs.FileMode
+}
+
+func (e *memEntry) Name() string               { return e.name }
+func (e *memEntry) Size() int64                { return int64(len(e.data)) }
+func (e *memEntry) Mode() fs.FileMode          { return e.mode }
+func (e *memEntry) ModTime() time.Time         { return e.modTime }
+func (e *memEntry) IsDir() bool                { return e.mode.IsDir() }
+func (e *memEntry) Sys() any                   { return nil }
+func (e *memEntry) Type() fs.FileMode          { return e.mode.Type() }
+func (e *memEntry) Info() (fs.FileInfo, error) { return e, nil }
+
+type memFile struct {
+	*bytes.Reader
+	entry *memEntry
+}
+
+func (f *memFile) Stat() (fs.FileInfo, error) { return f.entry, nil }
+func (f *memFile) Close() error               { return nil }
+
+type memDir struct {
+	entry  *memEntry
+	fsys   memFS
+	path   string
+	offset int
+}
+
+func (d *memDir) Stat() (fs.FileInfo, error) { return d.entry, nil }
+func (d *memDir) Close() error               { return nil }
+
+func (d *memDir) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.path, Err: errors.New("is a directory")}
+}
+
+func (d *memDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	remaining := d.entry.children[d.offset:]
+	if n > 0 && len(remaining) == 0 {
+		return nil, io.EOF
+	}
+
+	if n > 0 && n < len(remaining) {
+		remaining = remaining[:n]
+	}
+
+	entries := make([]fs.DirEntry, len(remaining))
+	for i, name := range remaining {
+		entries[i] = d.fsys[path.Join(d.path, name)]
+	}
+	d.offset += len(remaining)
+
+	return entries, nil
+}
@@ -0,0 +1,234 @@
+// Copyright 2021-2022 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package util_test
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/require"
+
+	policyv1 "github.com/cerbos/cerbos/api/genpb/cerbos/policy/v1"
+	"github.com/cerbos/cerbos/internal/util"
+)
+
+var archiveContents = map[string]string{
+	"_schemas/principal.json":                       "{}",
+	"_schemas/resources/leave_request.json":         "{}",
+	"resource_policies/leave_request.yaml":          "apiVersion: api.cerbos.dev/v1\nresourcePolicy:\n  resource: leave_request\n  version: default\n",
+	"resource_policies/leave_request_test.yaml":     "name: LeaveRequestTestSuite\n",
+	"resource_policies/testdata/principals.yaml":    "principals: {}\n",
+	"resource_policies/nested/.hidden/policy.yaml":  "apiVersion: api.cerbos.dev/v1\n",
+	"principal_policies/nested/donald_duck.json":    "{}",
+	"principal_policies/nested/donald_duck.old.txt": "",
+}
+
+var wantArchiveFileTypes = map[string]util.IndexedFileType{
+	"_schemas/principal.json":                       util.FileTypeSchema,
+	"_schemas/resources/leave_request.json":         util.FileTypeSchema,
+	"resource_policies/leave_request.yaml":          util.FileTypePolicy,
+	"resource_policies/leave_request_test.yaml":     util.FileTypeTest,
+	"resource_policies/testdata/principals.yaml":    util.FileTypeNotIndexed,
+	"resource_policies/nested/.hidden/policy.yaml":  util.FileTypeNotIndexed,
+	"principal_policies/nested/donald_duck.json":    util.FileTypePolicy,
+	"principal_policies/nested/donald_duck.old.txt": util.FileTypeNotIndexed,
+}
+
+func TestZipFS(t *testing.T) {
+	archive := filepath.Join(t.TempDir(), "policies.zip")
+	mkZipArchive(t, archive, archiveContents)
+
+	fsys, err := util.ZipFS(archive)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = fsys.(io.Closer).Close() })
+
+	requireArchiveFS(t, fsys)
+
+	t.Run("missing_archive", func(t *testing.T) {
+		_, err := util.ZipFS(filepath.Join(t.TempDir(), "missing.zip"))
+		require.ErrorIs(t, err, fs.ErrNotExist)
+	})
+
+	t.Run("file_too_large", func(t *testing.T) {
+		contents := strings.Repeat("a", 16)
+		archive := filepath.Join(t.TempDir(), "large.zip")
+		mkZipArchive(t, archive, map[string]string{"policy.yaml": contents})
+
+		_, err := util.ZipFS(archive, util.WithMaxBytes(int64(len(contents)-1)))
+		require.ErrorIs(t, err, util.ErrFileTooLarge)
+
+		fsys, err := util.ZipFS(archive, util.WithMaxBytes(int64(len(contents))))
+		require.NoError(t, err)
+		require.NoError(t, fsys.(io.Closer).Close())
+	})
+
+	t.Run("archive_too_large", func(t *testing.T) {
+		// each file is within the limit but the archive as a whole is not
+		contents := strings.Repeat("a", util.DefaultMaxFileSize)
+		files := make(map[string]string)
+		for i := 0; i <= util.DefaultMaxArchiveSize/util.DefaultMaxFileSize; i++ {
+			files[fmt.Sprintf("policy_%02d.yaml", i)] = contents
+		}
+
+		archive := filepath.Join(t.TempDir(), "large.zip")
+		mkZipArchive(t, archive, files)
+
+		_, err := util.ZipFS(archive)
+		require.ErrorIs(t, err, util.ErrFileTooLarge)
+	})
+}
+
+func TestTarGzFS(t *testing.T) {
+	// testdata/policies.tar.gz has the same contents as archiveContents
+	f, err := os.Open(filepath.Join("testdata", "policies.tar.gz"))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = f.Close() })
+
+	fsys, err := util.TarGzFS(f)
+	require.NoError(t, err)
+	require.NoError(t, fstest.TestFS(fsys, "resource_policies/leave_request.yaml", "_schemas/principal.json"))
+
+	requireArchiveFS(t, fsys)
+
+	t.Run("file_mode", func(t *testing.T) {
+		info, err := fs.Stat(fsys, "resource_policies/leave_request.yaml")
+		require.NoError(t, err)
+		require.Equal(t, fs.FileMode(0o600), info.Mode())
+
+		info, err = fs.Stat(fsys, "principal_policies/nested")
+		require.NoError(t, err)
+		require.True(t, info.IsDir())
+	})
+
+	t.Run("path_escapes_root", func(t *testing.T) {
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+		tw := tar.NewWriter(gz)
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: "../policy.yaml", Typeflag: tar.TypeReg, Mode: 0o644}))
+		require.NoError(t, tw.Close())
+		require.NoError(t, gz.Close())
+
+		_, err := util.TarGzFS(&buf)
+		require.Error(t, err)
+	})
+
+	t.Run("file_too_large", func(t *testing.T) {
+		contents := strings.Repeat("a", 16)
+		archive := mkTarGzArchive(t, map[string]string{"policy.yaml": contents})
+
+		_, err := util.TarGzFS(bytes.NewReader(archive), util.WithMaxBytes(int64(len(contents)-1)))
+		require.ErrorIs(t, err, util.ErrFileTooLarge)
+
+		_, err = util.TarGzFS(bytes.NewReader(archive), util.WithMaxBytes(int64(len(contents))))
+		require.NoError(t, err)
+	})
+
+	t.Run("archive_too_large", func(t *testing.T) {
+		// each file is within the limit but the archive as a whole is not
+		contents := strings.Repeat("a", util.DefaultMaxFileSize)
+		files := make(map[string]string)
+		for i := 0; i <= util.DefaultMaxArchiveSize/util.DefaultMaxFileSize; i++ {
+			files[fmt.Sprintf("policy_%02d.yaml", i)] = contents
+		}
+
+		_, err := util.TarGzFS(bytes.NewReader(mkTarGzArchive(t, files)))
+		require.ErrorIs(t, err, util.ErrFileTooLarge)
+	})
+
+	t.Run("not_gzip", func(t *testing.T) {
+		_, err := util.TarGzFS(strings.NewReader("not a gzip stream"))
+		require.Error(t, err)
+	})
+}
+
+func requireArchiveFS(t *testing.T, fsys fs.FS) {
+	t.Helper()
+
+	t.Run("file_types", func(t *testing.T) {
+		have := make(map[string]util.IndexedFileType)
+		require.NoError(t, fs.WalkDir(fsys, ".", func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+
+			if !d.IsDir() {
+				have[path] = util.FileType(path)
+			}
+			return nil
+		}))
+
+		require.Equal(t, wantArchiveFileTypes, have)
+	})
+
+	t.Run("open_one_of_supported_files", func(t *testing.T) {
+		f, err := util.OpenOneOfSupportedFiles(fsys, "principal_policies/nested/donald_duck")
+		require.NoError(t, err)
+		require.NoError(t, f.Close())
+	})
+
+	t.Run("load_from_json_or_yaml", func(t *testing.T) {
+		var p policyv1.Policy
+		require.NoError(t, util.LoadFromJSONOrYAML(fsys, "resource_policies/leave_request.yaml", &p))
+		require.Equal(t, "leave_request", p.GetResourcePolicy().GetResource())
+	})
+
+	t.Run("walk_schemas", func(t *testing.T) {
+		have := make(map[string]string)
+		require.NoError(t, util.WalkSchemas(fsys, ".", func(indexPath, schemaPath string) error {
+			have[indexPath] = schemaPath
+			return nil
+		}))
+
+		require.Equal(t, map[string]string{
+			"_schemas/principal.json":               "principal.json",
+			"_schemas/resources/leave_request.json": "resources/leave_request.json",
+		}, have)
+	})
+}
+
+func mkZipArchive(t *testing.T, path string, contents map[string]string) {
+	t.Helper()
+
+	f, err := os.Create(path)
+	require.NoError(t, err)
+	defer f.Close()
+
+	zw := zip.NewWriter(f)
+	for name, data := range contents {
+		w, err := zw.Create(name)
+		require.NoError(t, err)
+
+		_, err = io.WriteString(w, data)
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, zw.Close())
+}
+
+func mkTarGzArchive(t *testing.T, contents map[string]string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, data := range contents {
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Typeflag: tar.TypeReg, Mode: 0o644, Size: int64(len(data))}))
+		_, err := tw.Write([]byte(data))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, gz.Close())
+
+	return buf.Bytes()
+}
@@ -0,0 +1,144 @@
+// Copyright 2021-2022 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package util
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+
+	policyv1 "github.com/cerbos/cerbos/api/genpb/cerbos/policy/v1"
+)
+
+// IndexChecksum returns a checksum of the files under the given root that would be indexed by Cerbos using the default
+// directory layout. See DirLayout.IndexChecksum.
+func IndexChecksum(fsys fs.FS, root string) (string, error) {
+	return DefaultDirLayout.IndexChecksum(fsys, root)
+}
+
+// IndexChecksum returns a checksum of the files under the given root that would be indexed by Cerbos.
+// Policies and schemas are decoded and canonicalized before hashing, so formatting-only changes (such as
+// whitespace, comments, key order or converting a policy between YAML and JSON) don't alter the checksum.
+// The extensions of policy files are not part of the checksum for the same reason. Files that are not indexed
+// (for example, tests, hidden files and files excluded by the ignore file) are excluded. Like the index builder,
+// the ignore file in the root directory is used if the layout doesn't have an ignore matcher.
+//
+// Policies are canonicalized using deterministic protobuf serialization, which is only guaranteed to be stable
+// for a given build of Cerbos. Checksums should not be compared across Cerbos versions.
+func (dl DirLayout) IndexChecksum(fsys fs.FS, root string) (string, error) {
+	dl, err := dl.WithIgnoreFile(fsys, root)
+	if err != nil {
+		return "", err
+	}
+
+	type checksumEntry struct {
+		filePath string
+		key      string
+		fileType IndexedFileType
+	}
+
+	var entries []checksumEntry
+	if err := fs.WalkDir(fsys, root, func(filePath string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		indexPath := relativeToRoot(root, filePath)
+		if d.IsDir() {
+			if filePath != root && dl.isNotIndexedDir(indexPath) {
+				return fs.SkipDir
+			}
+			return nil
+		}
+
+		fileType := dl.FileType(indexPath)
+		switch fileType {
+		case FileTypePolicy:
+			entries = append(entries, checksumEntry{filePath: filePath, key: strings.TrimSuffix(indexPath, path.Ext(indexPath)), fileType: fileType})
+		case FileTypeSchema:
+			entries = append(entries, checksumEntry{filePath: filePath, key: indexPath, fileType: fileType})
+		case FileTypeTest, FileTypeNotIndexed:
+		}
+
+		return nil
+	}); err != nil {
+		return "", err
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].key == entries[j].key {
+			return entries[i].filePath < entries[j].filePath
+		}
+		return entries[i].key < entries[j].key
+	})
+
+	h := sha256.New()
+	var lenBuf [binary.MaxVarintLen64]byte
+	for _, e := range entries {
+		contents, err := canonicalContents(fsys, e.filePath, e.fileType)
+		if err != nil {
+			return "", err
+		}
+
+		fileSum := sha256.Sum256(contents)
+
+		n := binary.PutUvarint(lenBuf[:], uint64(len(e.key)))
+		_, _ = h.Write(lenBuf[:n])
+		_, _ = h.Write([]byte(e.key))
+		_, _ = h.Write(fileSum[:])
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func canonicalContents(fsys fs.FS, filePath string, fileType IndexedFileType) ([]byte, error) {
+	switch fileType {
+	case FileTypePolicy:
+		p := &policyv1.Policy{}
+		if err := LoadFromJSONOrYAML(fsys, filePath, p); err != nil {
+			return nil, err
+		}
+
+		b, err := proto.MarshalOptions{Deterministic: true}.Marshal(p)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal %s: %w", filePath, err)
+		}
+
+		return b, nil
+
+	case FileTypeSchema:
+		data, err := fs.ReadFile(fsys, filePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", filePath, err)
+		}
+
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.UseNumber()
+
+		var schema any
+		if err := dec.Decode(&schema); err != nil {
+			return nil, fmt.Errorf("failed to decode %s: %w", filePath, err)
+		}
+
+		// encoding/json sorts map keys, which makes the output independent of the key order in the file
+		b, err := json.Marshal(schema)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal %s: %w", filePath, err)
+		}
+
+		return b, nil
+
+	default:
+		return nil, fmt.Errorf("%s is not indexed", filePath)
+	}
+}
@@ -0,0 +1,151 @@
+// Copyright 2021-2022 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package util_test
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cerbos/cerbos/internal/util"
+)
+
+const (
+	checksumPolicyYAML = `apiVersion: api.cerbos.dev/v1
+resourcePolicy:
+  version: default
+  resource: leave_request
+  rules:
+    - actions: ["view"]
+      effect: EFFECT_ALLOW
+      roles: ["user"]
+`
+	checksumSchemaJSON = `{"type": "object", "properties": {"id": {"type": "string"}}}`
+)
+
+func TestIndexChecksum(t *testing.T) {
+	base := fstest.MapFS{
+		"policies/leave_request.yaml":                {Data: []byte(checksumPolicyYAML)},
+		"policies/_schemas/leave_request.json":       {Data: []byte(checksumSchemaJSON)},
+		"policies/leave_request_test.yaml":           {Data: []byte("name: tests")},
+		"policies/testdata/principals.yaml":          {Data: []byte("principals: {}")},
+		"policies/.hidden/leave_request.yaml":        {Data: []byte(checksumPolicyYAML)},
+		"policies/README.md":                         {Data: []byte("# Policies")},
+		"policies/_schemas/leave_request_notes.yaml": {Data: []byte("notes")},
+	}
+
+	want, err := util.IndexChecksum(base, "policies")
+	require.NoError(t, err)
+	require.NotEmpty(t, want)
+
+	checksum := func(t *testing.T, modify func(fstest.MapFS)) string {
+		t.Helper()
+
+		fsys := make(fstest.MapFS, len(base))
+		for k, v := range base {
+			fsys[k] = v
+		}
+		modify(fsys)
+
+		have, err := util.IndexChecksum(fsys, "policies")
+		require.NoError(t, err)
+		return have
+	}
+
+	t.Run("formatting_changes", func(t *testing.T) {
+		have := checksum(t, func(fsys fstest.MapFS) {
+			fsys["policies/leave_request.yaml"] = &fstest.MapFile{Data: []byte(`# A comment
+apiVersion: "api.cerbos.dev/v1"
+resourcePolicy:
+  resource: leave_request
+  version: "default"
+  rules:
+  - effect: EFFECT_ALLOW
+    roles:
+    - user
+    actions:
+    - view
+`)}
+			fsys["policies/_schemas/leave_request.json"] = &fstest.MapFile{Data: []byte(`{
+  "properties": {"id": {"type": "string"}},
+  "type": "object"
+}`)}
+		})
+		require.Equal(t, want, have)
+	})
+
+	t.Run("non_indexed_changes", func(t *testing.T) {
+		have := checksum(t, func(fsys fstest.MapFS) {
+			fsys["policies/leave_request_test.yaml"] = &fstest.MapFile{Data: []byte("name: other tests")}
+			fsys["policies/README.md"] = &fstest.MapFile{Data: []byte("# Other")}
+			fsys["policies/.hidden/other.yaml"] = &fstest.MapFile{Data: []byte("invalid")}
+		})
+		require.Equal(t, want, have)
+	})
+
+	t.Run("semantic_changes", func(t *testing.T) {
+		have := checksum(t, func(fsys fstest.MapFS) {
+			fsys["policies/_schemas/leave_request.json"] = &fstest.MapFile{Data: []byte(`{"type": "object"}`)}
+		})
+		require.NotEqual(t, want, have)
+	})
+
+	t.Run("renamed_file", func(t *testing.T) {
+		have := checksum(t, func(fsys fstest.MapFS) {
+			fsys["policies/leave_request_v2.yaml"] = fsys["policies/leave_request.yaml"]
+			delete(fsys, "policies/leave_request.yaml")
+		})
+		require.NotEqual(t, want, have)
+	})
+
+	t.Run("converted_to_json", func(t *testing.T) {
+		have := checksum(t, func(fsys fstest.MapFS) {
+			delete(fsys, "policies/leave_request.yaml")
+			fsys["policies/leave_request.json"] = &fstest.MapFile{Data: []byte(`{
+  "apiVersion": "api.cerbos.dev/v1",
+  "resourcePolicy": {
+    "version": "default",
+    "resource": "leave_request",
+    "rules": [{"actions": ["view"], "effect": "EFFECT_ALLOW", "roles": ["user"]}]
+  }
+}`)}
+		})
+		require.Equal(t, want, have)
+	})
+
+	t.Run("ignored_files", func(t *testing.T) {
+		have := checksum(t, func(fsys fstest.MapFS) {
+			fsys["policies/"+util.IgnoreFileName] = &fstest.MapFile{Data: []byte("vendor/\n")}
+			fsys["policies/vendor/invalid.yaml"] = &fstest.MapFile{Data: []byte("resourcePolicy: [")}
+		})
+		require.Equal(t, want, have)
+	})
+
+	t.Run("dir_layout", func(t *testing.T) {
+		fsys := fstest.MapFS{
+			"policies/leave_request.yaml":                {Data: []byte(checksumPolicyYAML)},
+			"policies/cerbos_schemas/leave_request.json": {Data: []byte(checksumSchemaJSON)},
+			"policies/fixtures/invalid.yaml":             {Data: []byte("resourcePolicy: [")},
+		}
+
+		_, err := util.IndexChecksum(fsys, "policies")
+		require.Error(t, err, "Files in the custom test data directory are policies in the default layout")
+
+		layout := util.DirLayout{SchemasDirectory: "cerbos_schemas", TestDataDirectory: "fixtures"}
+		have, err := layout.IndexChecksum(fsys, "policies")
+		require.NoError(t, err)
+
+		delete(fsys, "policies/fixtures/invalid.yaml")
+		withoutFixtures, err := layout.IndexChecksum(fsys, "policies")
+		require.NoError(t, err)
+		require.Equal(t, withoutFixtures, have)
+	})
+
+	t.Run("invalid_policy", func(t *testing.T) {
+		fsys := fstest.MapFS{"policies/invalid.yaml": {Data: []byte("resourcePolicy: [")}}
+		_, err := util.IndexChecksum(fsys, "policies")
+		require.Error(t, err)
+	})
+}
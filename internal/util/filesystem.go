@@ -4,18 +4,26 @@
 package util
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
+	"io"
 	"io/fs"
+	"path"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"google.golang.org/protobuf/proto"
 )
 
-var supportedFileTypes = map[string]struct{}{".yaml": {}, ".yml": {}, ".json": {}}
+var supportedFileTypes = map[string]struct{}{".yaml": {}, ".yml": {}, ".json": {}, ".toml": {}}
 
-var ErrNoMatchingFiles = errors.New("no matching files")
+var (
+	ErrNoMatchingFiles    = errors.New("no matching files")
+	ErrAmbiguousFile      = errors.New("more than one file with a supported extension matches the name")
+	ErrFileFormatMismatch = errors.New("file contents do not match the file extension")
+)
 
 // SchemasDirectory is the name of the special directory containing schemas. It is defined here to avoid an import loop.
 const SchemasDirectory = "_schemas"
@@ -23,7 +31,40 @@ const SchemasDirectory = "_schemas"
 // TestDataDirectory is the name of the special directory containing test fixtures. It is defined here to avoid an import loop.
 const TestDataDirectory = "testdata"
 
-// IsSupportedTestFile return true if the given file is a supported test file name, i.e. "*_test.{yaml,yml,json}".
+// DirLayout defines the names of the special directories of a policy repository.
+type DirLayout struct {
+	// SchemasDirectory is the name of the top-level directory containing schemas.
+	SchemasDirectory string
+	// Ignore optionally excludes paths from the index.
+	Ignore *IgnoreMatcher
+	// TestDataDirectory is the name of the directories containing test fixtures.
+	TestDataDirectory string
+}
+
+// DefaultDirLayout is the layout that uses SchemasDirectory and TestDataDirectory.
+var DefaultDirLayout = DirLayout{
+	SchemasDirectory:  SchemasDirectory,
+	TestDataDirectory: TestDataDirectory,
+}
+
+// Validate checks that the directory names are valid.
+func (dl DirLayout) Validate() error {
+	if err := validateDirName("schemas", dl.SchemasDirectory); err != nil {
+		return err
+	}
+
+	if err := validateDirName("test data", dl.TestDataDirectory); err != nil {
+		return err
+	}
+
+	if dl.SchemasDirectory == dl.TestDataDirectory {
+		return fmt.Errorf("schemas and test data directories must have different names: %q", dl.SchemasDirectory)
+	}
+
+	return nil
+}
+
+// IsSupportedTestFile return true if the given file is a supported test file name, i.e. "*_test.{yaml,yml,json,toml}".
 func IsSupportedTestFile(fileName string) bool {
 	if ext, ok := IsSupportedFileTypeExt(fileName); ok {
 		f := strings.ToLower(fileName)
@@ -41,11 +82,18 @@ func IsSupportedFileTypeExt(fileName string) (string, bool) {
 }
 
 // IsJSONFileTypeExt returns true if the given file has a json file extension.
+// Schemas must be JSON, so files with other supported extensions (including TOML) are not indexed as schemas.
 func IsJSONFileTypeExt(fileName string) bool {
 	ext := strings.ToLower(filepath.Ext(fileName))
 	return ext == ".json"
 }
 
+// IsTOMLFileTypeExt returns true if the given file has a toml file extension.
+func IsTOMLFileTypeExt(fileName string) bool {
+	ext := strings.ToLower(filepath.Ext(fileName))
+	return ext == ".toml"
+}
+
 // IsSupportedFileType returns true if the given file has a supported file extension.
 func IsSupportedFileType(fileName string) bool {
 	_, ok := IsSupportedFileTypeExt(fileName)
@@ -61,36 +109,130 @@ func IsHidden(fileName string) bool {
 	}
 }
 
-// LoadFromJSONOrYAML reads a JSON or YAML encoded protobuf from the given path.
-func LoadFromJSONOrYAML(fsys fs.FS, path string, dest proto.Message) error {
-	f, err := fsys.Open(path)
+// LoadFromJSONOrYAML reads a JSON, YAML or TOML encoded protobuf from the given path.
+// Files with the .toml extension are always decoded as TOML. The encoding of other files is detected from the contents.
+// Files larger than DefaultMaxFileSize are rejected with ErrFileTooLarge unless a different limit is set using WithMaxBytes.
+// If WithFileFormatCheck is used, files whose contents don't match their extension are rejected with an error wrapping
+// ErrFileFormatMismatch.
+func LoadFromJSONOrYAML(fsys fs.FS, path string, dest proto.Message, opts ...LoadOpt) error {
+	f, err := SafeOpen(fsys, path)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", path, err)
+	}
+
+	defer f.Close()
+
+	if mkLoadOpts(opts...).checkFileFormat {
+		return loadCheckingFileFormat(f, path, dest, opts...)
+	}
+
+	if IsTOMLFileTypeExt(path) {
+		return ReadTOML(f, dest, opts...)
+	}
+
+	return ReadJSONOrYAML(f, dest, opts...)
+}
+
+// loadCheckingFileFormat reads the contents once, checks that they match the extension of the file and decodes them.
+func loadCheckingFileFormat(src io.Reader, path string, dest proto.Message, opts ...LoadOpt) error {
+	ext, ok := IsSupportedFileTypeExt(path)
+	if !ok {
+		return fmt.Errorf("%s does not have a supported file extension", path)
+	}
+
+	contents, err := io.ReadAll(newMaxBytesReader(src, mkLoadOpts(opts...).maxBytes))
+	if err != nil {
+		return err
+	}
+
+	if err := checkFileFormat(path, fileFormatOf(ext), contents, false); err != nil {
+		return err
+	}
+
+	if ext == ".toml" {
+		return ReadTOML(bytes.NewReader(contents), dest, opts...)
+	}
+
+	return ReadJSONOrYAML(bytes.NewReader(contents), dest, opts...)
+}
+
+// ValidateFileFormat checks that the contents of the given file can be parsed in the format declared by its extension:
+// strict JSON for .json, YAML for .yaml and .yml, and TOML for .toml. JSON contents are accepted in YAML files because
+// YAML is a superset of JSON. If the contents cannot be parsed, it returns an error wrapping ErrFileFormatMismatch that
+// includes the format the contents appear to be in. Use LoadFromJSONOrYAML with WithFileFormatCheck to avoid reading
+// the file twice if it is going to be loaded anyway.
+func ValidateFileFormat(fsys fs.FS, path string) error {
+	ext, ok := IsSupportedFileTypeExt(path)
+	if !ok {
+		return fmt.Errorf("%s does not have a supported file extension", path)
+	}
+
+	f, err := SafeOpen(fsys, path)
 	if err != nil {
 		return fmt.Errorf("failed to open %s: %w", path, err)
 	}
 
 	defer f.Close()
 
-	return ReadJSONOrYAML(f, dest)
+	contents, err := io.ReadAll(newMaxBytesReader(f, DefaultMaxFileSize))
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	return checkFileFormat(path, fileFormatOf(ext), contents, true)
+}
+
+// checkFileFormat returns an error wrapping ErrFileFormatMismatch if the contents cannot be parsed in the declared format.
+// If strict is false, the contents are only parsed if they appear to be in a different format. That is enough to catch
+// misnamed files when the contents are decoded afterwards, because decoding reports any other syntax errors.
+func checkFileFormat(path string, declared fileFormat, contents []byte, strict bool) error {
+	detected := detectFileFormat(contents)
+	if !strict && (detected == declared || (declared == fileFormatYAML && detected == fileFormatJSON)) {
+		return nil
+	}
+
+	err := validateFormat(declared, contents)
+	// simple TOML documents happen to be valid YAML but they are parsed as a string rather than a mapping
+	if err == nil && declared == fileFormatYAML && detected == fileFormatTOML {
+		err = errors.New("TOML tables and key/value pairs are not YAML mappings")
+	}
+
+	if err != nil {
+		hint := ""
+		if detected != declared {
+			hint = fmt.Sprintf(" (the contents appear to be %s)", detected)
+		}
+
+		return fmt.Errorf("%w: %s is not valid %s%s: %s", ErrFileFormatMismatch, path, declared, hint, err.Error())
+	}
+
+	return nil
 }
 
 // OpenOneOfSupportedFiles attempts to open a fileName adding supported extensions.
+// It returns ErrNoMatchingFiles if there are no such files and ErrAmbiguousFile if there is more than one.
 func OpenOneOfSupportedFiles(fsys fs.FS, fileName string) (fs.File, error) {
 	matches, err := fs.Glob(fsys, fileName+".*")
 	if err != nil {
 		return nil, err
 	}
-	var filepath string
+
+	var supported []string
 	for _, match := range matches {
 		if IsSupportedFileType(match) {
-			filepath = match
-			break
+			supported = append(supported, match)
 		}
 	}
-	if filepath == "" {
+
+	switch len(supported) {
+	case 0:
 		return nil, ErrNoMatchingFiles
+	case 1:
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrAmbiguousFile, strings.Join(supported, ", "))
 	}
 
-	file, err := fsys.Open(filepath)
+	file, err := SafeOpen(fsys, supported[0])
 	if err != nil {
 		return nil, err
 	}
@@ -98,28 +240,153 @@ func OpenOneOfSupportedFiles(fsys fs.FS, fileName string) (fs.File, error) {
 	return file, nil
 }
 
+// GlobSupportedFiles returns the sorted paths of the files with supported extensions that match the pattern, using the
+// default directory layout. See DirLayout.GlobSupportedFiles.
+func GlobSupportedFiles(fsys fs.FS, pattern string) ([]string, error) {
+	return DefaultDirLayout.GlobSupportedFiles(fsys, pattern)
+}
+
+// GlobSupportedFiles returns the sorted paths of the files with supported extensions that match the pattern.
+// The pattern uses the syntax of path.Match. Files under hidden or test data directories (or hidden files) are excluded
+// unless the pattern names them explicitly, so "testdata/*.yaml" matches the fixtures in the testdata directory but
+// "*/*.yaml" doesn't. It returns ErrNoMatchingFiles if there are no such files.
+func (dl DirLayout) GlobSupportedFiles(fsys fs.FS, pattern string) ([]string, error) {
+	matches, err := fs.Glob(fsys, pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	patternSegments := strings.Split(pattern, "/")
+	var supported []string
+	for _, match := range matches {
+		if !IsSupportedFileType(match) || dl.isExcludedMatch(patternSegments, match) {
+			continue
+		}
+
+		if info, err := fs.Stat(fsys, match); err != nil || info.IsDir() {
+			continue
+		}
+
+		supported = append(supported, match)
+	}
+
+	if len(supported) == 0 {
+		return nil, ErrNoMatchingFiles
+	}
+
+	sort.Strings(supported)
+	return supported, nil
+}
+
+// isExcludedMatch returns true if a segment of the match that was matched by a wildcard is hidden or a test data directory.
+func (dl DirLayout) isExcludedMatch(patternSegments []string, match string) bool {
+	matchSegments := strings.Split(match, "/")
+	for i, segment := range matchSegments {
+		if i < len(patternSegments) && !strings.ContainsAny(patternSegments[i], `*?[\`) {
+			continue
+		}
+
+		if IsHidden(segment) || (i < len(matchSegments)-1 && segment == dl.TestDataDirectory) {
+			return true
+		}
+	}
+
+	return false
+}
+
 type IndexedFileType uint8
 
 const (
 	FileTypeNotIndexed IndexedFileType = iota
 	FileTypePolicy
 	FileTypeSchema
+	// FileTypeTest is a policy test suite. Test suites are not indexed, but are recognized so that they can be found by tooling.
+	FileTypeTest
 )
 
-// FileType categorizes the given path according to how it will be treated by the index.
+// IsIndexed returns true if files of this type are added to the index.
+func (ft IndexedFileType) IsIndexed() bool {
+	return ft == FileTypePolicy || ft == FileTypeSchema
+}
+
+func validateDirName(kind, dir string) error {
+	if dir == "" || dir == "." || dir == ".." || strings.ContainsAny(dir, `/\`) {
+		return fmt.Errorf("invalid %s directory name %q", kind, dir)
+	}
+
+	return nil
+}
+
+// IsSchemasDirectory returns true if the given "/"-separated path relative to the root policies directory is the schemas directory.
+// The name is compared case-insensitively so that the directory is recognized regardless of the case used by the file system.
+func (dl DirLayout) IsSchemasDirectory(path string) bool {
+	return strings.EqualFold(path, dl.SchemasDirectory)
+}
+
+// ResolveSchemasDirectory returns the path of the schemas directory under the given root, using the case of the name of
+// the directory that actually exists in the file system. The configured name is used if there is no such directory.
+func (dl DirLayout) ResolveSchemasDirectory(fsys fs.FS, root string) string {
+	entries, err := fs.ReadDir(fsys, root)
+	if err != nil {
+		return path.Join(root, dl.SchemasDirectory)
+	}
+
+	name := dl.SchemasDirectory
+	for _, entry := range entries {
+		if !entry.IsDir() || !dl.IsSchemasDirectory(entry.Name()) {
+			continue
+		}
+
+		// prefer an exact match if the file system is case-sensitive and has more than one candidate
+		if entry.Name() == dl.SchemasDirectory {
+			return path.Join(root, entry.Name())
+		}
+		name = entry.Name()
+	}
+
+	return path.Join(root, name)
+}
+
+// FileType categorizes the given path according to how it will be treated by the index using the default directory layout.
 // The path must be "/"-separated and relative to the root policies directory.
 func FileType(path string) IndexedFileType {
+	return DefaultDirLayout.FileType(path)
+}
+
+// FileTypeNormalized is like FileType but also accepts `\`-separated paths.
+func FileTypeNormalized(path string) IndexedFileType {
+	return DefaultDirLayout.FileTypeNormalized(path)
+}
+
+// FileTypeNormalized is like FileType but also accepts `\`-separated paths.
+// Backslashes are always treated as separators because paths produced on Windows can be passed around on other platforms.
+func (dl DirLayout) FileTypeNormalized(path string) IndexedFileType {
+	return dl.FileType(NormalizePath(path))
+}
+
+// NormalizePath converts the Windows-style `\` separators in the given path to "/".
+func NormalizePath(path string) string {
+	return strings.ReplaceAll(path, "\\", "/")
+}
+
+// FileType categorizes the given path according to how it will be treated by the index.
+// The path must be "/"-separated and relative to the root policies directory.
+func (dl DirLayout) FileType(path string) IndexedFileType {
 	segments := strings.Split(path, "/")
 	fileName := segments[len(segments)-1]
 
-	inSchemas := segments[0] == SchemasDirectory
+	inSchemas := dl.IsSchemasDirectory(segments[0])
 
 	for _, segment := range segments {
-		if IsHidden(segment) || (segment == TestDataDirectory && !inSchemas) {
+		if IsHidden(segment) || (segment == dl.TestDataDirectory && !inSchemas) {
 			return FileTypeNotIndexed
 		}
 	}
 
+	if dl.Ignore.Match(path, false) {
+		return FileTypeNotIndexed
+	}
+
 	if inSchemas {
 		if IsJSONFileTypeExt(fileName) {
 			return FileTypeSchema
@@ -128,21 +395,230 @@ func FileType(path string) IndexedFileType {
 		return FileTypeNotIndexed
 	}
 
-	if IsSupportedFileType(fileName) && !IsSupportedTestFile(fileName) {
+	if IsSupportedTestFile(fileName) {
+		return FileTypeTest
+	}
+
+	if IsSupportedFileType(fileName) {
 		return FileTypePolicy
 	}
 
 	return FileTypeNotIndexed
 }
 
-// RelativeSchemaPath returns the given path within the top-level schemas directory,
+// ListIndexableFiles returns the sorted paths of the policy and schema files in the given file system using the default directory layout.
+// The paths are "/"-separated and relative to the root of the file system.
+func ListIndexableFiles(fsys fs.FS) (policies, schemas []string, err error) {
+	return DefaultDirLayout.ListIndexableFiles(fsys)
+}
+
+// ListIndexableFiles returns the sorted paths of the policy and schema files in the given file system.
+// The paths are "/"-separated and relative to the root of the file system. Directories that can't contain any indexed
+// files (such as hidden and test data directories) are not descended into.
+func (dl DirLayout) ListIndexableFiles(fsys fs.FS) (policies, schemas []string, err error) {
+	err = fs.WalkDir(fsys, ".", func(filePath string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			if filePath != "." && dl.isNotIndexedDir(filePath) {
+				return fs.SkipDir
+			}
+			return nil
+		}
+
+		switch dl.FileType(filePath) {
+		case FileTypePolicy:
+			policies = append(policies, filePath)
+		case FileTypeSchema:
+			schemas = append(schemas, filePath)
+		case FileTypeTest, FileTypeNotIndexed:
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sort.Strings(policies)
+	sort.Strings(schemas)
+
+	return policies, schemas, nil
+}
+
+// isNotIndexedDir returns true if none of the files under the given directory are indexed.
+// The path must be "/"-separated and relative to the root policies directory.
+func (dl DirLayout) isNotIndexedDir(dirPath string) bool {
+	name := path.Base(dirPath)
+	if IsHidden(name) {
+		return true
+	}
+
+	if top, _, _ := strings.Cut(dirPath, "/"); name == dl.TestDataDirectory && !dl.IsSchemasDirectory(top) {
+		return true
+	}
+
+	return dl.Ignore.Match(dirPath, true)
+}
+
+// RelativeSchemaPath returns the given path within the top-level schemas directory of the default directory layout,
 // and a flag to indicate whether the path was actually contained in that directory.
 // The path must be "/"-separated and relative to the root policies directory.
 func RelativeSchemaPath(path string) (string, bool) {
-	schemaPath := strings.TrimPrefix(path, SchemasDirectory+"/")
-	if schemaPath == path {
+	return DefaultDirLayout.RelativeSchemaPath(path)
+}
+
+// RelativeSchemaPath returns the given path within the top-level schemas directory,
+// and a flag to indicate whether the path was actually contained in that directory.
+// The path must be "/"-separated and relative to the root policies directory.
+func (dl DirLayout) RelativeSchemaPath(path string) (string, bool) {
+	dir, schemaPath, ok := strings.Cut(path, "/")
+	if !ok || !dl.IsSchemasDirectory(dir) {
 		return "", false
 	}
 
 	return schemaPath, true
 }
+
+// WalkSchemas calls fn for each file under the given root that is indexed as a schema using the default directory layout.
+func WalkSchemas(fsys fs.FS, root string, fn func(indexPath, relativeSchemaPath string) error) error {
+	return DefaultDirLayout.WalkSchemas(fsys, root, fn)
+}
+
+// WalkSchemas calls fn for each file under the given root that is indexed as a schema.
+// The index path passed to fn is "/"-separated and relative to the root, and the relative schema path
+// is the path within the top-level schemas directory (as returned by RelativeSchemaPath).
+// Returning an error from fn stops the walk and WalkSchemas returns that error.
+func (dl DirLayout) WalkSchemas(fsys fs.FS, root string, fn func(indexPath, relativeSchemaPath string) error) error {
+	schemasDir := dl.ResolveSchemasDirectory(fsys, root)
+	return fs.WalkDir(fsys, schemasDir, func(filePath string, d fs.DirEntry, err error) error {
+		if err != nil {
+			// no schemas to walk
+			if filePath == schemasDir && errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+
+		if d.IsDir() {
+			if filePath != schemasDir && IsHidden(d.Name()) {
+				return fs.SkipDir
+			}
+			return nil
+		}
+
+		indexPath := relativeToRoot(root, filePath)
+		if dl.FileType(indexPath) != FileTypeSchema {
+			return nil
+		}
+
+		schemaPath, _ := dl.RelativeSchemaPath(indexPath)
+		return fn(indexPath, schemaPath)
+	})
+}
+
+func relativeToRoot(root, filePath string) string {
+	if root == "." || root == "" {
+		return filePath
+	}
+
+	return strings.TrimPrefix(filePath, strings.TrimSuffix(root, "/")+"/")
+}
+
+// SchemaReference is a reference to a schema from a policy.
+type SchemaReference struct {
+	// Path is the path of the schema relative to the schemas directory.
+	Path string
+	// Context optionally describes where the schema is referenced from (for example, the policy file).
+	Context string
+}
+
+func (sr SchemaReference) String() string {
+	if sr.Context == "" {
+		return sr.Path
+	}
+
+	return fmt.Sprintf("%s (referenced from %s)", sr.Path, sr.Context)
+}
+
+// ValidateSchemaReferences returns the referenced schemas that do not exist in the schemas directory under the given root.
+// Referenced paths must be "/"-separated and relative to the schemas directory. Only JSON files are considered to be schemas,
+// so references to files with other extensions are always reported as missing.
+func ValidateSchemaReferences(fsys fs.FS, root string, referenced []string) ([]string, error) {
+	return DefaultDirLayout.ValidateSchemaReferences(fsys, root, referenced)
+}
+
+// ValidateSchemaReferences is like the package-level ValidateSchemaReferences but uses the schemas directory of this layout.
+func (dl DirLayout) ValidateSchemaReferences(fsys fs.FS, root string, referenced []string) ([]string, error) {
+	refs := make([]SchemaReference, len(referenced))
+	for i, r := range referenced {
+		refs[i] = SchemaReference{Path: r}
+	}
+
+	missingRefs, err := dl.FindMissingSchemas(fsys, root, refs)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]struct{}, len(missingRefs))
+	missing := make([]string, 0, len(missingRefs))
+	for _, r := range missingRefs {
+		if _, ok := seen[r.Path]; ok {
+			continue
+		}
+
+		seen[r.Path] = struct{}{}
+		missing = append(missing, r.Path)
+	}
+
+	return missing, nil
+}
+
+// FindMissingSchemas is like ValidateSchemaReferences but preserves the context of each reference that is missing.
+func FindMissingSchemas(fsys fs.FS, root string, refs []SchemaReference) ([]SchemaReference, error) {
+	return DefaultDirLayout.FindMissingSchemas(fsys, root, refs)
+}
+
+// FindMissingSchemas is like the package-level FindMissingSchemas but uses the schemas directory of this layout.
+func (dl DirLayout) FindMissingSchemas(fsys fs.FS, root string, refs []SchemaReference) ([]SchemaReference, error) {
+	var missing []SchemaReference
+	schemasDir := dl.ResolveSchemasDirectory(fsys, root)
+	exists := make(map[string]bool, len(refs))
+	for _, ref := range refs {
+		found, ok := exists[ref.Path]
+		if !ok {
+			var err error
+			if found, err = dl.schemaExists(fsys, schemasDir, ref.Path); err != nil {
+				return nil, err
+			}
+			exists[ref.Path] = found
+		}
+
+		if !found {
+			missing = append(missing, ref)
+		}
+	}
+
+	return missing, nil
+}
+
+func (dl DirLayout) schemaExists(fsys fs.FS, schemasDir, schemaPath string) (bool, error) {
+	indexPath := path.Join(dl.SchemasDirectory, schemaPath)
+	if relPath, ok := dl.RelativeSchemaPath(indexPath); !ok || relPath != schemaPath || dl.FileType(indexPath) != FileTypeSchema {
+		// the path is not a valid reference to a schema file (e.g. it is not JSON or escapes the schemas directory)
+		return false, nil
+	}
+
+	finfo, err := fs.Stat(fsys, path.Join(schemasDir, schemaPath))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return false, nil
+		}
+
+		return false, fmt.Errorf("failed to stat schema %s: %w", schemaPath, err)
+	}
+
+	return !finfo.IsDir(), nil
+}
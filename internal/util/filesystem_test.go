@@ -4,13 +4,17 @@
 package util_test
 
 import (
+	"io/fs"
+	"path"
 	"path/filepath"
+	"strings"
 	"testing"
 	"testing/fstest"
 
 	"github.com/cerbos/cerbos/internal/util"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/structpb"
 )
 
 func TestIsSupportedTestFile(t *testing.T) {
@@ -22,11 +26,15 @@ func TestIsSupportedTestFile(t *testing.T) {
 		{"e_test.yaml", true},
 		{"e_test.json", true},
 		{"_test.json", true},
+		{"e_test.toml", true},
+		{"Policy_TEST.YAML", true},
+		{"e_Test.Json", true},
 		// Unsupported files
 		{"e_test.yl", false},
 		{"e_test", false},
 		{"e_bar.yaml", false},
 		{".yaml", false},
+		{"e_TEST.YL", false},
 	}
 	for _, tt := range tests {
 		t.Run(tt.fileName, func(t *testing.T) {
@@ -44,16 +52,21 @@ func TestOpenOneOfSupportedFiles(t *testing.T) {
 	fsys["testdata/b.yml"] = file
 	fsys["testdata/c.yaml"] = file
 	fsys["testdata/d.csv"] = file
+	fsys["testdata/e.yaml"] = file
+	fsys["testdata/e.json"] = file
+	fsys["testdata/f.old.yaml"] = file
 
 	tests := []struct {
 		fileName string
-		wantErr  bool
+		wantErr  error
 	}{
-		{"a", false},
-		{"b", false},
-		{"c", false},
-		{"d", true},
-		{"not_exist", true},
+		{"a", nil},
+		{"b", nil},
+		{"c", nil},
+		{"d", util.ErrNoMatchingFiles},
+		{"e", util.ErrAmbiguousFile},
+		{"f", nil},
+		{"not_exist", util.ErrNoMatchingFiles},
 	}
 	for _, tt := range tests {
 		t.Run(tt.fileName, func(t *testing.T) {
@@ -62,8 +75,8 @@ func TestOpenOneOfSupportedFiles(t *testing.T) {
 			if err == nil && file != nil {
 				file.Close()
 			}
-			if tt.wantErr {
-				is.Error(err)
+			if tt.wantErr != nil {
+				is.ErrorIs(err, tt.wantErr)
 				is.Nil(file)
 			} else {
 				is.NoError(err)
@@ -72,6 +85,52 @@ func TestOpenOneOfSupportedFiles(t *testing.T) {
 	}
 }
 
+func TestGlobSupportedFiles(t *testing.T) {
+	file := &fstest.MapFile{Data: []byte{}}
+	fsys := fstest.MapFS{
+		"fixtures/b.yaml":              file,
+		"fixtures/a.json":              file,
+		"fixtures/c.csv":               file,
+		"fixtures/.hidden.yaml":        file,
+		"fixtures/dir.yaml/x.yaml":     file,
+		"fixtures/nested/d.yml":        file,
+		"fixtures/testdata/e.yaml":     file,
+		"fixtures/.git/f.yaml":         file,
+		"testdata/principals.yaml":     file,
+		"testdata/resources.json":      file,
+		"testdata/auxdata/tokens.yaml": file,
+	}
+
+	testCases := []struct {
+		pattern string
+		wantErr error
+		want    []string
+	}{
+		{pattern: "fixtures/*", want: []string{"fixtures/a.json", "fixtures/b.yaml"}},
+		{pattern: "fixtures/*/*", want: []string{"fixtures/dir.yaml/x.yaml", "fixtures/nested/d.yml"}},
+		{pattern: "fixtures/testdata/*", want: []string{"fixtures/testdata/e.yaml"}},
+		{pattern: "testdata/*", want: []string{"testdata/principals.yaml", "testdata/resources.json"}},
+		{pattern: "*/*.yaml", want: []string{"fixtures/b.yaml"}},
+		{pattern: "fixtures/*.csv", wantErr: util.ErrNoMatchingFiles},
+		{pattern: "missing/*", wantErr: util.ErrNoMatchingFiles},
+		{pattern: "fixtures/[", wantErr: path.ErrBadPattern},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.pattern, func(t *testing.T) {
+			have, err := util.GlobSupportedFiles(fsys, tc.pattern)
+			if tc.wantErr != nil {
+				require.ErrorIs(t, err, tc.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.want, have)
+		})
+	}
+}
+
 func TestIsJSONFileTypeExt(t *testing.T) {
 	tests := []struct {
 		fileName string
@@ -79,10 +138,13 @@ func TestIsJSONFileTypeExt(t *testing.T) {
 	}{
 		{"e_test.json", true},
 		{"_test.json", true},
+		{"schema.JSON", true},
+		{"schema.Json", true},
 		// Unsupported files
 		{"e_test.yml", false},
 		{"e_test.yaml", false},
 		{"e_test.yl", false},
+		{"e_test.toml", false},
 		{"e_test", false},
 		{"e_bar.yaml", false},
 		{".yaml", false},
@@ -96,27 +158,75 @@ func TestIsJSONFileTypeExt(t *testing.T) {
 	}
 }
 
+func TestIsTOMLFileTypeExt(t *testing.T) {
+	tests := []struct {
+		fileName string
+		want     bool
+	}{
+		{"e_test.toml", true},
+		{"_test.toml", true},
+		{"config.TOML", true},
+		{"config.Toml", true},
+		// Other files
+		{"e_test.json", false},
+		{"e_test.yaml", false},
+		{"e_test.yml", false},
+		{"e_test", false},
+		{".json", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.fileName, func(t *testing.T) {
+			if got := util.IsTOMLFileTypeExt(tt.fileName); got != tt.want {
+				t.Errorf("IsTOMLFileTypeExt() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestFileType(t *testing.T) {
 	tests := map[util.IndexedFileType][]string{
 		util.FileTypePolicy: {
 			"foo/bar.json",
 			"foo/bar.yaml",
 			"foo/bar.yml",
+			"foo/bar.toml",
 			"foo/_schemas/bar.yaml",
+			"foo/bar.JSON",
+			"foo/bar.Yaml",
 		},
 		util.FileTypeSchema: {
 			"_schemas/foo/bar.json",
 			"_schemas/foo/testdata/bar.json",
+			"_schemas/foo/bar_test.json",
+			"_SCHEMAS/foo/bar.json",
+			"_Schemas/foo/Bar.JSON",
+			"_schemas/schema.JSON",
+			"_schemas/foo/schema.Json",
+		},
+		util.FileTypeTest: {
+			"bar_test.yaml",
+			"foo/bar_test.yml",
+			"foo/bar_test.json",
+			"foo/bar_test.toml",
+			"foo/bar_Test.YAML",
+			"Policy_TEST.YAML",
+			"foo/policy_TeSt.Json",
+			"foo/bar_TEST.TOML",
 		},
 		util.FileTypeNotIndexed: {
-			".foo/bar.json",          // in hidden directory
-			"foo/.bar.yaml",          // hidden file
-			"foo/bar_test.yml",       // test file
-			"foo/testdata/bar.yaml",  // in testdata directory
-			"foo/bar.yam",            // unsupported policy extension
-			"_schemas/.foo/bar.json", // in hidden directory
-			"_schemas/foo/.bar.json", // hidden file
-			"_schemas/foo/bar.yaml",  // unsupported schema extension
+			".foo/bar.json",              // in hidden directory
+			"foo/.bar.yaml",              // hidden file
+			"foo/.bar_test.yaml",         // hidden test file
+			"foo/testdata/bar.yaml",      // in testdata directory
+			"foo/testdata/bar_test.yaml", // in testdata directory
+			"foo/bar.yam",                // unsupported policy extension
+			"_schemas/.foo/bar.json",     // in hidden directory
+			"_schemas/foo/.bar.json",     // hidden file
+			"_schemas/foo/bar.yaml",      // unsupported schema extension
+			"_schemas/foo/bar.toml",      // unsupported schema extension
+			"_SCHEMAS/foo/bar.yaml",      // unsupported schema extension
+			"_schemas/foo/bar.YAML",      // unsupported schema extension
+			"foo/testdata/bar_TEST.yaml", // in testdata directory
 		},
 	}
 
@@ -129,6 +239,96 @@ func TestFileType(t *testing.T) {
 	}
 }
 
+func TestFileTypeNormalized(t *testing.T) {
+	tests := map[util.IndexedFileType][]string{
+		util.FileTypePolicy: {
+			`foo\bar.yaml`,
+			`foo\baz/bar.yaml`,
+			`foo\_schemas\bar.yaml`,
+		},
+		util.FileTypeSchema: {
+			`_schemas\foo\bar.json`,
+			`_SCHEMAS\foo/bar.json`,
+		},
+		util.FileTypeTest: {
+			`foo\bar_test.yaml`,
+		},
+		util.FileTypeNotIndexed: {
+			`.foo\bar.json`,          // in hidden directory
+			`foo\.bar.yaml`,          // hidden file
+			`foo\testdata\bar.yaml`,  // in testdata directory
+			`_schemas\foo\bar.yaml`,  // unsupported schema extension
+			`_schemas\foo\.bar.json`, // hidden file
+		},
+	}
+
+	for want, paths := range tests {
+		for _, path := range paths {
+			t.Run(path, func(t *testing.T) {
+				assert.Equal(t, want, util.FileTypeNormalized(path))
+				assert.Equal(t, want, util.FileType(util.NormalizePath(path)))
+			})
+		}
+	}
+
+	t.Run("dir_layout", func(t *testing.T) {
+		layout := util.DirLayout{SchemasDirectory: "cerbos_schemas", TestDataDirectory: "cerbos_testdata"}
+		require.NoError(t, layout.Validate())
+
+		assert.Equal(t, util.FileTypeSchema, layout.FileTypeNormalized(`cerbos_schemas\foo\bar.json`))
+		assert.Equal(t, util.FileTypeNotIndexed, layout.FileTypeNormalized(`foo\cerbos_testdata\bar.yaml`))
+		assert.Equal(t, util.FileTypePolicy, layout.FileTypeNormalized(`foo\testdata\bar.yaml`))
+	})
+}
+
+func TestDirLayout(t *testing.T) {
+	layout := util.DirLayout{SchemasDirectory: "cerbos_schemas", TestDataDirectory: "cerbos_testdata"}
+	require.NoError(t, layout.Validate())
+
+	tests := map[util.IndexedFileType][]string{
+		util.FileTypePolicy: {
+			"foo/bar.yaml",
+			"_schemas/bar.yaml",
+			"foo/testdata/bar.yaml",
+		},
+		util.FileTypeSchema: {
+			"cerbos_schemas/foo/bar.json",
+			"cerbos_schemas/foo/cerbos_testdata/bar.json",
+		},
+		util.FileTypeNotIndexed: {
+			"foo/cerbos_testdata/bar.yaml",
+			"cerbos_schemas/foo/bar.yaml",
+			"_schemas/foo/bar.json.bak",
+		},
+	}
+
+	for want, paths := range tests {
+		for _, path := range paths {
+			t.Run(path, func(t *testing.T) {
+				assert.Equal(t, want, layout.FileType(path))
+			})
+		}
+	}
+
+	schemaPath, ok := layout.RelativeSchemaPath("cerbos_schemas/foo/bar.json")
+	require.True(t, ok)
+	require.Equal(t, "foo/bar.json", schemaPath)
+
+	_, ok = layout.RelativeSchemaPath("_schemas/foo/bar.json")
+	require.False(t, ok)
+
+	for _, invalid := range []util.DirLayout{
+		{SchemasDirectory: "", TestDataDirectory: "testdata"},
+		{SchemasDirectory: "_schemas", TestDataDirectory: "a/b"},
+		{SchemasDirectory: "..", TestDataDirectory: "testdata"},
+		{SchemasDirectory: "same", TestDataDirectory: "same"},
+	} {
+		require.Error(t, invalid.Validate())
+	}
+
+	require.NoError(t, util.DefaultDirLayout.Validate())
+}
+
 func TestRelativeSchemaPath(t *testing.T) {
 	tests := []struct {
 		path       string
@@ -136,6 +336,8 @@ func TestRelativeSchemaPath(t *testing.T) {
 		wantOK     bool
 	}{
 		{"_schemas/foo/bar.json", "foo/bar.json", true},
+		{"_SCHEMAS/foo/bar.json", "foo/bar.json", true},
+		{"_schemas", "", false},
 		{"foo/bar.yaml", "", false},
 	}
 
@@ -147,3 +349,278 @@ func TestRelativeSchemaPath(t *testing.T) {
 		})
 	}
 }
+
+func TestWalkSchemas(t *testing.T) {
+	file := &fstest.MapFile{Data: []byte("{}")}
+	fsys := fstest.MapFS{
+		"policies/_schemas/principal.json":                 file,
+		"policies/_schemas/resources/leave_request.json":   file,
+		"policies/_schemas/resources/nested/expense.json":  file,
+		"policies/_schemas/resources/nested/expense.yaml":  file,
+		"policies/_schemas/testdata/fixture.json":          file,
+		"policies/_schemas/.hidden/secret.json":            file,
+		"policies/resources/_schemas/not_a_schema.json":    file,
+		"policies/resource_policies/leave_request.yaml":    file,
+		"policies/resource_policies/testdata/fixture.json": file,
+	}
+
+	for _, root := range []string{"policies", "policies/"} {
+		root := root
+		t.Run(root, func(t *testing.T) {
+			have := make(map[string]string)
+			require.NoError(t, util.WalkSchemas(fsys, root, func(indexPath, schemaPath string) error {
+				have[indexPath] = schemaPath
+				return nil
+			}))
+
+			want := map[string]string{
+				"_schemas/principal.json":                "principal.json",
+				"_schemas/resources/leave_request.json":  "resources/leave_request.json",
+				"_schemas/resources/nested/expense.json": "resources/nested/expense.json",
+				"_schemas/testdata/fixture.json":         "testdata/fixture.json",
+			}
+			require.Equal(t, want, have)
+		})
+	}
+
+	t.Run("no_schemas", func(t *testing.T) {
+		err := util.WalkSchemas(fsys, "policies/resource_policies", func(string, string) error {
+			t.Fatal("Unexpected call")
+			return nil
+		})
+		require.NoError(t, err)
+	})
+}
+
+func TestValidateSchemaReferences(t *testing.T) {
+	file := &fstest.MapFile{Data: []byte("{}")}
+	fsys := fstest.MapFS{
+		"policies/_schemas/principal.json":               file,
+		"policies/_schemas/resources/leave_request.json": file,
+		"policies/_schemas/resources/expense.yaml":       file,
+		"policies/_schemas/.hidden/secret.json":          file,
+		"policies/_schemas/resources/dir.json/x.json":    file,
+		"policies/principal.json":                        file,
+	}
+
+	t.Run("paths", func(t *testing.T) {
+		have, err := util.ValidateSchemaReferences(fsys, "policies", []string{
+			"principal.json",
+			"resources/leave_request.json",
+			"resources/missing.json",
+			"resources/expense.yaml",
+			".hidden/secret.json",
+			"resources/dir.json",
+			"../principal.json",
+			"resources/missing.json",
+		})
+		require.NoError(t, err)
+		require.Equal(t, []string{
+			"resources/missing.json",
+			"resources/expense.yaml",
+			".hidden/secret.json",
+			"resources/dir.json",
+			"../principal.json",
+		}, have)
+	})
+
+	t.Run("with_context", func(t *testing.T) {
+		have, err := util.FindMissingSchemas(fsys, "policies", []util.SchemaReference{
+			{Path: "principal.json", Context: "resource_policies/leave_request.yaml"},
+			{Path: "resources/missing.json", Context: "resource_policies/leave_request.yaml"},
+			{Path: "resources/missing.json", Context: "resource_policies/expense.yaml"},
+		})
+		require.NoError(t, err)
+		require.Equal(t, []util.SchemaReference{
+			{Path: "resources/missing.json", Context: "resource_policies/leave_request.yaml"},
+			{Path: "resources/missing.json", Context: "resource_policies/expense.yaml"},
+		}, have)
+		require.Equal(t, "resources/missing.json (referenced from resource_policies/expense.yaml)", have[1].String())
+	})
+
+	t.Run("no_schemas_directory", func(t *testing.T) {
+		have, err := util.ValidateSchemaReferences(fsys, "other", []string{"principal.json"})
+		require.NoError(t, err)
+		require.Equal(t, []string{"principal.json"}, have)
+	})
+
+	t.Run("uppercase_schemas_directory", func(t *testing.T) {
+		upper := fstest.MapFS{"policies/_SCHEMAS/principal.json": file}
+		have, err := util.ValidateSchemaReferences(upper, "policies", []string{"principal.json", "missing.json"})
+		require.NoError(t, err)
+		require.Equal(t, []string{"missing.json"}, have)
+	})
+}
+
+func TestWalkSchemas_Uppercase(t *testing.T) {
+	file := &fstest.MapFile{Data: []byte("{}")}
+	fsys := fstest.MapFS{
+		"policies/_SCHEMAS/principal.json":            file,
+		"policies/_SCHEMAS/resources/Expense.JSON":    file,
+		"policies/_SCHEMAS/resources/expense.yaml":    file,
+		"policies/resource_policies/expense.yaml":     file,
+		"policies/resource_policies/expense_TEST.YML": file,
+	}
+
+	have := make(map[string]string)
+	require.NoError(t, util.WalkSchemas(fsys, "policies", func(indexPath, schemaPath string) error {
+		have[indexPath] = schemaPath
+		return nil
+	}))
+
+	require.Equal(t, map[string]string{
+		"_SCHEMAS/principal.json":         "principal.json",
+		"_SCHEMAS/resources/Expense.JSON": "resources/Expense.JSON",
+	}, have)
+}
+
+func TestListIndexableFiles(t *testing.T) {
+	file := &fstest.MapFile{Data: []byte("{}")}
+	fsys := fstest.MapFS{
+		"_schemas/principal.json":                   file,
+		"_schemas/resources/leave_request.json":     file,
+		"_schemas/resources/nested/expense.json":    file,
+		"_schemas/resources/nested/expense.yaml":    file,
+		"_schemas/testdata/fixture.json":            file,
+		"_schemas/.hidden/secret.json":              file,
+		"derived_roles/common.yaml":                 file,
+		"resource_policies/leave_request.yaml":      file,
+		"resource_policies/leave_request_test.yaml": file,
+		"resource_policies/testdata/fixture.yaml":   file,
+		"resource_policies/generated/album.yaml":    file,
+		"resource_policies/album.yaml":              file,
+		".git/config.yaml":                          file,
+		"README.md":                                 file,
+	}
+
+	t.Run("default_layout", func(t *testing.T) {
+		rfs := &readDirRecorder{FS: fsys}
+		policies, schemas, err := util.ListIndexableFiles(rfs)
+		require.NoError(t, err)
+
+		require.Equal(t, []string{
+			"derived_roles/common.yaml",
+			"resource_policies/album.yaml",
+			"resource_policies/generated/album.yaml",
+			"resource_policies/leave_request.yaml",
+		}, policies)
+
+		require.Equal(t, []string{
+			"_schemas/principal.json",
+			"_schemas/resources/leave_request.json",
+			"_schemas/resources/nested/expense.json",
+			"_schemas/testdata/fixture.json",
+		}, schemas)
+
+		require.Contains(t, rfs.dirs, "_schemas/testdata")
+		require.NotContains(t, rfs.dirs, "resource_policies/testdata")
+		require.NotContains(t, rfs.dirs, "_schemas/.hidden")
+		require.NotContains(t, rfs.dirs, ".git")
+	})
+
+	t.Run("ignored_directory", func(t *testing.T) {
+		ignore, err := util.NewIgnoreMatcher([]string{"generated/"})
+		require.NoError(t, err)
+
+		layout := util.DefaultDirLayout
+		layout.Ignore = ignore
+
+		rfs := &readDirRecorder{FS: fsys}
+		policies, _, err := layout.ListIndexableFiles(rfs)
+		require.NoError(t, err)
+		require.NotContains(t, policies, "resource_policies/generated/album.yaml")
+		require.NotContains(t, rfs.dirs, "resource_policies/generated")
+	})
+}
+
+// readDirRecorder records the directories read from the underlying file system.
+type readDirRecorder struct {
+	fs.FS
+	dirs []string
+}
+
+func (r *readDirRecorder) ReadDir(name string) ([]fs.DirEntry, error) {
+	r.dirs = append(r.dirs, name)
+	return fs.ReadDir(r.FS, name)
+}
+
+func TestValidateFileFormat(t *testing.T) {
+	fsys := fstest.MapFS{
+		"json.json":         {Data: []byte(`{"apiVersion": "api.cerbos.dev/v1"}`)},
+		"yaml.yaml":         {Data: []byte("apiVersion: api.cerbos.dev/v1\n")},
+		"yaml.yml":          {Data: []byte("---\napiVersion: api.cerbos.dev/v1\n")},
+		"toml.toml":         {Data: []byte("apiVersion = \"api.cerbos.dev/v1\"\n")},
+		"json_in_yaml.yaml": {Data: []byte(`{"apiVersion": "api.cerbos.dev/v1"}`)},
+		"yaml_in_json.json": {Data: []byte("apiVersion: api.cerbos.dev/v1\n")},
+		"toml_in_yaml.yaml": {Data: []byte("apiVersion = \"api.cerbos.dev/v1\"\n")},
+		"toml_in_json.json": {Data: []byte("[resourcePolicy]\nversion = \"default\"\n")},
+		"yaml_in_toml.toml": {Data: []byte("apiVersion: api.cerbos.dev/v1\n")},
+		"trailing.json":     {Data: []byte(`{"apiVersion": "api.cerbos.dev/v1"} {}`)},
+		"invalid.yaml":      {Data: []byte("apiVersion: [api.cerbos.dev/v1\n")},
+		"empty.json":        {Data: []byte{}},
+		"policy.txt":        {Data: []byte("apiVersion: api.cerbos.dev/v1\n")},
+	}
+
+	testCases := []struct {
+		path       string
+		wantDetail string
+		wantErr    bool
+	}{
+		{path: "json.json"},
+		{path: "yaml.yaml"},
+		{path: "yaml.yml"},
+		{path: "toml.toml"},
+		{path: "json_in_yaml.yaml"},
+		{path: "yaml_in_json.json", wantErr: true, wantDetail: "is not valid JSON (the contents appear to be YAML)"},
+		{path: "toml_in_yaml.yaml", wantErr: true, wantDetail: "is not valid YAML (the contents appear to be TOML)"},
+		{path: "toml_in_json.json", wantErr: true, wantDetail: "is not valid JSON (the contents appear to be TOML)"},
+		{path: "yaml_in_toml.toml", wantErr: true, wantDetail: "is not valid TOML (the contents appear to be YAML)"},
+		{path: "trailing.json", wantErr: true, wantDetail: "unexpected data after the top-level value"},
+		{path: "invalid.yaml", wantErr: true, wantDetail: "is not valid YAML:"},
+		{path: "empty.json", wantErr: true, wantDetail: "the file is empty"},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.path, func(t *testing.T) {
+			err := util.ValidateFileFormat(fsys, tc.path)
+			if !tc.wantErr {
+				require.NoError(t, err)
+				return
+			}
+
+			require.ErrorIs(t, err, util.ErrFileFormatMismatch)
+			require.ErrorContains(t, err, tc.path)
+			require.ErrorContains(t, err, tc.wantDetail)
+		})
+	}
+
+	t.Run("load", func(t *testing.T) {
+		for _, tc := range testCases {
+			var dest structpb.Struct
+			err := util.LoadFromJSONOrYAML(fsys, tc.path, &dest, util.WithFileFormatCheck())
+			if !tc.wantErr {
+				require.NoError(t, err, tc.path)
+				require.Equal(t, "api.cerbos.dev/v1", dest.Fields["apiVersion"].GetStringValue(), tc.path)
+				continue
+			}
+
+			// other syntax errors are reported by the decoder
+			require.Error(t, err, tc.path)
+			if strings.Contains(tc.wantDetail, "appear to be") {
+				require.ErrorIs(t, err, util.ErrFileFormatMismatch, tc.path)
+				require.ErrorContains(t, err, tc.wantDetail, tc.path)
+			}
+		}
+	})
+
+	t.Run("unsupported_extension", func(t *testing.T) {
+		err := util.ValidateFileFormat(fsys, "policy.txt")
+		require.Error(t, err)
+		require.NotErrorIs(t, err, util.ErrFileFormatMismatch)
+	})
+
+	t.Run("missing_file", func(t *testing.T) {
+		require.Error(t, util.ValidateFileFormat(fsys, "missing.yaml"))
+	})
+}
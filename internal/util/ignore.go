@@ -0,0 +1,208 @@
+// Copyright 2021-2022 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package util
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// IgnoreFileName is the name of the file at the root of the policies directory that lists the paths to exclude from the index.
+const IgnoreFileName = ".cerbosignore"
+
+type ignorePattern struct {
+	regex   *regexp.Regexp
+	negate  bool
+	dirOnly bool
+}
+
+// IgnoreMatcher matches paths against a list of gitignore-style patterns.
+type IgnoreMatcher struct {
+	patterns []ignorePattern
+}
+
+// NewIgnoreMatcher parses the given gitignore-style patterns. Empty patterns and patterns starting with # are ignored.
+//
+// A pattern without a slash matches a file or directory with that name at any level, while a pattern containing a slash
+// is matched relative to the root. A trailing slash restricts the pattern to directories, `*` and `?` match anything
+// except a slash, `**` matches any number of directories and a leading `!` re-includes paths excluded by previous patterns.
+// When a directory is matched, everything under it is matched as well.
+func NewIgnoreMatcher(patterns []string) (*IgnoreMatcher, error) {
+	im := &IgnoreMatcher{}
+	for _, p := range patterns {
+		ip, ok, err := parseIgnorePattern(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ignore pattern %q: %w", p, err)
+		}
+
+		if ok {
+			im.patterns = append(im.patterns, ip)
+		}
+	}
+
+	return im, nil
+}
+
+// LoadIgnoreMatcher reads the patterns from the ignore file in the given root directory.
+// It returns nil if the file does not exist.
+func LoadIgnoreMatcher(fsys fs.FS, root string) (*IgnoreMatcher, error) {
+	f, err := fsys.Open(path.Join(root, IgnoreFileName))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("failed to read %s: %w", IgnoreFileName, err)
+	}
+	defer f.Close()
+
+	return ReadIgnoreMatcher(f)
+}
+
+// ReadIgnoreMatcher reads the contents of an ignore file (one pattern per line) from the given reader.
+func ReadIgnoreMatcher(r io.Reader) (*IgnoreMatcher, error) {
+	var patterns []string
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		patterns = append(patterns, s.Text())
+	}
+
+	if err := s.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", IgnoreFileName, err)
+	}
+
+	return NewIgnoreMatcher(patterns)
+}
+
+// WithIgnoreFile returns a copy of the layout that excludes the paths listed in the ignore file in the given root directory.
+// The layout is returned unchanged if it already has an ignore matcher.
+func (dl DirLayout) WithIgnoreFile(fsys fs.FS, root string) (DirLayout, error) {
+	if dl.Ignore != nil {
+		return dl, nil
+	}
+
+	ignore, err := LoadIgnoreMatcher(fsys, root)
+	if err != nil {
+		return dl, err
+	}
+
+	dl.Ignore = ignore
+	return dl, nil
+}
+
+// Match returns true if the given path is excluded by the patterns.
+// The path must be "/"-separated and relative to the root policies directory. A nil matcher does not match anything.
+func (im *IgnoreMatcher) Match(filePath string, isDir bool) bool {
+	if im == nil {
+		return false
+	}
+
+	filePath = strings.Trim(filePath, "/")
+	matched := false
+	for _, p := range im.patterns {
+		if p.matches(filePath, isDir) {
+			matched = !p.negate
+		}
+	}
+
+	return matched
+}
+
+func (p ignorePattern) matches(filePath string, isDir bool) bool {
+	m := p.regex.FindStringSubmatch(filePath)
+	if m == nil {
+		return false
+	}
+
+	// directory-only patterns must match a directory: either the path itself or one of its parents
+	return !p.dirOnly || isDir || m[1] != ""
+}
+
+func parseIgnorePattern(pattern string) (ignorePattern, bool, error) {
+	pattern = strings.TrimRight(pattern, " \t\r")
+	if pattern == "" || strings.HasPrefix(pattern, "#") {
+		return ignorePattern{}, false, nil
+	}
+
+	var ip ignorePattern
+	switch {
+	case strings.HasPrefix(pattern, "!"):
+		ip.negate = true
+		pattern = pattern[1:]
+	case strings.HasPrefix(pattern, `\!`), strings.HasPrefix(pattern, `\#`):
+		pattern = pattern[1:]
+	}
+
+	if strings.HasSuffix(pattern, "/") {
+		ip.dirOnly = true
+		pattern = strings.TrimRight(pattern, "/")
+	}
+
+	if pattern == "" {
+		return ignorePattern{}, false, nil
+	}
+
+	prefix := "^(?:.*/)?"
+	if strings.Contains(pattern, "/") {
+		prefix = "^"
+		pattern = strings.TrimPrefix(pattern, "/")
+	}
+
+	regex, err := regexp.Compile(prefix + globToRegex(pattern) + "(/.*)?$")
+	if err != nil {
+		return ignorePattern{}, false, err
+	}
+
+	ip.regex = regex
+	return ip, true, nil
+}
+
+func globToRegex(glob string) string {
+	var sb strings.Builder
+	for i := 0; i < len(glob); i++ {
+		switch c := glob[i]; c {
+		case '*':
+			if i+1 < len(glob) && glob[i+1] == '*' {
+				i++
+				if i+1 < len(glob) && glob[i+1] == '/' {
+					// **/ matches zero or more directories
+					i++
+					sb.WriteString("(?:.*?/)?")
+				} else {
+					sb.WriteString(".*?")
+				}
+			} else {
+				sb.WriteString("[^/]*")
+			}
+		case '?':
+			sb.WriteString("[^/]")
+		case '[':
+			if end := strings.IndexByte(glob[i+1:], ']'); end > 0 {
+				class := glob[i+1 : i+1+end]
+				if strings.HasPrefix(class, "!") {
+					class = "^" + class[1:]
+				}
+				sb.WriteString("[" + class + "]")
+				i += end + 1
+			} else {
+				sb.WriteString(regexp.QuoteMeta("["))
+			}
+		case '\\':
+			if i+1 < len(glob) {
+				i++
+				sb.WriteString(regexp.QuoteMeta(glob[i : i+1]))
+			}
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+
+	return sb.String()
+}
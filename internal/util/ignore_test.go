@@ -0,0 +1,107 @@
+// Copyright 2021-2022 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package util_test
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cerbos/cerbos/internal/util"
+)
+
+func TestIgnoreMatcher(t *testing.T) {
+	im, err := util.NewIgnoreMatcher([]string{
+		"# comment",
+		"",
+		"vendor/",
+		"/examples/*.yaml",
+		"!examples/keep.yaml",
+		"**/tmp/**",
+		"*.bak.yaml",
+		"a/**/b.yaml",
+	})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		path  string
+		isDir bool
+		want  bool
+	}{
+		{path: "vendor", isDir: true, want: true},
+		{path: "vendor", want: false},
+		{path: "vendor/policy.yaml", want: true},
+		{path: "foo/vendor/policy.yaml", want: true},
+		{path: "examples/policy.yaml", want: true},
+		{path: "examples/keep.yaml", want: false},
+		{path: "foo/examples/policy.yaml", want: false},
+		{path: "examples/foo/policy.yaml", want: false},
+		{path: "tmp/policy.yaml", want: true},
+		{path: "foo/tmp/bar/policy.yaml", want: true},
+		{path: "policy.bak.yaml", want: true},
+		{path: "foo/policy.bak.yaml", want: true},
+		{path: "a/b.yaml", want: true},
+		{path: "a/x/y/b.yaml", want: true},
+		{path: "# comment", want: false},
+		{path: "policy.yaml", want: false},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.path, func(t *testing.T) {
+			require.Equal(t, tc.want, im.Match(tc.path, tc.isDir))
+		})
+	}
+
+	t.Run("nil", func(t *testing.T) {
+		var im *util.IgnoreMatcher
+		require.False(t, im.Match("policy.yaml", false))
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := util.NewIgnoreMatcher([]string{"[[:foo:]]"})
+		require.Error(t, err)
+	})
+}
+
+func TestLoadIgnoreMatcher(t *testing.T) {
+	fsys := fstest.MapFS{
+		"policies/.cerbosignore": {Data: []byte("# third-party examples\nvendor/\n")},
+	}
+
+	im, err := util.LoadIgnoreMatcher(fsys, "policies")
+	require.NoError(t, err)
+	require.True(t, im.Match("vendor/policy.yaml", false))
+
+	layout := util.DefaultDirLayout
+	layout.Ignore = im
+	require.Equal(t, util.FileTypeNotIndexed, layout.FileType("vendor/policy.yaml"))
+	require.Equal(t, util.FileTypePolicy, layout.FileType("policy.yaml"))
+
+	im, err = util.LoadIgnoreMatcher(fsys, ".")
+	require.NoError(t, err)
+	require.Nil(t, im)
+}
+
+func TestDirLayoutWithIgnoreFile(t *testing.T) {
+	fsys := fstest.MapFS{
+		"policies/.cerbosignore": {Data: []byte("vendor/\n")},
+	}
+
+	layout, err := util.DefaultDirLayout.WithIgnoreFile(fsys, "policies")
+	require.NoError(t, err)
+	require.Equal(t, util.FileTypeNotIndexed, layout.FileType("vendor/policy.yaml"))
+	require.Nil(t, util.DefaultDirLayout.Ignore)
+
+	// an existing matcher is kept
+	im, err := util.NewIgnoreMatcher([]string{"*.draft.yaml"})
+	require.NoError(t, err)
+
+	layout.Ignore = im
+	layout, err = layout.WithIgnoreFile(fsys, "policies")
+	require.NoError(t, err)
+	require.Equal(t, util.FileTypePolicy, layout.FileType("vendor/policy.yaml"))
+	require.Equal(t, util.FileTypeNotIndexed, layout.FileType("policy.draft.yaml"))
+}
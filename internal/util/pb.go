@@ -4,20 +4,47 @@
 package util
 
 import (
+	"encoding/base64"
 	"reflect"
 	"time"
 
 	"google.golang.org/protobuf/types/known/structpb"
 )
 
+// ToStructPB converts the given value to a protobuf value. In addition to the types supported by structpb.NewValue,
+// it converts arbitrary slices and maps with string keys, time.Time values (to RFC3339 strings) and []byte values
+// (to base64 strings) at any level of nesting.
 func ToStructPB(v any) (*structpb.Value, error) {
 	val, err := structpb.NewValue(v)
 	if err == nil {
 		return val, nil
 	}
 
-	if t, ok := v.(time.Time); ok {
-		return structpb.NewStringValue(t.Format(time.RFC3339)), nil
+	normalized, ok := normalizeValue(v)
+	if !ok {
+		return nil, err
+	}
+
+	return structpb.NewValue(normalized)
+}
+
+// normalizeValue converts the given value to one of the types supported by structpb.NewValue.
+// It returns false if the value, or any value nested in it, cannot be converted.
+func normalizeValue(v any) (any, bool) {
+	switch t := v.(type) {
+	case time.Time:
+		return t.Format(time.RFC3339), true
+	case *time.Time:
+		if t == nil {
+			return nil, true
+		}
+		return t.Format(time.RFC3339), true
+	case []byte:
+		return base64.StdEncoding.EncodeToString(t), true
+	}
+
+	if _, err := structpb.NewValue(v); err == nil {
+		return v, true
 	}
 
 	vv := reflect.ValueOf(v)
@@ -25,26 +52,31 @@ func ToStructPB(v any) (*structpb.Value, error) {
 	case reflect.Array, reflect.Slice:
 		arr := make([]any, vv.Len())
 		for i := 0; i < vv.Len(); i++ {
-			el := vv.Index(i)
-			// TODO (cell) Recurse
-			arr[i] = el.Interface()
+			el, ok := normalizeValue(vv.Index(i).Interface())
+			if !ok {
+				return nil, false
+			}
+			arr[i] = el
 		}
 
-		return structpb.NewValue(arr)
+		return arr, true
 	case reflect.Map:
-		if vv.Type().Key().Kind() == reflect.String {
-			m := make(map[string]any)
+		if vv.Type().Key().Kind() != reflect.String {
+			return nil, false
+		}
 
-			iter := vv.MapRange()
-			for iter.Next() {
-				m[iter.Key().String()] = iter.Value().Interface()
+		m := make(map[string]any, vv.Len())
+		iter := vv.MapRange()
+		for iter.Next() {
+			el, ok := normalizeValue(iter.Value().Interface())
+			if !ok {
+				return nil, false
 			}
-
-			return structpb.NewValue(m)
+			m[iter.Key().String()] = el
 		}
+
+		return m, true
 	default:
-		return nil, err
+		return nil, false
 	}
-
-	return nil, err
 }
@@ -0,0 +1,59 @@
+// Copyright 2021-2022 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package util_test
+
+import (
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cerbos/cerbos/internal/util"
+)
+
+func TestToStructPB(t *testing.T) {
+	issuedAt := time.Date(2022, 11, 1, 10, 15, 0, 0, time.UTC)
+	fingerprint := []byte{0xde, 0xad, 0xbe, 0xef}
+
+	claims := map[string]any{
+		"sub":         "harry",
+		"aud":         []string{"cerbos", "jwt-tests"},
+		"iat":         issuedAt,
+		"fingerprint": fingerprint,
+		"custom": map[string]any{
+			"reviewed_at": []time.Time{issuedAt, issuedAt.Add(time.Hour)},
+			"keys":        map[string][]byte{"primary": fingerprint},
+			"score":       42,
+		},
+	}
+
+	have, err := util.ToStructPB(claims)
+	require.NoError(t, err)
+
+	encodedFingerprint := base64.StdEncoding.EncodeToString(fingerprint)
+	require.Equal(t, map[string]any{
+		"sub":         "harry",
+		"aud":         []any{"cerbos", "jwt-tests"},
+		"iat":         "2022-11-01T10:15:00Z",
+		"fingerprint": encodedFingerprint,
+		"custom": map[string]any{
+			"reviewed_at": []any{"2022-11-01T10:15:00Z", "2022-11-01T11:15:00Z"},
+			"keys":        map[string]any{"primary": encodedFingerprint},
+			"score":       float64(42),
+		},
+	}, have.AsInterface())
+
+	for name, value := range claims {
+		have, err := util.ToStructPB(value)
+		require.NoError(t, err, "Failed to convert %q", name)
+		require.NotNil(t, have)
+	}
+
+	_, err = util.ToStructPB(map[int]string{1: "one"})
+	require.Error(t, err)
+
+	_, err = util.ToStructPB([]any{make(chan int)})
+	require.Error(t, err)
+}
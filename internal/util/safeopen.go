@@ -0,0 +1,159 @@
+// Copyright 2021-2022 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package util
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrPathEscapesRoot is returned by SafeOpen when a path resolves to a location outside the root of the file system.
+var ErrPathEscapesRoot = errors.New("path resolves to a location outside the root directory")
+
+// PathResolver is implemented by file systems that can resolve symbolic links.
+type PathResolver interface {
+	// ResolvePath returns the given path with all symbolic links resolved, relative to the root of the file system.
+	// It returns ErrPathEscapesRoot if the resolved path is outside the root.
+	ResolvePath(name string) (string, error)
+	// OpenResolved opens a path returned by ResolvePath.
+	// It returns ErrPathEscapesRoot if the path no longer refers to the file that was resolved.
+	OpenResolved(resolved string) (fs.File, error)
+}
+
+type rootedDirFS struct {
+	fs.FS
+	// rootFS serves the resolved root directory.
+	rootFS fs.FS
+	// rootErr is the error encountered while resolving the root directory.
+	rootErr error
+	// root is the directory that files must resolve to, with all symbolic links resolved.
+	root string
+	// dir is the directory served by the file system. It is either the root or a subdirectory of the root.
+	dir string
+}
+
+// RootedDirFS returns a file system for the tree of files rooted at the given directory, like os.DirFS.
+// Files opened using SafeOpen from the returned file system cannot escape the directory through symbolic links.
+// Sub-trees obtained using fs.Sub are confined to the same directory.
+func RootedDirFS(dir string) fs.FS {
+	r := rootedDirFS{FS: os.DirFS(dir), dir: dir}
+
+	// the root itself may be a symbolic link
+	root, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		r.rootErr = fmt.Errorf("failed to resolve root directory: %w", err)
+		return r
+	}
+
+	r.root = root
+	r.rootFS = os.DirFS(root)
+	return r
+}
+
+func (r rootedDirFS) Sub(dir string) (fs.FS, error) {
+	if !fs.ValidPath(dir) {
+		return nil, &fs.PathError{Op: "sub", Path: dir, Err: fs.ErrInvalid}
+	}
+
+	if dir == "." {
+		return r, nil
+	}
+
+	subDir := filepath.Join(r.dir, filepath.FromSlash(dir))
+	return rootedDirFS{FS: os.DirFS(subDir), rootFS: r.rootFS, rootErr: r.rootErr, root: r.root, dir: subDir}, nil
+}
+
+// ResolvePath returns the resolved path relative to the directory given to RootedDirFS, which may be outside the
+// sub-tree served by a file system obtained using fs.Sub.
+func (r rootedDirFS) ResolvePath(name string) (string, error) {
+	if !fs.ValidPath(name) {
+		return "", &fs.PathError{Op: "resolve", Path: name, Err: fs.ErrInvalid}
+	}
+
+	if r.rootErr != nil {
+		return "", r.rootErr
+	}
+
+	return r.relativeToRoot(name, filepath.Join(r.dir, filepath.FromSlash(name)))
+}
+
+// OpenResolved opens the resolved path in the resolved root directory, so that symbolic links created after the
+// path was resolved are not followed. Directories on the path could still have been replaced by symbolic links,
+// so the opened file is checked against the path once more.
+func (r rootedDirFS) OpenResolved(resolved string) (fs.File, error) {
+	if r.rootErr != nil {
+		return nil, r.rootErr
+	}
+
+	f, err := r.rootFS.Open(resolved)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.checkOpened(f, resolved); err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+
+	return f, nil
+}
+
+func (r rootedDirFS) checkOpened(f fs.File, resolved string) error {
+	opened, err := f.Stat()
+	if err != nil {
+		return &fs.PathError{Op: "resolve", Path: resolved, Err: err}
+	}
+
+	// the path was resolved and must still be free of symbolic links
+	if rel, err := r.relativeToRoot(resolved, filepath.Join(r.root, filepath.FromSlash(resolved))); err != nil {
+		return err
+	} else if rel != resolved {
+		return &fs.PathError{Op: "resolve", Path: resolved, Err: ErrPathEscapesRoot}
+	}
+
+	current, err := fs.Stat(r.rootFS, resolved)
+	if err != nil {
+		return &fs.PathError{Op: "resolve", Path: resolved, Err: err}
+	}
+
+	if !os.SameFile(opened, current) {
+		return &fs.PathError{Op: "resolve", Path: resolved, Err: ErrPathEscapesRoot}
+	}
+
+	return nil
+}
+
+func (r rootedDirFS) relativeToRoot(name, fullPath string) (string, error) {
+	resolved, err := filepath.EvalSymlinks(fullPath)
+	if err != nil {
+		return "", &fs.PathError{Op: "resolve", Path: name, Err: err}
+	}
+
+	rel, err := filepath.Rel(r.root, resolved)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", &fs.PathError{Op: "resolve", Path: name, Err: ErrPathEscapesRoot}
+	}
+
+	return filepath.ToSlash(rel), nil
+}
+
+// SafeOpen opens the named file, making sure that it does not resolve to a location outside the root of the file system
+// through symbolic links. The check is only done if the file system implements PathResolver (e.g. file systems created using RootedDirFS).
+func SafeOpen(fsys fs.FS, name string) (fs.File, error) {
+	resolver, ok := fsys.(PathResolver)
+	if !ok {
+		return fsys.Open(name)
+	}
+
+	resolved, err := resolver.ResolvePath(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return resolver.OpenResolved(resolved)
+}
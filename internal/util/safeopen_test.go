@@ -0,0 +1,112 @@
+// Copyright 2021-2022 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package util_test
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/cerbos/cerbos/internal/util"
+)
+
+func TestSafeOpen(t *testing.T) {
+	outside := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(outside, "secret.yaml"), []byte("secret: true\n"), 0o600))
+
+	root := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(root, "policies"), 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "policies", "policy.yaml"), []byte("name: test\n"), 0o600))
+
+	symlink := func(target, link string) {
+		t.Helper()
+		if err := os.Symlink(target, filepath.Join(root, link)); err != nil {
+			t.Skipf("Symbolic links are not supported: %v", err)
+		}
+	}
+
+	symlink(filepath.Join(outside, "secret.yaml"), "escape.yaml")
+	symlink(filepath.Join(outside), "escape_dir")
+	symlink(filepath.Join("policies", "policy.yaml"), "internal.yaml")
+	symlink(filepath.Join("..", filepath.Base(root), "policies"), "relative_dir")
+
+	fsys := util.RootedDirFS(root)
+
+	testCases := []struct {
+		wantErr error
+		name    string
+	}{
+		{name: "policies/policy.yaml"},
+		{name: "internal.yaml"},
+		{name: "relative_dir/policy.yaml"},
+		{name: "escape.yaml", wantErr: util.ErrPathEscapesRoot},
+		{name: "escape_dir/secret.yaml", wantErr: util.ErrPathEscapesRoot},
+		{name: "missing.yaml", wantErr: fs.ErrNotExist},
+		{name: "../outside.yaml", wantErr: fs.ErrInvalid},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			f, err := util.SafeOpen(fsys, tc.name)
+			if tc.wantErr != nil {
+				require.Error(t, err)
+				require.True(t, errors.Is(err, tc.wantErr), "Unexpected error: %v", err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.NoError(t, f.Close())
+		})
+	}
+
+	t.Run("load", func(t *testing.T) {
+		var s structpb.Struct
+		require.ErrorIs(t, util.LoadFromJSONOrYAML(fsys, "escape.yaml", &s), util.ErrPathEscapesRoot)
+		require.NoError(t, util.LoadFromJSONOrYAML(os.DirFS(root), "escape.yaml", &s), "Symbolic links should be followed if the file system does not implement PathResolver")
+	})
+
+	t.Run("sub", func(t *testing.T) {
+		sub, err := fs.Sub(fsys, "relative_dir")
+		require.NoError(t, err)
+
+		f, err := util.SafeOpen(sub, "policy.yaml")
+		require.NoError(t, err)
+		require.NoError(t, f.Close())
+
+		sub, err = fs.Sub(fsys, "escape_dir")
+		require.NoError(t, err)
+
+		_, err = util.SafeOpen(sub, "secret.yaml")
+		require.ErrorIs(t, err, util.ErrPathEscapesRoot)
+	})
+
+	t.Run("swapped_after_resolve", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(filepath.Join(root, "swapped.yaml"), []byte("name: test\n"), 0o600))
+
+		resolver, ok := fsys.(util.PathResolver)
+		require.True(t, ok)
+
+		resolved, err := resolver.ResolvePath("swapped.yaml")
+		require.NoError(t, err)
+
+		require.NoError(t, os.Remove(filepath.Join(root, "swapped.yaml")))
+		symlink(filepath.Join(outside, "secret.yaml"), "swapped.yaml")
+
+		_, err = resolver.OpenResolved(resolved)
+		require.ErrorIs(t, err, util.ErrPathEscapesRoot)
+	})
+
+	t.Run("not_resolver", func(t *testing.T) {
+		f, err := util.SafeOpen(fstest.MapFS{"policy.yaml": {}}, "policy.yaml")
+		require.NoError(t, err)
+		require.NoError(t, f.Close())
+	})
+}
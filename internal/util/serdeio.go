@@ -6,34 +6,114 @@ package util
 import (
 	"bufio"
 	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"regexp"
 	"unicode"
 
 	"github.com/ghodss/yaml"
+	"github.com/pelletier/go-toml/v2"
 	"google.golang.org/protobuf/encoding/protojson"
 	"google.golang.org/protobuf/proto"
 )
 
 const (
-	bufSize     = 1024 * 4        // 4KiB
-	maxFileSize = 1024 * 1024 * 4 // 4MiB
-	newline     = '\n'
+	bufSize = 1024 * 4 // 4KiB
+	newline = '\n'
 )
 
+// DefaultMaxFileSize is the maximum size of the files read by ReadJSONOrYAML and LoadFromJSONOrYAML unless WithMaxBytes is used.
+const DefaultMaxFileSize = 1024 * 1024 * 4 // 4MiB
+
 var (
 	jsonStart           = []byte("{")
 	yamlSep             = []byte("---")
 	yamlComment         = []byte("#")
 	ErrMultipleYAMLDocs = errors.New("more than one YAML document detected")
+	ErrFileTooLarge     = errors.New("file is too large")
+
+	// tomlStart matches the first significant line of a TOML document: a table header or a key/value pair.
+	// Neither of them can start a YAML document that decodes to a protobuf message.
+	tomlStart = regexp.MustCompile(`^(\[\[?\s*[A-Za-z0-9_\-."' ]+\]\]?\s*(#.*)?|[A-Za-z0-9_\-."' ]+=.*)$`)
 )
 
-func ReadJSONOrYAML(src io.Reader, dest proto.Message) error {
-	d := mkDecoder(io.LimitReader(src, maxFileSize))
+type loadOptions struct {
+	maxBytes        int64
+	checkFileFormat bool
+}
+
+// LoadOpt configures the functions that read protobufs from files.
+type LoadOpt func(*loadOptions)
+
+// WithMaxBytes sets the maximum number of bytes to read. ErrFileTooLarge is returned if the source is larger.
+func WithMaxBytes(n int64) LoadOpt {
+	return func(o *loadOptions) {
+		o.maxBytes = n
+	}
+}
+
+// WithFileFormatCheck makes LoadFromJSONOrYAML reject files whose contents don't match their extension.
+// It has no effect on the functions that read from an io.Reader.
+func WithFileFormatCheck() LoadOpt {
+	return func(o *loadOptions) {
+		o.checkFileFormat = true
+	}
+}
+
+func mkLoadOpts(opts ...LoadOpt) loadOptions {
+	o := loadOptions{maxBytes: DefaultMaxFileSize}
+	for _, optFn := range opts {
+		optFn(&o)
+	}
+
+	return o
+}
+
+// ReadJSONOrYAML reads a JSON, YAML or TOML encoded protobuf from the given source.
+// The encoding is detected from the contents.
+func ReadJSONOrYAML(src io.Reader, dest proto.Message, opts ...LoadOpt) error {
+	d := mkDecoder(newMaxBytesReader(src, mkLoadOpts(opts...).maxBytes))
 	return d.decode(dest)
 }
 
+// ReadTOML reads a TOML encoded protobuf from the given source.
+func ReadTOML(src io.Reader, dest proto.Message, opts ...LoadOpt) error {
+	return newTOMLDecoder(bufio.NewReaderSize(newMaxBytesReader(src, mkLoadOpts(opts...).maxBytes), bufSize)).decode(dest)
+}
+
+// maxBytesReader is like io.LimitedReader but returns ErrFileTooLarge if the underlying reader has more data than allowed.
+type maxBytesReader struct {
+	src       io.Reader
+	limit     int64
+	remaining int64
+}
+
+func newMaxBytesReader(src io.Reader, limit int64) *maxBytesReader {
+	return &maxBytesReader{src: src, limit: limit, remaining: limit}
+}
+
+func (r *maxBytesReader) Read(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+
+	// read one byte more than allowed to detect whether the source is too large
+	if int64(len(p)) > r.remaining+1 {
+		p = p[:r.remaining+1]
+	}
+
+	n, err := r.src.Read(p)
+	if int64(n) > r.remaining {
+		r.remaining = 0
+		return 0, fmt.Errorf("%w: the maximum size is %d bytes", ErrFileTooLarge, r.limit)
+	}
+
+	r.remaining -= int64(n)
+	return n, err
+}
+
 func mkDecoder(src io.Reader) decoder {
 	buf := bufio.NewReaderSize(src, bufSize)
 	prelude, _ := buf.Peek(bufSize)
@@ -43,9 +123,94 @@ func mkDecoder(src io.Reader) decoder {
 		return newJSONDecoder(buf)
 	}
 
+	if isTOML(prelude) {
+		return newTOMLDecoder(buf)
+	}
+
 	return newYAMLDecoder(buf)
 }
 
+// isTOML returns true if the first line that is not empty or a comment looks like TOML.
+func isTOML(prelude []byte) bool {
+	s := bufio.NewScanner(bytes.NewReader(prelude))
+	for s.Scan() {
+		line := bytes.TrimSpace(s.Bytes())
+		if len(line) == 0 || bytes.HasPrefix(line, yamlComment) {
+			continue
+		}
+
+		return tomlStart.Match(line)
+	}
+
+	return false
+}
+
+// fileFormat is the encoding of a file.
+type fileFormat string
+
+const (
+	fileFormatJSON fileFormat = "JSON"
+	fileFormatTOML fileFormat = "TOML"
+	fileFormatYAML fileFormat = "YAML"
+)
+
+// fileFormatOf returns the format declared by the given supported file extension.
+func fileFormatOf(ext string) fileFormat {
+	switch ext {
+	case ".json":
+		return fileFormatJSON
+	case ".toml":
+		return fileFormatTOML
+	default:
+		return fileFormatYAML
+	}
+}
+
+// detectFileFormat guesses the format of the given contents using the same rules as ReadJSONOrYAML.
+func detectFileFormat(contents []byte) fileFormat {
+	prelude := contents
+	if len(prelude) > bufSize {
+		prelude = prelude[:bufSize]
+	}
+
+	if bytes.HasPrefix(bytes.TrimLeftFunc(prelude, unicode.IsSpace), jsonStart) {
+		return fileFormatJSON
+	}
+
+	if isTOML(prelude) {
+		return fileFormatTOML
+	}
+
+	return fileFormatYAML
+}
+
+// validateFormat returns an error if the contents cannot be parsed in the given format.
+func validateFormat(format fileFormat, contents []byte) error {
+	switch format {
+	case fileFormatJSON:
+		dec := json.NewDecoder(bytes.NewReader(contents))
+		var doc any
+		if err := dec.Decode(&doc); err != nil {
+			if errors.Is(err, io.EOF) {
+				return errors.New("the file is empty")
+			}
+			return err
+		}
+
+		if _, err := dec.Token(); !errors.Is(err, io.EOF) {
+			return errors.New("unexpected data after the top-level value")
+		}
+
+		return nil
+	case fileFormatTOML:
+		var doc map[string]any
+		return toml.Unmarshal(contents, &doc)
+	default:
+		var doc any
+		return yaml.Unmarshal(contents, &doc)
+	}
+}
+
 type decoder interface {
 	decode(dest proto.Message) error
 }
@@ -121,6 +286,25 @@ func newYAMLDecoder(src *bufio.Reader) decoderFunc {
 	}
 }
 
+func newTOMLDecoder(src *bufio.Reader) decoderFunc {
+	return func(dest proto.Message) error {
+		var doc map[string]any
+		if err := toml.NewDecoder(src).Decode(&doc); err != nil {
+			return fmt.Errorf("failed to unmarshal TOML: %w", err)
+		}
+
+		jsonBytes, err := json.Marshal(doc)
+		if err != nil {
+			return fmt.Errorf("failed to convert TOML to JSON: %w", err)
+		}
+
+		if err := protojson.Unmarshal(jsonBytes, dest); err != nil {
+			return fmt.Errorf("failed to unmarshal JSON: %w", err)
+		}
+		return nil
+	}
+}
+
 func WriteYAML(dest io.Writer, data proto.Message) error {
 	jsonBytes, err := protojson.Marshal(data)
 	if err != nil {
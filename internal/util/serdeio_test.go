@@ -4,11 +4,19 @@
 package util_test
 
 import (
+	"bytes"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"testing/fstest"
 
+	"github.com/ghodss/yaml"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pelletier/go-toml/v2"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/testing/protocmp"
 	"google.golang.org/protobuf/types/known/structpb"
 
 	"github.com/cerbos/cerbos/internal/util"
@@ -25,6 +33,13 @@ func TestReadJSONOrYAML(t *testing.T) {
 		{
 			input: "single_json.json",
 		},
+		{
+			input: "single_toml.toml",
+		},
+		{
+			input:   "invalid.toml",
+			wantErr: true,
+		},
 		{
 			input:   "multiple_yaml1.yaml",
 			wantErr: true,
@@ -62,3 +77,96 @@ func TestReadJSONOrYAML(t *testing.T) {
 		})
 	}
 }
+
+func TestRoundTrip(t *testing.T) {
+	want, err := structpb.NewStruct(map[string]any{
+		"name":    "leave_request",
+		"version": "default",
+		"rules": []any{
+			map[string]any{"actions": []any{"view", "approve"}, "effect": "EFFECT_ALLOW", "roles": []any{"user"}},
+		},
+		"enabled": true,
+		"limit":   42.5,
+	})
+	require.NoError(t, err)
+
+	jsonBytes, err := protojson.Marshal(want)
+	require.NoError(t, err)
+
+	yamlBytes, err := yaml.JSONToYAML(jsonBytes)
+	require.NoError(t, err)
+
+	tomlBytes, err := toml.Marshal(want.AsMap())
+	require.NoError(t, err)
+
+	testCases := map[string][]byte{
+		"policy.json": jsonBytes,
+		"policy.yaml": yamlBytes,
+		"policy.yml":  yamlBytes,
+		"policy.toml": tomlBytes,
+	}
+
+	fsys := make(fstest.MapFS, len(testCases))
+	for fileName, data := range testCases {
+		fsys[fileName] = &fstest.MapFile{Data: data}
+	}
+
+	for fileName, data := range testCases {
+		fileName, data := fileName, data
+		t.Run(fileName, func(t *testing.T) {
+			require.True(t, util.IsSupportedFileType(fileName))
+
+			t.Run("load", func(t *testing.T) {
+				var have structpb.Struct
+				require.NoError(t, util.LoadFromJSONOrYAML(fsys, fileName, &have))
+				require.Empty(t, cmp.Diff(want, &have, protocmp.Transform()))
+			})
+
+			t.Run("read", func(t *testing.T) {
+				var have structpb.Struct
+				require.NoError(t, util.ReadJSONOrYAML(bytes.NewReader(data), &have))
+				require.Empty(t, cmp.Diff(want, &have, protocmp.Transform()))
+			})
+		})
+	}
+}
+
+func TestReadJSONOrYAMLMaxBytes(t *testing.T) {
+	testCases := map[string]string{
+		"json": `{"f1": "test", "f2": 42}`,
+		"yaml": "f1: test\nf2: 42\n",
+		"toml": "f1 = \"test\"\nf2 = 42\n",
+	}
+
+	for format, contents := range testCases {
+		format, contents := format, contents
+		t.Run(format, func(t *testing.T) {
+			t.Run("within_limit", func(t *testing.T) {
+				var m structpb.Struct
+				require.NoError(t, util.ReadJSONOrYAML(strings.NewReader(contents), &m, util.WithMaxBytes(int64(len(contents)))))
+				require.Equal(t, "test", m.AsMap()["f1"])
+			})
+
+			t.Run("too_large", func(t *testing.T) {
+				var m structpb.Struct
+				err := util.ReadJSONOrYAML(strings.NewReader(contents), &m, util.WithMaxBytes(int64(len(contents)-1)))
+				require.ErrorIs(t, err, util.ErrFileTooLarge)
+			})
+
+			t.Run("load", func(t *testing.T) {
+				fileName := "policy." + format
+				fsys := fstest.MapFS{fileName: {Data: []byte(contents)}}
+
+				var m structpb.Struct
+				require.NoError(t, util.LoadFromJSONOrYAML(fsys, fileName, &m))
+				require.ErrorIs(t, util.LoadFromJSONOrYAML(fsys, fileName, &m, util.WithMaxBytes(8)), util.ErrFileTooLarge)
+			})
+		})
+	}
+
+	t.Run("default", func(t *testing.T) {
+		contents := `{"f1": "` + strings.Repeat("a", util.DefaultMaxFileSize) + `"}`
+		var m structpb.Struct
+		require.ErrorIs(t, util.ReadJSONOrYAML(strings.NewReader(contents), &m), util.ErrFileTooLarge)
+	})
+}